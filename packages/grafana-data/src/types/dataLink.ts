@@ -50,6 +50,10 @@ export interface DataLink<T extends DataQuery = any> {
   // @internal and subject to change in future releases
   internal?: InternalDataLink<T>;
 
+  // Transformations of the field value that create variables for the URL of an external link.
+  // @internal and subject to change in future releases
+  transformations?: DataLinkTransformationConfig[];
+
   origin?: DataLinkConfigOrigin;
   sortIndex?: number;
 }
@@ -63,6 +67,8 @@ export interface DataLink<T extends DataQuery = any> {
 export enum SupportedTransformationType {
   Regex = 'regex',
   Logfmt = 'logfmt',
+  JSONPath = 'jsonpath',
+  Template = 'template',
 }
 
 /** @internal */
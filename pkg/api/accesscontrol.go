@@ -29,6 +29,7 @@ var (
 	ScopeProvisionersDatasources   = ac.Scope("provisioners", "datasources")
 	ScopeProvisionersNotifications = ac.Scope("provisioners", "notifications")
 	ScopeProvisionersAlertRules    = ac.Scope("provisioners", "alerting")
+	ScopeProvisionersAnnotations   = ac.Scope("provisioners", "annotations")
 )
 
 // declareFixedRoles declares to the AccessControl service fixed roles and their
@@ -651,6 +652,38 @@ func (hs *HTTPServer) declareFixedRoles() error {
 		roles = append(roles, allAnnotationsReaderRole, allAnnotationsWriterRole)
 	}
 
+	if hs.Features.IsEnabledGlobally(featuremgmt.FlagLivePipeline) {
+		livePipelineReaderRole := ac.RoleRegistration{
+			Role: ac.RoleDTO{
+				Name:        "fixed:live.pipeline:reader",
+				DisplayName: "Reader",
+				Description: "Read Live pipeline channel rules and write configs.",
+				Group:       "Live",
+				Permissions: []ac.Permission{
+					{Action: ac.ActionLiveChannelRulesRead},
+					{Action: ac.ActionLiveWriteConfigsRead},
+				},
+			},
+			Grants: []string{string(org.RoleAdmin)},
+		}
+
+		livePipelineWriterRole := ac.RoleRegistration{
+			Role: ac.RoleDTO{
+				Name:        "fixed:live.pipeline:writer",
+				DisplayName: "Writer",
+				Description: "Create, update and delete Live pipeline channel rules and write configs.",
+				Group:       "Live",
+				Permissions: ac.ConcatPermissions(livePipelineReaderRole.Role.Permissions, []ac.Permission{
+					{Action: ac.ActionLiveChannelRulesWrite},
+					{Action: ac.ActionLiveWriteConfigsWrite},
+				}),
+			},
+			Grants: []string{string(org.RoleAdmin)},
+		}
+
+		roles = append(roles, livePipelineReaderRole, livePipelineWriterRole)
+	}
+
 	return hs.accesscontrolService.DeclareFixedRoles(roles...)
 }
 
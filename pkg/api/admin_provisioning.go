@@ -85,3 +85,11 @@ func (hs *HTTPServer) AdminProvisioningReloadAlerting(c *contextmodel.ReqContext
 	}
 	return response.Success("Alerting config reloaded")
 }
+
+func (hs *HTTPServer) AdminProvisioningReloadAnnotations(c *contextmodel.ReqContext) response.Response {
+	err := hs.ProvisioningService.ProvisionAnnotations(c.Req.Context())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to reload annotations config", err)
+	}
+	return response.Success("Annotations config reloaded")
+}
@@ -136,6 +136,26 @@ func TestAPI_AdminProvisioningReload_AccessControl(t *testing.T) {
 			expectedCode: http.StatusForbidden,
 			url:          "/api/admin/provisioning/alerting/reload",
 		},
+		{
+			desc:         "should work for annotations with specific scope",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"message":"Annotations config reloaded"}`,
+			permissions: []accesscontrol.Permission{
+				{
+					Action: ActionProvisioningReload,
+					Scope:  ScopeProvisionersAnnotations,
+				},
+			},
+			url: "/api/admin/provisioning/annotations/reload",
+			checkCall: func(mock provisioning.ProvisioningServiceMock) {
+				assert.Len(t, mock.Calls.ProvisionAnnotations, 1)
+			},
+		},
+		{
+			desc:         "should fail for annotations with no permission",
+			expectedCode: http.StatusForbidden,
+			url:          "/api/admin/provisioning/annotations/reload",
+		},
 	}
 
 	for _, tt := range tests {
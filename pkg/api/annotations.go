@@ -27,6 +27,7 @@ import (
 // Find Annotations.
 //
 // Starting in Grafana v6.4 regions annotations are now returned in one entity that now includes the timeEnd property.
+// If there can be more annotations than the limit, the cursor of the next page is returned in the X-Grafana-Next-Cursor header.
 //
 // Responses:
 // 200: getAnnotationsResponse
@@ -46,6 +47,8 @@ func (hs *HTTPServer) GetAnnotations(c *contextmodel.ReqContext) response.Respon
 		Tags:         c.QueryStrings("tags"),
 		Type:         c.Query("type"),
 		MatchAny:     c.QueryBool("matchAny"),
+		Text:         c.Query("text"),
+		Cursor:       c.Query("cursor"),
 		SignedInUser: c.SignedInUser,
 	}
 
@@ -62,7 +65,7 @@ func (hs *HTTPServer) GetAnnotations(c *contextmodel.ReqContext) response.Respon
 
 	items, err := hs.annotationsRepo.Find(c.Req.Context(), query)
 	if err != nil {
-		return response.Error(http.StatusInternalServerError, "Failed to get annotations", err)
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get annotations", err)
 	}
 
 	// since there are several annotations per dashboard, we can cache dashboard uid
@@ -86,7 +89,61 @@ func (hs *HTTPServer) GetAnnotations(c *contextmodel.ReqContext) response.Respon
 		}
 	}
 
-	return response.JSON(http.StatusOK, items)
+	resp := response.JSON(http.StatusOK, items)
+	if cursor := annotations.NextCursor(items, query.Limit); cursor != "" {
+		resp.SetHeader(annotationsNextCursorHeader, cursor)
+	}
+	return resp
+}
+
+const annotationsNextCursorHeader = "X-Grafana-Next-Cursor"
+
+// swagger:route GET /annotations/counts annotations getAnnotationCounts
+//
+// Count Annotations.
+//
+// Counts the annotations grouped by tag, dashboard or time. The annotations are filtered the same way as when finding annotations.
+//
+// Responses:
+// 200: getAnnotationCountsResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 500: internalServerError
+func (hs *HTTPServer) GetAnnotationCounts(c *contextmodel.ReqContext) response.Response {
+	query := &annotations.CountQuery{
+		ItemQuery: annotations.ItemQuery{
+			From:         c.QueryInt64("from"),
+			To:           c.QueryInt64("to"),
+			OrgID:        c.SignedInUser.GetOrgID(),
+			UserID:       c.QueryInt64("userId"),
+			AlertID:      c.QueryInt64("alertId"),
+			DashboardUID: c.Query("dashboardUID"),
+			PanelID:      c.QueryInt64("panelId"),
+			Tags:         c.QueryStrings("tags"),
+			Type:         c.Query("type"),
+			MatchAny:     c.QueryBool("matchAny"),
+			Text:         c.Query("text"),
+			SignedInUser: c.SignedInUser,
+		},
+		GroupBy:  annotations.CountGroupBy(c.Query("groupBy")),
+		Interval: c.QueryInt64("interval"),
+	}
+
+	if query.DashboardUID != "" {
+		dq := dashboards.GetDashboardQuery{UID: query.DashboardUID, OrgID: c.SignedInUser.GetOrgID()}
+		dqResult, err := hs.DashboardService.GetDashboard(c.Req.Context(), &dq)
+		if err != nil {
+			return response.Error(http.StatusBadRequest, "Invalid dashboard UID in annotation request", err)
+		}
+		query.DashboardID = dqResult.ID
+	}
+
+	result, err := hs.annotationsRepo.Count(c.Req.Context(), query)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to count annotations", err)
+	}
+
+	return response.JSON(http.StatusOK, result)
 }
 
 type AnnotationError struct {
@@ -777,6 +834,70 @@ type GetAnnotationsParams struct {
 	// in:query
 	// required:false
 	MatchAny bool `json:"matchAny"`
+	// Find annotations with text that contains all the words.
+	// in:query
+	// required:false
+	Text string `json:"text"`
+	// Find annotations after the cursor returned in the X-Grafana-Next-Cursor header of the previous page.
+	// in:query
+	// required:false
+	Cursor string `json:"cursor"`
+}
+
+// swagger:parameters getAnnotationCounts
+type GetAnnotationCountsParams struct {
+	// Group annotations by tag, dashboard or time.
+	// in:query
+	// required:true
+	// enum: tag,dashboard,time
+	GroupBy string `json:"groupBy"`
+	// Size of time buckets in milliseconds, required when grouping by time.
+	// in:query
+	// required:false
+	Interval int64 `json:"interval"`
+	// Count annotations created after specific epoch datetime in milliseconds.
+	// in:query
+	// required:false
+	From int64 `json:"from"`
+	// Count annotations created before specific epoch datetime in milliseconds.
+	// in:query
+	// required:false
+	To int64 `json:"to"`
+	// Count annotations created by specific user.
+	// in:query
+	// required:false
+	UserID int64 `json:"userId"`
+	// Count annotations for a specified alert.
+	// in:query
+	// required:false
+	AlertID int64 `json:"alertId"`
+	// Count annotations that are scoped to a specific dashboard
+	// in:query
+	// required:false
+	DashboardUID string `json:"dashboardUID"`
+	// Count annotations that are scoped to a specific panel
+	// in:query
+	// required:false
+	PanelID int64 `json:"panelId"`
+	// Count annotations with the tags.
+	// in:query
+	// required:false
+	// type: array
+	// collectionFormat: multi
+	Tags []string `json:"tags"`
+	// Count alerts or user created annotations
+	// in:query
+	// required:false
+	// enum: alert,annotation
+	Type string `json:"type"`
+	// Match any or all tags
+	// in:query
+	// required:false
+	MatchAny bool `json:"matchAny"`
+	// Count annotations with text that contains all the words.
+	// in:query
+	// required:false
+	Text string `json:"text"`
 }
 
 // swagger:parameters getAnnotationTags
@@ -840,6 +961,13 @@ type GetAnnotationsResponse struct {
 	Body []*annotations.ItemDTO `json:"body"`
 }
 
+// swagger:response getAnnotationCountsResponse
+type GetAnnotationCountsResponse struct {
+	// The response message
+	// in: body
+	Body annotations.CountResult `json:"body"`
+}
+
 // swagger:response getAnnotationByIDResponse
 type GetAnnotationByIDResponse struct {
 	// The response message
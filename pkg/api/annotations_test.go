@@ -106,6 +106,20 @@ func TestAPI_Annotations(t *testing.T) {
 			expectedCode: http.StatusForbidden,
 			permissions:  []accesscontrol.Permission{},
 		},
+		{
+			desc:         "should be able to count annotations with correct permission",
+			path:         "/api/annotations/counts?groupBy=tag",
+			method:       http.MethodGet,
+			expectedCode: http.StatusOK,
+			permissions:  []accesscontrol.Permission{{Action: accesscontrol.ActionAnnotationsRead}},
+		},
+		{
+			desc:         "should not be able to count annotations without correct permission",
+			path:         "/api/annotations/counts?groupBy=tag",
+			method:       http.MethodGet,
+			expectedCode: http.StatusForbidden,
+			permissions:  []accesscontrol.Permission{},
+		},
 		{
 			desc:         "should be able to update dashboard annotation with correct permission",
 			path:         "/api/annotations/2",
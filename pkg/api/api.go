@@ -364,6 +364,12 @@ func (hs *HTTPServer) registerRoutes() {
 			orgsRoute.Put("/quotas/:target", authorizeInOrg(ac.UseOrgFromContextParams, ac.EvalPermission(ac.ActionOrgsQuotasWrite)), routing.Wrap(hs.UpdateOrgQuota))
 		})
 
+		// team quotas
+		apiRoute.Group("/teams/:teamId/quotas", func(teamQuotasRoute routing.RouteRegister) {
+			teamQuotasRoute.Get("/", authorize(ac.EvalPermission(ac.ActionOrgsQuotasRead)), routing.Wrap(hs.GetTeamQuotas))
+			teamQuotasRoute.Put("/:target", authorize(ac.EvalPermission(ac.ActionOrgsQuotasWrite)), routing.Wrap(hs.UpdateTeamQuota))
+		}, requestmeta.SetOwner(requestmeta.TeamAuth))
+
 		// orgs (admin routes)
 		apiRoute.Get("/orgs/name/:name/", authorizeInOrg(ac.UseGlobalOrg, ac.EvalPermission(ac.ActionOrgsRead)), routing.Wrap(hs.GetOrgByName))
 
@@ -541,6 +547,7 @@ func (hs *HTTPServer) registerRoutes() {
 			annotationsRoute.Patch("/:annotationId", authorize(ac.EvalPermission(ac.ActionAnnotationsWrite, ac.ScopeAnnotationsID)), routing.Wrap(hs.PatchAnnotation))
 			annotationsRoute.Post("/graphite", authorize(ac.EvalPermission(ac.ActionAnnotationsCreate, ac.ScopeAnnotationsTypeOrganization)), routing.Wrap(hs.PostGraphiteAnnotation))
 			annotationsRoute.Get("/tags", authorize(ac.EvalPermission(ac.ActionAnnotationsRead)), routing.Wrap(hs.GetAnnotationTags))
+			annotationsRoute.Get("/counts", authorize(ac.EvalPermission(ac.ActionAnnotationsRead)), routing.Wrap(hs.GetAnnotationCounts))
 		})
 
 		apiRoute.Post("/frontend-metrics", routing.Wrap(hs.PostFrontendMetrics))
@@ -557,6 +564,23 @@ func (hs *HTTPServer) registerRoutes() {
 
 			// Some channels may have info
 			liveRoute.Get("/info/*", routing.Wrap(hs.Live.HandleInfoHTTP))
+
+			if hs.Features.IsEnabledGlobally(featuremgmt.FlagLivePipeline) {
+				// POST Live data to be processed according to channel rules.
+				liveRoute.Post("/pipeline/push/*", reqOrgAdmin, hs.LivePushGateway.HandlePipelinePush)
+				liveRoute.Post("/pipeline-convert-test", authorize(ac.EvalPermission(ac.ActionLiveChannelRulesRead)), routing.Wrap(hs.Live.HandlePipelineConvertTestHTTP))
+				liveRoute.Get("/pipeline-entities", authorize(ac.EvalPermission(ac.ActionLiveChannelRulesRead)), routing.Wrap(hs.Live.HandlePipelineEntitiesListHTTP))
+
+				liveRoute.Get("/channel-rules", authorize(ac.EvalPermission(ac.ActionLiveChannelRulesRead)), routing.Wrap(hs.Live.HandleChannelRulesListHTTP))
+				liveRoute.Post("/channel-rules", authorize(ac.EvalPermission(ac.ActionLiveChannelRulesWrite)), routing.Wrap(hs.Live.HandleChannelRulesPostHTTP))
+				liveRoute.Put("/channel-rules", authorize(ac.EvalPermission(ac.ActionLiveChannelRulesWrite)), routing.Wrap(hs.Live.HandleChannelRulesPutHTTP))
+				liveRoute.Delete("/channel-rules", authorize(ac.EvalPermission(ac.ActionLiveChannelRulesWrite)), routing.Wrap(hs.Live.HandleChannelRulesDeleteHTTP))
+
+				liveRoute.Get("/write-configs", authorize(ac.EvalPermission(ac.ActionLiveWriteConfigsRead)), routing.Wrap(hs.Live.HandleWriteConfigsListHTTP))
+				liveRoute.Post("/write-configs", authorize(ac.EvalPermission(ac.ActionLiveWriteConfigsWrite)), routing.Wrap(hs.Live.HandleWriteConfigsPostHTTP))
+				liveRoute.Put("/write-configs", authorize(ac.EvalPermission(ac.ActionLiveWriteConfigsWrite)), routing.Wrap(hs.Live.HandleWriteConfigsPutHTTP))
+				liveRoute.Delete("/write-configs", authorize(ac.EvalPermission(ac.ActionLiveWriteConfigsWrite)), routing.Wrap(hs.Live.HandleWriteConfigsDeleteHTTP))
+			}
 		}, requestmeta.SetSLOGroup(requestmeta.SLOGroupNone))
 
 		// short urls
@@ -582,6 +606,7 @@ func (hs *HTTPServer) registerRoutes() {
 		adminRoute.Post("/provisioning/plugins/reload", authorize(ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersPlugins)), routing.Wrap(hs.AdminProvisioningReloadPlugins))
 		adminRoute.Post("/provisioning/datasources/reload", authorize(ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersDatasources)), routing.Wrap(hs.AdminProvisioningReloadDatasources))
 		adminRoute.Post("/provisioning/alerting/reload", authorize(ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersAlertRules)), routing.Wrap(hs.AdminProvisioningReloadAlerting))
+		adminRoute.Post("/provisioning/annotations/reload", authorize(ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersAnnotations)), routing.Wrap(hs.AdminProvisioningReloadAnnotations))
 	}, reqSignedIn)
 
 	// Administering users
@@ -613,6 +638,7 @@ func (hs *HTTPServer) registerRoutes() {
 
 	// Snapshots
 	r.Post("/api/snapshots/", reqSnapshotPublicModeOrSignedIn, hs.getCreatedSnapshotHandler())
+	r.Post("/api/snapshots/render", reqSignedIn, hs.RenderDashboardSnapshot)
 	r.Get("/api/snapshot/shared-options/", reqSignedIn, hs.GetSharingOptions)
 	r.Get("/api/snapshots/:key", routing.Wrap(hs.GetDashboardSnapshot))
 	r.Get("/api/snapshots-delete/:deleteKey", reqSnapshotPublicModeOrSignedIn, routing.Wrap(hs.DeleteDashboardSnapshotByDeleteKey))
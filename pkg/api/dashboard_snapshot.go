@@ -10,6 +10,7 @@ import (
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
 	dashboardsnapshot "github.com/grafana/grafana/pkg/apis/dashboardsnapshot/v0alpha1"
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/apiserver/endpoints/request"
@@ -92,6 +93,95 @@ func (hs *HTTPServer) CreateDashboardSnapshot(c *contextmodel.ReqContext) {
 	}, cmd, hs.dashboardsnapshotsService)
 }
 
+// swagger:route POST /snapshots/render snapshots renderDashboardSnapshot
+//
+// Create a snapshot of a saved dashboard by running its queries on the server.
+//
+// The queries of the panels are run with the time range and template variables of the request, and their results are stored in the snapshot. This endpoint lets automation, such as scheduled jobs using a service account token, create snapshots without a browser.
+//
+// Responses:
+// 200: createDashboardSnapshotResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) RenderDashboardSnapshot(c *contextmodel.ReqContext) {
+	if !hs.Cfg.SnapshotEnabled {
+		c.JsonApiErr(http.StatusForbidden, "Dashboard Snapshots are disabled", nil)
+		return
+	}
+
+	cmd := dashboardsnapshots.RenderDashboardSnapshotCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		c.JsonApiErr(http.StatusBadRequest, "bad request data", err)
+		return
+	}
+	if cmd.DashboardUID == "" {
+		c.JsonApiErr(http.StatusBadRequest, "dashboardUid is required", nil)
+		return
+	}
+
+	// The dashboard is read on the server, so reading it is required even in snapshot public mode
+	scope := dashboards.ScopeDashboardsProvider.GetResourceScopeUID(cmd.DashboardUID)
+	evaluator := ac.EvalAll(ac.EvalPermission(dashboards.ActionDashboardsRead, scope), ac.EvalPermission(dashboards.ActionDashboardsWrite, scope))
+	if hs.Cfg.SnapshotPublicMode {
+		evaluator = ac.EvalPermission(dashboards.ActionDashboardsRead, scope)
+	}
+	if canSave, err := hs.AccessControl.Evaluate(c.Req.Context(), c.SignedInUser, evaluator); err != nil || !canSave {
+		c.JsonApiErr(http.StatusForbidden, "forbidden", err)
+		return
+	}
+
+	dash, err := hs.DashboardService.GetDashboard(c.Req.Context(), &dashboards.GetDashboardQuery{UID: cmd.DashboardUID, OrgID: c.SignedInUser.GetOrgID()})
+	if err != nil {
+		if errors.Is(err, dashboards.ErrDashboardNotFound) {
+			c.JsonApiErr(http.StatusNotFound, "Dashboard not found", err)
+			return
+		}
+		c.JsonApiErr(http.StatusInternalServerError, "Failed to get dashboard", err)
+		return
+	}
+
+	// the snapshot model is built from a copy, the dashboard can be cached
+	data, err := dash.Data.MarshalJSON()
+	if err != nil {
+		c.JsonApiErr(http.StatusInternalServerError, "Failed to read dashboard", err)
+		return
+	}
+	model, err := simplejson.NewJson(data)
+	if err != nil {
+		c.JsonApiErr(http.StatusInternalServerError, "Failed to read dashboard", err)
+		return
+	}
+	model.Set("uid", dash.UID)
+
+	snapshotModel, err := dashboardsnapshots.RenderDashboard(c.Req.Context(), hs.queryDataService, hs.LibraryElementService, c.SignedInUser, model, cmd)
+	if err != nil {
+		c.WriteErrOrFallback(http.StatusInternalServerError, "Failed to render dashboard snapshot", err)
+		return
+	}
+
+	name := cmd.Name
+	if name == "" {
+		name = dash.Title
+	}
+
+	dashboardsnapshots.CreateDashboardSnapshot(c, dashboardsnapshot.SnapshotSharingOptions{
+		SnapshotsEnabled:     hs.Cfg.SnapshotEnabled,
+		ExternalEnabled:      hs.Cfg.ExternalEnabled,
+		ExternalSnapshotName: hs.Cfg.ExternalSnapshotName,
+		ExternalSnapshotURL:  hs.Cfg.ExternalSnapshotUrl,
+	}, dashboardsnapshots.CreateDashboardSnapshotCommand{
+		DashboardCreateCommand: dashboardsnapshot.DashboardCreateCommand{
+			Name:      name,
+			Dashboard: snapshotModel,
+			Expires:   cmd.Expires,
+			External:  cmd.External,
+		},
+	}, hs.dashboardsnapshotsService)
+}
+
 // GET /api/snapshots/:key
 // swagger:route GET /snapshots/{key} snapshots getDashboardSnapshot
 //
@@ -314,6 +404,13 @@ type CreateSnapshotParams struct {
 	Body dashboardsnapshots.CreateDashboardSnapshotCommand `json:"body"`
 }
 
+// swagger:parameters renderDashboardSnapshot
+type RenderSnapshotParams struct {
+	// in:body
+	// required:true
+	Body dashboardsnapshots.RenderDashboardSnapshotCommand `json:"body"`
+}
+
 // swagger:parameters searchDashboardSnapshots
 type GetSnapshotsParams struct {
 	// Search Query
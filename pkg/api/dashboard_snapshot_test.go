@@ -9,20 +9,25 @@ import (
 	"testing"
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/db/dbtest"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
 	"github.com/grafana/grafana/pkg/services/authz/zanzana"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/guardian"
 	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/query"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web/webtest"
@@ -125,6 +130,102 @@ func TestHTTPServer_DeleteDashboardSnapshot(t *testing.T) {
 	})
 }
 
+func TestHTTPServer_RenderDashboardSnapshot(t *testing.T) {
+	setup := func(t *testing.T, snapshotSvc dashboardsnapshots.Service) *webtest.Server {
+		t.Helper()
+
+		dashboardSvc := dashboards.NewFakeDashboardService(t)
+		dashboardSvc.On("GetDashboard", mock.Anything, mock.MatchedBy(func(q *dashboards.GetDashboardQuery) bool {
+			return q.UID == "dash"
+		})).Return(&dashboards.Dashboard{UID: "dash", Title: "Dashboard", Data: simplejson.NewFromAny(map[string]any{
+			"panels": []any{map[string]any{"id": 1, "datasource": map[string]any{"uid": "prom"}, "targets": []any{map[string]any{"refId": "A"}}}},
+		})}, nil).Maybe()
+		dashboardSvc.On("GetDashboard", mock.Anything, mock.Anything).Return(nil, dashboards.ErrDashboardNotFound).Maybe()
+
+		queryService := &query.FakeQueryService{}
+		queryService.On("QueryData", mock.Anything, mock.Anything, false, mock.Anything).Return(&backend.QueryDataResponse{
+			Responses: backend.Responses{"A": {Frames: data.Frames{data.NewFrame("A", data.NewField("value", nil, []float64{1}))}}},
+		}, nil)
+
+		server := SetupAPITestServer(t, func(hs *HTTPServer) {
+			cfg := setting.NewCfg()
+			cfg.SnapshotEnabled = true
+			hs.Cfg = cfg
+			hs.dashboardsnapshotsService = snapshotSvc
+			hs.DashboardService = dashboardSvc
+			hs.queryDataService = queryService
+			hs.AccessControl = acimpl.ProvideAccessControl(featuremgmt.WithFeatures(), zanzana.NewNoopClient())
+		})
+		// the snapshot is created with the requester of the request context
+		server.Mux.Use(func(c *contextmodel.ReqContext) {
+			c.Req = c.Req.WithContext(identity.WithRequester(c.Req.Context(), c.SignedInUser))
+		})
+		return server
+	}
+
+	allowedUser := userWithPermissions(1, []accesscontrol.Permission{
+		{Action: dashboards.ActionDashboardsRead, Scope: "dashboards:uid:dash"},
+		{Action: dashboards.ActionDashboardsWrite, Scope: "dashboards:uid:dash"},
+		{Action: dashboards.ActionDashboardsRead, Scope: "dashboards:uid:other"},
+		{Action: dashboards.ActionDashboardsWrite, Scope: "dashboards:uid:other"},
+	})
+
+	request := func(server *webtest.Server, body string, user *user.SignedInUser) *http.Request {
+		req := server.NewPostRequest("/api/snapshots/render", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		return webtest.RequestWithSignedInUser(req, user)
+	}
+
+	t.Run("User should be able to render a snapshot of a dashboard", func(t *testing.T) {
+		snapshotSvc := dashboardsnapshots.NewMockService(t)
+		snapshotSvc.On("ValidateDashboardExists", mock.Anything, int64(1), "dash").Return(nil)
+		var created *dashboardsnapshots.CreateDashboardSnapshotCommand
+		snapshotSvc.On("CreateDashboardSnapshot", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			created = args.Get(1).(*dashboardsnapshots.CreateDashboardSnapshotCommand)
+		}).Return(&dashboardsnapshots.DashboardSnapshot{Key: "key", DeleteKey: "deleteKey"}, nil)
+
+		server := setup(t, snapshotSvc)
+		res, err := server.Send(request(server, `{"dashboardUid": "dash", "expires": 3600, "time": {"from": "now-1h", "to": "now"}}`, allowedUser))
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+		require.NotNil(t, created)
+		assert.Equal(t, "Dashboard", created.Name)
+		assert.Equal(t, int64(3600), created.Expires)
+		raw, err := created.Dashboard.MarshalJSON()
+		require.NoError(t, err)
+		assert.Contains(t, string(raw), `"snapshotData":[{"fields":[{"config":{},"name":"value","type":"number","values":[1]}]`)
+	})
+
+	t.Run("User should not be able to render a snapshot without permissions", func(t *testing.T) {
+		server := setup(t, dashboardsnapshots.NewMockService(t))
+		res, err := server.Send(request(server, `{"dashboardUid": "dash"}`, userWithPermissions(1, []accesscontrol.Permission{{Action: dashboards.ActionDashboardsRead, Scope: "dashboards:uid:dash"}})))
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("Rendering a snapshot of a dashboard that does not exist should return 404", func(t *testing.T) {
+		server := setup(t, dashboardsnapshots.NewMockService(t))
+		res, err := server.Send(request(server, `{"dashboardUid": "other"}`, allowedUser))
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("Rendering a snapshot with an unknown variable should return 400", func(t *testing.T) {
+		server := setup(t, dashboardsnapshots.NewMockService(t))
+		res, err := server.Send(request(server, `{"dashboardUid": "dash", "variables": {"env": "prod"}}`, allowedUser))
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+}
+
 func TestDashboardSnapshotAPIEndpoint_singleSnapshot(t *testing.T) {
 	setupRemoteServer := func(fn func(http.ResponseWriter, *http.Request)) *httptest.Server {
 		s := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
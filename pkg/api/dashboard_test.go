@@ -1081,3 +1081,8 @@ func (l *mockLibraryElementService) DisconnectElementsFromDashboard(c context.Co
 func (l *mockLibraryElementService) DeleteLibraryElementsInFolder(c context.Context, signedInUser identity.Requester, folderUID string) error {
 	return nil
 }
+
+// DeleteExpiredVersions deletes the versions of elements that exceed the number of versions to keep.
+func (l *mockLibraryElementService) DeleteExpiredVersions(c context.Context, cmd *model.DeleteExpiredVersionsCommand) error {
+	return nil
+}
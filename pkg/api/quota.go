@@ -1,12 +1,14 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/services/team"
 	"github.com/grafana/grafana/pkg/web"
 )
 
@@ -160,6 +162,85 @@ func (hs *HTTPServer) UpdateUserQuota(c *contextmodel.ReqContext) response.Respo
 	return response.Success("Organization quota updated")
 }
 
+// swagger:route GET /teams/{team_id}/quotas teams getTeamQuota
+//
+// Fetch team quota.
+//
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `orgs.quotas:read`.
+//
+// Responses:
+// 200: getQuotaResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetTeamQuotas(c *contextmodel.ReqContext) response.Response {
+	teamID, rsp := hs.getTeamIDFromParams(c)
+	if rsp != nil {
+		return rsp
+	}
+
+	q, err := hs.QuotaService.GetQuotasByScope(c.Req.Context(), quota.TeamScope, teamID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get team quotas", err)
+	}
+
+	return response.JSON(http.StatusOK, q)
+}
+
+// swagger:route PUT /teams/{team_id}/quotas/{quota_target} teams updateTeamQuota
+//
+// Update team quota.
+//
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `orgs.quotas:write`.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) UpdateTeamQuota(c *contextmodel.ReqContext) response.Response {
+	cmd := quota.UpdateQuotaCmd{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Err(quota.ErrBadRequest.Errorf("bad request data: %w", err))
+	}
+
+	teamID, rsp := hs.getTeamIDFromParams(c)
+	if rsp != nil {
+		return rsp
+	}
+	cmd.TeamID = teamID
+	cmd.OrgID = c.SignedInUser.GetOrgID()
+	cmd.Target = web.Params(c.Req)[":target"]
+
+	if err := hs.QuotaService.Update(c.Req.Context(), &cmd); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to update team quotas", err)
+	}
+	return response.Success("Team quota updated")
+}
+
+// getTeamIDFromParams returns the ID of the team in the request params if the team exists in the current organization
+func (hs *HTTPServer) getTeamIDFromParams(c *contextmodel.ReqContext) (int64, response.Response) {
+	teamID, err := strconv.ParseInt(web.Params(c.Req)[":teamId"], 10, 64)
+	if err != nil {
+		return 0, response.Err(quota.ErrBadRequest.Errorf("teamId is invalid: %w", err))
+	}
+
+	if _, err := hs.TeamService.GetTeamByID(c.Req.Context(), &team.GetTeamByIDQuery{
+		OrgID:        c.SignedInUser.GetOrgID(),
+		ID:           teamID,
+		SignedInUser: c.SignedInUser,
+	}); err != nil {
+		if errors.Is(err, team.ErrTeamNotFound) {
+			return 0, response.Error(http.StatusNotFound, "Team not found", err)
+		}
+		return 0, response.Error(http.StatusInternalServerError, "Failed to get team", err)
+	}
+
+	return teamID, nil
+}
+
 // swagger:parameters updateUserQuota
 type UpdateUserQuotaParams struct {
 	// in:body
@@ -200,6 +281,26 @@ type UpdateOrgQuotaParam struct {
 	OrgID int64 `json:"org_id"`
 }
 
+// swagger:parameters getTeamQuota
+type GetTeamQuotaParams struct {
+	// in:path
+	// required:true
+	TeamID int64 `json:"team_id"`
+}
+
+// swagger:parameters updateTeamQuota
+type UpdateTeamQuotaParams struct {
+	// in:body
+	// required:true
+	Body quota.UpdateQuotaCmd `json:"body"`
+	// in:path
+	// required:true
+	QuotaTarget string `json:"quota_target"`
+	// in:path
+	// required:true
+	TeamID int64 `json:"team_id"`
+}
+
 // swagger:response getQuotaResponse
 type GetQuotaResponseResponse struct {
 	// in:body
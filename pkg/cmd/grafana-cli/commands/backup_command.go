@@ -0,0 +1,117 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/fatih/color"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/server"
+	"github.com/grafana/grafana/pkg/services/backup"
+	"github.com/grafana/grafana/pkg/services/backup/backupimpl"
+)
+
+func newBackupService(runner server.Runner) *backupimpl.Service {
+	serverLock := serverlock.ProvideService(runner.SQLStore, tracing.NewNoopTracerService())
+	return backupimpl.NewService(runner.Cfg, runner.SQLStore, runner.SecretsService, serverLock)
+}
+
+func createBackupCommand(c utils.CommandLine, runner server.Runner) (err error) {
+	output := c.String("output")
+	if output == "" {
+		output = backupimpl.ArchiveFilename(time.Now())
+	}
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning since the path is provided by the user running the command
+	f, err := os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create the backup file: %w", err)
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			_ = os.Remove(output)
+		}
+	}()
+
+	manifest, err := newBackupService(runner).Create(context.Background(), f)
+	if err != nil {
+		return err
+	}
+
+	logBackupManifest(manifest)
+	logger.Infof("Backup written to %s %s\n", output, color.GreenString("✔"))
+	return nil
+}
+
+func verifyBackupCommand(c utils.CommandLine, runner server.Runner) error {
+	f, err := openBackupArchive(c)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	manifest, err := newBackupService(runner).Verify(context.Background(), f)
+	if manifest != nil {
+		logBackupManifest(manifest)
+	}
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Backup can be restored %s\n", color.GreenString("✔"))
+	return nil
+}
+
+func restoreBackupCommand(c utils.CommandLine, runner server.Runner) error {
+	f, err := openBackupArchive(c)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	manifest, err := newBackupService(runner).Restore(context.Background(), f)
+	if manifest != nil {
+		logBackupManifest(manifest)
+	}
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Backup restored %s\n", color.GreenString("✔"))
+	return nil
+}
+
+func openBackupArchive(c utils.CommandLine) (*os.File, error) {
+	path := c.Args().First()
+	if path == "" {
+		return nil, errors.New("missing path of the backup archive")
+	}
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning since the path is provided by the user running the command
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the backup archive: %w", err)
+	}
+	return f, nil
+}
+
+func logBackupManifest(manifest *backup.Manifest) {
+	logger.Infof("Grafana version: %s (%s)\n", manifest.Version, manifest.Commit)
+	logger.Infof("Created at: %s\n", manifest.CreatedAt.Format(time.RFC3339))
+	logger.Infof("Database type: %s\n", manifest.DatabaseType)
+	for _, section := range manifest.Sections {
+		logger.Infof("Section %s: %d files\n", section.UID, len(section.Files))
+	}
+}
@@ -0,0 +1,251 @@
+package datamigrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/server"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrations"
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+	"github.com/grafana/grafana/pkg/services/sqlstore/tabledata"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// migrationLogTable is not copied: the target database gets its own migration log when
+// the migrations are run on it.
+const migrationLogTable = "migration_log"
+
+// MigrateDatabase copies the Grafana database to another database, for example to move an
+// installation from SQLite to MySQL or PostgreSQL. The database configured for the command is
+// the source; the target is configured with the --target-config file and/or the --target-url
+// flag. The target database must be empty: the migrations are run on it before the tables are
+// copied. Grafana must be stopped while the command runs.
+func MigrateDatabase(c utils.CommandLine, runner server.Runner) error {
+	targetCfg, err := migrationTargetCfg(runner.Cfg, c.String("target-config"), c.String("target-url"))
+	if err != nil {
+		return err
+	}
+
+	tracer := tracing.NewNoopTracerService()
+	target, err := sqlstore.NewSQLStoreWithoutSideEffects(targetCfg, runner.Features, bus.ProvideBus(tracer), tracer)
+	if err != nil {
+		return fmt.Errorf("failed to connect to the target database: %w", err)
+	}
+
+	source := runner.SQLStore
+	if source.GetEngine().DriverName() == target.GetEngine().DriverName() &&
+		source.GetEngine().DataSourceName() == target.GetEngine().DataSourceName() {
+		return errors.New("the source and the target are the same database")
+	}
+
+	logger.Infof("Migrating the %s database to the %s database\n", source.GetDialect().DriverName(), target.GetDialect().DriverName())
+
+	migration := &databaseMigration{source: source, target: target, cfg: targetCfg, features: runner.Features}
+	if err := migration.migrateTarget(); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	tables, err := migration.tables()
+	if err != nil {
+		return err
+	}
+	if err := migration.copyTables(ctx, tables); err != nil {
+		return err
+	}
+
+	if c.Bool("skip-verify") {
+		logger.Warn("Warning: the copied data has not been verified")
+		return nil
+	}
+	return migration.verify(ctx, tables)
+}
+
+// migrationTargetCfg loads the configuration of the target database. The configuration file only
+// needs a [database] section; settings that are not in it come from the defaults.
+func migrationTargetCfg(cfg *setting.Cfg, configFile, url string) (*setting.Cfg, error) {
+	if configFile == "" && url == "" {
+		return nil, errors.New("the target database must be configured with --target-config or --target-url")
+	}
+
+	var args []string
+	if url != "" {
+		args = append(args, "cfg:database.url="+url)
+	}
+	targetCfg, err := setting.NewCfgFromArgs(setting.CommandLineArgs{
+		Config:   configFile,
+		HomePath: cfg.HomePath,
+		Args:     args,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the configuration of the target database: %w", err)
+	}
+	return targetCfg, nil
+}
+
+type databaseMigration struct {
+	source   db.DB
+	target   db.DB
+	cfg      *setting.Cfg
+	features featuremgmt.FeatureToggles
+}
+
+// migrateTarget runs the migrations on the target database, which must not have been migrated
+// before, and checks that the source database does not have migrations the target lacks. Such
+// migrations come from a newer version of Grafana, whose tables this version cannot create.
+func (m *databaseMigration) migrateTarget() error {
+	sourceLog, err := migrator.NewMigrator(m.source.GetEngine(), m.cfg).GetMigrationLog()
+	if err != nil {
+		return fmt.Errorf("failed to read the migration log of the source database: %w", err)
+	}
+
+	targetMigrator := migrator.NewMigrator(m.target.GetEngine(), m.cfg)
+	targetLog, err := targetMigrator.GetMigrationLog()
+	if err != nil {
+		return fmt.Errorf("failed to read the migration log of the target database: %w", err)
+	}
+	if len(targetLog) > 0 {
+		return errors.New("the target database already contains a Grafana database, it must be empty")
+	}
+
+	migrations.ProvideOSSMigrations(m.features).AddMigration(targetMigrator)
+	var unknown []string
+	known := make(map[string]struct{})
+	for _, id := range targetMigrator.GetMigrationIDs(false) {
+		known[id] = struct{}{}
+	}
+	for id := range sourceLog {
+		if _, ok := known[id]; !ok {
+			unknown = append(unknown, id)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("the source database has migrations unknown to this version of Grafana, use the version that last ran on it: %s",
+			strings.Join(unknown, ", "))
+	}
+
+	logger.Info("Running the migrations on the target database\n")
+	if err := targetMigrator.Start(false, 0); err != nil {
+		return fmt.Errorf("failed to run the migrations on the target database: %w", err)
+	}
+	return nil
+}
+
+// tables returns the tables to copy in dependency order. Tables that only exist in the source
+// database are skipped with a warning, as well as columns that only exist in the source table.
+// The values are converted to the kinds of the columns of the target tables.
+func (m *databaseMigration) tables() ([]*tabledata.Table, error) {
+	sourceTables, err := tabledata.ReadTables(m.source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the tables of the source database: %w", err)
+	}
+	targetTables, err := tabledata.ReadTables(m.target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the tables of the target database: %w", err)
+	}
+
+	sourceColumns := make(map[string]map[string]struct{}, len(sourceTables))
+	for _, table := range sourceTables {
+		columns := make(map[string]struct{}, len(table.Columns))
+		for _, column := range table.Columns {
+			columns[column] = struct{}{}
+		}
+		sourceColumns[table.Name] = columns
+	}
+
+	tables := make([]*tabledata.Table, 0, len(targetTables))
+	targetNames := make(map[string]struct{}, len(targetTables))
+	for _, table := range targetTables {
+		targetNames[table.Name] = struct{}{}
+		columns, ok := sourceColumns[table.Name]
+		if !ok || table.Name == migrationLogTable {
+			continue
+		}
+
+		t := &tabledata.Table{Name: table.Name, Sequences: table.Sequences}
+		for i, column := range table.Columns {
+			if _, ok := columns[column]; ok {
+				t.Columns = append(t.Columns, column)
+				t.Kinds = append(t.Kinds, table.Kinds[i])
+			}
+		}
+		tables = append(tables, t)
+	}
+
+	for _, table := range sourceTables {
+		if _, ok := targetNames[table.Name]; !ok {
+			logger.Warnf("Warning: skipping table %s that does not exist in the target database\n", table.Name)
+		}
+	}
+
+	return tabledata.SortTables(tables), nil
+}
+
+// copyTables copies the rows of the tables, one transaction per table. Rows inserted in the
+// target tables by the migrations, such as the default dashboard permissions, are replaced by
+// the rows of the source tables. The auto-increment counters are moved past the copied rows.
+func (m *databaseMigration) copyTables(ctx context.Context, tables []*tabledata.Table) error {
+	dialect := m.target.GetDialect()
+	for _, t := range tables {
+		var count int64
+		err := m.target.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+			count = 0
+			if err := tabledata.Truncate(sess, dialect, t); err != nil {
+				return err
+			}
+
+			err := tabledata.Scan(ctx, m.source, t, func(values []any) error {
+				count++
+				return tabledata.Insert(sess, dialect, t, values)
+			})
+			if err != nil {
+				return err
+			}
+			return tabledata.ResetSequences(sess, dialect, t)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to copy table %s: %w", t.Name, err)
+		}
+		logger.Infof("%s Copied %d rows of table %s\n", color.GreenString("✔"), count, t.Name)
+	}
+	return nil
+}
+
+// verify compares the number of rows and the checksum of every table in both databases.
+func (m *databaseMigration) verify(ctx context.Context, tables []*tabledata.Table) error {
+	var mismatched []string
+	for _, t := range tables {
+		source, err := tabledata.ChecksumTable(ctx, m.source, t)
+		if err != nil {
+			return fmt.Errorf("failed to verify table %s in the source database: %w", t.Name, err)
+		}
+		target, err := tabledata.ChecksumTable(ctx, m.target, t)
+		if err != nil {
+			return fmt.Errorf("failed to verify table %s in the target database: %w", t.Name, err)
+		}
+		if source != target {
+			logger.Errorf("%s Table %s has %d rows with checksum %x in the source database and %d rows with checksum %x in the target database\n",
+				color.RedString("✗"), t.Name, source.Rows, source.Sum, target.Rows, target.Sum)
+			mismatched = append(mismatched, t.Name)
+		}
+	}
+
+	if len(mismatched) > 0 {
+		return fmt.Errorf("the data of %d tables differs between the databases: %s", len(mismatched), strings.Join(mismatched, ", "))
+	}
+	logger.Infof("%s Verified the row counts and checksums of %d tables\n", color.GreenString("✔"), len(tables))
+	return nil
+}
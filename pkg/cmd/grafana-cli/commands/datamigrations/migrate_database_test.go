@@ -0,0 +1,123 @@
+package datamigrations
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestIntegrationMigrateDatabase(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	if !db.IsTestDbSQLite() {
+		t.Skip("the target database of the test is SQLite")
+	}
+
+	source := db.InitTestDB(t)
+	now := time.Date(2024, 3, 1, 12, 30, 45, 0, time.UTC)
+	err := source.WithDbSession(context.Background(), func(sess *db.Session) error {
+		for _, sql := range [][]any{
+			{"INSERT INTO org (id, version, name, created, updated) VALUES (?, ?, ?, ?, ?)", 1, 0, "Main Org.", now, now},
+			{"INSERT INTO org (id, version, name, created, updated) VALUES (?, ?, ?, ?, ?)", 7, 0, "Other Org.", now, now},
+			{"INSERT INTO \"user\" (id, version, login, email, org_id, is_admin, is_disabled, created, updated, last_seen_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+				3, 0, "admin", "admin@localhost", 1, true, false, now, now, now},
+			{"INSERT INTO dashboard (id, version, slug, title, data, org_id, created, updated, uid, is_folder) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+				12, 2, "dash", "Dash", `{"title":"Dash"}`, 7, now, now, "dash-uid", false},
+		} {
+			if _, err := sess.Exec(sql...); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	target, cfg := newTargetStore(t)
+	migration := &databaseMigration{source: source, target: target, cfg: cfg, features: featuremgmt.WithFeatures()}
+	require.NoError(t, migration.migrateTarget())
+	tables, err := migration.tables()
+	require.NoError(t, err)
+	require.NoError(t, migration.copyTables(context.Background(), tables))
+	require.NoError(t, migration.verify(context.Background(), tables))
+
+	t.Run("copies the rows", func(t *testing.T) {
+		var orgs []struct {
+			ID   int64  `xorm:"id"`
+			Name string `xorm:"name"`
+		}
+		require.NoError(t, target.GetEngine().Table("org").Asc("id").Find(&orgs))
+		require.Len(t, orgs, 2)
+		assert.Equal(t, int64(7), orgs[1].ID)
+		assert.Equal(t, "Other Org.", orgs[1].Name)
+
+		var users []struct {
+			IsAdmin bool `xorm:"is_admin"`
+		}
+		require.NoError(t, target.GetEngine().Table("user").Where("id = ?", 3).Find(&users))
+		require.Len(t, users, 1)
+		assert.True(t, users[0].IsAdmin)
+
+		var dashboards []struct {
+			Created time.Time `xorm:"created"`
+		}
+		require.NoError(t, target.GetEngine().Table("dashboard").Where("uid = ?", "dash-uid").Find(&dashboards))
+		require.Len(t, dashboards, 1)
+		assert.True(t, now.Equal(dashboards[0].Created))
+	})
+
+	t.Run("replaces the rows inserted by the migrations", func(t *testing.T) {
+		sourceCount, err := source.GetEngine().Table("dashboard_acl").Count()
+		require.NoError(t, err)
+		targetCount, err := target.GetEngine().Table("dashboard_acl").Count()
+		require.NoError(t, err)
+		assert.Equal(t, sourceCount, targetCount)
+	})
+
+	t.Run("resets the sequences", func(t *testing.T) {
+		_, err := target.GetEngine().Exec("INSERT INTO org (version, name, created, updated) VALUES (?, ?, ?, ?)", 0, "New Org.", now, now)
+		require.NoError(t, err)
+		var orgs []struct {
+			ID int64 `xorm:"id"`
+		}
+		require.NoError(t, target.GetEngine().Table("org").Where("name = ?", "New Org.").Find(&orgs))
+		require.Len(t, orgs, 1)
+		assert.Equal(t, int64(8), orgs[0].ID)
+	})
+
+	t.Run("verify detects changed rows", func(t *testing.T) {
+		_, err := target.GetEngine().Exec("UPDATE dashboard SET title = ? WHERE uid = ?", "Changed", "dash-uid")
+		require.NoError(t, err)
+		require.ErrorContains(t, migration.verify(context.Background(), tables), "dashboard")
+	})
+
+	t.Run("refuses a target database that is not empty", func(t *testing.T) {
+		require.ErrorContains(t, migration.migrateTarget(), "must be empty")
+	})
+}
+
+func newTargetStore(t *testing.T) (*sqlstore.SQLStore, *setting.Cfg) {
+	t.Helper()
+
+	cfg := setting.NewCfg()
+	cfg.DataPath = t.TempDir()
+	sec := cfg.Raw.Section("database")
+	sec.Key("type").SetValue("sqlite3")
+	sec.Key("path").SetValue(filepath.Join(cfg.DataPath, "target.db"))
+
+	tracer := tracing.NewNoopTracerService()
+	store, err := sqlstore.NewSQLStoreWithoutSideEffects(cfg, featuremgmt.WithFeatures(), bus.ProvideBus(tracer), tracer)
+	require.NoError(t, err)
+	return store, cfg
+}
@@ -0,0 +1,147 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+const (
+	defaultAnomalyDeviations = 3.0
+	defaultHoltWintersAlpha  = 0.5
+	defaultHoltWintersBeta   = 0.1
+	defaultHoltWintersGamma  = 0.1
+)
+
+// AnomalyCommand is an expression command that detects anomalies in time series locally,
+// without sending the data to the Machine Learning back-end.
+type AnomalyCommand struct {
+	VarToDetect string
+	Options     mathexp.AnomalyOptions
+	Output      mathexp.AnomalyOutput
+	LatestOnly  bool
+	refID       string
+}
+
+// NewAnomalyCommand creates a new AnomalyCommand.
+// If latestOnly is true, the command returns the output at the latest point of each series as a number,
+// which allows the command to be used as the condition of an alert rule.
+func NewAnomalyCommand(refID, varToDetect string, opts mathexp.AnomalyOptions, output mathexp.AnomalyOutput, latestOnly bool) (*AnomalyCommand, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	switch output {
+	case mathexp.AnomalyOutputIsAnomaly, mathexp.AnomalyOutputScore, mathexp.AnomalyOutputExpected,
+		mathexp.AnomalyOutputLower, mathexp.AnomalyOutputUpper:
+	default:
+		return nil, fmt.Errorf("unsupported anomaly detection output '%s'", output)
+	}
+	return &AnomalyCommand{
+		VarToDetect: varToDetect,
+		Options:     opts,
+		Output:      output,
+		LatestOnly:  latestOnly,
+		refID:       refID,
+	}, nil
+}
+
+// newAnomalyCommandFromQuery applies the defaults of the optional fields of the query and creates a new AnomalyCommand.
+func newAnomalyCommandFromQuery(refID, varToDetect string, q *AnomalyQuery) (*AnomalyCommand, error) {
+	opts := mathexp.AnomalyOptions{
+		Method:       q.Method,
+		Deviations:   defaultAnomalyDeviations,
+		SeasonLength: q.SeasonLength,
+		Alpha:        defaultHoltWintersAlpha,
+		Beta:         defaultHoltWintersBeta,
+		Gamma:        defaultHoltWintersGamma,
+	}
+	if q.Window != "" {
+		window, err := gtime.ParseDuration(q.Window)
+		if err != nil {
+			return nil, fmt.Errorf(`failed to parse anomaly "window" duration field %q: %w`, q.Window, err)
+		}
+		opts.Window = window
+	}
+	if q.Deviations != nil {
+		opts.Deviations = *q.Deviations
+	}
+	if q.Alpha != nil {
+		opts.Alpha = *q.Alpha
+	}
+	if q.Beta != nil {
+		opts.Beta = *q.Beta
+	}
+	if q.Gamma != nil {
+		opts.Gamma = *q.Gamma
+	}
+	output := q.Output
+	if output == "" {
+		output = mathexp.AnomalyOutputIsAnomaly
+	}
+	return NewAnomalyCommand(refID, varToDetect, opts, output, q.LatestOnly)
+}
+
+// UnmarshalAnomalyCommand creates an AnomalyCommand from Grafana's frontend query.
+func UnmarshalAnomalyCommand(rn *rawNode) (*AnomalyCommand, error) {
+	q := &AnomalyQuery{}
+	if err := json.Unmarshal(rn.QueryRaw, q); err != nil {
+		return nil, fmt.Errorf("failed to parse the anomaly command: %w", err)
+	}
+	varToDetect := strings.TrimPrefix(q.Expression, "$")
+	if varToDetect == "" {
+		return nil, fmt.Errorf("no variable specified to reference for refId %v", rn.RefID)
+	}
+	return newAnomalyCommandFromQuery(rn.RefID, varToDetect, q)
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (ac *AnomalyCommand) NeedsVars() []string {
+	return []string{ac.VarToDetect}
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute.
+func (ac *AnomalyCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.Vars, tracer tracing.Tracer) (mathexp.Results, error) {
+	_, span := tracer.Start(ctx, "SSE.ExecuteAnomaly")
+	defer span.End()
+	newRes := mathexp.Results{}
+	for _, val := range vars[ac.VarToDetect].Values {
+		if val == nil {
+			continue
+		}
+		switch v := val.(type) {
+		case mathexp.Series:
+			s, err := v.DetectAnomalies(ac.refID, ac.Options, ac.Output)
+			if err != nil {
+				return newRes, err
+			}
+			if !ac.LatestOnly {
+				newRes.Values = append(newRes.Values, s)
+				continue
+			}
+			n := mathexp.NewNumber(ac.refID, s.GetLabels())
+			if s.Len() > 0 {
+				n.SetValue(s.GetValue(s.Len() - 1))
+			}
+			newRes.Values = append(newRes.Values, n)
+		case mathexp.NoData:
+			newRes.Values = append(newRes.Values, v.New())
+			return newRes, nil
+		default:
+			return newRes, fmt.Errorf("can only detect anomalies in type series, got type %v", val.Type())
+		}
+	}
+	return newRes, nil
+}
+
+func (ac *AnomalyCommand) Type() string {
+	return TypeAnomaly.String()
+}
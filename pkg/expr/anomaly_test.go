@@ -0,0 +1,160 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestUnmarshalAnomalyCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected *AnomalyCommand
+		error    string
+	}{
+		{
+			name:  "applies defaults",
+			query: `{"expression": "$A", "type": "anomaly", "method": "zscore", "window": "1h"}`,
+			expected: &AnomalyCommand{
+				VarToDetect: "A",
+				Options: mathexp.AnomalyOptions{
+					Method:     mathexp.AnomalyMethodZScore,
+					Window:     time.Hour,
+					Deviations: 3,
+					Alpha:      0.5,
+					Beta:       0.1,
+					Gamma:      0.1,
+				},
+				Output: mathexp.AnomalyOutputIsAnomaly,
+				refID:  "B",
+			},
+		},
+		{
+			name: "reads all fields",
+			query: `{"expression": "A", "type": "anomaly", "method": "holt_winters", "output": "expected", "deviations": 2,
+				"seasonLength": 24, "alpha": 0.3, "beta": 0.2, "gamma": 0.4, "latestOnly": true}`,
+			expected: &AnomalyCommand{
+				VarToDetect: "A",
+				Options: mathexp.AnomalyOptions{
+					Method:       mathexp.AnomalyMethodHoltWinters,
+					Deviations:   2,
+					SeasonLength: 24,
+					Alpha:        0.3,
+					Beta:         0.2,
+					Gamma:        0.4,
+				},
+				Output:     mathexp.AnomalyOutputExpected,
+				LatestOnly: true,
+				refID:      "B",
+			},
+		},
+		{
+			name:  "error when expression is missing",
+			query: `{"type": "anomaly", "method": "zscore", "window": "1h"}`,
+			error: "no variable specified",
+		},
+		{
+			name:  "error when window is invalid",
+			query: `{"expression": "$A", "type": "anomaly", "method": "mad", "window": "foo"}`,
+			error: `failed to parse anomaly "window" duration field`,
+		},
+		{
+			name:  "error when window is missing",
+			query: `{"expression": "$A", "type": "anomaly", "method": "mad"}`,
+			error: "requires a positive window",
+		},
+		{
+			name:  "error when output is not supported",
+			query: `{"expression": "$A", "type": "anomaly", "method": "zscore", "window": "1h", "output": "foo"}`,
+			error: "unsupported anomaly detection output",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var qmap = make(map[string]any)
+			require.NoError(t, json.Unmarshal([]byte(tt.query), &qmap))
+
+			cmd, err := UnmarshalAnomalyCommand(&rawNode{
+				RefID:    "B",
+				Query:    qmap,
+				QueryRaw: []byte(tt.query),
+			})
+			if tt.error != "" {
+				require.ErrorContains(t, err, tt.error)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, cmd)
+			require.Equal(t, []string{"A"}, cmd.NeedsVars())
+		})
+	}
+}
+
+func TestAnomalyCommand_Execute(t *testing.T) {
+	opts := mathexp.AnomalyOptions{Method: mathexp.AnomalyMethodZScore, Window: time.Hour, Deviations: 3}
+	labels := data.Labels{"host": "a"}
+	input := newSeriesWithLabels(labels,
+		util.Pointer(10.0), util.Pointer(10.0), util.Pointer(12.0), util.Pointer(10.0), util.Pointer(50.0))
+
+	t.Run("returns a series", func(t *testing.T) {
+		cmd, err := NewAnomalyCommand("B", "A", opts, mathexp.AnomalyOutputIsAnomaly, false)
+		require.NoError(t, err)
+
+		result, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+			"A": newResults(input),
+		}, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Len(t, result.Values, 1)
+		s, ok := result.Values[0].(mathexp.Series)
+		require.True(t, ok)
+		require.Equal(t, labels, s.GetLabels())
+		require.Equal(t, []*float64{nil, nil, nil, util.Pointer(0.0), util.Pointer(1.0)},
+			[]*float64{s.GetValue(0), s.GetValue(1), s.GetValue(2), s.GetValue(3), s.GetValue(4)})
+	})
+
+	t.Run("returns a number for the latest point when latestOnly is set", func(t *testing.T) {
+		cmd, err := NewAnomalyCommand("B", "A", opts, mathexp.AnomalyOutputIsAnomaly, true)
+		require.NoError(t, err)
+
+		result, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+			"A": newResults(input),
+		}, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Len(t, result.Values, 1)
+		n, ok := result.Values[0].(mathexp.Number)
+		require.True(t, ok)
+		require.Equal(t, labels, n.GetLabels())
+		require.Equal(t, util.Pointer(1.0), n.GetFloat64Value())
+	})
+
+	t.Run("returns no data when input is no data", func(t *testing.T) {
+		cmd, err := NewAnomalyCommand("B", "A", opts, mathexp.AnomalyOutputIsAnomaly, true)
+		require.NoError(t, err)
+
+		result, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+			"A": newResults(mathexp.NewNoData()),
+		}, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Equal(t, newResults(mathexp.NewNoData()), result)
+	})
+
+	t.Run("fails when input is not a series", func(t *testing.T) {
+		cmd, err := NewAnomalyCommand("B", "A", opts, mathexp.AnomalyOutputIsAnomaly, false)
+		require.NoError(t, err)
+
+		_, err = cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+			"A": newResults(newNumber(nil, util.Pointer(1.0))),
+		}, tracing.InitializeTracerForTest())
+		require.ErrorContains(t, err, "can only detect anomalies in type series")
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
@@ -84,13 +85,15 @@ func (gm *MathCommand) Type() string {
 type ReduceCommand struct {
 	Reducer      mathexp.ReducerID
 	VarToReduce  string
+	Percentile   *float64
 	refID        string
 	seriesMapper mathexp.ReduceMapper
+	reduceFunc   mathexp.ReducerFunc
 }
 
 // NewReduceCommand creates a new ReduceCMD.
 func NewReduceCommand(refID string, reducer mathexp.ReducerID, varToReduce string, mapper mathexp.ReduceMapper) (*ReduceCommand, error) {
-	_, err := mathexp.GetReduceFunc(reducer)
+	reduceFunc, err := mathexp.GetReduceFunc(reducer)
 	if err != nil {
 		return nil, err
 	}
@@ -100,9 +103,37 @@ func NewReduceCommand(refID string, reducer mathexp.ReducerID, varToReduce strin
 		VarToReduce:  varToReduce,
 		refID:        refID,
 		seriesMapper: mapper,
+		reduceFunc:   reduceFunc,
 	}, nil
 }
 
+// NewPercentileReduceCommand creates a new ReduceCMD that reduces to the given percentile (0-100).
+func NewPercentileReduceCommand(refID string, percentile float64, varToReduce string, mapper mathexp.ReduceMapper) (*ReduceCommand, error) {
+	if err := mathexp.ValidatePercentile(percentile); err != nil {
+		return nil, err
+	}
+
+	return &ReduceCommand{
+		Reducer:      mathexp.ReducerPercentile,
+		VarToReduce:  varToReduce,
+		Percentile:   &percentile,
+		refID:        refID,
+		seriesMapper: mapper,
+		reduceFunc:   mathexp.Percentile(percentile),
+	}, nil
+}
+
+// newReduceCommand creates a ReduceCMD, reading the percentile parameter when the reducer requires it.
+func newReduceCommand(refID string, reducer mathexp.ReducerID, percentile *float64, varToReduce string, mapper mathexp.ReduceMapper) (*ReduceCommand, error) {
+	if reducer != mathexp.ReducerPercentile {
+		return NewReduceCommand(refID, reducer, varToReduce, mapper)
+	}
+	if percentile == nil {
+		return nil, errors.New("percentile must be specified when reducer is 'percentile'")
+	}
+	return NewPercentileReduceCommand(refID, *percentile, varToReduce, mapper)
+}
+
 // UnmarshalReduceCommand creates a MathCMD from Grafana's frontend query.
 func UnmarshalReduceCommand(rn *rawNode) (*ReduceCommand, error) {
 	rawVar, ok := rn.Query["expression"]
@@ -154,7 +185,16 @@ func UnmarshalReduceCommand(rn *rawNode) (*ReduceCommand, error) {
 			return nil, fmt.Errorf("field settings must be an object, got %T for refId %v", s, rn.RefID)
 		}
 	}
-	return NewReduceCommand(rn.RefID, redFunc, varToReduce, mapper)
+
+	var percentile *float64
+	if rawPercentile, ok := rn.Query["percentile"]; ok {
+		p, ok := rawPercentile.(float64)
+		if !ok {
+			return nil, fmt.Errorf("expected percentile to be a number, got %T", rawPercentile)
+		}
+		percentile = &p
+	}
+	return newReduceCommand(rn.RefID, redFunc, percentile, varToReduce, mapper)
 }
 
 // NeedsVars returns the variable names (refIds) that are dependencies
@@ -175,11 +215,7 @@ func (gr *ReduceCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.
 	for i, val := range vars[gr.VarToReduce].Values {
 		switch v := val.(type) {
 		case mathexp.Series:
-			num, err := v.Reduce(gr.refID, gr.Reducer, gr.seriesMapper)
-			if err != nil {
-				return newRes, err
-			}
-			newRes.Values = append(newRes.Values, num)
+			newRes.Values = append(newRes.Values, v.ReduceWithFunc(gr.refID, gr.reduceFunc, gr.seriesMapper))
 		case mathexp.Number: // if incoming vars is just a number, any reduce op is just a noop, add it as it is
 			value := v.GetFloat64Value()
 			if gr.seriesMapper != nil {
@@ -216,22 +252,28 @@ type ResampleCommand struct {
 	VarToResample string
 	Downsampler   mathexp.ReducerID
 	Upsampler     mathexp.Upsampler
+	MaxGap        int
 	TimeRange     TimeRange
 	refID         string
 }
 
 // NewResampleCommand creates a new ResampleCMD.
-func NewResampleCommand(refID, rawWindow, varToResample string, downsampler mathexp.ReducerID, upsampler mathexp.Upsampler, tr TimeRange) (*ResampleCommand, error) {
+// If maxGap is greater than 0, upsampling only fills gaps that span at most maxGap windows.
+func NewResampleCommand(refID, rawWindow, varToResample string, downsampler mathexp.ReducerID, upsampler mathexp.Upsampler, maxGap int, tr TimeRange) (*ResampleCommand, error) {
 	// TODO: validate reducer here, before execution
 	window, err := gtime.ParseDuration(rawWindow)
 	if err != nil {
 		return nil, fmt.Errorf(`failed to parse resample "window" duration field %q: %w`, window, err)
 	}
+	if maxGap < 0 {
+		return nil, fmt.Errorf("resample maxGap must not be negative, got %d", maxGap)
+	}
 	return &ResampleCommand{
 		Window:        window,
 		VarToResample: varToResample,
 		Downsampler:   downsampler,
 		Upsampler:     upsampler,
+		MaxGap:        maxGap,
 		TimeRange:     tr,
 		refID:         refID,
 	}, nil
@@ -280,10 +322,20 @@ func UnmarshalResampleCommand(rn *rawNode) (*ResampleCommand, error) {
 		return nil, fmt.Errorf("expected resample downsampler to be a string, got type %T", upsampler)
 	}
 
+	maxGap := 0
+	if rawMaxGap, ok := rn.Query["maxGap"]; ok {
+		f, ok := rawMaxGap.(float64)
+		if !ok || f != math.Trunc(f) {
+			return nil, fmt.Errorf("expected resample maxGap to be an integer, got %v", rawMaxGap)
+		}
+		maxGap = int(f)
+	}
+
 	return NewResampleCommand(rn.RefID, window,
 		varToResample,
 		mathexp.ReducerID(downsampler),
 		mathexp.Upsampler(upsampler),
+		maxGap,
 		rn.TimeRange)
 }
 
@@ -306,7 +358,7 @@ func (gr *ResampleCommand) Execute(ctx context.Context, now time.Time, vars math
 		}
 		switch v := val.(type) {
 		case mathexp.Series:
-			num, err := v.Resample(gr.refID, gr.Window, gr.Downsampler, gr.Upsampler, timeRange.From, timeRange.To)
+			num, err := v.ResampleWithMaxGap(gr.refID, gr.Window, gr.Downsampler, gr.Upsampler, timeRange.From, timeRange.To, gr.MaxGap)
 			if err != nil {
 				return newRes, err
 			}
@@ -343,6 +395,8 @@ const (
 	TypeThreshold
 	// TypeSQL is the CMDType for running SQL expressions
 	TypeSQL
+	// TypeAnomaly is the CMDType for detecting anomalies in time series
+	TypeAnomaly
 )
 
 func (gt CommandType) String() string {
@@ -359,6 +413,8 @@ func (gt CommandType) String() string {
 		return "threshold"
 	case TypeSQL:
 		return "sql"
+	case TypeAnomaly:
+		return "anomaly"
 	default:
 		return "unknown"
 	}
@@ -379,6 +435,8 @@ func ParseCommandType(s string) (CommandType, error) {
 		return TypeThreshold, nil
 	case "sql":
 		return TypeSQL, nil
+	case "anomaly":
+		return TypeAnomaly, nil
 	default:
 		return TypeUnknown, fmt.Errorf("'%v' is not a recognized expression type", s)
 	}
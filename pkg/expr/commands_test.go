@@ -99,6 +99,78 @@ func Test_UnmarshalReduceCommand_Settings(t *testing.T) {
 	}
 }
 
+func Test_UnmarshalReduceCommand_Percentile(t *testing.T) {
+	var tests = []struct {
+		name       string
+		query      string
+		isError    bool
+		percentile float64
+	}{
+		{
+			name:       "percentile reducer with percentile",
+			query:      `{ "expression" : "$A", "reducer": "percentile", "percentile": 95 }`,
+			percentile: 95,
+		},
+		{
+			name:    "error when percentile is missing",
+			query:   `{ "expression" : "$A", "reducer": "percentile" }`,
+			isError: true,
+		},
+		{
+			name:    "error when percentile is out of range",
+			query:   `{ "expression" : "$A", "reducer": "percentile", "percentile": 101 }`,
+			isError: true,
+		},
+		{
+			name:    "error when percentile is not a number",
+			query:   `{ "expression" : "$A", "reducer": "percentile", "percentile": "p95" }`,
+			isError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var qmap = make(map[string]any)
+			require.NoError(t, json.Unmarshal([]byte(test.query), &qmap))
+
+			cmd, err := UnmarshalReduceCommand(&rawNode{
+				RefID: "B",
+				Query: qmap,
+			})
+
+			if test.isError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, mathexp.ReducerPercentile, cmd.Reducer)
+			require.NotNil(t, cmd.Percentile)
+			require.Equal(t, test.percentile, *cmd.Percentile)
+		})
+	}
+}
+
+func TestReduceExecute_Percentile(t *testing.T) {
+	varToReduce := util.GenerateShortUID()
+	cmd, err := NewPercentileReduceCommand(util.GenerateShortUID(), 50, varToReduce, nil)
+	require.NoError(t, err)
+
+	series := mathexp.NewSeries(varToReduce, nil, 3)
+	for i, v := range []float64{3, 1, 2} {
+		f := v
+		series.SetPoint(i, time.Unix(int64(i), 0), &f)
+	}
+	vars := map[string]mathexp.Results{
+		varToReduce: {Values: mathexp.Values{series}},
+	}
+
+	results, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest())
+	require.NoError(t, err)
+	require.Len(t, results.Values, 1)
+	require.Equal(t, 2.0, *results.Values[0].(mathexp.Number).GetFloat64Value())
+}
+
 func TestReduceExecute(t *testing.T) {
 	varToReduce := util.GenerateShortUID()
 
@@ -221,7 +293,7 @@ func TestResampleCommand_Execute(t *testing.T) {
 		From: -10 * time.Second,
 		To:   0,
 	}
-	cmd, err := NewResampleCommand(util.GenerateShortUID(), "1s", varToReduce, "sum", "pad", tr)
+	cmd, err := NewResampleCommand(util.GenerateShortUID(), "1s", varToReduce, "sum", "pad", 0, tr)
 	require.NoError(t, err)
 
 	var tests = []struct {
@@ -273,3 +345,56 @@ func TestResampleCommand_Execute(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func Test_UnmarshalResampleCommand_MaxGap(t *testing.T) {
+	var tests = []struct {
+		name     string
+		maxGap   string
+		isError  bool
+		expected int
+	}{
+		{
+			name:     "max gap is 0 when not specified",
+			maxGap:   ``,
+			expected: 0,
+		},
+		{
+			name:     "max gap is read",
+			maxGap:   `, "maxGap": 3`,
+			expected: 3,
+		},
+		{
+			name:    "error when max gap is not an integer",
+			maxGap:  `, "maxGap": 1.5`,
+			isError: true,
+		},
+		{
+			name:    "error when max gap is negative",
+			maxGap:  `, "maxGap": -1`,
+			isError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q := fmt.Sprintf(`{ "expression" : "$A", "window": "1m", "downsampler": "last", "upsampler": "linear"%s }`, test.maxGap)
+			var qmap = make(map[string]any)
+			require.NoError(t, json.Unmarshal([]byte(q), &qmap))
+
+			cmd, err := UnmarshalResampleCommand(&rawNode{
+				RefID:     "B",
+				Query:     qmap,
+				TimeRange: RelativeTimeRange{From: -time.Hour},
+			})
+
+			if test.isError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, mathexp.UpsamplerLinear, cmd.Upsampler)
+			require.Equal(t, test.expected, cmd.MaxGap)
+		})
+	}
+}
@@ -219,3 +219,68 @@ func TestFingerprintsToFrame(t *testing.T) {
 		})
 	}
 }
+
+func TestHysteresisExecuteWithSchedule(t *testing.T) {
+	weekend := ThresholdScheduleJSON{
+		TimeIntervals: []ThresholdTimeIntervalJSON{{Weekdays: []string{"saturday", "sunday"}}},
+	}
+	loadSchedule, unloadSchedule := weekend, weekend
+	loadSchedule.Params = []float64{200}
+	unloadSchedule.Params = []float64{150}
+
+	loading, err := NewThresholdCommandFromEvaluator("B", "A", ConditionEvalJSON{
+		Type: ThresholdIsAbove, Params: []float64{100}, Schedule: []ThresholdScheduleJSON{loadSchedule},
+	})
+	require.NoError(t, err)
+	unloading, err := NewThresholdCommandFromEvaluator("B", "A", ConditionEvalJSON{
+		Type: ThresholdIsBelow, Params: []float64{30}, Schedule: []ThresholdScheduleJSON{unloadSchedule},
+	})
+	require.NoError(t, err)
+	unloading.Invert = true
+
+	loaded := data.Labels{"label": "loaded"}
+	cmd, err := NewHysteresisCommand("B", "A", *loading, *unloading, Fingerprints{loaded.Fingerprint(): {}})
+	require.NoError(t, err)
+
+	number := func(labels data.Labels, value float64) mathexp.Number {
+		n := mathexp.NewNumber("B", labels)
+		n.SetValue(&value)
+		return n
+	}
+	input := mathexp.Values{
+		number(data.Labels{"label": "new"}, 120),
+		number(loaded, 120),
+	}
+
+	testCases := []struct {
+		name     string
+		now      time.Time
+		expected mathexp.Values
+	}{
+		{
+			name: "weekday uses default params",
+			now:  time.Date(2024, 1, 3, 12, 0, 0, 0, time.UTC),
+			expected: mathexp.Values{
+				number(data.Labels{"label": "new"}, 1),
+				number(loaded, 1),
+			},
+		},
+		{
+			name: "weekend uses scheduled params",
+			now:  time.Date(2024, 1, 6, 12, 0, 0, 0, time.UTC),
+			expected: mathexp.Values{
+				number(data.Labels{"label": "new"}, 0),
+				number(loaded, 0),
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := cmd.Execute(context.Background(), tc.now, mathexp.Vars{
+				"A": mathexp.Results{Values: input},
+			}, tracing.InitializeTracerForTest())
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expected, result.Values)
+		})
+	}
+}
@@ -0,0 +1,311 @@
+package mathexp
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// The anomaly detection method
+// +enum
+type AnomalyMethod string
+
+const (
+	// Distance from the mean of the preceding window in standard deviations
+	AnomalyMethodZScore AnomalyMethod = "zscore"
+
+	// Distance from the median of the preceding window in median absolute deviations, robust to outliers in the window
+	AnomalyMethodMAD AnomalyMethod = "mad"
+
+	// Distance from the one-step-ahead forecast of an additive Holt-Winters model
+	AnomalyMethodHoltWinters AnomalyMethod = "holt_winters"
+)
+
+// The value returned for each point by the anomaly detection
+// +enum
+type AnomalyOutput string
+
+const (
+	// 1 when the point is outside of the expected band, otherwise 0
+	AnomalyOutputIsAnomaly AnomalyOutput = "is_anomaly"
+
+	// The signed distance from the expected value in deviations
+	AnomalyOutputScore AnomalyOutput = "score"
+
+	// The expected value
+	AnomalyOutputExpected AnomalyOutput = "expected"
+
+	// The lower bound of the expected band
+	AnomalyOutputLower AnomalyOutput = "lower"
+
+	// The upper bound of the expected band
+	AnomalyOutputUpper AnomalyOutput = "upper"
+)
+
+const (
+	// minAnomalyWindowPoints is the number of points a rolling window needs to have before a band is calculated.
+	minAnomalyWindowPoints = 3
+
+	// madScale makes the median absolute deviation a consistent estimator of the standard deviation for normal data.
+	madScale = 1.4826
+)
+
+// AnomalyOptions configures the detection of anomalies in a series.
+type AnomalyOptions struct {
+	Method AnomalyMethod
+	// Window is the length of the rolling window preceding each point. Used by zscore and mad.
+	Window time.Duration
+	// Deviations is the width of the expected band on each side of the expected value.
+	Deviations float64
+	// SeasonLength is the number of points in a season. Used by holt_winters, 0 disables seasonality.
+	SeasonLength int
+	// Alpha, Beta and Gamma are the smoothing factors of the level, trend and season. Used by holt_winters.
+	Alpha float64
+	Beta  float64
+	Gamma float64
+}
+
+// Validate returns an error if the options cannot be used to detect anomalies.
+func (o AnomalyOptions) Validate() error {
+	switch o.Method {
+	case AnomalyMethodZScore, AnomalyMethodMAD:
+		if o.Window <= 0 {
+			return fmt.Errorf("anomaly detection method '%s' requires a positive window", o.Method)
+		}
+	case AnomalyMethodHoltWinters:
+		if o.SeasonLength < 0 {
+			return fmt.Errorf("season length must not be negative, got %d", o.SeasonLength)
+		}
+		factors := []struct {
+			name  string
+			value float64
+		}{{"alpha", o.Alpha}, {"beta", o.Beta}, {"gamma", o.Gamma}}
+		for _, f := range factors {
+			if f.value < 0 || f.value > 1 {
+				return fmt.Errorf("smoothing factor %s must be between 0 and 1, got %v", f.name, f.value)
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported anomaly detection method '%s'", o.Method)
+	}
+	if o.Deviations <= 0 || math.IsNaN(o.Deviations) || math.IsInf(o.Deviations, 0) {
+		return fmt.Errorf("deviations must be a positive number, got %v", o.Deviations)
+	}
+	return nil
+}
+
+// anomalyBand is the expected value of a point and the size of one deviation from it.
+type anomalyBand struct {
+	ok       bool
+	expected float64
+	spread   float64
+}
+
+type anomalyPoint struct {
+	t time.Time
+	v *float64
+}
+
+// DetectAnomalies compares every point of the series against the band of values expected from the preceding points
+// and returns a series with the requested output for every point. Points without enough history, as well as null and
+// NaN points, are null in the result. The result is sorted by time.
+func (s Series) DetectAnomalies(refID string, opts AnomalyOptions, output AnomalyOutput) (Series, error) {
+	if err := opts.Validate(); err != nil {
+		return s, err
+	}
+
+	points := make([]anomalyPoint, 0, s.Len())
+	for i := 0; i < s.Len(); i++ {
+		t, v := s.GetPoint(i)
+		if v != nil && math.IsNaN(*v) {
+			v = nil
+		}
+		points = append(points, anomalyPoint{t: t, v: v})
+	}
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].t.Before(points[j].t)
+	})
+
+	var bands []anomalyBand
+	switch opts.Method {
+	case AnomalyMethodZScore:
+		bands = rollingBands(points, opts.Window, meanAndStdDev)
+	case AnomalyMethodMAD:
+		bands = rollingBands(points, opts.Window, medianAndMAD)
+	case AnomalyMethodHoltWinters:
+		bands = holtWintersBands(points, opts)
+	}
+
+	result := NewSeries(refID, s.GetLabels(), len(points))
+	for i, p := range points {
+		var value *float64
+		if p.v != nil && bands[i].ok {
+			v, err := anomalyOutputValue(*p.v, bands[i], opts.Deviations, output)
+			if err != nil {
+				return s, err
+			}
+			value = &v
+		}
+		result.SetPoint(i, p.t, value)
+	}
+	return result, nil
+}
+
+func anomalyOutputValue(v float64, band anomalyBand, deviations float64, output AnomalyOutput) (float64, error) {
+	lower := band.expected - deviations*band.spread
+	upper := band.expected + deviations*band.spread
+	switch output {
+	case AnomalyOutputIsAnomaly:
+		if v < lower || v > upper {
+			return 1, nil
+		}
+		return 0, nil
+	case AnomalyOutputScore:
+		if band.spread == 0 {
+			if v == band.expected {
+				return 0, nil
+			}
+			return math.Inf(int(math.Copysign(1, v-band.expected))), nil
+		}
+		return (v - band.expected) / band.spread, nil
+	case AnomalyOutputExpected:
+		return band.expected, nil
+	case AnomalyOutputLower:
+		return lower, nil
+	case AnomalyOutputUpper:
+		return upper, nil
+	default:
+		return 0, fmt.Errorf("unsupported anomaly detection output '%s'", output)
+	}
+}
+
+// rollingBands calculates the band of every point from the non-null points in the window that precedes it.
+// The points must be sorted by time.
+func rollingBands(points []anomalyPoint, window time.Duration, band func(values []float64) anomalyBand) []anomalyBand {
+	bands := make([]anomalyBand, len(points))
+	start := 0
+	for i, p := range points {
+		for start < i && !points[start].t.After(p.t.Add(-window)) {
+			start++
+		}
+		values := make([]float64, 0, i-start)
+		for _, w := range points[start:i] {
+			if w.v != nil {
+				values = append(values, *w.v)
+			}
+		}
+		if len(values) >= minAnomalyWindowPoints {
+			bands[i] = band(values)
+		}
+	}
+	return bands
+}
+
+func meanAndStdDev(values []float64) anomalyBand {
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return anomalyBand{ok: true, expected: mean, spread: math.Sqrt(variance / float64(len(values)))}
+}
+
+func medianAndMAD(values []float64) anomalyBand {
+	median := medianOf(values)
+	deviations := make([]float64, len(values))
+	for i, v := range values {
+		deviations[i] = math.Abs(v - median)
+	}
+	return anomalyBand{ok: true, expected: median, spread: madScale * medianOf(deviations)}
+}
+
+// medianOf returns the median of the values. The values are sorted in place.
+func medianOf(values []float64) float64 {
+	sort.Float64s(values)
+	mid := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[mid-1] + values[mid]) / 2
+	}
+	return values[mid]
+}
+
+// holtWintersBands calculates the band of every point from the one-step-ahead forecast of an additive Holt-Winters
+// model. The deviation is the standard deviation of the forecast errors of the preceding points.
+// The model is initialized from the first two seasons, or the first two points when seasonality is disabled.
+// Null points do not update the model.
+func holtWintersBands(points []anomalyPoint, opts AnomalyOptions) []anomalyBand {
+	bands := make([]anomalyBand, len(points))
+
+	idx := make([]int, 0, len(points))
+	values := make([]float64, 0, len(points))
+	for i, p := range points {
+		if p.v != nil {
+			idx = append(idx, i)
+			values = append(values, *p.v)
+		}
+	}
+
+	m := opts.SeasonLength
+	warmup := 2
+	if m > 0 {
+		warmup = 2 * m
+	}
+	if len(values) <= warmup {
+		return bands
+	}
+
+	var level, trend float64
+	seasonal := make([]float64, max(m, 1))
+	if m > 0 {
+		first, second := 0.0, 0.0
+		for i := 0; i < m; i++ {
+			first += values[i]
+			second += values[m+i]
+		}
+		first /= float64(m)
+		second /= float64(m)
+		trend = (second - first) / float64(m)
+		for i := 0; i < m; i++ {
+			seasonal[i] = values[i] - first
+		}
+		// run the model through the second season, the first forecast is for the third season
+		level = first
+		for t := m; t < warmup; t++ {
+			level, trend = holtWintersUpdate(values[t], level, trend, seasonal, t%m, opts)
+		}
+	} else {
+		level = values[1]
+		trend = values[1] - values[0]
+	}
+
+	var residuals []float64
+	for t := warmup; t < len(values); t++ {
+		s := 0
+		if m > 0 {
+			s = t % m
+		}
+		forecast := level + trend + seasonal[s]
+		if len(residuals) >= minAnomalyWindowPoints {
+			bands[idx[t]] = anomalyBand{ok: true, expected: forecast, spread: meanAndStdDev(residuals).spread}
+		}
+		residuals = append(residuals, values[t]-forecast)
+		level, trend = holtWintersUpdate(values[t], level, trend, seasonal, s, opts)
+	}
+	return bands
+}
+
+// holtWintersUpdate updates the model with the observed value and returns the new level and trend.
+// The seasonal component at index s is updated in place.
+func holtWintersUpdate(v, level, trend float64, seasonal []float64, s int, opts AnomalyOptions) (float64, float64) {
+	newLevel := opts.Alpha*(v-seasonal[s]) + (1-opts.Alpha)*(level+trend)
+	newTrend := opts.Beta*(newLevel-level) + (1-opts.Beta)*trend
+	if opts.SeasonLength > 0 {
+		seasonal[s] = opts.Gamma*(v-newLevel) + (1-opts.Gamma)*seasonal[s]
+	}
+	return newLevel, newTrend
+}
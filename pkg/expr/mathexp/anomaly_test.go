@@ -0,0 +1,201 @@
+package mathexp
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectAnomalies(t *testing.T) {
+	seriesOf := func(values ...*float64) Series {
+		s := NewSeries("", nil, len(values))
+		for i, v := range values {
+			s.SetPoint(i, time.Unix(int64(i), 0), v)
+		}
+		return s
+	}
+	numbers := func(values ...float64) Series {
+		pointers := make([]*float64, 0, len(values))
+		for _, v := range values {
+			pointers = append(pointers, float64Pointer(v))
+		}
+		return seriesOf(pointers...)
+	}
+
+	tests := []struct {
+		name     string
+		series   Series
+		opts     AnomalyOptions
+		output   AnomalyOutput
+		expected Series
+	}{
+		{
+			name:     "zscore flags points outside of the band",
+			series:   numbers(10, 10, 12, 10, 11, 50),
+			opts:     AnomalyOptions{Method: AnomalyMethodZScore, Window: time.Minute, Deviations: 3},
+			output:   AnomalyOutputIsAnomaly,
+			expected: seriesOf(nil, nil, nil, float64Pointer(0), float64Pointer(0), float64Pointer(1)),
+		},
+		{
+			name:     "zscore returns the expected value",
+			series:   numbers(10, 12, 14, 10),
+			opts:     AnomalyOptions{Method: AnomalyMethodZScore, Window: time.Minute, Deviations: 3},
+			output:   AnomalyOutputExpected,
+			expected: seriesOf(nil, nil, nil, float64Pointer(12)),
+		},
+		{
+			name:     "zscore only uses the points in the window",
+			series:   numbers(10, 10, 12, 10, 11, 50),
+			opts:     AnomalyOptions{Method: AnomalyMethodZScore, Window: 4 * time.Second, Deviations: 3},
+			output:   AnomalyOutputIsAnomaly,
+			expected: seriesOf(nil, nil, nil, float64Pointer(0), float64Pointer(0), float64Pointer(1)),
+		},
+		{
+			name:     "zscore needs enough points in the window",
+			series:   numbers(10, 10, 12, 10, 11, 50),
+			opts:     AnomalyOptions{Method: AnomalyMethodZScore, Window: 2 * time.Second, Deviations: 3},
+			output:   AnomalyOutputIsAnomaly,
+			expected: seriesOf(nil, nil, nil, nil, nil, nil),
+		},
+		{
+			name:     "zscore score is infinite when the window has no variance",
+			series:   numbers(10, 10, 10, 11, 9),
+			opts:     AnomalyOptions{Method: AnomalyMethodZScore, Window: time.Minute, Deviations: 3},
+			output:   AnomalyOutputScore,
+			expected: seriesOf(nil, nil, nil, float64Pointer(math.Inf(1)), float64Pointer(-1.25/math.Sqrt(0.1875))),
+		},
+		{
+			name:     "mad is not affected by outliers in the window",
+			series:   numbers(10, 12, 11, 13, 100, 12),
+			opts:     AnomalyOptions{Method: AnomalyMethodMAD, Window: time.Minute, Deviations: 3},
+			output:   AnomalyOutputIsAnomaly,
+			expected: seriesOf(nil, nil, nil, float64Pointer(0), float64Pointer(1), float64Pointer(0)),
+		},
+		{
+			name:     "mad band",
+			series:   numbers(10, 12, 11, 13),
+			opts:     AnomalyOptions{Method: AnomalyMethodMAD, Window: time.Minute, Deviations: 2},
+			output:   AnomalyOutputUpper,
+			expected: seriesOf(nil, nil, nil, float64Pointer(11+2*madScale)),
+		},
+		{
+			name:     "holt winters follows the trend",
+			series:   numbers(1, 2, 3, 4, 5, 6, 7, 8, 100),
+			opts:     AnomalyOptions{Method: AnomalyMethodHoltWinters, Deviations: 3, Alpha: 0.5, Beta: 0.1},
+			output:   AnomalyOutputIsAnomaly,
+			expected: seriesOf(nil, nil, nil, nil, nil, float64Pointer(0), float64Pointer(0), float64Pointer(0), float64Pointer(1)),
+		},
+		{
+			name:     "holt winters forecast",
+			series:   numbers(1, 2, 3, 4, 5, 6, 7, 8, 100),
+			opts:     AnomalyOptions{Method: AnomalyMethodHoltWinters, Deviations: 3, Alpha: 0.5, Beta: 0.1},
+			output:   AnomalyOutputExpected,
+			expected: seriesOf(nil, nil, nil, nil, nil, float64Pointer(6), float64Pointer(7), float64Pointer(8), float64Pointer(9)),
+		},
+		{
+			name:   "holt winters follows the season",
+			series: numbers(0, 10, 0, 10, 0, 10, 0, 10, 0, 50),
+			opts: AnomalyOptions{
+				Method: AnomalyMethodHoltWinters, Deviations: 3, SeasonLength: 2, Alpha: 0.5, Beta: 0.1, Gamma: 0.1,
+			},
+			output:   AnomalyOutputIsAnomaly,
+			expected: seriesOf(nil, nil, nil, nil, nil, nil, nil, float64Pointer(0), float64Pointer(0), float64Pointer(1)),
+		},
+		{
+			name:     "null and NaN points are null in the result",
+			series:   seriesOf(float64Pointer(10), float64Pointer(10), float64Pointer(12), nil, float64Pointer(math.NaN()), float64Pointer(50)),
+			opts:     AnomalyOptions{Method: AnomalyMethodZScore, Window: time.Minute, Deviations: 3},
+			output:   AnomalyOutputIsAnomaly,
+			expected: seriesOf(nil, nil, nil, nil, nil, float64Pointer(1)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.series.DetectAnomalies("", tt.opts, tt.output)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected.Len(), result.Len())
+			for i := 0; i < result.Len(); i++ {
+				expectedTime, expected := tt.expected.GetPoint(i)
+				actualTime, actual := result.GetPoint(i)
+				require.Equal(t, expectedTime, actualTime)
+				if expected == nil {
+					require.Nilf(t, actual, "point %d", i)
+					continue
+				}
+				require.NotNilf(t, actual, "point %d", i)
+				require.InDeltaf(t, *expected, *actual, 1e-9, "point %d", i)
+			}
+		})
+	}
+}
+
+func TestDetectAnomaliesSortsByTime(t *testing.T) {
+	s := NewSeries("", nil, 4)
+	s.SetPoint(0, time.Unix(3, 0), float64Pointer(50))
+	s.SetPoint(1, time.Unix(0, 0), float64Pointer(10))
+	s.SetPoint(2, time.Unix(2, 0), float64Pointer(12))
+	s.SetPoint(3, time.Unix(1, 0), float64Pointer(10))
+
+	result, err := s.DetectAnomalies("B", AnomalyOptions{Method: AnomalyMethodZScore, Window: time.Minute, Deviations: 3}, AnomalyOutputIsAnomaly)
+	require.NoError(t, err)
+	require.Equal(t, 4, result.Len())
+	for i := 0; i < result.Len(); i++ {
+		require.Equal(t, time.Unix(int64(i), 0), result.GetTime(i))
+	}
+	require.Equal(t, float64Pointer(1), result.GetValue(3))
+}
+
+func TestAnomalyOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  AnomalyOptions
+		error string
+	}{
+		{
+			name: "valid zscore",
+			opts: AnomalyOptions{Method: AnomalyMethodZScore, Window: time.Hour, Deviations: 3},
+		},
+		{
+			name: "valid holt winters",
+			opts: AnomalyOptions{Method: AnomalyMethodHoltWinters, Deviations: 3, SeasonLength: 24, Alpha: 0.5, Beta: 0.1, Gamma: 0.1},
+		},
+		{
+			name:  "unsupported method",
+			opts:  AnomalyOptions{Method: "foo", Deviations: 3},
+			error: "unsupported anomaly detection method",
+		},
+		{
+			name:  "missing window",
+			opts:  AnomalyOptions{Method: AnomalyMethodMAD, Deviations: 3},
+			error: "requires a positive window",
+		},
+		{
+			name:  "deviations must be positive",
+			opts:  AnomalyOptions{Method: AnomalyMethodZScore, Window: time.Hour},
+			error: "deviations must be a positive number",
+		},
+		{
+			name:  "negative season length",
+			opts:  AnomalyOptions{Method: AnomalyMethodHoltWinters, Deviations: 3, SeasonLength: -1},
+			error: "season length must not be negative",
+		},
+		{
+			name:  "smoothing factor out of range",
+			opts:  AnomalyOptions{Method: AnomalyMethodHoltWinters, Deviations: 3, Alpha: 2},
+			error: "smoothing factor alpha must be between 0 and 1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate()
+			if tt.error == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.error)
+		})
+	}
+}
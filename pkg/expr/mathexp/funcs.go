@@ -1,7 +1,12 @@
 package mathexp
 
 import (
+	"fmt"
 	"math"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
 
 	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
 )
@@ -68,6 +73,38 @@ var builtins = map[string]parse.Func{
 		VariantReturn: true,
 		F:             floor,
 	},
+	"clamp": {
+		Args:          []parse.ReturnType{parse.TypeVariantSet, parse.TypeScalar, parse.TypeScalar},
+		VariantReturn: true,
+		F:             clamp,
+	},
+	"rate": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      rate,
+	},
+	"delta": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      delta,
+	},
+	"cumsum": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      cumsum,
+	},
+	"moving_avg": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet, parse.TypeString},
+		Return: parse.TypeSeriesSet,
+		F:      movingAvg,
+		Check:  checkDurationArg(1),
+	},
+	"time_shift": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet, parse.TypeString},
+		Return: parse.TypeSeriesSet,
+		F:      timeShift,
+		Check:  checkDurationArg(1),
+	},
 }
 
 // abs returns the absolute value for each result in NumberSet, SeriesSet, or Scalar
@@ -307,3 +344,223 @@ func floor(e *State, varSet Results) (Results, error) {
 	}
 	return newRes, nil
 }
+
+// clamp limits the value for each result in NumberSet, SeriesSet, or Scalar to the range [min, max].
+func clamp(e *State, varSet Results, minSet Results, maxSet Results) (Results, error) {
+	minV, err := scalarArg(minSet)
+	if err != nil {
+		return Results{}, fmt.Errorf("clamp: min: %w", err)
+	}
+	maxV, err := scalarArg(maxSet)
+	if err != nil {
+		return Results{}, fmt.Errorf("clamp: max: %w", err)
+	}
+	if minV > maxV {
+		return Results{}, fmt.Errorf("clamp: min (%v) must not be greater than max (%v)", minV, maxV)
+	}
+
+	newRes := Results{}
+	for _, res := range varSet.Values {
+		newVal, err := perFloat(e, res, func(f float64) float64 {
+			return math.Max(minV, math.Min(maxV, f))
+		})
+		if err != nil {
+			return newRes, err
+		}
+		newRes.Values = append(newRes.Values, newVal)
+	}
+	return newRes, nil
+}
+
+// rate returns the per-second rate of increase between consecutive points of each series.
+// A decrease in value is treated as a counter reset. The first point of each series is dropped,
+// and a point with the same time as the previous one is null since it has no rate.
+func rate(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "rate", varSet, func(s Series) Series {
+		return perPointPair(e, s, func(prevT, t time.Time, prev, cur float64) (float64, bool) {
+			dt := t.Sub(prevT).Seconds()
+			if dt == 0 {
+				return 0, false
+			}
+			increase := cur - prev
+			if increase < 0 {
+				increase = cur
+			}
+			return increase / dt, true
+		})
+	})
+}
+
+// delta returns the difference between consecutive points of each series.
+// The first point of each series is dropped.
+func delta(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "delta", varSet, func(s Series) Series {
+		return perPointPair(e, s, func(_, _ time.Time, prev, cur float64) (float64, bool) {
+			return cur - prev, true
+		})
+	})
+}
+
+// cumsum returns the running total of each series. Null points are kept as null and do not
+// contribute to the total.
+func cumsum(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "cumsum", varSet, func(s Series) Series {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), s.Len())
+		total := float64(0)
+		for i := 0; i < s.Len(); i++ {
+			t, f := s.GetPoint(i)
+			if f == nil {
+				newSeries.SetPoint(i, t, nil)
+				continue
+			}
+			total += *f
+			nF := total
+			newSeries.SetPoint(i, t, &nF)
+		}
+		return newSeries
+	})
+}
+
+// movingAvg returns the average of the non-null points within the trailing window
+// (t - window, t] for every point of each series.
+func movingAvg(e *State, varSet Results, window string) (Results, error) {
+	d, err := parseFuncDuration(window)
+	if err != nil {
+		return Results{}, fmt.Errorf("moving_avg: %w", err)
+	}
+	if d <= 0 {
+		return Results{}, fmt.Errorf("moving_avg: window must be positive, got %q", window)
+	}
+
+	return perSeries(e, "moving_avg", varSet, func(s Series) Series {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), s.Len())
+		start, sum, count := 0, float64(0), 0
+		for i := 0; i < s.Len(); i++ {
+			t, f := s.GetPoint(i)
+			if f != nil {
+				sum += *f
+				count++
+			}
+			for ; !s.GetTime(start).After(t.Add(-d)); start++ {
+				if v := s.GetValue(start); v != nil {
+					sum -= *v
+					count--
+				}
+			}
+			if count == 0 {
+				newSeries.SetPoint(i, t, nil)
+				continue
+			}
+			avg := sum / float64(count)
+			newSeries.SetPoint(i, t, &avg)
+		}
+		return newSeries
+	})
+}
+
+// timeShift moves every point of each series forward in time by the given duration.
+// A negative duration moves points backwards.
+func timeShift(e *State, varSet Results, shift string) (Results, error) {
+	d, err := parseFuncDuration(shift)
+	if err != nil {
+		return Results{}, fmt.Errorf("time_shift: %w", err)
+	}
+
+	return perSeries(e, "time_shift", varSet, func(s Series) Series {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), s.Len())
+		for i := 0; i < s.Len(); i++ {
+			t, f := s.GetPoint(i)
+			newSeries.SetPoint(i, t.Add(d), f)
+		}
+		return newSeries
+	})
+}
+
+// perSeries applies seriesF to a time-sorted copy of each series in varSet.
+// NoData is passed through, other value types result in an error.
+func perSeries(e *State, name string, varSet Results, seriesF func(s Series) Series) (Results, error) {
+	newRes := Results{}
+	for _, res := range varSet.Values {
+		switch res.Type() {
+		case parse.TypeSeriesSet:
+			s := res.(Series)
+			sorted := NewSeries(e.RefID, s.GetLabels(), s.Len())
+			for i := 0; i < s.Len(); i++ {
+				t, f := s.GetPoint(i)
+				sorted.SetPoint(i, t, f)
+			}
+			sorted.SortByTime(false)
+			newRes.Values = append(newRes.Values, seriesF(sorted))
+		case parse.TypeNoData:
+			newRes.Values = append(newRes.Values, NewNoData())
+		default:
+			return newRes, fmt.Errorf("%s: expected a series, got %v", name, res.Type())
+		}
+	}
+	return newRes, nil
+}
+
+// perPointPair returns a series with one point less than s, where each point is the result of
+// pairF on the point and its predecessor. If either value is null, or pairF has no result for the
+// pair, the resulting point is null.
+func perPointPair(e *State, s Series, pairF func(prevT, t time.Time, prev, cur float64) (float64, bool)) Series {
+	if s.Len() < 2 {
+		return NewSeries(e.RefID, s.GetLabels(), 0)
+	}
+	newSeries := NewSeries(e.RefID, s.GetLabels(), s.Len()-1)
+	for i := 1; i < s.Len(); i++ {
+		prevT, prev := s.GetPoint(i - 1)
+		t, cur := s.GetPoint(i)
+		if prev == nil || cur == nil {
+			newSeries.SetPoint(i-1, t, nil)
+			continue
+		}
+		nF, ok := pairF(prevT, t, *prev, *cur)
+		if !ok {
+			newSeries.SetPoint(i-1, t, nil)
+			continue
+		}
+		newSeries.SetPoint(i-1, t, &nF)
+	}
+	return newSeries
+}
+
+// scalarArg returns the value of a Results that holds a single non-null Scalar.
+func scalarArg(res Results) (float64, error) {
+	if len(res.Values) != 1 || res.Values[0].Type() != parse.TypeScalar {
+		return 0, fmt.Errorf("expected a scalar")
+	}
+	f := res.Values[0].(Scalar).GetFloat64Value()
+	if f == nil {
+		return 0, fmt.Errorf("expected a non-null scalar")
+	}
+	return *f, nil
+}
+
+// parseFuncDuration parses a duration argument such as "5m", "1d" or "-1w".
+func parseFuncDuration(s string) (time.Duration, error) {
+	sign := time.Duration(1)
+	if strings.HasPrefix(s, "-") {
+		sign = -1
+		s = s[1:]
+	}
+	d, err := gtime.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return sign * d, nil
+}
+
+// checkDurationArg returns a parse time check that the argument at idx is a valid duration string.
+func checkDurationArg(idx int) func(*parse.Tree, *parse.FuncNode) error {
+	return func(t *parse.Tree, f *parse.FuncNode) error {
+		arg, ok := f.Args[idx].(*parse.StringNode)
+		if !ok {
+			return fmt.Errorf("parse: expected a duration string for argument %v of %s", idx, f.Name)
+		}
+		if _, err := parseFuncDuration(arg.Text); err != nil {
+			return fmt.Errorf("parse: %s: %w", f.Name, err)
+		}
+		return nil
+	}
+}
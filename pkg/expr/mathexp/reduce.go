@@ -22,11 +22,23 @@ const (
 	ReducerCount  ReducerID = "count"
 	ReducerLast   ReducerID = "last"
 	ReducerMedian ReducerID = "median"
+	ReducerFirst  ReducerID = "first"
+	ReducerStdDev ReducerID = "stddev"
+	ReducerRange  ReducerID = "range"
+	ReducerDiff   ReducerID = "diff"
+
+	// Number of values that are neither null nor NaN
+	ReducerCountNonNull ReducerID = "count_non_null"
+
+	// Percentile of the values, requires the percentile parameter
+	ReducerPercentile ReducerID = "percentile"
 )
 
-// GetSupportedReduceFuncs returns collection of supported function names
+// GetSupportedReduceFuncs returns collection of supported function names that don't take parameters.
+// ReducerPercentile is not included, see Percentile.
 func GetSupportedReduceFuncs() []ReducerID {
-	return []ReducerID{ReducerSum, ReducerMean, ReducerMin, ReducerMax, ReducerCount, ReducerLast, ReducerMedian}
+	return []ReducerID{ReducerSum, ReducerMean, ReducerMin, ReducerMax, ReducerCount, ReducerLast, ReducerMedian,
+		ReducerFirst, ReducerStdDev, ReducerRange, ReducerDiff, ReducerCountNonNull}
 }
 
 func Sum(fv *Float64Field) *float64 {
@@ -126,6 +138,99 @@ func Median(fv *Float64Field) *float64 {
 	}
 }
 
+func First(fv *Float64Field) *float64 {
+	var f float64
+	if fv.Len() == 0 {
+		f = math.NaN()
+		return &f
+	}
+	return fv.GetValue(0)
+}
+
+// StdDev returns the population standard deviation of the values.
+func StdDev(fv *Float64Field) *float64 {
+	if fv.Len() == 0 {
+		nan := math.NaN()
+		return &nan
+	}
+	mean := Avg(fv)
+	if math.IsNaN(*mean) {
+		return mean
+	}
+	var sum float64
+	for i := 0; i < fv.Len(); i++ {
+		d := *fv.GetValue(i) - *mean
+		sum += d * d
+	}
+	f := math.Sqrt(sum / float64(fv.Len()))
+	return &f
+}
+
+// Range returns the difference between the maximum and the minimum value.
+func Range(fv *Float64Field) *float64 {
+	minV, maxV := Min(fv), Max(fv)
+	f := *maxV - *minV
+	return &f
+}
+
+// Diff returns the difference between the last and the first value.
+func Diff(fv *Float64Field) *float64 {
+	first, last := First(fv), Last(fv)
+	if first == nil || last == nil {
+		nan := math.NaN()
+		return &nan
+	}
+	f := *last - *first
+	return &f
+}
+
+func CountNonNull(fv *Float64Field) *float64 {
+	var f float64
+	for i := 0; i < fv.Len(); i++ {
+		v := fv.GetValue(i)
+		if v != nil && !math.IsNaN(*v) {
+			f++
+		}
+	}
+	return &f
+}
+
+// Percentile returns a ReducerFunc for the p-th percentile (0-100) of the values.
+// It interpolates linearly between the two closest ranks.
+func Percentile(p float64) ReducerFunc {
+	return func(fv *Float64Field) *float64 {
+		values := make([]float64, 0, fv.Len())
+		for i := 0; i < fv.Len(); i++ {
+			v := fv.GetValue(i)
+			if v == nil || math.IsNaN(*v) {
+				nan := math.NaN()
+				return &nan
+			}
+			values = append(values, *v)
+		}
+
+		if len(values) == 0 {
+			nan := math.NaN()
+			return &nan
+		}
+
+		sort.Float64s(values)
+		rank := p / 100 * float64(len(values)-1)
+		lower := int(math.Floor(rank))
+		upper := int(math.Ceil(rank))
+		f := values[lower] + (values[upper]-values[lower])*(rank-float64(lower))
+		return &f
+	}
+}
+
+// ValidatePercentile returns an error if p is not a valid parameter for the percentile reducer.
+func ValidatePercentile(p float64) error {
+	if math.IsNaN(p) || p < 0 || p > 100 {
+		return fmt.Errorf("percentile must be between 0 and 100, got %v", p)
+	}
+	return nil
+}
+
 func GetReduceFunc(rFunc ReducerID) (ReducerFunc, error) {
 	switch rFunc {
 	case ReducerSum:
@@ -142,6 +247,18 @@ func GetReduceFunc(rFunc ReducerID) (ReducerFunc, error) {
 		return Last, nil
 	case ReducerMedian:
 		return Median, nil
+	case ReducerFirst:
+		return First, nil
+	case ReducerStdDev:
+		return StdDev, nil
+	case ReducerRange:
+		return Range, nil
+	case ReducerDiff:
+		return Diff, nil
+	case ReducerCountNonNull:
+		return CountNonNull, nil
+	case ReducerPercentile:
+		return nil, fmt.Errorf("reduction %v requires a percentile parameter", rFunc)
 	default:
 		return nil, fmt.Errorf("reduction %v not implemented", rFunc)
 	}
@@ -151,6 +268,16 @@ func GetReduceFunc(rFunc ReducerID) (ReducerFunc, error) {
 // if ReduceMapper is defined it applies it to the provided series and performs reduction of the resulting series.
 // Otherwise, the reduction operation is done against the original series.
 func (s Series) Reduce(refID string, rFunc ReducerID, mapper ReduceMapper) (Number, error) {
+	reduceFunc, err := GetReduceFunc(rFunc)
+	if err != nil {
+		return NewNumber(refID, nil), fmt.Errorf("invalid expression '%s': %w", refID, err)
+	}
+	return s.ReduceWithFunc(refID, reduceFunc, mapper), nil
+}
+
+// ReduceWithFunc turns the Series into a Number using reduceFunc. It behaves like Reduce,
+// and is used for reducers that take parameters such as Percentile.
+func (s Series) ReduceWithFunc(refID string, reduceFunc ReducerFunc, mapper ReduceMapper) Number {
 	var l data.Labels
 	if s.GetLabels() != nil {
 		l = s.GetLabels().Copy()
@@ -163,16 +290,12 @@ func (s Series) Reduce(refID string, rFunc ReducerID, mapper ReduceMapper) (Numb
 	}
 	fVec := series.Frame.Fields[seriesTypeValIdx]
 	floatField := Float64Field(*fVec)
-	reduceFunc, err := GetReduceFunc(rFunc)
-	if err != nil {
-		return number, fmt.Errorf("invalid expression '%s': %w", refID, err)
-	}
 	f = reduceFunc(&floatField)
 	if f != nil && mapper != nil {
 		f = mapper.MapOutput(f)
 	}
 	number.SetValue(f)
-	return number, nil
+	return number
 }
 
 type ReduceMapper interface {
@@ -274,6 +274,86 @@ func TestSeriesReduce(t *testing.T) {
 			resultsIs:   require.Equal,
 			results:     resultValuesNoErr(makeNumber("", nil, nil)),
 		},
+		{
+			name:        "first series",
+			red:         "first",
+			varToReduce: "A",
+			vars:        aSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(2))),
+		},
+		{
+			name:        "first empty series",
+			red:         "first",
+			varToReduce: "A",
+			vars:        seriesEmpty,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "stddev series",
+			red:         "stddev",
+			varToReduce: "A",
+			vars:        aSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(0.5))),
+		},
+		{
+			name:        "stddev series with a nil value",
+			red:         "stddev",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "range series",
+			red:         "range",
+			varToReduce: "A",
+			vars:        aSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(1))),
+		},
+		{
+			name:        "diff series",
+			red:         "diff",
+			varToReduce: "A",
+			vars:        aSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(-1))),
+		},
+		{
+			name:        "diff series with a nil value",
+			red:         "diff",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "count_non_null series with a nil value",
+			red:         "count_non_null",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(1))),
+		},
+		{
+			name:        "percentile without parameter will error",
+			red:         "percentile",
+			varToReduce: "A",
+			vars:        aSeries,
+			errIs:       require.Error,
+			resultsIs:   require.Equal,
+		},
 	}
 
 	for _, tt := range tests {
@@ -543,3 +623,39 @@ func sortedFloat64(f []float64) []float64 {
 	sort.Float64s(f)
 	return f
 }
+
+func TestPercentile(t *testing.T) {
+	series := makeSeries("", nil,
+		tp{time.Unix(5, 0), float64Pointer(4)},
+		tp{time.Unix(10, 0), float64Pointer(1)},
+		tp{time.Unix(15, 0), float64Pointer(3)},
+		tp{time.Unix(20, 0), float64Pointer(2)},
+		tp{time.Unix(25, 0), float64Pointer(5)},
+	)
+
+	tests := []struct {
+		percentile float64
+		expected   float64
+	}{
+		{percentile: 0, expected: 1},
+		{percentile: 50, expected: 3},
+		{percentile: 95, expected: 4.8},
+		{percentile: 100, expected: 5},
+	}
+	for _, tt := range tests {
+		n := series.ReduceWithFunc("", Percentile(tt.percentile), nil)
+		require.InDelta(t, tt.expected, *n.GetFloat64Value(), 1e-9, "percentile %v", tt.percentile)
+	}
+
+	t.Run("empty series is NaN", func(t *testing.T) {
+		n := makeSeries("", nil).ReduceWithFunc("", Percentile(95), nil)
+		require.True(t, math.IsNaN(*n.GetFloat64Value()))
+	})
+
+	t.Run("invalid percentiles", func(t *testing.T) {
+		require.Error(t, ValidatePercentile(-1))
+		require.Error(t, ValidatePercentile(101))
+		require.Error(t, ValidatePercentile(math.NaN()))
+		require.NoError(t, ValidatePercentile(99.9))
+	})
+}
@@ -20,10 +20,23 @@ const (
 
 	// Do not fill values (nill)
 	UpsamplerFillNA Upsampler = "fillna"
+
+	// Linear interpolation between the previous and the next value
+	UpsamplerLinear Upsampler = "linear"
 )
 
 // Resample turns the Series into a Number based on the given reduction function
 func (s Series) Resample(refID string, interval time.Duration, downsampler ReducerID, upsampler Upsampler, from, to time.Time) (Series, error) {
+	return s.ResampleWithMaxGap(refID, interval, downsampler, upsampler, from, to, 0)
+}
+
+// ResampleWithMaxGap is like Resample, but when upsampling it only fills gaps between two points of the series
+// that span at most maxGap intervals. Points in longer gaps are set to null. At the edges of the series, the gap
+// is measured from the nearest point. If maxGap is 0, all gaps are filled.
+func (s Series) ResampleWithMaxGap(refID string, interval time.Duration, downsampler ReducerID, upsampler Upsampler, from, to time.Time, maxGap int) (Series, error) {
+	if maxGap < 0 {
+		return s, fmt.Errorf("the maximum gap to fill must not be negative, got %d", maxGap)
+	}
 	newSeriesLength := int(float64(to.Sub(from).Nanoseconds()) / float64(interval.Nanoseconds()))
 	if newSeriesLength <= 0 {
 		return s, fmt.Errorf("the series cannot be sampled further; the time range is shorter than the interval")
@@ -31,6 +44,7 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler Reduc
 	resampled := NewSeries(refID, s.GetLabels(), newSeriesLength+1)
 	bookmark := 0
 	var lastSeen *float64
+	var lastSeenTime time.Time
 	idx := 0
 	t := from
 	for !t.After(to) && idx <= newSeriesLength {
@@ -47,10 +61,13 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler Reduc
 			bookmark++
 			sIdx++
 			lastSeen = v
+			lastSeenTime = st
 			vals = append(vals, v)
 		}
 		var value *float64
-		if len(vals) == 0 { // upsampling
+		if len(vals) == 0 && !withinMaxGap(s, sIdx, bookmark > 0, lastSeenTime, t, interval, maxGap) {
+			value = nil
+		} else if len(vals) == 0 { // upsampling
 			switch upsampler {
 			case UpsamplerPad:
 				if lastSeen != nil {
@@ -66,6 +83,13 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler Reduc
 				}
 			case UpsamplerFillNA:
 				value = nil
+			case UpsamplerLinear:
+				if lastSeen == nil || sIdx == s.Len() {
+					value = nil
+				} else {
+					nextTime, next := s.GetPoint(sIdx)
+					value = interpolate(lastSeenTime, *lastSeen, nextTime, next, t)
+				}
 			default:
 				return s, fmt.Errorf("upsampling %v not implemented", upsampler)
 			}
@@ -97,3 +121,36 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler Reduc
 	}
 	return resampled, nil
 }
+
+// withinMaxGap reports whether the empty interval at t may be filled when upsampling. nextIdx is the
+// index of the next point of the series, hasLast reports whether a point was seen before t.
+func withinMaxGap(s Series, nextIdx int, hasLast bool, lastTime, t time.Time, interval time.Duration, maxGap int) bool {
+	if maxGap == 0 {
+		return true
+	}
+	limit := time.Duration(maxGap) * interval
+	hasNext := nextIdx < s.Len()
+	switch {
+	case hasLast && hasNext:
+		return s.GetTime(nextIdx).Sub(lastTime) <= limit
+	case hasLast:
+		return t.Sub(lastTime) <= limit
+	case hasNext:
+		return s.GetTime(nextIdx).Sub(t) <= limit
+	default:
+		return false
+	}
+}
+
+// interpolate returns the value at t on the line between (prevTime, prev) and (nextTime, next).
+func interpolate(prevTime time.Time, prev float64, nextTime time.Time, next *float64, t time.Time) *float64 {
+	if next == nil {
+		return nil
+	}
+	span := nextTime.Sub(prevTime)
+	if span <= 0 {
+		return &prev
+	}
+	f := prev + (*next-prev)*float64(t.Sub(prevTime))/float64(span)
+	return &f
+}
@@ -290,3 +290,75 @@ func TestResampleSeries(t *testing.T) {
 		})
 	}
 }
+
+func TestResampleSeriesWithMaxGap(t *testing.T) {
+	seriesToResample := makeSeries("", nil,
+		tp{time.Unix(0, 0), float64Pointer(0)},
+		tp{time.Unix(4, 0), float64Pointer(4)},
+		tp{time.Unix(12, 0), float64Pointer(0)},
+	)
+
+	var tests = []struct {
+		name      string
+		upsampler Upsampler
+		maxGap    int
+		series    Series
+	}{
+		{
+			name:      "linear without max gap",
+			upsampler: UpsamplerLinear,
+			series: makeSeries("", nil,
+				tp{time.Unix(0, 0), float64Pointer(0)},
+				tp{time.Unix(2, 0), float64Pointer(2)},
+				tp{time.Unix(4, 0), float64Pointer(4)},
+				tp{time.Unix(6, 0), float64Pointer(3)},
+				tp{time.Unix(8, 0), float64Pointer(2)},
+				tp{time.Unix(10, 0), float64Pointer(1)},
+				tp{time.Unix(12, 0), float64Pointer(0)},
+				tp{time.Unix(14, 0), nil},
+			),
+		},
+		{
+			name:      "linear with max gap",
+			upsampler: UpsamplerLinear,
+			maxGap:    2,
+			series: makeSeries("", nil,
+				tp{time.Unix(0, 0), float64Pointer(0)},
+				tp{time.Unix(2, 0), float64Pointer(2)},
+				tp{time.Unix(4, 0), float64Pointer(4)},
+				tp{time.Unix(6, 0), nil},
+				tp{time.Unix(8, 0), nil},
+				tp{time.Unix(10, 0), nil},
+				tp{time.Unix(12, 0), float64Pointer(0)},
+				tp{time.Unix(14, 0), nil},
+			),
+		},
+		{
+			name:      "pad with max gap fills trailing points",
+			upsampler: UpsamplerPad,
+			maxGap:    2,
+			series: makeSeries("", nil,
+				tp{time.Unix(0, 0), float64Pointer(0)},
+				tp{time.Unix(2, 0), float64Pointer(0)},
+				tp{time.Unix(4, 0), float64Pointer(4)},
+				tp{time.Unix(6, 0), nil},
+				tp{time.Unix(8, 0), nil},
+				tp{time.Unix(10, 0), nil},
+				tp{time.Unix(12, 0), float64Pointer(0)},
+				tp{time.Unix(14, 0), float64Pointer(0)},
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series, err := seriesToResample.ResampleWithMaxGap("", 2*time.Second, ReducerLast, tt.upsampler, time.Unix(0, 0), time.Unix(14, 0), tt.maxGap)
+			require.NoError(t, err)
+			assert.Equal(t, tt.series, series)
+		})
+	}
+
+	t.Run("negative max gap will error", func(t *testing.T) {
+		_, err := seriesToResample.ResampleWithMaxGap("", 2*time.Second, ReducerLast, UpsamplerPad, time.Unix(0, 0), time.Unix(14, 0), -1)
+		require.Error(t, err)
+	})
+}
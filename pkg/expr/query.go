@@ -29,6 +29,9 @@ const (
 
 	// SQL query via DuckDB
 	QueryTypeSQL QueryType = "sql"
+
+	// Detect anomalies in time series
+	QueryTypeAnomaly QueryType = "anomaly"
 )
 
 type MathQuery struct {
@@ -43,6 +46,9 @@ type ReduceQuery struct {
 	// The reducer
 	Reducer mathexp.ReducerID `json:"reducer"`
 
+	// The percentile (0-100) to compute, only valid when reducer is percentile
+	Percentile *float64 `json:"percentile,omitempty" jsonschema:"minimum=0,maximum=100,example=95,example=99"`
+
 	// Reducer Options
 	Settings *ReduceSettings `json:"settings,omitempty"`
 }
@@ -60,6 +66,44 @@ type ResampleQuery struct {
 
 	// The upsample function
 	Upsampler mathexp.Upsampler `json:"upsampler"`
+
+	// Only fill gaps that span at most this many windows when upsampling, longer gaps are left empty.
+	// When not set, all gaps are filled.
+	MaxGap int `json:"maxGap,omitempty" jsonschema:"minimum=0,example=3"`
+}
+
+// QueryType = anomaly
+type AnomalyQuery struct {
+	// Reference to single query result
+	Expression string `json:"expression" jsonschema:"minLength=1,example=$A"`
+
+	// The detection method
+	Method mathexp.AnomalyMethod `json:"method"`
+
+	// The value returned for each point, defaults to is_anomaly
+	Output mathexp.AnomalyOutput `json:"output,omitempty"`
+
+	// The rolling window preceding each point, required by zscore and mad
+	Window string `json:"window,omitempty" jsonschema:"example=1h,example=1d"`
+
+	// The width of the expected band on each side of the expected value in deviations, defaults to 3
+	Deviations *float64 `json:"deviations,omitempty" jsonschema:"example=3"`
+
+	// The number of points in a season, used by holt_winters. When not set, seasonality is disabled.
+	SeasonLength int `json:"seasonLength,omitempty" jsonschema:"minimum=0,example=24"`
+
+	// Smoothing factor of the level, used by holt_winters, defaults to 0.5
+	Alpha *float64 `json:"alpha,omitempty" jsonschema:"minimum=0,maximum=1"`
+
+	// Smoothing factor of the trend, used by holt_winters, defaults to 0.1
+	Beta *float64 `json:"beta,omitempty" jsonschema:"minimum=0,maximum=1"`
+
+	// Smoothing factor of the season, used by holt_winters, defaults to 0.1
+	Gamma *float64 `json:"gamma,omitempty" jsonschema:"minimum=0,maximum=1"`
+
+	// Return only the output at the latest point of each series as a number,
+	// so that the expression can be used as the alert condition
+	LatestOnly bool `json:"latestOnly,omitempty"`
 }
 
 type ThresholdQuery struct {
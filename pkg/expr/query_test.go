@@ -24,7 +24,9 @@ func TestQueryTypeDefinitions(t *testing.T) {
 			Enums: []reflect.Type{
 				reflect.TypeOf(mathexp.ReducerSum),   // pick an example value (not the root)
 				reflect.TypeOf(mathexp.UpsamplerPad), // pick an example value (not the root)
-				reflect.TypeOf(ReduceModeDrop),       // pick an example value (not the root)
+				reflect.TypeOf(mathexp.AnomalyMethodZScore),
+				reflect.TypeOf(mathexp.AnomalyOutputIsAnomaly),
+				reflect.TypeOf(ReduceModeDrop), // pick an example value (not the root)
 				reflect.TypeOf(ThresholdIsAbove),
 				reflect.TypeOf(classic.ConditionOperatorAnd),
 			},
@@ -80,6 +82,20 @@ func TestQueryTypeDefinitions(t *testing.T) {
 				},
 			},
 		},
+		schemabuilder.QueryTypeInfo{
+			Discriminators: data.NewDiscriminators("type", QueryTypeAnomaly),
+			GoType:         reflect.TypeOf(&AnomalyQuery{}),
+			Examples: []data.QueryExample{
+				{
+					Name: "flag points outside of 3 standard deviations of the last hour",
+					SaveModel: data.AsUnstructured(AnomalyQuery{
+						Expression: "$A",
+						Method:     mathexp.AnomalyMethodZScore,
+						Window:     "1h",
+					}),
+				},
+			},
+		},
 		schemabuilder.QueryTypeInfo{
 			Discriminators: data.NewDiscriminators("type", QueryTypeSQL),
 			GoType:         reflect.TypeOf(&SQLExpression{}),
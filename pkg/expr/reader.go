@@ -87,8 +87,8 @@ func (h *ExpressionQueryReader) ReadQuery(
 		}
 		if err == nil {
 			eq.Properties = q
-			eq.Command, err = NewReduceCommand(common.RefID,
-				q.Reducer, referenceVar, mapper)
+			eq.Command, err = newReduceCommand(common.RefID,
+				q.Reducer, q.Percentile, referenceVar, mapper)
 		}
 
 	case QueryTypeResample:
@@ -108,6 +108,7 @@ func (h *ExpressionQueryReader) ReadQuery(
 				referenceVar,
 				q.Downsampler,
 				q.Upsampler,
+				q.MaxGap,
 				AbsoluteTimeRange{
 					From: tr.GetFromAsTimeUTC(),
 					To:   tr.GetToAsTimeUTC(),
@@ -131,6 +132,17 @@ func (h *ExpressionQueryReader) ReadQuery(
 			eq.Command, err = NewSQLCommand(common.RefID, q.Expression)
 		}
 
+	case QueryTypeAnomaly:
+		q := &AnomalyQuery{}
+		err = iter.ReadVal(q)
+		if err == nil {
+			referenceVar, err = getReferenceVar(q.Expression, common.RefID)
+		}
+		if err == nil {
+			eq.Properties = q
+			eq.Command, err = newAnomalyCommandFromQuery(common.RefID, referenceVar, q)
+		}
+
 	case QueryTypeThreshold:
 		q := &ThresholdQuery{}
 		err = iter.ReadVal(q)
@@ -144,7 +156,7 @@ func (h *ExpressionQueryReader) ReadQuery(
 			}
 			firstCondition := q.Conditions[0]
 
-			threshold, err := NewThresholdCommand(common.RefID, referenceVar, firstCondition.Evaluator.Type, firstCondition.Evaluator.Params)
+			threshold, err := NewThresholdCommandFromEvaluator(common.RefID, referenceVar, firstCondition.Evaluator)
 			if err != nil {
 				return eq, fmt.Errorf("invalid condition: %w", err)
 			}
@@ -152,11 +164,11 @@ func (h *ExpressionQueryReader) ReadQuery(
 			eq.Properties = q
 
 			if firstCondition.UnloadEvaluator != nil && h.features.IsEnabledGlobally(featuremgmt.FlagRecoveryThreshold) {
-				unloading, err := NewThresholdCommand(common.RefID, referenceVar, firstCondition.UnloadEvaluator.Type, firstCondition.UnloadEvaluator.Params)
-				unloading.Invert = true
+				unloading, err := NewThresholdCommandFromEvaluator(common.RefID, referenceVar, *firstCondition.UnloadEvaluator)
 				if err != nil {
 					return eq, fmt.Errorf("invalid unloadCondition: %w", err)
 				}
+				unloading.Invert = true
 				var d Fingerprints
 				if firstCondition.LoadedDimensions != nil {
 					d, err = FingerprintsFromFrame(firstCondition.LoadedDimensions)
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/alertmanager/timeinterval"
 
 	"github.com/grafana/grafana/pkg/expr/mathexp"
 	"github.com/grafana/grafana/pkg/infra/tracing"
@@ -26,6 +27,14 @@ type ThresholdCommand struct {
 	ThresholdFunc ThresholdType
 	Invert        bool
 	predicate     predicate
+	schedule      []scheduledPredicate
+}
+
+// scheduledPredicate is a predicate that replaces the default one while the evaluated time
+// falls into any of its time intervals.
+type scheduledPredicate struct {
+	intervals []timeinterval.TimeInterval
+	predicate predicate
 }
 
 // +enum
@@ -36,6 +45,10 @@ const (
 	ThresholdIsBelow        ThresholdType = "lt"
 	ThresholdIsWithinRange  ThresholdType = "within_range"
 	ThresholdIsOutsideRange ThresholdType = "outside_range"
+	ThresholdIsAboveOrEqual ThresholdType = "gte"
+	ThresholdIsBelowOrEqual ThresholdType = "lte"
+	ThresholdIsEqual        ThresholdType = "eq"
+	ThresholdIsNotEqual     ThresholdType = "ne"
 )
 
 var (
@@ -44,47 +57,130 @@ var (
 		string(ThresholdIsBelow),
 		string(ThresholdIsWithinRange),
 		string(ThresholdIsOutsideRange),
+		string(ThresholdIsAboveOrEqual),
+		string(ThresholdIsBelowOrEqual),
+		string(ThresholdIsEqual),
+		string(ThresholdIsNotEqual),
 	}
 )
 
 func NewThresholdCommand(refID, referenceVar string, thresholdFunc ThresholdType, conditions []float64) (*ThresholdCommand, error) {
-	var predicate predicate
-	switch thresholdFunc {
-	case ThresholdIsOutsideRange:
-		if len(conditions) < 2 {
-			return nil, fmt.Errorf("incorrect number of arguments for threshold function '%s': got %d but need 2", thresholdFunc, len(conditions))
+	predicate, err := newPredicate(thresholdFunc, conditions)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ThresholdCommand{
+		RefID:         refID,
+		ReferenceVar:  referenceVar,
+		ThresholdFunc: thresholdFunc,
+		predicate:     predicate,
+	}, nil
+}
+
+// NewThresholdCommandFromEvaluator creates a ThresholdCommand from the evaluator model.
+// In addition to the default params, the evaluator can define a schedule of params that
+// apply only during specific time intervals, e.g. business hours or weekends.
+func NewThresholdCommandFromEvaluator(refID, referenceVar string, evaluator ConditionEvalJSON) (*ThresholdCommand, error) {
+	cmd, err := NewThresholdCommand(refID, referenceVar, evaluator.Type, evaluator.Params)
+	if err != nil {
+		return nil, err
+	}
+	for i, entry := range evaluator.Schedule {
+		if len(entry.TimeIntervals) == 0 {
+			return nil, fmt.Errorf("schedule entry %d: at least one time interval is required", i)
 		}
-		predicate = outsideRangePredicate{left: conditions[0], right: conditions[1]}
-	case ThresholdIsWithinRange:
+		intervals, err := entry.timeIntervals()
+		if err != nil {
+			return nil, fmt.Errorf("schedule entry %d: %w", i, err)
+		}
+		p, err := newPredicate(evaluator.Type, entry.Params)
+		if err != nil {
+			return nil, fmt.Errorf("schedule entry %d: %w", i, err)
+		}
+		cmd.schedule = append(cmd.schedule, scheduledPredicate{intervals: intervals, predicate: p})
+	}
+	return cmd, nil
+}
+
+func newPredicate(thresholdFunc ThresholdType, conditions []float64) (predicate, error) {
+	switch thresholdFunc {
+	case ThresholdIsOutsideRange, ThresholdIsWithinRange:
 		if len(conditions) < 2 {
 			return nil, fmt.Errorf("incorrect number of arguments for threshold function '%s': got %d but need 2", thresholdFunc, len(conditions))
 		}
-		predicate = withinRangePredicate{left: conditions[0], right: conditions[1]}
-	case ThresholdIsAbove:
-		if len(conditions) < 1 {
-			return nil, fmt.Errorf("incorrect number of arguments for threshold function '%s': got %d but need 1", thresholdFunc, len(conditions))
+		if thresholdFunc == ThresholdIsOutsideRange {
+			return outsideRangePredicate{left: conditions[0], right: conditions[1]}, nil
 		}
-		predicate = greaterThanPredicate{value: conditions[0]}
-	case ThresholdIsBelow:
+		return withinRangePredicate{left: conditions[0], right: conditions[1]}, nil
+	case ThresholdIsAbove, ThresholdIsBelow, ThresholdIsAboveOrEqual, ThresholdIsBelowOrEqual, ThresholdIsEqual, ThresholdIsNotEqual:
 		if len(conditions) < 1 {
 			return nil, fmt.Errorf("incorrect number of arguments for threshold function '%s': got %d but need 1", thresholdFunc, len(conditions))
 		}
-		predicate = lessThanPredicate{value: conditions[0]}
 	default:
 		return nil, fmt.Errorf("expected threshold function to be one of [%s], got %s", strings.Join(supportedThresholdFuncs, ", "), thresholdFunc)
 	}
 
-	return &ThresholdCommand{
-		RefID:         refID,
-		ReferenceVar:  referenceVar,
-		ThresholdFunc: thresholdFunc,
-		predicate:     predicate,
-	}, nil
+	value := conditions[0]
+	switch thresholdFunc {
+	case ThresholdIsAbove:
+		return greaterThanPredicate{value: value}, nil
+	case ThresholdIsBelow:
+		return lessThanPredicate{value: value}, nil
+	case ThresholdIsAboveOrEqual:
+		return greaterThanOrEqualPredicate{value: value}, nil
+	case ThresholdIsBelowOrEqual:
+		return lessThanOrEqualPredicate{value: value}, nil
+	case ThresholdIsEqual:
+		return equalPredicate{value: value}, nil
+	default:
+		return notEqualPredicate{value: value}, nil
+	}
 }
 
 type ConditionEvalJSON struct {
 	Params []float64     `json:"params"`
 	Type   ThresholdType `json:"type"` // e.g. "gt"
+	// Params that replace the default ones during specific time intervals.
+	// The first entry that matches the evaluation time wins.
+	Schedule []ThresholdScheduleJSON `json:"schedule,omitempty"`
+}
+
+// ThresholdScheduleJSON overrides the params of a threshold evaluator
+// while the evaluation time falls into any of the time intervals.
+type ThresholdScheduleJSON struct {
+	TimeIntervals []ThresholdTimeIntervalJSON `json:"timeIntervals"`
+	Params        []float64                   `json:"params"`
+}
+
+// ThresholdTimeIntervalJSON describes a recurring time interval.
+// It uses the same format as the time intervals of mute timings.
+type ThresholdTimeIntervalJSON struct {
+	Times       []ThresholdTimeRangeJSON `json:"times,omitempty"`
+	Weekdays    []string                 `json:"weekdays,omitempty" jsonschema:"example=monday:friday"`
+	DaysOfMonth []string                 `json:"days_of_month,omitempty" jsonschema:"example=1:15"`
+	Months      []string                 `json:"months,omitempty" jsonschema:"example=january:march"`
+	Years       []string                 `json:"years,omitempty" jsonschema:"example=2024:2025"`
+	Location    string                   `json:"location,omitempty" jsonschema:"example=Europe/Berlin"`
+}
+
+type ThresholdTimeRangeJSON struct {
+	StartTime string `json:"start_time" jsonschema:"example=09:00"`
+	EndTime   string `json:"end_time" jsonschema:"example=17:00"`
+}
+
+// timeIntervals converts the model to the intervals supported by Alertmanager.
+// The JSON representation is shared, so the conversion also validates the model.
+func (s ThresholdScheduleJSON) timeIntervals() ([]timeinterval.TimeInterval, error) {
+	raw, err := json.Marshal(s.TimeIntervals)
+	if err != nil {
+		return nil, err
+	}
+	var result []timeinterval.TimeInterval
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("invalid time interval: %w", err)
+	}
+	return result, nil
 }
 
 // UnmarshalResampleCommand creates a ResampleCMD from Grafana's frontend query.
@@ -104,12 +200,12 @@ func UnmarshalThresholdCommand(rn *rawNode, features featuremgmt.FeatureToggles)
 	}
 	firstCondition := cmdConfig.Conditions[0]
 
-	threshold, err := NewThresholdCommand(rn.RefID, referenceVar, firstCondition.Evaluator.Type, firstCondition.Evaluator.Params)
+	threshold, err := NewThresholdCommandFromEvaluator(rn.RefID, referenceVar, firstCondition.Evaluator)
 	if err != nil {
 		return nil, fmt.Errorf("invalid condition: %w", err)
 	}
 	if firstCondition.UnloadEvaluator != nil && features.IsEnabledGlobally(featuremgmt.FlagRecoveryThreshold) {
-		unloading, err := NewThresholdCommand(rn.RefID, referenceVar, firstCondition.UnloadEvaluator.Type, firstCondition.UnloadEvaluator.Params)
+		unloading, err := NewThresholdCommandFromEvaluator(rn.RefID, referenceVar, *firstCondition.UnloadEvaluator)
 		if err != nil {
 			return nil, fmt.Errorf("invalid unloadCondition: %w", err)
 		}
@@ -132,12 +228,24 @@ func (tc *ThresholdCommand) NeedsVars() []string {
 	return []string{tc.ReferenceVar}
 }
 
-func (tc *ThresholdCommand) Execute(_ context.Context, _ time.Time, vars mathexp.Vars, _ tracing.Tracer) (mathexp.Results, error) {
-	eval := func(maybeValue *float64) *float64 {
+// predicateAt returns the predicate that applies at the given time.
+func (tc *ThresholdCommand) predicateAt(t time.Time) predicate {
+	for _, s := range tc.schedule {
+		for _, interval := range s.intervals {
+			if interval.ContainsTime(t) {
+				return s.predicate
+			}
+		}
+	}
+	return tc.predicate
+}
+
+func (tc *ThresholdCommand) Execute(_ context.Context, now time.Time, vars mathexp.Vars, _ tracing.Tracer) (mathexp.Results, error) {
+	eval := func(at time.Time, maybeValue *float64) *float64 {
 		if maybeValue == nil {
 			return nil
 		}
-		result := tc.predicate.Eval(*maybeValue)
+		result := tc.predicateAt(at).Eval(*maybeValue)
 		if tc.Invert {
 			result = !result
 		}
@@ -155,15 +263,16 @@ func (tc *ThresholdCommand) Execute(_ context.Context, _ time.Time, vars mathexp
 			s := mathexp.NewSeries(tc.RefID, v.GetLabels(), v.Len())
 			for i := 0; i < v.Len(); i++ {
 				t, value := v.GetPoint(i)
-				s.SetPoint(i, t, eval(value))
+				// series are evaluated against the schedule at the time of each point
+				s.SetPoint(i, t, eval(t, value))
 			}
 			newRes.Values = append(newRes.Values, s)
 		case mathexp.Number:
 			copyV := mathexp.NewNumber(tc.RefID, v.GetLabels())
-			copyV.SetValue(eval(v.GetFloat64Value()))
+			copyV.SetValue(eval(now, v.GetFloat64Value()))
 			newRes.Values = append(newRes.Values, copyV)
 		case mathexp.Scalar:
-			copyV := mathexp.NewScalar(tc.RefID, eval(v.GetFloat64Value()))
+			copyV := mathexp.NewScalar(tc.RefID, eval(now, v.GetFloat64Value()))
 			newRes.Values = append(newRes.Values, copyV)
 		case mathexp.NoData:
 			newRes.Values = append(newRes.Values, mathexp.NewNoData())
@@ -294,3 +403,35 @@ type greaterThanPredicate struct {
 func (r greaterThanPredicate) Eval(f float64) bool {
 	return f > r.value
 }
+
+type lessThanOrEqualPredicate struct {
+	value float64
+}
+
+func (r lessThanOrEqualPredicate) Eval(f float64) bool {
+	return f <= r.value
+}
+
+type greaterThanOrEqualPredicate struct {
+	value float64
+}
+
+func (r greaterThanOrEqualPredicate) Eval(f float64) bool {
+	return f >= r.value
+}
+
+type equalPredicate struct {
+	value float64
+}
+
+func (r equalPredicate) Eval(f float64) bool {
+	return f == r.value
+}
+
+type notEqualPredicate struct {
+	value float64
+}
+
+func (r notEqualPredicate) Eval(f float64) bool {
+	return f != r.value
+}
@@ -48,12 +48,38 @@ func TestNewThresholdCommand(t *testing.T) {
 			args:        []float64{0, 1},
 			shouldError: false,
 		},
+		{
+			fn:          "gte",
+			args:        []float64{0},
+			shouldError: false,
+		},
+		{
+			fn:          "lte",
+			args:        []float64{0},
+			shouldError: false,
+		},
+		{
+			fn:          "eq",
+			args:        []float64{0},
+			shouldError: false,
+		},
+		{
+			fn:          "ne",
+			args:        []float64{0},
+			shouldError: false,
+		},
 		{
 			fn:            "gt",
 			args:          []float64{},
 			shouldError:   true,
 			expectedError: "incorrect number of arguments",
 		},
+		{
+			fn:            "eq",
+			args:          []float64{},
+			shouldError:   true,
+			expectedError: "incorrect number of arguments",
+		},
 		{
 			fn:            "lt",
 			args:          []float64{},
@@ -143,6 +169,87 @@ func TestUnmarshalThresholdCommand(t *testing.T) {
 			shouldError:   true,
 			expectedError: "expected threshold function to be one of",
 		},
+		{
+			description: "unmarshal with schedule",
+			query: `{
+				"expression" : "A",
+				"type": "threshold",
+				"conditions": [{
+					"evaluator": {
+						"type": "gte",
+						"params": [20],
+						"schedule": [{
+							"timeIntervals": [{"weekdays": ["saturday", "sunday"]}],
+							"params": [50]
+						}]
+					}
+				}]
+			}`,
+			assert: func(t *testing.T, command Command) {
+				require.IsType(t, &ThresholdCommand{}, command)
+				cmd := command.(*ThresholdCommand)
+				require.Equal(t, ThresholdIsAboveOrEqual, cmd.ThresholdFunc)
+				require.Equal(t, greaterThanOrEqualPredicate{20.0}, cmd.predicate)
+				require.Len(t, cmd.schedule, 1)
+				require.Equal(t, greaterThanOrEqualPredicate{50.0}, cmd.schedule[0].predicate)
+			},
+		},
+		{
+			description: "unmarshal with invalid schedule params should error",
+			query: `{
+				"expression" : "A",
+				"type": "threshold",
+				"conditions": [{
+					"evaluator": {
+						"type": "within_range",
+						"params": [20, 80],
+						"schedule": [{
+							"timeIntervals": [{"weekdays": ["saturday"]}],
+							"params": [50]
+						}]
+					}
+				}]
+			}`,
+			shouldError:   true,
+			expectedError: "schedule entry 0: incorrect number of arguments",
+		},
+		{
+			description: "unmarshal with invalid schedule time interval should error",
+			query: `{
+				"expression" : "A",
+				"type": "threshold",
+				"conditions": [{
+					"evaluator": {
+						"type": "gt",
+						"params": [20],
+						"schedule": [{
+							"timeIntervals": [{"weekdays": ["someday"]}],
+							"params": [50]
+						}]
+					}
+				}]
+			}`,
+			shouldError:   true,
+			expectedError: "schedule entry 0: invalid time interval",
+		},
+		{
+			description: "unmarshal with schedule without time intervals should error",
+			query: `{
+				"expression" : "A",
+				"type": "threshold",
+				"conditions": [{
+					"evaluator": {
+						"type": "gt",
+						"params": [20],
+						"schedule": [{
+							"params": [50]
+						}]
+					}
+				}]
+			}`,
+			shouldError:   true,
+			expectedError: "schedule entry 0: at least one time interval is required",
+		},
 		{
 			description: "unmarshal with bad expression",
 			query: `{
@@ -257,6 +364,22 @@ func TestIsSupportedThresholdFunc(t *testing.T) {
 			function:  ThresholdIsOutsideRange,
 			supported: true,
 		},
+		{
+			function:  ThresholdIsAboveOrEqual,
+			supported: true,
+		},
+		{
+			function:  ThresholdIsBelowOrEqual,
+			supported: true,
+		},
+		{
+			function:  ThresholdIsEqual,
+			supported: true,
+		},
+		{
+			function:  ThresholdIsNotEqual,
+			supported: true,
+		},
 		{
 			function:  "foo",
 			supported: false,
@@ -557,3 +680,133 @@ func TestThresholdExecute(t *testing.T) {
 		})
 	}
 }
+
+func TestThresholdExecuteComparisonPredicates(t *testing.T) {
+	testCases := []struct {
+		name     string
+		pred     predicate
+		expected mathexp.Series
+	}{
+		{
+			name:     "greater than or equal 10",
+			pred:     greaterThanOrEqualPredicate{10.0},
+			expected: newSeries(0, 0, 1, 1, 1),
+		},
+		{
+			name:     "less than or equal 10",
+			pred:     lessThanOrEqualPredicate{10.0},
+			expected: newSeries(1, 1, 1, 0, 0),
+		},
+		{
+			name:     "equal 10",
+			pred:     equalPredicate{10.0},
+			expected: newSeries(0, 0, 1, 0, 0),
+		},
+		{
+			name:     "not equal 10",
+			pred:     notEqualPredicate{10.0},
+			expected: newSeries(1, 1, 0, 1, 1),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := ThresholdCommand{
+				predicate:    tc.pred,
+				ReferenceVar: "A",
+			}
+			result, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+				"A": newResults(newSeries(8, 9, 10, 11, 12)),
+			}, tracing.InitializeTracerForTest())
+			require.NoError(t, err)
+			require.Equal(t, newResults(tc.expected), result)
+		})
+	}
+}
+
+func TestThresholdExecuteWithSchedule(t *testing.T) {
+	cmd, err := NewThresholdCommandFromEvaluator("", "A", ConditionEvalJSON{
+		Type:   ThresholdIsAbove,
+		Params: []float64{10},
+		Schedule: []ThresholdScheduleJSON{
+			{
+				TimeIntervals: []ThresholdTimeIntervalJSON{{
+					Weekdays: []string{"saturday", "sunday"},
+				}},
+				Params: []float64{100},
+			},
+			{
+				TimeIntervals: []ThresholdTimeIntervalJSON{{
+					Times: []ThresholdTimeRangeJSON{{StartTime: "00:00", EndTime: "06:00"}},
+				}},
+				Params: []float64{50},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	// Wednesday
+	weekday := time.Date(2024, 1, 3, 12, 0, 0, 0, time.UTC)
+	night := time.Date(2024, 1, 3, 3, 0, 0, 0, time.UTC)
+	// Saturday, the first matching entry wins
+	weekend := time.Date(2024, 1, 6, 3, 0, 0, 0, time.UTC)
+
+	t.Run("numbers are evaluated at the evaluation time", func(t *testing.T) {
+		cases := map[time.Time]float64{
+			weekday: 1,
+			night:   1,
+			weekend: 0,
+		}
+		for now, expected := range cases {
+			result, err := cmd.Execute(context.Background(), now, mathexp.Vars{
+				"A": newResults(newNumber(nil, util.Pointer(float64(75)))),
+			}, tracing.InitializeTracerForTest())
+			require.NoError(t, err)
+			require.Equal(t, newResults(newNumber(nil, util.Pointer(expected))), result)
+		}
+
+		result, err := cmd.Execute(context.Background(), night, mathexp.Vars{
+			"A": newResults(newNumber(nil, util.Pointer(float64(25)))),
+		}, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Equal(t, newResults(newNumber(nil, util.Pointer(float64(0)))), result)
+	})
+
+	t.Run("series are evaluated at the time of each point", func(t *testing.T) {
+		input := mathexp.NewSeries("", nil, 3)
+		input.SetPoint(0, weekday, util.Pointer(float64(75)))
+		input.SetPoint(1, night, util.Pointer(float64(75)))
+		input.SetPoint(2, weekend, util.Pointer(float64(75)))
+
+		result, err := cmd.Execute(context.Background(), weekday, mathexp.Vars{
+			"A": newResults(input),
+		}, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Len(t, result.Values, 1)
+		s := result.Values[0].(mathexp.Series)
+		for i, expected := range []float64{1, 1, 0} {
+			_, v := s.GetPoint(i)
+			require.Equal(t, expected, *v)
+		}
+	})
+
+	t.Run("location of the time interval is respected", func(t *testing.T) {
+		cmd, err := NewThresholdCommandFromEvaluator("", "A", ConditionEvalJSON{
+			Type:   ThresholdIsAbove,
+			Params: []float64{10},
+			Schedule: []ThresholdScheduleJSON{{
+				TimeIntervals: []ThresholdTimeIntervalJSON{{
+					Times:    []ThresholdTimeRangeJSON{{StartTime: "09:00", EndTime: "17:00"}},
+					Location: "Asia/Tokyo",
+				}},
+				Params: []float64{100},
+			}},
+		})
+		require.NoError(t, err)
+		// 12:00 UTC is 21:00 in Tokyo, so the default params apply
+		result, err := cmd.Execute(context.Background(), weekday, mathexp.Vars{
+			"A": newResults(newNumber(nil, util.Pointer(float64(75)))),
+		}, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Equal(t, newResults(newNumber(nil, util.Pointer(float64(1)))), result)
+	})
+}
@@ -484,6 +484,12 @@ const (
 
 	// Usage stats actions
 	ActionUsageStatsRead = "server.usagestats.report:read"
+
+	// Live pipeline actions
+	ActionLiveChannelRulesRead  = "live.channel-rules:read"
+	ActionLiveChannelRulesWrite = "live.channel-rules:write"
+	ActionLiveWriteConfigsRead  = "live.write-configs:read"
+	ActionLiveWriteConfigsWrite = "live.write-configs:write"
 )
 
 var (
@@ -11,6 +11,8 @@ import (
 var (
 	ErrTimerangeMissing     = errors.New("missing timerange")
 	ErrBaseTagLimitExceeded = errutil.BadRequest("annotations.tag-limit-exceeded", errutil.WithPublicMessage("Tags length exceeds the maximum allowed."))
+	ErrInvalidCursor        = errutil.BadRequest("annotations.invalid-cursor", errutil.WithPublicMessage("Invalid cursor."))
+	ErrInvalidCountQuery    = errutil.BadRequest("annotations.invalid-count-query")
 )
 
 //go:generate mockery --name Repository --structname FakeAnnotationsRepo --inpackage --filename annotations_repository_mock.go
@@ -21,9 +23,15 @@ type Repository interface {
 	Find(ctx context.Context, query *ItemQuery) ([]*ItemDTO, error)
 	Delete(ctx context.Context, params *DeleteParams) error
 	FindTags(ctx context.Context, query *TagsQuery) (FindTagsResult, error)
+	Count(ctx context.Context, query *CountQuery) (CountResult, error)
 }
 
 // Cleaner is responsible for cleaning up old annotations
 type Cleaner interface {
 	Run(ctx context.Context, cfg *setting.Cfg) (int64, int64, error)
+	// RunRetentionRules deletes the annotations exceeding the limits of the
+	// retention rules from the configuration and from provisioning.
+	RunRetentionRules(ctx context.Context, cfg *setting.Cfg) (int64, int64, error)
+	// SetProvisionedRetentionRules replaces the retention rules read from provisioning.
+	SetProvisionedRetentionRules(rules []setting.AnnotationRetentionRule)
 }
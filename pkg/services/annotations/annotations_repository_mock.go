@@ -14,6 +14,27 @@ type FakeAnnotationsRepo struct {
 	mock.Mock
 }
 
+// Count provides a mock function with given fields: ctx, query
+func (_m *FakeAnnotationsRepo) Count(ctx context.Context, query *CountQuery) (CountResult, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 CountResult
+	if rf, ok := ret.Get(0).(func(context.Context, *CountQuery) CountResult); ok {
+		r0 = rf(ctx, query)
+	} else {
+		r0 = ret.Get(0).(CountResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *CountQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Delete provides a mock function with given fields: ctx, params
 func (_m *FakeAnnotationsRepo) Delete(ctx context.Context, params *DeleteParams) error {
 	ret := _m.Called(ctx, params)
@@ -78,6 +78,19 @@ func (r *RepositoryImpl) Find(ctx context.Context, query *annotations.ItemQuery)
 	return r.reader.Get(ctx, query, resources)
 }
 
+func (r *RepositoryImpl) Count(ctx context.Context, query *annotations.CountQuery) (annotations.CountResult, error) {
+	if err := query.Validate(); err != nil {
+		return annotations.CountResult{}, err
+	}
+
+	resources, err := r.authZ.Authorize(ctx, query.OrgID, &query.ItemQuery)
+	if err != nil {
+		return annotations.CountResult{Counts: make([]*annotations.CountDTO, 0)}, err
+	}
+
+	return r.reader.Count(ctx, query, resources)
+}
+
 func (r *RepositoryImpl) Delete(ctx context.Context, params *annotations.DeleteParams) error {
 	return r.writer.Delete(ctx, params)
 }
@@ -2,6 +2,9 @@ package annotationsimpl
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"sync"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -11,6 +14,9 @@ import (
 // CleanupServiceImpl is responsible for cleaning old annotations.
 type CleanupServiceImpl struct {
 	store store
+
+	mtx                       sync.Mutex
+	provisionedRetentionRules []setting.AnnotationRetentionRule
 }
 
 func ProvideCleanupService(db db.DB, cfg *setting.Cfg) *CleanupServiceImpl {
@@ -56,3 +62,38 @@ func (cs *CleanupServiceImpl) Run(ctx context.Context, cfg *setting.Cfg) (int64,
 	}
 	return totalCleanedAnnotations, affected, err
 }
+
+// RunRetentionRules deletes the annotations matching the retention rules from
+// the configuration and from provisioning that are older than the max age or
+// exceed the max count of the rule. The rules are applied one after the other,
+// so an annotation matching several rules is kept only if all of them keep it.
+//
+// Returns the number of annotation and annotation_tag rows deleted. If an
+// error occurs, it returns the number of rows affected so far.
+func (cs *CleanupServiceImpl) RunRetentionRules(ctx context.Context, cfg *setting.Cfg) (int64, int64, error) {
+	cs.mtx.Lock()
+	rules := append(slices.Clone(cfg.AnnotationRetentionRules), cs.provisionedRetentionRules...)
+	cs.mtx.Unlock()
+
+	var totalCleanedAnnotations int64
+	for _, rule := range rules {
+		affected, err := cs.store.CleanAnnotationsByRule(ctx, rule)
+		totalCleanedAnnotations += affected
+		if err != nil {
+			return totalCleanedAnnotations, 0, fmt.Errorf("failed to apply retention rule %q: %w", rule.Name, err)
+		}
+	}
+
+	var affected int64
+	var err error
+	if totalCleanedAnnotations > 0 {
+		affected, err = cs.store.CleanOrphanedAnnotationTags(ctx)
+	}
+	return totalCleanedAnnotations, affected, err
+}
+
+func (cs *CleanupServiceImpl) SetProvisionedRetentionRules(rules []setting.AnnotationRetentionRule) {
+	cs.mtx.Lock()
+	defer cs.mtx.Unlock()
+	cs.provisionedRetentionRules = rules
+}
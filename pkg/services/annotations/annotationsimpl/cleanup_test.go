@@ -9,9 +9,14 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/annotations/testutil"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/tag/tagimpl"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -205,6 +210,79 @@ func TestIntegrationOldAnnotationsAreDeletedFirst(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestIntegrationAnnotationRetentionRules(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	sql := db.InitTestReplDB(t)
+	cfg := setting.NewCfg()
+	cfg.AnnotationCleanupJobBatchSize = 2
+	cfg.AnnotationMaximumTagsLength = 60
+	features := featuremgmt.WithFeatures()
+
+	dashboard := testutil.CreateDashboard(t, sql, cfg, features, dashboards.SaveDashboardCommand{
+		UserID:    1,
+		OrgID:     1,
+		Dashboard: simplejson.NewFromAny(map[string]any{"title": "Dashboard 1"}),
+	})
+
+	store := NewXormStore(cfg, log.New("annotation.test"), sql, tagimpl.ProvideService(sql))
+	old := time.Now().AddDate(0, 0, -10).UnixMilli()
+	items := []*annotations.Item{
+		// deploy annotations, only the ones of org 1 that are newer than a week are kept
+		{OrgID: 1, Epoch: 1, Created: old, Tags: []string{"deploy", "env:prod"}},
+		{OrgID: 1, Epoch: 1, Tags: []string{"deploy", "env:prod"}},
+		{OrgID: 1, Epoch: 1, Created: old, Tags: []string{"deploy"}},
+		{OrgID: 2, Epoch: 1, Created: old, Tags: []string{"deploy", "env:prod"}},
+		// dashboard annotations, only the last two are kept
+		{OrgID: 1, Epoch: 1, DashboardID: dashboard.ID, Tags: []string{"other"}},
+		{OrgID: 1, Epoch: 1, DashboardID: dashboard.ID},
+		{OrgID: 1, Epoch: 1, DashboardID: dashboard.ID},
+		{OrgID: 1, Epoch: 1, DashboardID: dashboard.ID + 1},
+	}
+	for _, item := range items {
+		created := item.Created
+		require.NoError(t, store.Add(context.Background(), item))
+		if created != 0 {
+			err := sql.WithDbSession(context.Background(), func(sess *db.Session) error {
+				_, err := sess.Exec("UPDATE annotation SET created = ? WHERE id = ?", created, item.ID)
+				return err
+			})
+			require.NoError(t, err)
+		}
+	}
+
+	cleaner := &CleanupServiceImpl{store: store}
+	cfg.AnnotationRetentionRules = []setting.AnnotationRetentionRule{
+		{
+			Name:                      "deployments",
+			OrgID:                     1,
+			Tags:                      []string{"deploy", "env:prod"},
+			AnnotationCleanupSettings: settingsFn(7*24*time.Hour, 0),
+		},
+	}
+	cleaner.SetProvisionedRetentionRules([]setting.AnnotationRetentionRule{
+		{
+			Name:                      "dashboard",
+			DashboardUID:              dashboard.UID,
+			AnnotationCleanupSettings: settingsFn(0, 2),
+		},
+	})
+
+	affected, affectedTags, err := cleaner.RunRetentionRules(context.Background(), cfg)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), affected)
+	require.Equal(t, int64(3), affectedTags)
+
+	remaining := make([]int64, 0)
+	err = sql.WithDbSession(context.Background(), func(sess *db.Session) error {
+		return sess.SQL("SELECT id FROM annotation ORDER BY id").Find(&remaining)
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int64{items[1].ID, items[2].ID, items[3].ID, items[5].ID, items[6].ID, items[7].ID}, remaining)
+}
+
 func assertAnnotationCount(t *testing.T, fakeSQL db.DB, sql string, expectedCount int64) {
 	t.Helper()
 
@@ -52,6 +52,12 @@ func (c *CompositeStore) Get(ctx context.Context, query *annotations.ItemQuery,
 	}
 	sort.Sort(annotations.SortedItems(res))
 
+	// each store returns up to the limit of items, the items after the limit might be missing
+	// items of other stores and must not be returned, otherwise pagination would skip them.
+	if query != nil && query.Limit > 0 && int64(len(res)) > query.Limit {
+		res = res[:query.Limit]
+	}
+
 	return res, nil
 }
 
@@ -80,6 +86,30 @@ func (c *CompositeStore) GetTags(ctx context.Context, query *annotations.TagsQue
 	return annotations.FindTagsResult{Tags: res}, nil
 }
 
+// Count returns the counts of annotations from all stores, and combines the results.
+func (c *CompositeStore) Count(ctx context.Context, query *annotations.CountQuery, accessResources *accesscontrol.AccessResources) (annotations.CountResult, error) {
+	resCh := make(chan annotations.CountResult, len(c.readers))
+
+	err := concurrency.ForEachJob(ctx, len(c.readers), len(c.readers), func(ctx context.Context, i int) (err error) {
+		defer handleJobPanic(c.logger, c.readers[i].Type(), &err)
+
+		res, err := c.readers[i].Count(ctx, query, accessResources)
+		resCh <- res
+		return err
+	})
+	if err != nil {
+		return annotations.CountResult{}, err
+	}
+
+	close(resCh)
+	counts := make([][]*annotations.CountDTO, 0, len(c.readers))
+	for r := range resCh {
+		counts = append(counts, r.Counts)
+	}
+
+	return annotations.CountResult{Counts: annotations.MergeCounts(query.GroupBy, counts...)}, nil
+}
+
 // handleJobPanic is a helper function that recovers from a panic in a concurrent job.,
 // It will log the error and set the job error if it is not nil.
 func handleJobPanic(logger log.Logger, storeType string, jobErr *error) {
@@ -97,6 +97,44 @@ func TestCompositeStore(t *testing.T) {
 		require.Equal(t, expected, items)
 	})
 
+	t.Run("should not return more items than the limit", func(t *testing.T) {
+		r1 := newFakeReader(withItems([]*annotations.ItemDTO{{ID: 3, TimeEnd: 3}, {ID: 1, TimeEnd: 1}}))
+		r2 := newFakeReader(withItems([]*annotations.ItemDTO{{TimeEnd: 4}, {TimeEnd: 2}}))
+
+		store := &CompositeStore{
+			log.NewNopLogger(),
+			[]readStore{r1, r2},
+		}
+
+		items, err := store.Get(context.Background(), &annotations.ItemQuery{Limit: 2}, nil)
+		require.NoError(t, err)
+		require.Equal(t, []*annotations.ItemDTO{{TimeEnd: 4}, {ID: 3, TimeEnd: 3}}, items)
+	})
+
+	t.Run("should combine and sort results from Count", func(t *testing.T) {
+		r1 := newFakeReader(withCounts([]*annotations.CountDTO{
+			{DashboardUID: "a", Count: 1},
+			{DashboardUID: "b", Count: 2},
+		}))
+		r2 := newFakeReader(withCounts([]*annotations.CountDTO{
+			{DashboardUID: "a", Count: 3},
+			{DashboardUID: "c", Count: 1},
+		}))
+
+		store := &CompositeStore{
+			log.NewNopLogger(),
+			[]readStore{r1, r2},
+		}
+
+		res, err := store.Count(context.Background(), &annotations.CountQuery{GroupBy: annotations.CountGroupByDashboard}, nil)
+		require.NoError(t, err)
+		require.Equal(t, []*annotations.CountDTO{
+			{DashboardUID: "a", Count: 4},
+			{DashboardUID: "b", Count: 2},
+			{DashboardUID: "c", Count: 1},
+		}, res.Counts)
+	})
+
 	t.Run("should combine and sort results from GetTags", func(t *testing.T) {
 		tags1 := []*annotations.TagsDTO{
 			{Tag: "key1:val1"},
@@ -130,6 +168,7 @@ func TestCompositeStore(t *testing.T) {
 type fakeReader struct {
 	items    []*annotations.ItemDTO
 	tagRes   annotations.FindTagsResult
+	countRes annotations.CountResult
 	getFn    func(context.Context, *annotations.ItemQuery, *accesscontrol.AccessResources) ([]*annotations.ItemDTO, error)
 	getTagFn func(context.Context, *annotations.TagsQuery) (annotations.FindTagsResult, error)
 	wait     time.Duration
@@ -174,6 +213,14 @@ func (f *fakeReader) GetTags(ctx context.Context, query *annotations.TagsQuery)
 	return f.tagRes, nil
 }
 
+func (f *fakeReader) Count(ctx context.Context, query *annotations.CountQuery, accessResources *accesscontrol.AccessResources) (annotations.CountResult, error) {
+	if f.err != nil {
+		return annotations.CountResult{}, f.err
+	}
+
+	return f.countRes, nil
+}
+
 func withWait(wait time.Duration) func(*fakeReader) {
 	return func(f *fakeReader) {
 		f.wait = wait
@@ -192,6 +239,12 @@ func withItems(items []*annotations.ItemDTO) func(*fakeReader) {
 	}
 }
 
+func withCounts(counts []*annotations.CountDTO) func(*fakeReader) {
+	return func(f *fakeReader) {
+		f.countRes = annotations.CountResult{Counts: counts}
+	}
+}
+
 func withTags(tags []*annotations.TagsDTO) func(*fakeReader) {
 	return func(f *fakeReader) {
 		f.tagRes = annotations.FindTagsResult{Tags: tags}
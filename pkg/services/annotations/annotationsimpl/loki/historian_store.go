@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"golang.org/x/exp/constraints"
@@ -34,6 +35,10 @@ import (
 const (
 	subsystem         = "annotations"
 	defaultQueryRange = 6 * time.Hour // from grafana/pkg/services/ngalert/state/historian/loki.go
+	// defaultPageSize and maximumPageSize are the default and maximum number of entries queried at once,
+	// from grafana/pkg/services/ngalert/state/historian/loki_http.go
+	defaultPageSize = 1000
+	maximumPageSize = 5000
 )
 
 var (
@@ -77,6 +82,22 @@ func (r *LokiHistorianStore) Type() string {
 }
 
 func (r *LokiHistorianStore) Get(ctx context.Context, query *annotations.ItemQuery, accessResources *accesscontrol.AccessResources) ([]*annotations.ItemDTO, error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	items, err := r.getItems(ctx, query, accessResources, limit)
+	if err != nil {
+		return make([]*annotations.ItemDTO, 0), err
+	}
+	if int64(len(items)) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+// getItems returns at least limit annotations that match the query if there are as many, or all of them if limit is negative.
+func (r *LokiHistorianStore) getItems(ctx context.Context, query *annotations.ItemQuery, accessResources *accesscontrol.AccessResources, limit int64) ([]*annotations.ItemDTO, error) {
 	if query.Type == "annotation" {
 		return make([]*annotations.ItemDTO, 0), nil
 	}
@@ -112,6 +133,15 @@ func (r *LokiHistorianStore) Get(ctx context.Context, query *annotations.ItemQue
 		r.log.FromContext(ctx).Info("Execute query in multiple batches", "batches", logQL, "maxQueryLimit", r.client.MaxQuerySize())
 	}
 
+	var cursor *annotations.Cursor
+	if query.Cursor != "" {
+		c, err := annotations.ParseCursor(query.Cursor)
+		if err != nil {
+			return make([]*annotations.ItemDTO, 0), err
+		}
+		cursor = &c
+	}
+
 	now := time.Now().UTC()
 	if query.To == 0 {
 		query.To = now.UnixMilli()
@@ -123,18 +153,143 @@ func (r *LokiHistorianStore) Get(ctx context.Context, query *annotations.ItemQue
 	// query.From and query.To are always in milliseconds, convert them to nanoseconds for loki
 	from := query.From * 1e6
 	to := query.To * 1e6
+	if cursor != nil && cursor.TimeEnd < query.To {
+		// entries are returned in descending order of time, so the following entries are not newer than the cursor.
+		// The end of the range is exclusive, include the entries at the time of the cursor.
+		to = (cursor.TimeEnd + 1) * 1e6
+	}
+	return r.find(ctx, logQL, from, to, itemFilter(cursor, query.Text), *accessResources, limit)
+}
+
+// itemFilter returns a function that returns true for the annotations after the cursor
+// that contain all the words of the text.
+func itemFilter(cursor *annotations.Cursor, text string) func(*annotations.ItemDTO) bool {
+	words := strings.Fields(strings.ToLower(text))
+	return func(item *annotations.ItemDTO) bool {
+		if cursor != nil && !cursor.Includes(item) {
+			return false
+		}
+		return containsWords(item.Text, words)
+	}
+}
+
+// find queries the annotations that match the filter, sorted in descending order.
+// Each query is paged through until it has limit matching annotations, or until
+// the last page if limit is negative. Entries are filtered after they are queried,
+// so a page of entries can have less matching annotations than its size.
+func (r *LokiHistorianStore) find(ctx context.Context, logQL []string, from, to int64, filter func(*annotations.ItemDTO) bool, ac accesscontrol.AccessResources, limit int64) ([]*annotations.ItemDTO, error) {
+	pageSize := int64(maximumPageSize)
+	if limit > 0 && limit < pageSize {
+		pageSize = limit
+	}
+
 	items := make([]*annotations.ItemDTO, 0)
 	for _, q := range logQL {
-		res, err := r.client.RangeQuery(ctx, q, from, to, query.Limit)
-		if err != nil {
-			return make([]*annotations.ItemDTO, 0), ErrLokiStoreInternal.Errorf("failed to query loki: %w", err)
-		}
-		for _, stream := range res.Data.Result {
-			items = append(items, r.annotationsFromStream(stream, *accessResources)...)
+		end := to
+		// seen are the entries at the time of the oldest entry of the previous page. The end of the range is
+		// exclusive, the next page starts at that time so that other entries at the same time are not missed.
+		seen := map[string]struct{}{}
+		matched := int64(0)
+		for limit < 0 || matched < limit {
+			res, err := r.client.RangeQuery(ctx, q, from, end, pageSize)
+			if err != nil {
+				return nil, ErrLokiStoreInternal.Errorf("failed to query loki: %w", err)
+			}
+
+			entries := int64(0)
+			oldest := end
+			oldestSeen := map[string]struct{}{}
+			for _, stream := range res.Data.Result {
+				entries += int64(len(stream.Values))
+				values := make([]historian.Sample, 0, len(stream.Values))
+				for _, sample := range stream.Values {
+					key := fmt.Sprint(stream.Stream, sample.V)
+					ts := sample.T.UnixNano()
+					if ts < oldest {
+						oldest = ts
+						oldestSeen = map[string]struct{}{}
+					}
+					if ts == oldest {
+						oldestSeen[key] = struct{}{}
+					}
+					if _, ok := seen[key]; ok && ts == end-1 {
+						continue
+					}
+					values = append(values, sample)
+				}
+				for _, item := range r.annotationsFromStream(historian.Stream{Stream: stream.Stream, Values: values}, ac) {
+					if !filter(item) {
+						continue
+					}
+					items = append(items, item)
+					matched++
+				}
+			}
+
+			// a short page is the last page
+			if entries < pageSize {
+				break
+			}
+			if oldest == end-1 {
+				// the page only has entries at the start of the range, stop when there are no new ones.
+				n := len(seen)
+				for key := range oldestSeen {
+					seen[key] = struct{}{}
+				}
+				if len(seen) == n {
+					break
+				}
+			} else {
+				seen = oldestSeen
+			}
+			end = oldest + 1
 		}
 	}
 	sort.Sort(annotations.SortedItems(items))
-	return items, err
+	return items, nil
+}
+
+// Count counts the annotations of alert state history. State history does not have tags,
+// so nothing is counted when grouping by tag.
+func (r *LokiHistorianStore) Count(ctx context.Context, query *annotations.CountQuery, accessResources *accesscontrol.AccessResources) (annotations.CountResult, error) {
+	res := annotations.CountResult{Counts: make([]*annotations.CountDTO, 0)}
+	if query.GroupBy == annotations.CountGroupByTag {
+		return res, nil
+	}
+
+	itemQuery := query.ItemQuery
+	itemQuery.Cursor = ""
+	items, err := r.getItems(ctx, &itemQuery, accessResources, -1)
+	if err != nil {
+		return res, err
+	}
+
+	counts := make([]*annotations.CountDTO, 0, len(items))
+	for _, item := range items {
+		count := &annotations.CountDTO{Count: 1}
+		switch query.GroupBy {
+		case annotations.CountGroupByDashboard:
+			if item.DashboardUID != nil {
+				count.DashboardUID = *item.DashboardUID
+			}
+		case annotations.CountGroupByTime:
+			count.Time = item.Time / query.Interval * query.Interval
+		}
+		counts = append(counts, count)
+	}
+	res.Counts = annotations.MergeCounts(query.GroupBy, counts)
+	return res, nil
+}
+
+// containsWords returns true if the text contains all the words, which must be lower case.
+func containsWords(text string, words []string) bool {
+	text = strings.ToLower(text)
+	for _, word := range words {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
 }
 
 func (r *LokiHistorianStore) annotationsFromStream(stream historian.Stream, ac accesscontrol.AccessResources) []*annotations.ItemDTO {
@@ -180,6 +335,7 @@ func (r *LokiHistorianStore) annotationsFromStream(stream historian.Stream, ac a
 			NewState:     entry.Current,
 			PrevState:    entry.Previous,
 			Time:         sample.T.UnixMilli(),
+			TimeEnd:      sample.T.UnixMilli(),
 			Text:         annotationText,
 			Data:         annotationData,
 		})
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"math/rand"
 	"net/url"
+	"sort"
 	"strconv"
 	"testing"
 	"time"
@@ -273,6 +274,148 @@ func TestIntegrationAlertStateHistoryStore(t *testing.T) {
 			require.NoError(t, err)
 			require.Empty(t, res)
 		})
+
+		accessResources := &annotation_ac.AccessResources{
+			Dashboards: map[string]int64{
+				dashboard1.UID: dashboard1.ID,
+			},
+			CanAccessDashAnnotations: true,
+		}
+		dashboard1Streams := func() []historian.Stream {
+			return []historian.Stream{
+				historian.StatesToStream(ruleMetaFromRule(t, dashboardRules[dashboard1.UID][0]), transitions, map[string]string{}, log.NewNopLogger()),
+				historian.StatesToStream(ruleMetaFromRule(t, dashboardRules[dashboard1.UID][1]), transitions, map[string]string{}, log.NewNopLogger()),
+			}
+		}
+
+		t.Run("should filter history by text", func(t *testing.T) {
+			// only the second rule has a title in the annotation text
+			rule := dashboardRules[dashboard1.UID][1]
+			meta := ruleMetaFromRule(t, rule)
+			meta.Title = rule.Title
+			fakeLokiClient.rangeQueryRes = []historian.Stream{
+				historian.StatesToStream(ruleMetaFromRule(t, dashboardRules[dashboard1.UID][0]), transitions, map[string]string{}, log.NewNopLogger()),
+				historian.StatesToStream(meta, transitions, map[string]string{}, log.NewNopLogger()),
+			}
+
+			query := annotations.ItemQuery{
+				OrgID:       1,
+				DashboardID: dashboard1.ID,
+				From:        start.UnixMilli(),
+				To:          start.Add(time.Second * time.Duration(numTransitions+1)).UnixMilli(),
+				Text:        "test RULE",
+			}
+			res, err := store.Get(context.Background(), &query, accessResources)
+			require.NoError(t, err)
+			require.Len(t, res, numTransitions)
+			for _, item := range res {
+				require.Contains(t, item.Text, "Test Rule 2")
+			}
+		})
+
+		t.Run("should query more entries until the limit of history matches the text", func(t *testing.T) {
+			// the entries of the first rule are newer and do not match the text
+			rule := dashboardRules[dashboard1.UID][1]
+			meta := ruleMetaFromRule(t, rule)
+			meta.Title = rule.Title
+			fakeLokiClient.rangeQueryRes = []historian.Stream{
+				historian.StatesToStream(ruleMetaFromRule(t, dashboardRules[dashboard1.UID][0]), genStateTransitions(t, 5, start.Add(10*time.Second)), map[string]string{}, log.NewNopLogger()),
+				historian.StatesToStream(meta, genStateTransitions(t, 3, start), map[string]string{}, log.NewNopLogger()),
+			}
+			fakeLokiClient.keepRangeQueryRes = true
+			fakeLokiClient.rangeQueries = 0
+			t.Cleanup(func() {
+				fakeLokiClient.keepRangeQueryRes = false
+			})
+
+			query := annotations.ItemQuery{
+				OrgID:       1,
+				DashboardID: dashboard1.ID,
+				From:        start.UnixMilli(),
+				To:          start.Add(time.Minute).UnixMilli(),
+				Text:        "test RULE 2",
+				Limit:       2,
+			}
+			res, err := store.Get(context.Background(), &query, accessResources)
+			require.NoError(t, err)
+			require.Len(t, res, 2)
+			for _, item := range res {
+				require.Contains(t, item.Text, "Test Rule 2")
+			}
+			require.Greater(t, fakeLokiClient.rangeQueries, 1)
+
+			query.Cursor = annotations.NextCursor(res, query.Limit)
+			res, err = store.Get(context.Background(), &query, accessResources)
+			require.NoError(t, err)
+			require.Len(t, res, 1)
+			require.Contains(t, res[0].Text, "Test Rule 2")
+		})
+
+		t.Run("should count history on all pages", func(t *testing.T) {
+			manyTransitions := genStateTransitions(t, maximumPageSize+1, start)
+			for i := range manyTransitions {
+				manyTransitions[i].State.LastEvaluationTime = start.Add(time.Duration(i) * time.Millisecond)
+			}
+			fakeLokiClient.rangeQueryRes = []historian.Stream{
+				historian.StatesToStream(ruleMetaFromRule(t, dashboardRules[dashboard1.UID][0]), manyTransitions, map[string]string{}, log.NewNopLogger()),
+			}
+			fakeLokiClient.keepRangeQueryRes = true
+			t.Cleanup(func() {
+				fakeLokiClient.keepRangeQueryRes = false
+			})
+
+			query := annotations.CountQuery{
+				ItemQuery: annotations.ItemQuery{
+					OrgID: 1,
+					From:  start.UnixMilli(),
+					To:    start.Add(time.Minute).UnixMilli(),
+				},
+				GroupBy: annotations.CountGroupByDashboard,
+			}
+			res, err := store.Count(context.Background(), &query, accessResources)
+			require.NoError(t, err)
+			require.Equal(t, []*annotations.CountDTO{{DashboardUID: dashboard1.UID, Count: maximumPageSize + 1}}, res.Counts)
+		})
+
+		t.Run("should return history after cursor", func(t *testing.T) {
+			query := annotations.ItemQuery{
+				OrgID:       1,
+				DashboardID: dashboard1.ID,
+				From:        start.UnixMilli(),
+				To:          start.Add(time.Second * time.Duration(numTransitions+1)).UnixMilli(),
+			}
+			fakeLokiClient.rangeQueryRes = dashboard1Streams()
+			all, err := store.Get(context.Background(), &query, accessResources)
+			require.NoError(t, err)
+			require.Len(t, all, 2*numTransitions)
+
+			query.Cursor = annotations.CursorFromItem(all[1]).String()
+			fakeLokiClient.rangeQueryRes = dashboard1Streams()
+			res, err := store.Get(context.Background(), &query, accessResources)
+			require.NoError(t, err)
+			require.Equal(t, all[2:], res)
+		})
+
+		t.Run("can count history by dashboard", func(t *testing.T) {
+			fakeLokiClient.rangeQueryRes = dashboard1Streams()
+
+			query := annotations.CountQuery{
+				ItemQuery: annotations.ItemQuery{
+					OrgID: 1,
+					From:  start.UnixMilli(),
+					To:    start.Add(time.Second * time.Duration(numTransitions+1)).UnixMilli(),
+				},
+				GroupBy: annotations.CountGroupByDashboard,
+			}
+			res, err := store.Count(context.Background(), &query, accessResources)
+			require.NoError(t, err)
+			require.Equal(t, []*annotations.CountDTO{{DashboardUID: dashboard1.UID, Count: 2 * int64(numTransitions)}}, res.Counts)
+
+			query.GroupBy = annotations.CountGroupByTag
+			res, err = store.Count(context.Background(), &query, accessResources)
+			require.NoError(t, err)
+			require.Empty(t, res.Counts)
+		})
 	})
 
 	t.Run("Testing items from Loki stream", func(t *testing.T) {
@@ -317,6 +460,7 @@ func TestIntegrationAlertStateHistoryStore(t *testing.T) {
 					DashboardUID: &dashboard1.UID,
 					PanelID:      *rule.PanelID,
 					Time:         transition.State.LastEvaluationTime.UnixMilli(),
+					TimeEnd:      transition.State.LastEvaluationTime.UnixMilli(),
 					NewState:     transition.Formatted(),
 				}
 				if i > 0 {
@@ -763,6 +907,10 @@ type FakeLokiClient struct {
 	metrics       *metrics.Historian
 	log           log.Logger
 	rangeQueryRes []historian.Stream
+	// keepRangeQueryRes keeps rangeQueryRes after a query, to query it again with another range.
+	keepRangeQueryRes bool
+	// rangeQueries is the number of range queries.
+	rangeQueries int
 }
 
 func NewFakeLokiClient() *FakeLokiClient {
@@ -785,11 +933,30 @@ func NewFakeLokiClient() *FakeLokiClient {
 }
 
 func (c *FakeLokiClient) RangeQuery(ctx context.Context, query string, from, to, limit int64) (historian.QueryRes, error) {
+	c.rangeQueries++
 	streams := make([]historian.Stream, len(c.rangeQueryRes))
 
 	// clamp time range using logic from historian
 	from, to = historian.ClampRange(from, to, c.cfg.MaxQueryLength.Nanoseconds())
 
+	// entries are returned in descending order of time up to the limit, the oldest entries are not returned.
+	var minTime int64
+	if limit > 0 {
+		var times []int64
+		for _, stream := range c.rangeQueryRes {
+			for _, sample := range stream.Values {
+				if sample.T.UnixNano() >= from && sample.T.UnixNano() < to {
+					times = append(times, sample.T.UnixNano())
+				}
+			}
+		}
+		if int64(len(times)) > limit {
+			sort.Slice(times, func(i, j int) bool { return times[i] > times[j] })
+			minTime = times[limit-1]
+		}
+	}
+
+	returned := int64(0)
 	for n, stream := range c.rangeQueryRes {
 		streams[n].Stream = stream.Stream
 		streams[n].Values = []historian.Sample{}
@@ -797,7 +964,11 @@ func (c *FakeLokiClient) RangeQuery(ctx context.Context, query string, from, to,
 			if sample.T.UnixNano() < from || sample.T.UnixNano() >= to { // matches Loki behavior
 				continue
 			}
+			if sample.T.UnixNano() < minTime || limit > 0 && returned >= limit {
+				continue
+			}
 			streams[n].Values = append(streams[n].Values, sample)
+			returned++
 		}
 	}
 
@@ -807,8 +978,10 @@ func (c *FakeLokiClient) RangeQuery(ctx context.Context, query string, from, to,
 		},
 	}
 
-	// reset expected streams on read
-	c.rangeQueryRes = []historian.Stream{}
+	// reset expected streams on read, unless the results are paged through
+	if !c.keepRangeQueryRes {
+		c.rangeQueryRes = []historian.Stream{}
+	}
 	return res, nil
 }
 
@@ -22,6 +22,7 @@ type readStore interface {
 	commonStore
 	Get(ctx context.Context, query *annotations.ItemQuery, accessResources *accesscontrol.AccessResources) ([]*annotations.ItemDTO, error)
 	GetTags(ctx context.Context, query *annotations.TagsQuery) (annotations.FindTagsResult, error)
+	Count(ctx context.Context, query *annotations.CountQuery, accessResources *accesscontrol.AccessResources) (annotations.CountResult, error)
 }
 
 type writeStore interface {
@@ -31,5 +32,6 @@ type writeStore interface {
 	Update(ctx context.Context, item *annotations.Item) error
 	Delete(ctx context.Context, params *annotations.DeleteParams) error
 	CleanAnnotations(ctx context.Context, cfg setting.AnnotationCleanupSettings, annotationType string) (int64, error)
+	CleanAnnotationsByRule(ctx context.Context, rule setting.AnnotationRetentionRule) (int64, error)
 	CleanOrphanedAnnotationTags(ctx context.Context) (int64, error)
 }
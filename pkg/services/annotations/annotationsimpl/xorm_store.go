@@ -275,102 +275,230 @@ func (r *xormRepositoryImpl) Get(ctx context.Context, query *annotations.ItemQue
 				SELECT a.id from annotation a
 			`)
 
-		sql.WriteString(`WHERE a.org_id = ?`)
-		params = append(params, query.OrgID)
-
-		if query.AnnotationID != 0 {
-			// fmt.Print("annotation query")
-			sql.WriteString(` AND a.id = ?`)
-			params = append(params, query.AnnotationID)
+		filter, filterParams, err := r.buildFilter(query, accessResources)
+		if err != nil {
+			return err
 		}
+		sql.WriteString(`WHERE ` + filter)
+		params = append(params, filterParams...)
 
-		if query.AlertID != 0 {
-			sql.WriteString(` AND a.alert_id = ?`)
-			params = append(params, query.AlertID)
+		if query.Cursor != "" {
+			cursor, err := annotations.ParseCursor(query.Cursor)
+			if err != nil {
+				return err
+			}
+			sql.WriteString(` AND (a.epoch_end < ? OR (a.epoch_end = ? AND (a.epoch < ? OR (a.epoch = ? AND a.id < ?))))`)
+			params = append(params, cursor.TimeEnd, cursor.TimeEnd, cursor.Time, cursor.Time, cursor.ID)
 		}
 
-		if query.DashboardID != 0 {
-			sql.WriteString(` AND a.dashboard_id = ?`)
-			params = append(params, query.DashboardID)
+		if query.Limit == 0 {
+			query.Limit = 100
 		}
 
-		if query.PanelID != 0 {
-			sql.WriteString(` AND a.panel_id = ?`)
-			params = append(params, query.PanelID)
-		}
+		// order of ORDER BY arguments match the order of a sql index for performance
+		sql.WriteString(" ORDER BY a.org_id, a.epoch_end DESC, a.epoch DESC, a.id DESC" + r.db.GetDialect().Limit(query.Limit) + " ) dt on dt.id = annotation.id")
+		sql.WriteString(" ORDER BY annotation.epoch_end DESC, annotation.epoch DESC, annotation.id DESC")
 
-		if query.UserID != 0 {
-			sql.WriteString(` AND a.user_id = ?`)
-			params = append(params, query.UserID)
+		if err := sess.SQL(sql.String(), params...).Find(&items); err != nil {
+			items = nil
+			return err
 		}
+		return nil
+	},
+	)
 
-		if query.From > 0 && query.To > 0 {
-			sql.WriteString(` AND a.epoch <= ? AND a.epoch_end >= ?`)
-			params = append(params, query.To, query.From)
-		}
+	return items, err
+}
 
-		if query.Type == "alert" {
-			sql.WriteString(` AND a.alert_id > 0`)
-		} else if query.Type == "annotation" {
-			sql.WriteString(` AND a.alert_id = 0`)
-		}
+func (r *xormRepositoryImpl) Count(ctx context.Context, query *annotations.CountQuery, accessResources *accesscontrol.AccessResources) (annotations.CountResult, error) {
+	res := annotations.CountResult{Counts: make([]*annotations.CountDTO, 0)}
+	filter, params, err := r.buildFilter(&query.ItemQuery, accessResources)
+	if err != nil {
+		return res, err
+	}
 
-		if len(query.Tags) > 0 {
-			keyValueFilters := []string{}
+	var sql string
+	switch query.GroupBy {
+	case annotations.CountGroupByTag:
+		tagKey := `t.` + r.db.GetDialect().Quote("key")
+		tagValue := `t.` + r.db.GetDialect().Quote("value")
+		sql = `SELECT ` + tagKey + `, ` + tagValue + `, count(*) AS count FROM annotation a
+			INNER JOIN annotation_tag ant ON ant.annotation_id = a.id
+			INNER JOIN tag t ON t.id = ant.tag_id
+			WHERE ` + filter + ` GROUP BY ` + tagKey + `, ` + tagValue
+	case annotations.CountGroupByDashboard:
+		sql = `SELECT a.dashboard_id, count(*) AS count FROM annotation a WHERE ` + filter + ` GROUP BY a.dashboard_id`
+	case annotations.CountGroupByTime:
+		// the interval is an integer, so it is safe to put it into the statement. This way the grouping
+		// expression is the same in SELECT and GROUP BY, which is required by PostgreSQL.
+		bucket := fmt.Sprintf("(a.epoch / %d) * %d", query.Interval, query.Interval)
+		if r.db.GetDBType() == migrator.MySQL {
+			bucket = fmt.Sprintf("(a.epoch DIV %d) * %d", query.Interval, query.Interval)
+		}
+		sql = `SELECT ` + bucket + ` AS time, count(*) AS count FROM annotation a WHERE ` + filter + ` GROUP BY ` + bucket
+	default:
+		return res, annotations.ErrInvalidCountQuery.Errorf("unknown group by: %s", query.GroupBy)
+	}
 
-			tags := tag.ParseTagPairs(query.Tags)
-			for _, tag := range tags {
-				if tag.Value == "" {
-					keyValueFilters = append(keyValueFilters, "(tag."+r.db.GetDialect().Quote("key")+" = ?)")
-					params = append(params, tag.Key)
-				} else {
-					keyValueFilters = append(keyValueFilters, "(tag."+r.db.GetDialect().Quote("key")+" = ? AND tag."+r.db.GetDialect().Quote("value")+" = ?)")
-					params = append(params, tag.Key, tag.Value)
-				}
-			}
+	var rows []annotationCount
+	err = r.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.SQL(sql, params...).Find(&rows)
+	})
+	if err != nil {
+		return res, err
+	}
 
-			if len(tags) > 0 {
-				tagsSubQuery := fmt.Sprintf(`
-			SELECT SUM(1) FROM annotation_tag at
-			INNER JOIN tag on tag.id = at.tag_id
-			WHERE at.annotation_id = a.id
-				AND (
-				%s
-				)
-		`, strings.Join(keyValueFilters, " OR "))
-
-				if query.MatchAny {
-					sql.WriteString(fmt.Sprintf(" AND (%s) > 0 ", tagsSubQuery))
-				} else {
-					sql.WriteString(fmt.Sprintf(" AND (%s) = %d ", tagsSubQuery, len(tags)))
-				}
+	dashboardUIDs := make(map[int64]string, len(accessResources.Dashboards))
+	for uid, id := range accessResources.Dashboards {
+		dashboardUIDs[id] = uid
+	}
+	counts := make([]*annotations.CountDTO, 0, len(rows))
+	for _, row := range rows {
+		count := &annotations.CountDTO{Time: row.Time, Count: row.Count}
+		if query.GroupBy == annotations.CountGroupByTag {
+			count.Tag = row.Key
+			if row.Value != "" {
+				count.Tag = row.Key + ":" + row.Value
 			}
 		}
+		if query.GroupBy == annotations.CountGroupByDashboard {
+			count.DashboardUID = dashboardUIDs[row.DashboardID]
+		}
+		counts = append(counts, count)
+	}
+	res.Counts = annotations.MergeCounts(query.GroupBy, counts)
+	return res, nil
+}
 
-		acFilter, err := r.getAccessControlFilter(query.SignedInUser, accessResources)
-		if err != nil {
-			return err
+// buildFilter returns the condition on annotations aliased as `a` that matches the query.
+func (r *xormRepositoryImpl) buildFilter(query *annotations.ItemQuery, accessResources *accesscontrol.AccessResources) (string, []any, error) {
+	var sql bytes.Buffer
+	params := make([]any, 0)
+
+	sql.WriteString(`a.org_id = ?`)
+	params = append(params, query.OrgID)
+
+	if query.AnnotationID != 0 {
+		// fmt.Print("annotation query")
+		sql.WriteString(` AND a.id = ?`)
+		params = append(params, query.AnnotationID)
+	}
+
+	if query.AlertID != 0 {
+		sql.WriteString(` AND a.alert_id = ?`)
+		params = append(params, query.AlertID)
+	}
+
+	if query.DashboardID != 0 {
+		sql.WriteString(` AND a.dashboard_id = ?`)
+		params = append(params, query.DashboardID)
+	}
+
+	if query.PanelID != 0 {
+		sql.WriteString(` AND a.panel_id = ?`)
+		params = append(params, query.PanelID)
+	}
+
+	if query.UserID != 0 {
+		sql.WriteString(` AND a.user_id = ?`)
+		params = append(params, query.UserID)
+	}
+
+	if query.From > 0 && query.To > 0 {
+		sql.WriteString(` AND a.epoch <= ? AND a.epoch_end >= ?`)
+		params = append(params, query.To, query.From)
+	}
+
+	if query.Type == "alert" {
+		sql.WriteString(` AND a.alert_id > 0`)
+	} else if query.Type == "annotation" {
+		sql.WriteString(` AND a.alert_id = 0`)
+	}
+
+	if len(query.Tags) > 0 {
+		keyValueFilters := []string{}
+
+		tags := tag.ParseTagPairs(query.Tags)
+		for _, tag := range tags {
+			if tag.Value == "" {
+				keyValueFilters = append(keyValueFilters, "(tag."+r.db.GetDialect().Quote("key")+" = ?)")
+				params = append(params, tag.Key)
+			} else {
+				keyValueFilters = append(keyValueFilters, "(tag."+r.db.GetDialect().Quote("key")+" = ? AND tag."+r.db.GetDialect().Quote("value")+" = ?)")
+				params = append(params, tag.Key, tag.Value)
+			}
 		}
-		sql.WriteString(fmt.Sprintf(" AND (%s)", acFilter))
 
-		if query.Limit == 0 {
-			query.Limit = 100
+		if len(tags) > 0 {
+			tagsSubQuery := fmt.Sprintf(`
+		SELECT SUM(1) FROM annotation_tag at
+		INNER JOIN tag on tag.id = at.tag_id
+		WHERE at.annotation_id = a.id
+			AND (
+			%s
+			)
+	`, strings.Join(keyValueFilters, " OR "))
+
+			if query.MatchAny {
+				sql.WriteString(fmt.Sprintf(" AND (%s) > 0 ", tagsSubQuery))
+			} else {
+				sql.WriteString(fmt.Sprintf(" AND (%s) = %d ", tagsSubQuery, len(tags)))
+			}
 		}
+	}
 
-		// order of ORDER BY arguments match the order of a sql index for performance
-		sql.WriteString(" ORDER BY a.org_id, a.epoch_end DESC, a.epoch DESC" + r.db.GetDialect().Limit(query.Limit) + " ) dt on dt.id = annotation.id")
+	if textFilter, textParams := r.textFilter(query.Text); textFilter != "" {
+		sql.WriteString(" AND " + textFilter)
+		params = append(params, textParams...)
+	}
 
-		if err := sess.SQL(sql.String(), params...).Find(&items); err != nil {
-			items = nil
-			return err
-		}
-		return nil
-	},
-	)
+	acFilter, err := r.getAccessControlFilter(query.SignedInUser, accessResources)
+	if err != nil {
+		return "", nil, err
+	}
+	sql.WriteString(fmt.Sprintf(" AND (%s)", acFilter))
 
-	return items, err
+	return sql.String(), params, nil
 }
 
+// textFilter returns the condition matching annotations with text that contains all words of the search text.
+// Full-text search is used on MySQL and PostgreSQL, which have full-text indexes on the annotation text.
+// Other databases match the words as substrings of the text.
+func (r *xormRepositoryImpl) textFilter(text string) (string, []any) {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return "", nil
+	}
+	switch r.db.GetDBType() {
+	case migrator.Postgres:
+		return "to_tsvector('simple', a.text) @@ plainto_tsquery('simple', ?)", []any{text}
+	case migrator.MySQL:
+		terms := make([]string, 0, len(words))
+		for _, word := range words {
+			// remove the operators of boolean full-text search
+			word = strings.Trim(word, `+-<>()~*"@`)
+			if word != "" {
+				terms = append(terms, "+"+word+"*")
+			}
+		}
+		if len(terms) == 0 {
+			return "", nil
+		}
+		return "MATCH (a.text) AGAINST (? IN BOOLEAN MODE)", []any{strings.Join(terms, " ")}
+	default:
+		filters := make([]string, 0, len(words))
+		params := make([]any, 0, len(words))
+		for _, word := range words {
+			filters = append(filters, "a.text "+r.db.GetDialect().LikeStr()+` ? ESCAPE '\'`)
+			params = append(params, "%"+likeEscaper.Replace(word)+"%")
+		}
+		return "(" + strings.Join(filters, " AND ") + ")", params
+	}
+}
+
+// likeEscaper escapes the wildcards of LIKE patterns, so that they are matched literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 func (r *xormRepositoryImpl) getAccessControlFilter(user identity.Requester, accessResources *accesscontrol.AccessResources) (string, error) {
 	var filters []string
 
@@ -521,6 +649,48 @@ func (r *xormRepositoryImpl) validateTagsLength(item *annotations.Item) error {
 }
 
 func (r *xormRepositoryImpl) CleanAnnotations(ctx context.Context, cfg setting.AnnotationCleanupSettings, annotationType string) (int64, error) {
+	return r.cleanAnnotations(ctx, cfg, annotationType)
+}
+
+// CleanAnnotationsByRule deletes the annotations matching the retention rule that are
+// older than its max age or exceed its max count.
+func (r *xormRepositoryImpl) CleanAnnotationsByRule(ctx context.Context, rule setting.AnnotationRetentionRule) (int64, error) {
+	if err := rule.Validate(); err != nil {
+		return 0, err
+	}
+
+	filters := make([]string, 0)
+	params := make([]any, 0)
+	if rule.OrgID > 0 {
+		filters = append(filters, "org_id = ?")
+		params = append(params, rule.OrgID)
+	}
+
+	if rule.DashboardUID != "" {
+		if rule.OrgID > 0 {
+			filters = append(filters, "dashboard_id IN (SELECT id FROM dashboard WHERE uid = ? AND org_id = ?)")
+			params = append(params, rule.DashboardUID, rule.OrgID)
+		} else {
+			filters = append(filters, "dashboard_id IN (SELECT id FROM dashboard WHERE uid = ?)")
+			params = append(params, rule.DashboardUID)
+		}
+	}
+
+	// an annotation must have all the tags of the rule
+	for _, t := range tag.ParseTagPairs(rule.Tags) {
+		tagFilter := "tag." + r.db.GetDialect().Quote("key") + " = ?"
+		params = append(params, t.Key)
+		if t.Value != "" {
+			tagFilter += " AND tag." + r.db.GetDialect().Quote("value") + " = ?"
+			params = append(params, t.Value)
+		}
+		filters = append(filters, fmt.Sprintf(`EXISTS (SELECT 1 FROM annotation_tag at INNER JOIN tag ON tag.id = at.tag_id WHERE at.annotation_id = annotation.id AND %s)`, tagFilter))
+	}
+
+	return r.cleanAnnotations(ctx, rule.AnnotationCleanupSettings, strings.Join(filters, " AND "), params...)
+}
+
+func (r *xormRepositoryImpl) cleanAnnotations(ctx context.Context, cfg setting.AnnotationCleanupSettings, condition string, params ...any) (int64, error) {
 	var totalAffected int64
 	if cfg.MaxAge > 0 {
 		cutoffDate := timeNow().Add(-cfg.MaxAge).UnixNano() / int64(time.Millisecond)
@@ -530,8 +700,8 @@ func (r *xormRepositoryImpl) CleanAnnotations(ctx context.Context, cfg setting.A
 		//
 		// We execute the following batched operation repeatedly until either we run out of objects, the context is cancelled, or there is an error.
 		affected, err := untilDoneOrCancelled(ctx, func() (int64, error) {
-			cond := fmt.Sprintf(`%s AND created < %v ORDER BY id DESC %s`, annotationType, cutoffDate, r.db.GetDialect().Limit(r.cfg.AnnotationCleanupJobBatchSize))
-			ids, err := r.fetchIDs(ctx, "annotation", cond)
+			cond := fmt.Sprintf(`%s AND created < %v ORDER BY id DESC %s`, condition, cutoffDate, r.db.GetDialect().Limit(r.cfg.AnnotationCleanupJobBatchSize))
+			ids, err := r.fetchIDs(ctx, "annotation", cond, params...)
 			if err != nil {
 				return 0, err
 			}
@@ -547,8 +717,8 @@ func (r *xormRepositoryImpl) CleanAnnotations(ctx context.Context, cfg setting.A
 	if cfg.MaxCount > 0 {
 		// Similar strategy as the above cleanup process, to avoid deadlocks.
 		affected, err := untilDoneOrCancelled(ctx, func() (int64, error) {
-			cond := fmt.Sprintf(`%s ORDER BY id DESC %s`, annotationType, r.db.GetDialect().LimitOffset(r.cfg.AnnotationCleanupJobBatchSize, cfg.MaxCount))
-			ids, err := r.fetchIDs(ctx, "annotation", cond)
+			cond := fmt.Sprintf(`%s ORDER BY id DESC %s`, condition, r.db.GetDialect().LimitOffset(r.cfg.AnnotationCleanupJobBatchSize, cfg.MaxCount))
+			ids, err := r.fetchIDs(ctx, "annotation", cond, params...)
 			if err != nil {
 				return 0, err
 			}
@@ -576,7 +746,7 @@ func (r *xormRepositoryImpl) CleanOrphanedAnnotationTags(ctx context.Context) (i
 	})
 }
 
-func (r *xormRepositoryImpl) fetchIDs(ctx context.Context, table, condition string) ([]int64, error) {
+func (r *xormRepositoryImpl) fetchIDs(ctx context.Context, table, condition string, params ...any) ([]int64, error) {
 	sql := fmt.Sprintf(`SELECT id FROM %s`, table)
 	if condition == "" {
 		return nil, fmt.Errorf("condition must be supplied; cannot fetch IDs from entire table")
@@ -584,7 +754,7 @@ func (r *xormRepositoryImpl) fetchIDs(ctx context.Context, table, condition stri
 	sql += fmt.Sprintf(` WHERE %s`, condition)
 	ids := make([]int64, 0)
 	err := r.db.WithDbSession(ctx, func(session *db.Session) error {
-		return session.SQL(sql).Find(&ids)
+		return session.SQL(sql, params...).Find(&ids)
 	})
 	return ids, err
 }
@@ -659,3 +829,11 @@ type annotationTag struct {
 	AnnotationID int64 `xorm:"annotation_id"`
 	TagID        int64 `xorm:"tag_id"`
 }
+
+type annotationCount struct {
+	Key         string
+	Value       string
+	DashboardID int64 `xorm:"dashboard_id"`
+	Time        int64
+	Count       int64
+}
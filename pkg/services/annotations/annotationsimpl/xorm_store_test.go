@@ -19,6 +19,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
 	"github.com/grafana/grafana/pkg/services/tag"
 	"github.com/grafana/grafana/pkg/services/tag/tagimpl"
 	"github.com/grafana/grafana/pkg/services/user"
@@ -574,6 +575,178 @@ func TestIntegrationAnnotations(t *testing.T) {
 	})
 }
 
+func TestIntegrationAnnotationsSearchAndCount(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sql := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	cfg.AnnotationMaximumTagsLength = 60
+	store := NewXormStore(cfg, log.New("annotation.test"), sql, tagimpl.ProvideService(sql))
+
+	testUser := &user.SignedInUser{
+		OrgID: 1,
+		Permissions: map[int64]map[string][]string{
+			1: {accesscontrol.ActionAnnotationsRead: []string{accesscontrol.ScopeAnnotationsAll}},
+		},
+	}
+	accRes := &annotation_ac.AccessResources{
+		Dashboards:               map[string]int64{"dash-1": 1},
+		CanAccessOrgAnnotations:  true,
+		CanAccessDashAnnotations: true,
+	}
+
+	items := []*annotations.Item{
+		{OrgID: 1, DashboardID: 1, Text: "Deploy of service A failed", Epoch: 1000, Tags: []string{"deploy", "service:a"}},
+		{OrgID: 1, DashboardID: 1, Text: "Deploy of service B", Epoch: 2000, Tags: []string{"deploy", "service:b"}},
+		{OrgID: 1, Text: "Rollback of service A", Epoch: 2500, Tags: []string{"rollback", "service:a"}},
+		{OrgID: 1, Text: "Deploy of service C", Epoch: 2500, Tags: []string{"deploy"}},
+		{OrgID: 1, Text: "Maintenance", Epoch: 4000, EpochEnd: 5000},
+	}
+	for _, item := range items {
+		require.NoError(t, store.Add(context.Background(), item))
+	}
+
+	t.Run("Should find annotations containing all the words", func(t *testing.T) {
+		res, err := store.Get(context.Background(), &annotations.ItemQuery{
+			OrgID:        1,
+			Text:         "deploy service",
+			SignedInUser: testUser,
+		}, accRes)
+		require.NoError(t, err)
+		require.Len(t, res, 3)
+
+		res, err = store.Get(context.Background(), &annotations.ItemQuery{
+			OrgID:        1,
+			Text:         "deploy failed",
+			SignedInUser: testUser,
+		}, accRes)
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		require.Equal(t, items[0].ID, res[0].ID)
+	})
+
+	t.Run("Should match words as case insensitive substrings without full-text search", func(t *testing.T) {
+		if sql.GetDBType() != migrator.SQLite {
+			t.Skip("full-text search is used on this database")
+		}
+		for text, expected := range map[string][]int64{
+			"DEPLO ervice": {items[3].ID, items[1].ID, items[0].ID},
+			"of servic":    {items[3].ID, items[2].ID, items[1].ID, items[0].ID},
+			"mainten":      {items[4].ID},
+			"enance":       {items[4].ID},
+			"E":            {items[4].ID, items[3].ID, items[2].ID, items[1].ID, items[0].ID},
+			"deploys":      {},
+		} {
+			res, err := store.Get(context.Background(), &annotations.ItemQuery{
+				OrgID:        1,
+				Text:         text,
+				SignedInUser: testUser,
+			}, accRes)
+			require.NoError(t, err)
+			ids := make([]int64, 0, len(res))
+			for _, item := range res {
+				ids = append(ids, item.ID)
+			}
+			require.Equal(t, expected, ids, text)
+		}
+	})
+
+	t.Run("Should match wildcard characters literally without full-text search", func(t *testing.T) {
+		if sql.GetDBType() != migrator.SQLite {
+			t.Skip("full-text search is used on this database")
+		}
+		other := []*annotations.Item{
+			{OrgID: 2, Text: "CPU at 100%", Epoch: 1000},
+			{OrgID: 2, Text: "CPU at 1000", Epoch: 2000},
+			{OrgID: 2, Text: "Restarted a_b", Epoch: 3000},
+			{OrgID: 2, Text: "Restarted axb", Epoch: 4000},
+			{OrgID: 2, Text: `Path C:\data`, Epoch: 5000},
+		}
+		for _, item := range other {
+			require.NoError(t, store.Add(context.Background(), item))
+		}
+		for text, expected := range map[string][]int64{
+			"100%":     {other[0].ID},
+			"a_b":      {other[2].ID},
+			`C:\data`: {other[4].ID},
+			`:\`:      {other[4].ID},
+		} {
+			res, err := store.Get(context.Background(), &annotations.ItemQuery{
+				OrgID:        2,
+				Text:         text,
+				SignedInUser: testUser,
+			}, accRes)
+			require.NoError(t, err)
+			ids := make([]int64, 0, len(res))
+			for _, item := range res {
+				ids = append(ids, item.ID)
+			}
+			require.Equal(t, expected, ids, text)
+		}
+	})
+
+	t.Run("Should page through annotations with cursor", func(t *testing.T) {
+		var ids []int64
+		query := &annotations.ItemQuery{OrgID: 1, Limit: 2, SignedInUser: testUser}
+		for page := 0; page < 3; page++ {
+			res, err := store.Get(context.Background(), query, accRes)
+			require.NoError(t, err)
+			for _, item := range res {
+				ids = append(ids, item.ID)
+			}
+			query.Cursor = annotations.NextCursor(res, query.Limit)
+			if query.Cursor == "" {
+				break
+			}
+		}
+		// annotations with the same time are sorted by ID
+		require.Equal(t, []int64{items[4].ID, items[3].ID, items[2].ID, items[1].ID, items[0].ID}, ids)
+
+		_, err := store.Get(context.Background(), &annotations.ItemQuery{OrgID: 1, Cursor: "invalid", SignedInUser: testUser}, accRes)
+		require.ErrorIs(t, err, annotations.ErrInvalidCursor)
+	})
+
+	t.Run("Should count annotations by tag", func(t *testing.T) {
+		res, err := store.Count(context.Background(), &annotations.CountQuery{
+			ItemQuery: annotations.ItemQuery{OrgID: 1, SignedInUser: testUser},
+			GroupBy:   annotations.CountGroupByTag,
+		}, accRes)
+		require.NoError(t, err)
+		require.Equal(t, []*annotations.CountDTO{
+			{Tag: "deploy", Count: 3},
+			{Tag: "service:a", Count: 2},
+			{Tag: "rollback", Count: 1},
+			{Tag: "service:b", Count: 1},
+		}, res.Counts)
+	})
+
+	t.Run("Should count annotations by dashboard", func(t *testing.T) {
+		res, err := store.Count(context.Background(), &annotations.CountQuery{
+			ItemQuery: annotations.ItemQuery{OrgID: 1, Text: "service", SignedInUser: testUser},
+			GroupBy:   annotations.CountGroupByDashboard,
+		}, accRes)
+		require.NoError(t, err)
+		require.Equal(t, []*annotations.CountDTO{
+			{Count: 2},
+			{DashboardUID: "dash-1", Count: 2},
+		}, res.Counts)
+	})
+
+	t.Run("Should count annotations by time", func(t *testing.T) {
+		res, err := store.Count(context.Background(), &annotations.CountQuery{
+			ItemQuery: annotations.ItemQuery{OrgID: 1, Tags: []string{"deploy"}, SignedInUser: testUser},
+			GroupBy:   annotations.CountGroupByTime,
+			Interval:  2000,
+		}, accRes)
+		require.NoError(t, err)
+		require.Equal(t, []*annotations.CountDTO{
+			{Time: 0, Count: 1},
+			{Time: 2000, Count: 2},
+		}, res.Counts)
+	})
+}
+
 func BenchmarkFindTags_10k(b *testing.B) {
 	benchmarkFindTags(b, 10000)
 }
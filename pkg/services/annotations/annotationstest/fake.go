@@ -83,6 +83,10 @@ func (repo *fakeAnnotationsRepo) FindTags(_ context.Context, query *annotations.
 	return result, nil
 }
 
+func (repo *fakeAnnotationsRepo) Count(_ context.Context, query *annotations.CountQuery) (annotations.CountResult, error) {
+	return annotations.CountResult{Counts: []*annotations.CountDTO{}}, nil
+}
+
 func (repo *fakeAnnotationsRepo) Len() int {
 	repo.mtx.Lock()
 	defer repo.mtx.Unlock()
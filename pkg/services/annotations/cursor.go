@@ -0,0 +1,57 @@
+package annotations
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// Cursor is the position of an annotation in the results, which are sorted
+// in descending order by end time, start time and ID. It allows paging
+// through results: the next page contains the annotations after the cursor
+// of the last annotation of the previous page.
+type Cursor struct {
+	TimeEnd int64
+	Time    int64
+	ID      int64
+}
+
+func CursorFromItem(item *ItemDTO) Cursor {
+	return Cursor{TimeEnd: item.TimeEnd, Time: item.Time, ID: item.ID}
+}
+
+// NextCursor returns the cursor of the next page of results or an empty string
+// if there are less items than the limit, which means there is no next page.
+func NextCursor(items []*ItemDTO, limit int64) string {
+	if len(items) == 0 || limit <= 0 || int64(len(items)) < limit {
+		return ""
+	}
+	return CursorFromItem(items[len(items)-1]).String()
+}
+
+// ParseCursor parses the cursor encoded by Cursor.String.
+func ParseCursor(s string) (Cursor, error) {
+	var c Cursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, ErrInvalidCursor.Errorf("failed to decode cursor: %w", err)
+	}
+	if _, err := fmt.Sscanf(string(b), "%d:%d:%d", &c.TimeEnd, &c.Time, &c.ID); err != nil {
+		return c, ErrInvalidCursor.Errorf("failed to parse cursor: %w", err)
+	}
+	return c, nil
+}
+
+func (c Cursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%d:%d", c.TimeEnd, c.Time, c.ID)))
+}
+
+// Includes returns true if the item comes after the cursor in the results.
+func (c Cursor) Includes(item *ItemDTO) bool {
+	if item.TimeEnd != c.TimeEnd {
+		return item.TimeEnd < c.TimeEnd
+	}
+	if item.Time != c.Time {
+		return item.Time < c.Time
+	}
+	return item.ID < c.ID
+}
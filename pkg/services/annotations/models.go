@@ -1,6 +1,9 @@
 package annotations
 
 import (
+	"fmt"
+	"sort"
+
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 )
@@ -18,9 +21,93 @@ type ItemQuery struct {
 	Tags         []string `json:"tags"`
 	Type         string   `json:"type"`
 	MatchAny     bool     `json:"matchAny"`
+	// Text is used to search annotations by text, an annotation must contain all the words.
+	Text         string `json:"text"`
 	SignedInUser identity.Requester
 
 	Limit int64 `json:"limit"`
+	// Cursor is the position after which annotations are returned, see Cursor.
+	Cursor string `json:"cursor"`
+}
+
+// CountGroupBy is the attribute annotations are grouped by when counting them.
+type CountGroupBy string
+
+const (
+	CountGroupByTag       CountGroupBy = "tag"
+	CountGroupByDashboard CountGroupBy = "dashboard"
+	CountGroupByTime      CountGroupBy = "time"
+)
+
+// CountQuery is the query for counting annotations grouped by tag, dashboard or time.
+// The annotations are filtered the same way as by ItemQuery, ItemQuery.Limit and
+// ItemQuery.Cursor are ignored.
+type CountQuery struct {
+	ItemQuery
+	GroupBy CountGroupBy `json:"groupBy"`
+	// Interval is the size of time buckets in milliseconds when grouping by time.
+	Interval int64 `json:"interval"`
+}
+
+func (q *CountQuery) Validate() error {
+	switch q.GroupBy {
+	case CountGroupByTag, CountGroupByDashboard:
+		return nil
+	case CountGroupByTime:
+		if q.Interval <= 0 {
+			return ErrInvalidCountQuery.Errorf("interval is required to group annotations by time")
+		}
+		return nil
+	default:
+		return ErrInvalidCountQuery.Errorf("unknown group by: %s", q.GroupBy)
+	}
+}
+
+// CountDTO is the number of annotations with the same tag, dashboard or time bucket.
+type CountDTO struct {
+	Tag          string `json:"tag,omitempty"`
+	DashboardUID string `json:"dashboardUID,omitempty"`
+	// Time is the start of the time bucket in milliseconds.
+	Time  int64 `json:"time,omitempty"`
+	Count int64 `json:"count"`
+}
+
+// key returns the value the count is grouped by.
+func (c *CountDTO) key() string {
+	return fmt.Sprintf("%s\x00%s\x00%d", c.Tag, c.DashboardUID, c.Time)
+}
+
+// CountResult is the result of a count query. Counts are sorted by time when
+// grouped by time, otherwise by count in descending order.
+type CountResult struct {
+	Counts []*CountDTO `json:"counts"`
+}
+
+// MergeCounts sums the counts of the same group and sorts the result.
+func MergeCounts(groupBy CountGroupBy, counts ...[]*CountDTO) []*CountDTO {
+	merged := make(map[string]*CountDTO)
+	res := make([]*CountDTO, 0)
+	for _, c := range counts {
+		for _, count := range c {
+			if existing, ok := merged[count.key()]; ok {
+				existing.Count += count.Count
+				continue
+			}
+			cp := *count
+			merged[count.key()] = &cp
+			res = append(res, &cp)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		if groupBy == CountGroupByTime {
+			return res[i].Time < res[j].Time
+		}
+		if res[i].Count != res[j].Count {
+			return res[i].Count > res[j].Count
+		}
+		return res[i].key() < res[j].key()
+	})
+	return res
 }
 
 // TagsQuery is the query for a tags search.
@@ -127,7 +214,7 @@ type ItemDTO struct {
 
 type SortedItems []*ItemDTO
 
-// sort annotations in descending order by end time, then by start time
+// sort annotations in descending order by end time, then by start time and ID
 func (s SortedItems) Len() int {
 	return len(s)
 }
@@ -136,7 +223,10 @@ func (s SortedItems) Less(i, j int) bool {
 	if s[i].TimeEnd != s[j].TimeEnd {
 		return s[i].TimeEnd > s[j].TimeEnd
 	}
-	return s[i].Time > s[j].Time
+	if s[i].Time != s[j].Time {
+		return s[i].Time > s[j].Time
+	}
+	return s[i].ID > s[j].ID
 }
 
 func (s SortedItems) Swap(i, j int) {
@@ -0,0 +1,85 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"time"
+)
+
+// Item is a file of a backup section. The content of the file is in FileBytes, or in the file
+// at Path for the files read from disk or from a backup archive, which are not loaded in memory.
+type Item struct {
+	Filename  string
+	FileBytes []byte
+	Path      string
+}
+
+// Open returns a reader of the content of the file.
+func (i Item) Open() (io.ReadCloser, error) {
+	if i.Path == "" {
+		return io.NopCloser(bytes.NewReader(i.FileBytes)), nil
+	}
+	return os.Open(i.Path)
+}
+
+type BackupFunc func(context.Context) ([]Item, error)
+
+type RestoreFunc func(context.Context, []Item) error
+
+// Section is a part of an instance backup, such as the database or the provisioning files.
+type Section struct {
+	// UID is a unique identifier for the section. The files of the section are stored in a
+	// directory named after it in the backup archive.
+	UID string `json:"uid"`
+	// DisplayName is the name of the section. User facing.
+	DisplayName string `json:"displayName"`
+	// Description is a description of the section. User facing.
+	Description string `json:"description"`
+	// Tables are the database tables of the section, which are left out of the database section.
+	// All the tables are read from the same snapshot of the database, the files of the tables
+	// of the section are passed to Restore with the other files of the section.
+	Tables []string `json:"tables,omitempty"`
+	// Backup is the optional function that collects the files of the section other than the
+	// files of its tables.
+	Backup BackupFunc `json:"-"`
+	// Restore is the function that restores the files of the section. Sections are restored in
+	// the order in which they are registered, after the database section.
+	Restore RestoreFunc `json:"-"`
+	// Verify is the optional function that checks that the files of the section can be restored
+	// in the instance. All the sections are verified before the first one is restored.
+	Verify RestoreFunc `json:"-"`
+	// EnabledFn is a function that determines if the section is enabled. If nil, the section is always enabled.
+	EnabledFn func() bool `json:"-"`
+}
+
+// Manifest describes the content of a backup archive.
+type Manifest struct {
+	// Version is the version of Grafana that created the backup.
+	Version string `json:"version"`
+	// Commit is the commit of Grafana that created the backup.
+	Commit    string    `json:"commit"`
+	CreatedAt time.Time `json:"createdAt"`
+	// DatabaseType is the type of the database that was backed up.
+	DatabaseType string `json:"databaseType"`
+	// Migrations are the IDs of the migrations that had been run on the database. A backup can
+	// only be restored in a database with the same migrations.
+	Migrations []string          `json:"migrations"`
+	Sections   []ManifestSection `json:"sections"`
+}
+
+type ManifestSection struct {
+	UID   string         `json:"uid"`
+	Files []ManifestFile `json:"files"`
+}
+
+type ManifestFile struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+type Service interface {
+	RegisterBackupSection(section Section)
+}
@@ -0,0 +1,59 @@
+package backupimpl
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+)
+
+const rootUrl = "/api/admin/backup"
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	authorize := ac.Middleware(s.accessControl)
+
+	routeRegister.Post(rootUrl, authorize(ac.EvalPermission(ActionCreate)), s.handleCreate)
+}
+
+// handleCreate streams the archive of the backup in the response as it is created.
+func (s *Service) handleCreate(ctx *contextmodel.ReqContext) {
+	w := &downloadWriter{resp: ctx.Resp, filename: ArchiveFilename(time.Now())}
+	_, err := s.Create(ctx.Req.Context(), w)
+	if err == nil {
+		return
+	}
+
+	// the archive is incomplete, which is detected when it is read
+	if w.started {
+		s.log.Error("Failed to create backup", "error", err)
+		return
+	}
+	if errors.Is(err, ErrInProgress) {
+		response.Error(http.StatusConflict, err.Error(), err).WriteTo(ctx)
+		return
+	}
+	response.Error(http.StatusInternalServerError, "failed to create backup", err).WriteTo(ctx)
+}
+
+// downloadWriter writes the headers of the download of the archive before the first write, so
+// that the errors that happen before anything is written are returned as error responses.
+type downloadWriter struct {
+	resp     http.ResponseWriter
+	filename string
+	started  bool
+}
+
+func (w *downloadWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.resp.Header().Set("Content-Type", "application/gzip")
+		w.resp.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", w.filename))
+		w.resp.WriteHeader(http.StatusOK)
+	}
+	return w.resp.Write(p)
+}
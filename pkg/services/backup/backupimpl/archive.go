@@ -0,0 +1,175 @@
+package backupimpl
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/backup"
+)
+
+const manifestFilename = "manifest.json"
+
+// archiveWriter writes a tar.gz archive with the files of the sections followed by the manifest,
+// which lists the sizes and checksums of the files.
+type archiveWriter struct {
+	zw      *gzip.Writer
+	tw      *tar.Writer
+	modTime time.Time
+}
+
+func newArchiveWriter(w io.Writer, modTime time.Time) *archiveWriter {
+	// tar > gzip > w
+	zw := gzip.NewWriter(w)
+	return &archiveWriter{zw: zw, tw: tar.NewWriter(zw), modTime: modTime}
+}
+
+// writeFile writes a file with the size bytes read from r and returns the SHA-256 checksum of
+// the file.
+func (a *archiveWriter) writeFile(name string, size int64, r io.Reader) (string, error) {
+	header := &tar.Header{
+		Name:    name,
+		ModTime: a.modTime,
+		Mode:    int64(0o600),
+		Size:    size,
+	}
+	if err := a.tw.WriteHeader(header); err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	if _, err := io.CopyN(io.MultiWriter(a.tw, h), r, size); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// close writes the manifest and ends the archive.
+func (a *archiveWriter) close(manifest *backup.Manifest) error {
+	data, err := json.MarshalIndent(manifest, "", " ")
+	if err != nil {
+		return err
+	}
+	if _, err := a.writeFile(manifestFilename, int64(len(data)), bytes.NewReader(data)); err != nil {
+		return err
+	}
+
+	if err := a.tw.Close(); err != nil {
+		return err
+	}
+	return a.zw.Close()
+}
+
+// readArchive reads a backup archive and checks that it contains the files listed in the
+// manifest, with the same sizes and checksums, and no other files. The files are written to
+// the directory dir, and are returned as the paths of the files by name in the archive.
+func readArchive(r io.Reader, dir string) (*backup.Manifest, map[string]string, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+	defer func() { _ = zr.Close() }()
+
+	var manifest *backup.Manifest
+	files := map[string]string{}
+	actual := map[string]backup.ManifestFile{}
+	tr := tar.NewReader(zr)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %w", ErrInvalid, err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			return nil, nil, fmt.Errorf("%w: %s is not a regular file", ErrInvalid, header.Name)
+		}
+
+		if header.Name == manifestFilename {
+			manifest = &backup.Manifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, nil, fmt.Errorf("%w: failed to read the manifest: %w", ErrInvalid, err)
+			}
+			continue
+		}
+		if _, ok := files[header.Name]; ok {
+			return nil, nil, fmt.Errorf("%w: %s is in the archive several times", ErrInvalid, header.Name)
+		}
+
+		// the files are named after their position in the archive, so that the names in the
+		// archive can't write outside of the directory
+		path := filepath.Join(dir, strconv.Itoa(len(files)))
+		file, err := spoolFile(path, tr)
+		if err != nil {
+			return nil, nil, err
+		}
+		files[header.Name] = path
+		actual[header.Name] = file
+	}
+
+	if manifest == nil {
+		return nil, nil, fmt.Errorf("%w: the archive does not contain a manifest", ErrInvalid)
+	}
+
+	listed := make(map[string]struct{}, len(files))
+	for _, section := range manifest.Sections {
+		for _, file := range section.Files {
+			name := section.UID + "/" + file.Name
+			listed[name] = struct{}{}
+			archived, ok := actual[name]
+			if !ok {
+				return nil, nil, fmt.Errorf("%w: %s is missing", ErrInvalid, name)
+			}
+			archived.Name = file.Name
+			if archived != file {
+				return nil, nil, fmt.Errorf("%w: %s does not match its size and checksum", ErrInvalid, name)
+			}
+		}
+	}
+	for name := range files {
+		if _, ok := listed[name]; !ok {
+			return nil, nil, fmt.Errorf("%w: %s is not listed in the manifest", ErrInvalid, name)
+		}
+	}
+
+	return manifest, files, nil
+}
+
+// spoolFile writes the content read from r to a file at path and returns its size and checksum.
+func spoolFile(path string, r io.Reader) (file backup.ManifestFile, err error) {
+	// nolint:gosec
+	// We can ignore the gosec G304 warning since the path is in the temporary directory of the restore
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return file, err
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	h := sha256.New()
+	file.Size, err = io.Copy(io.MultiWriter(f, h), r)
+	if err != nil {
+		return file, fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+	file.SHA256 = hex.EncodeToString(h.Sum(nil))
+	return file, nil
+}
+
+// ArchiveFilename returns the name of the archive of a backup created at the time.
+func ArchiveFilename(createdAt time.Time) string {
+	return fmt.Sprintf("grafana-backup-%s.tar.gz", createdAt.UTC().Format("20060102-150405"))
+}
@@ -0,0 +1,30 @@
+package backupimpl
+
+import (
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+const (
+	ActionCreate = "backups:create"
+)
+
+var (
+	backupWriterRole = accesscontrol.RoleDTO{
+		Name:        "fixed:backups:writer",
+		DisplayName: "Backup writer",
+		Description: "Create and download backups of the instance",
+		Group:       "Backups",
+		Permissions: []accesscontrol.Permission{
+			{Action: ActionCreate},
+		},
+	}
+)
+
+func (s *Service) declareFixedRoles(ac accesscontrol.Service) error {
+	backupWriter := accesscontrol.RoleRegistration{
+		Role:   backupWriterRole,
+		Grants: []string{accesscontrol.RoleGrafanaAdmin},
+	}
+
+	return ac.DeclareFixedRoles(backupWriter)
+}
@@ -0,0 +1,371 @@
+package backupimpl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/backup"
+	"github.com/grafana/grafana/pkg/services/kmsproviders"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/secrets/manager"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	filesDir = "files/"
+	// secretsCheckFilename is the file of the secrets section with a value encrypted with the
+	// key encryption key providers when the backup is created. It is decrypted before the backup
+	// is restored to check that the instance can decrypt the data keys of the backup.
+	secretsCheckFilename = "check.json"
+	secretsCheckValue    = "grafana-backup"
+)
+
+// excludedTables are not backed up: the migration log is compared with the one of the
+// database the backup is restored in, and the server locks belong to the running instance.
+var excludedTables = map[string]bool{
+	"migration_log": true,
+	"server_lock":   true,
+}
+
+// databaseSectionUID is the UID of the section of the tables that are not owned by other sections.
+const databaseSectionUID = "database"
+
+func (s *Service) databaseSection() backup.Section {
+	return backup.Section{
+		UID:         databaseSectionUID,
+		DisplayName: "Database",
+		Description: "Rows of the tables of the Grafana database",
+		Restore: func(ctx context.Context, items []backup.Item) error {
+			return restoreTables(ctx, s.sqlStore, items)
+		},
+	}
+}
+
+func secretsSection(sqlStore db.DB, secretsService *manager.SecretsService) backup.Section {
+	return backup.Section{
+		UID:         "secrets",
+		DisplayName: "Secrets",
+		Description: "Encrypted secrets and their data keys",
+		Tables:      []string{"data_keys", "secrets"},
+		Backup: func(ctx context.Context) ([]backup.Item, error) {
+			// the check value is encrypted with every key encryption key provider of the instance
+			check := make(map[secrets.ProviderID][]byte)
+			for id, provider := range secretsService.GetProviders() {
+				encrypted, err := provider.Encrypt(ctx, []byte(secretsCheckValue))
+				if err != nil {
+					return nil, fmt.Errorf("failed to encrypt the check value with provider %s: %w", id, err)
+				}
+				check[id] = encrypted
+			}
+			data, err := json.Marshal(check)
+			if err != nil {
+				return nil, err
+			}
+			return []backup.Item{{Filename: secretsCheckFilename, FileBytes: data}}, nil
+		},
+		Restore: func(ctx context.Context, items []backup.Item) error {
+			return restoreTables(ctx, sqlStore, items)
+		},
+		Verify: func(ctx context.Context, items []backup.Item) error {
+			return verifyDataKeys(ctx, secretsService.GetProviders(), items)
+		},
+	}
+}
+
+// verifyDataKeys checks that the providers of the instance decrypt the check value of the backup,
+// for every provider that encrypted data keys of the backup. This means that the instance uses
+// the secret key or the KMS provider of the instance that was backed up, and can decrypt the
+// data keys. The data keys themselves can't be checked: with the default encryption algorithm,
+// decrypting them with another key doesn't fail.
+func verifyDataKeys(ctx context.Context, providers map[secrets.ProviderID]secrets.Provider, items []backup.Item) error {
+	var dataKeys *backup.Item
+	check := map[secrets.ProviderID][]byte{}
+	for _, item := range items {
+		switch item.Filename {
+		case tablesDir + "data_keys.json":
+			dataKeys = &item
+		case secretsCheckFilename:
+			data, err := readItem(item)
+			if err != nil {
+				return err
+			}
+			if err := json.Unmarshal(data, &check); err != nil {
+				return fmt.Errorf("failed to read the check value: %w", err)
+			}
+		}
+	}
+	if dataKeys == nil {
+		return nil
+	}
+
+	dump, err := openTableDump(*dataKeys)
+	if err != nil {
+		return fmt.Errorf("failed to read the data keys: %w", err)
+	}
+	defer func() { _ = dump.Close() }()
+
+	column := slices.Index(dump.columns, "provider")
+	if column < 0 {
+		return errors.New("the data keys do not have a provider column")
+	}
+	verified := map[secrets.ProviderID]bool{}
+	var row []any
+	for {
+		ok, err := dump.next(&row)
+		if err != nil {
+			return fmt.Errorf("failed to read the data keys: %w", err)
+		}
+		if !ok {
+			break
+		}
+		name, _ := row[column].(string)
+		id := kmsproviders.NormalizeProviderID(secrets.ProviderID(name))
+		if verified[id] {
+			continue
+		}
+
+		provider, ok := providers[id]
+		if !ok {
+			return fmt.Errorf("%w: the data keys of the backup are encrypted with the provider %s, which is not configured", ErrIncompatible, id)
+		}
+		encrypted, ok := check[id]
+		if !ok {
+			return fmt.Errorf("%w: the backup does not contain the check value of the provider %s", ErrInvalid, id)
+		}
+		decrypted, err := provider.Decrypt(ctx, encrypted)
+		if err == nil && string(decrypted) != secretsCheckValue {
+			err = errors.New("unexpected check value")
+		}
+		if err != nil {
+			return fmt.Errorf("%w: the secrets of the backup cannot be decrypted, the instance must use the secret key or the KMS provider of the instance that was backed up: %w", ErrIncompatible, err)
+		}
+		verified[id] = true
+	}
+	return nil
+}
+
+// readItem returns the content of a small file of a section, such as a list or a check value.
+func readItem(item backup.Item) ([]byte, error) {
+	f, err := item.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	return io.ReadAll(f)
+}
+
+type pluginInfo struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Version string `json:"version"`
+}
+
+// pluginsSection backs up the list of the installed plugins. Plugins are not restored: the
+// plugins that are missing or installed in another version are logged so that they can be
+// installed with the CLI.
+func pluginsSection(cfg *setting.Cfg, logger log.Logger) backup.Section {
+	return backup.Section{
+		UID:         "plugins",
+		DisplayName: "Plugins",
+		Description: "List of the installed plugins",
+		Backup: func(ctx context.Context) ([]backup.Item, error) {
+			plugins, err := installedPlugins(cfg.PluginsPath)
+			if err != nil {
+				return nil, err
+			}
+			data, err := json.MarshalIndent(plugins, "", " ")
+			if err != nil {
+				return nil, err
+			}
+			return []backup.Item{{Filename: "plugins.json", FileBytes: data}}, nil
+		},
+		Restore: func(ctx context.Context, items []backup.Item) error {
+			installed, err := installedPlugins(cfg.PluginsPath)
+			if err != nil {
+				return err
+			}
+			versions := make(map[string]string, len(installed))
+			for _, p := range installed {
+				versions[p.ID] = p.Version
+			}
+
+			for _, item := range items {
+				data, err := readItem(item)
+				if err != nil {
+					return err
+				}
+				var plugins []pluginInfo
+				if err := json.Unmarshal(data, &plugins); err != nil {
+					return err
+				}
+				for _, p := range plugins {
+					if version, ok := versions[p.ID]; !ok || version != p.Version {
+						logger.Warn("Plugin of the backup is not installed, install it with: grafana cli plugins install <id> <version>",
+							"id", p.ID, "version", p.Version, "installedVersion", version)
+					}
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func installedPlugins(dir string) ([]pluginInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []pluginInfo{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the plugins: %w", err)
+	}
+
+	plugins := []pluginInfo{}
+	for _, entry := range entries {
+		for _, name := range []string{"plugin.json", filepath.Join("dist", "plugin.json")} {
+			// nolint:gosec
+			// We can ignore the gosec G304 warning since the path is in the plugins directory
+			data, err := os.ReadFile(filepath.Join(dir, entry.Name(), name))
+			if err != nil {
+				continue
+			}
+
+			var manifest struct {
+				ID   string `json:"id"`
+				Type string `json:"type"`
+				Info struct {
+					Version string `json:"version"`
+				} `json:"info"`
+			}
+			if err := json.Unmarshal(data, &manifest); err != nil || manifest.ID == "" {
+				continue
+			}
+			plugins = append(plugins, pluginInfo{ID: manifest.ID, Type: manifest.Type, Version: manifest.Info.Version})
+			break
+		}
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].ID < plugins[j].ID })
+	return plugins, nil
+}
+
+func provisioningSection(cfg *setting.Cfg) backup.Section {
+	return backup.Section{
+		UID:         "provisioning",
+		DisplayName: "Provisioning",
+		Description: "Files of the provisioning directory",
+		Backup: func(ctx context.Context) ([]backup.Item, error) {
+			return readFiles(cfg.ProvisioningPath, "")
+		},
+		Restore: func(ctx context.Context, items []backup.Item) error {
+			return writeFiles(cfg.ProvisioningPath, "", items)
+		},
+	}
+}
+
+// storageSection backs up the files of the storage service: the files stored in the database
+// and the files of the storage directory, which include the storage configuration.
+func storageSection(cfg *setting.Cfg, sqlStore db.DB) backup.Section {
+	dir := filepath.Join(cfg.DataPath, "storage")
+	return backup.Section{
+		UID:         "storage",
+		DisplayName: "File storage",
+		Description: "Files of the storage service",
+		Tables:      []string{"file", "file_meta"},
+		Backup: func(ctx context.Context) ([]backup.Item, error) {
+			return readFiles(dir, filesDir)
+		},
+		Restore: func(ctx context.Context, items []backup.Item) error {
+			if err := restoreTables(ctx, sqlStore, items); err != nil {
+				return err
+			}
+			return writeFiles(dir, filesDir, items)
+		},
+	}
+}
+
+// readFiles returns the regular files of the directory and its subdirectories, named after
+// their path relative to the directory with the prefix. The files are read when they are
+// written to the archive.
+func readFiles(dir string, prefix string) ([]backup.Item, error) {
+	var items []backup.Item
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir && errors.Is(err, fs.ErrNotExist) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		items = append(items, backup.Item{Filename: prefix + filepath.ToSlash(rel), Path: path})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the files of %s: %w", dir, err)
+	}
+	return items, nil
+}
+
+// writeFiles writes the files whose name has the prefix in the directory. Files that would be
+// written outside of the directory are rejected.
+func writeFiles(dir string, prefix string, items []backup.Item) error {
+	for _, item := range items {
+		name, ok := strings.CutPrefix(item.Filename, prefix)
+		if !ok {
+			continue
+		}
+
+		rel := filepath.FromSlash(name)
+		if !filepath.IsLocal(rel) {
+			return fmt.Errorf("invalid file name %s", item.Filename)
+		}
+		path := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			return err
+		}
+		if err := writeItem(path, item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeItem copies the content of the file of a section to a file at path.
+func writeItem(path string, item backup.Item) (err error) {
+	src, err := item.Open()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = src.Close() }()
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning since the path is checked to be in the directory
+	dst, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o640)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := dst.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	_, err = io.Copy(dst, src)
+	return err
+}
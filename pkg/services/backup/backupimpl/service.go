@@ -0,0 +1,379 @@
+package backupimpl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/backup"
+	"github.com/grafana/grafana/pkg/services/secrets/manager"
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	// lockActionName is the server lock that prevents backups and restores from running concurrently.
+	lockActionName = "instance-backup"
+	// lockMaxInterval is the time after which the lock of a backup or restore that did not
+	// release it is considered dead.
+	lockMaxInterval = time.Hour
+)
+
+var (
+	ErrInProgress   = errors.New("another backup or restore is in progress")
+	ErrInvalid      = errors.New("invalid backup archive")
+	ErrIncompatible = errors.New("incompatible backup")
+)
+
+type Service struct {
+	accessControl ac.AccessControl
+	cfg           *setting.Cfg
+	serverLock    *serverlock.ServerLockService
+	sqlStore      db.DB
+
+	log      log.Logger
+	sections []backup.Section
+}
+
+func ProvideService(
+	accessControl ac.AccessControl,
+	accesscontrolService ac.Service,
+	cfg *setting.Cfg,
+	routeRegister routing.RouteRegister,
+	secretsService *manager.SecretsService,
+	serverLock *serverlock.ServerLockService,
+	sqlStore db.DB,
+) (*Service, error) {
+	s := NewService(cfg, sqlStore, secretsService, serverLock)
+	s.accessControl = accessControl
+
+	if err := s.declareFixedRoles(accesscontrolService); err != nil {
+		return nil, err
+	}
+
+	s.registerAPIEndpoints(routeRegister)
+
+	return s, nil
+}
+
+// NewService creates a backup service with the built-in sections, without the API, for the CLI.
+func NewService(cfg *setting.Cfg, sqlStore db.DB, secretsService *manager.SecretsService, serverLock *serverlock.ServerLockService) *Service {
+	s := &Service{
+		cfg:        cfg,
+		log:        log.New("backup.service"),
+		serverLock: serverLock,
+		sqlStore:   sqlStore,
+	}
+
+	// the database section must be restored first
+	s.RegisterBackupSection(s.databaseSection())
+	s.RegisterBackupSection(secretsSection(sqlStore, secretsService))
+	s.RegisterBackupSection(pluginsSection(cfg, s.log))
+	s.RegisterBackupSection(provisioningSection(cfg))
+	s.RegisterBackupSection(storageSection(cfg, sqlStore))
+
+	return s
+}
+
+func (s *Service) RegisterBackupSection(section backup.Section) {
+	for i, registered := range s.sections {
+		if registered.UID == section.UID {
+			s.log.Warn("Backup section with the same UID already registered", "uid", section.UID)
+			s.sections[i] = section
+			return
+		}
+	}
+
+	s.sections = append(s.sections, section)
+}
+
+// Sections returns the registered sections in restore order.
+func (s *Service) Sections() []backup.Section {
+	return s.sections
+}
+
+// Create writes a backup archive of all the enabled sections to w.
+func (s *Service) Create(ctx context.Context, w io.Writer) (*backup.Manifest, error) {
+	var manifest *backup.Manifest
+	var backupErr error
+	err := s.withLock(ctx, func(ctx context.Context) {
+		manifest, backupErr = s.create(ctx, w)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return manifest, backupErr
+}
+
+// Verify checks the integrity of a backup archive and that it can be restored in the instance.
+func (s *Service) Verify(ctx context.Context, r io.Reader) (*backup.Manifest, error) {
+	dir, err := os.MkdirTemp("", "grafana-restore-")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	manifest, files, err := readArchive(r, dir)
+	if err != nil {
+		return nil, err
+	}
+	return manifest, s.verify(ctx, manifest, files)
+}
+
+// Restore replaces the data of the instance with the data of a backup archive. The archive
+// is verified before anything is restored.
+//
+// The restore is not transactional: the sections are restored one after the other and the
+// tables one transaction per table. If a section fails to be restored, the sections and the
+// tables restored before it keep the data of the backup, and the restore must be run again
+// once the cause of the failure is fixed.
+func (s *Service) Restore(ctx context.Context, r io.Reader) (*backup.Manifest, error) {
+	dir, err := os.MkdirTemp("", "grafana-restore-")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	manifest, files, err := readArchive(r, dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.verify(ctx, manifest, files); err != nil {
+		return manifest, err
+	}
+
+	var restoreErr error
+	err = s.withLock(ctx, func(ctx context.Context) {
+		restoreErr = s.restore(ctx, manifest, files)
+	})
+	if err != nil {
+		return manifest, err
+	}
+	return manifest, restoreErr
+}
+
+func (s *Service) withLock(ctx context.Context, fn func(ctx context.Context)) error {
+	err := s.serverLock.LockExecuteAndRelease(ctx, lockActionName, lockMaxInterval, fn)
+	var lockErr *serverlock.ServerLockExistsError
+	if errors.As(err, &lockErr) {
+		return ErrInProgress
+	}
+	return err
+}
+
+func (s *Service) create(ctx context.Context, w io.Writer) (*backup.Manifest, error) {
+	migrations, err := s.migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &backup.Manifest{
+		Version:      s.cfg.BuildVersion,
+		Commit:       s.cfg.BuildCommit,
+		CreatedAt:    time.Now().UTC(),
+		DatabaseType: string(s.sqlStore.GetDBType()),
+		Migrations:   migrations,
+	}
+
+	archive := newArchiveWriter(w, manifest.CreatedAt)
+	sections := make(map[string]*backup.ManifestSection, len(s.sections))
+	for _, section := range s.sections {
+		if section.EnabledFn != nil && !section.EnabledFn() {
+			continue
+		}
+
+		manifestSection := &backup.ManifestSection{UID: section.UID, Files: []backup.ManifestFile{}}
+		sections[section.UID] = manifestSection
+		if section.Backup == nil {
+			continue
+		}
+		items, err := section.Backup(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to back up section %s: %w", section.UID, err)
+		}
+		for _, item := range items {
+			if item.Path != "" {
+				err = writeFileFromDisk(archive, manifestSection, item.Filename, item.Path)
+			} else {
+				err = writeSectionFile(archive, manifestSection, item.Filename, int64(len(item.FileBytes)), bytes.NewReader(item.FileBytes))
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := s.writeTables(ctx, archive, sections); err != nil {
+		return nil, err
+	}
+
+	for _, section := range s.sections {
+		if manifestSection, ok := sections[section.UID]; ok {
+			manifest.Sections = append(manifest.Sections, *manifestSection)
+			s.log.Debug("Backed up section", "uid", section.UID, "files", len(manifestSection.Files))
+		}
+	}
+
+	if err := archive.close(manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// writeSectionFile writes a file of the section to the archive and lists it in the manifest of
+// the section.
+func writeSectionFile(archive *archiveWriter, section *backup.ManifestSection, filename string, size int64, r io.Reader) error {
+	for _, file := range section.Files {
+		if file.Name == filename {
+			return fmt.Errorf("section %s has several files named %s", section.UID, filename)
+		}
+	}
+
+	sum, err := archive.writeFile(section.UID+"/"+filename, size, r)
+	if err != nil {
+		return err
+	}
+	section.Files = append(section.Files, backup.ManifestFile{Name: filename, Size: size, SHA256: sum})
+	return nil
+}
+
+// writeFileFromDisk writes the file at path to the archive as a file of the section, without
+// loading it in memory.
+func writeFileFromDisk(archive *archiveWriter, section *backup.ManifestSection, filename string, path string) error {
+	// nolint:gosec
+	// We can ignore the gosec G304 warning since the path is a table dump or a file of a section directory
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	return writeSectionFile(archive, section, filename, info.Size(), f)
+}
+
+func (s *Service) restore(ctx context.Context, manifest *backup.Manifest, files map[string]string) error {
+	archived := make(map[string]backup.ManifestSection, len(manifest.Sections))
+	for _, section := range manifest.Sections {
+		archived[section.UID] = section
+	}
+
+	for _, section := range s.sections {
+		manifestSection, ok := archived[section.UID]
+		if !ok {
+			s.log.Warn("Backup does not contain section, skipping", "uid", section.UID)
+			continue
+		}
+		delete(archived, section.UID)
+
+		items := sectionItems(manifestSection, files)
+		if err := section.Restore(ctx, items); err != nil {
+			return fmt.Errorf("failed to restore section %s: %w", section.UID, err)
+		}
+		s.log.Info("Restored backup section", "uid", section.UID, "files", len(items))
+	}
+
+	for uid := range archived {
+		s.log.Warn("Backup section is not known to this instance, skipping", "uid", uid)
+	}
+	return nil
+}
+
+// verify checks that the backup can be restored: the database must have run the same migrations
+// and every section of the backup must pass the verification of the section.
+func (s *Service) verify(ctx context.Context, manifest *backup.Manifest, files map[string]string) error {
+	if err := s.checkCompatibility(manifest); err != nil {
+		return err
+	}
+
+	sections := make(map[string]backup.Section, len(s.sections))
+	for _, section := range s.sections {
+		sections[section.UID] = section
+	}
+	for _, manifestSection := range manifest.Sections {
+		section, ok := sections[manifestSection.UID]
+		if !ok || section.Verify == nil {
+			continue
+		}
+		if err := section.Verify(ctx, sectionItems(manifestSection, files)); err != nil {
+			return fmt.Errorf("section %s cannot be restored: %w", section.UID, err)
+		}
+	}
+	return nil
+}
+
+// sectionItems returns the files of the archive that belong to the section.
+func sectionItems(section backup.ManifestSection, files map[string]string) []backup.Item {
+	items := make([]backup.Item, 0, len(section.Files))
+	for _, file := range section.Files {
+		items = append(items, backup.Item{Filename: file.Name, Path: files[section.UID+"/"+file.Name]})
+	}
+	return items
+}
+
+// migrations returns the IDs of the successful migrations of the database.
+func (s *Service) migrations() ([]string, error) {
+	migrationLog, err := migrator.NewMigrator(s.sqlStore.GetEngine(), s.cfg).GetMigrationLog()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the migration log: %w", err)
+	}
+
+	ids := make([]string, 0, len(migrationLog))
+	for id := range migrationLog {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// checkCompatibility checks that the database has run the same migrations as the database of the
+// backup, which means that the tables of the backup match the tables of the database.
+func (s *Service) checkCompatibility(manifest *backup.Manifest) error {
+	current, err := s.migrations()
+	if err != nil {
+		return err
+	}
+
+	archived := make(map[string]struct{}, len(manifest.Migrations))
+	for _, id := range manifest.Migrations {
+		archived[id] = struct{}{}
+	}
+
+	var missing []string
+	for _, id := range current {
+		if _, ok := archived[id]; ok {
+			delete(archived, id)
+			continue
+		}
+		missing = append(missing, id)
+	}
+
+	if len(archived) > 0 {
+		unknown := make([]string, 0, len(archived))
+		for id := range archived {
+			unknown = append(unknown, id)
+		}
+		sort.Strings(unknown)
+		return fmt.Errorf("%w: the backup was created by Grafana %s, which ran migrations unknown to this version: %s",
+			ErrIncompatible, manifest.Version, strings.Join(unknown, ", "))
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: the backup was created by Grafana %s before the migrations %s, restore it with that version and upgrade afterwards",
+			ErrIncompatible, manifest.Version, strings.Join(missing, ", "))
+	}
+	return nil
+}
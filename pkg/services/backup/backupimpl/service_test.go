@@ -0,0 +1,312 @@
+package backupimpl
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/backup"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/secrets/database"
+	secretsManager "github.com/grafana/grafana/pkg/services/secrets/manager"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+func TestIntegrationBackupAndRestore(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore, cfg := db.InitTestDBWithCfg(t)
+	cfg.PluginsPath = t.TempDir()
+	cfg.ProvisioningPath = t.TempDir()
+	cfg.DataPath = t.TempDir()
+	writeFile(t, filepath.Join(cfg.PluginsPath, "my-panel", "plugin.json"), `{"id":"my-panel","type":"panel","info":{"version":"1.2.3"}}`)
+	writeFile(t, filepath.Join(cfg.ProvisioningPath, "datasources", "datasources.yaml"), "apiVersion: 1\n")
+	writeFile(t, filepath.Join(cfg.DataPath, "storage", "storage.json"), `{"roots":[]}`)
+
+	secretsService := secretsManager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
+	serverLock := serverlock.ProvideService(sqlStore, tracing.InitializeTracerForTest())
+	s := NewService(cfg, sqlStore, secretsService, serverLock)
+
+	now := time.Date(2024, 3, 1, 12, 30, 45, 0, time.UTC)
+	_, err := sqlStore.GetEngine().Exec("INSERT INTO dashboard (version, slug, title, data, org_id, created, updated, uid, is_folder) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		1, "dash", "Dash", `{"title":"Dash"}`, 1, now, now, "dash-uid", false)
+	require.NoError(t, err)
+	encrypted, err := secretsService.Encrypt(context.Background(), []byte("secret"), secrets.WithoutScope())
+	require.NoError(t, err)
+
+	var archive bytes.Buffer
+	manifest, err := s.Create(context.Background(), &archive)
+	require.NoError(t, err)
+	require.Len(t, manifest.Sections, 5)
+	assert.NotEmpty(t, manifest.Migrations)
+
+	t.Run("verify accepts the archive", func(t *testing.T) {
+		verified, err := s.Verify(context.Background(), bytes.NewReader(archive.Bytes()))
+		require.NoError(t, err)
+		assert.Equal(t, manifest.Migrations, verified.Migrations)
+	})
+
+	t.Run("restore refuses a backup whose secrets cannot be decrypted before restoring anything", func(t *testing.T) {
+		read, files := readArchiveFiles(t, bytes.NewReader(archive.Bytes()))
+		// the check value encrypted with another secret key
+		name := "secrets/" + secretsCheckFilename
+		files[name], err = json.Marshal(map[string][]byte{"secretKey.v1": bytes.Repeat([]byte("encrypted with another key"), 4)})
+		require.NoError(t, err)
+		for _, section := range read.Sections {
+			for i, file := range section.Files {
+				if section.UID+"/"+file.Name == name {
+					section.Files[i] = manifestFile(backup.Item{Filename: file.Name, FileBytes: files[name]})
+				}
+			}
+		}
+		var buf bytes.Buffer
+		require.NoError(t, writeArchive(&buf, read, files))
+
+		_, err = s.Verify(context.Background(), bytes.NewReader(buf.Bytes()))
+		require.ErrorIs(t, err, ErrIncompatible)
+
+		_, err = sqlStore.GetEngine().Exec("UPDATE dashboard SET title = ? WHERE uid = ?", "Changed", "dash-uid")
+		require.NoError(t, err)
+		_, err = s.Restore(context.Background(), &buf)
+		require.ErrorIs(t, err, ErrIncompatible)
+
+		var titles []string
+		require.NoError(t, sqlStore.GetEngine().Table("dashboard").Where("uid = ?", "dash-uid").Cols("title").Find(&titles))
+		assert.Equal(t, []string{"Changed"}, titles)
+	})
+
+	t.Run("the API streams the archive", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		s.handleCreate(newCreateRequest(recorder))
+
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "application/gzip", recorder.Header().Get("Content-Type"))
+		_, err := s.Verify(context.Background(), recorder.Body)
+		require.NoError(t, err)
+	})
+
+	t.Run("database section does not contain the tables of other sections", func(t *testing.T) {
+		for _, section := range manifest.Sections {
+			if section.UID != "database" {
+				continue
+			}
+			for _, file := range section.Files {
+				assert.NotContains(t, []string{"tables/data_keys.json", "tables/file.json", "tables/migration_log.json", "tables/server_lock.json"}, file.Name)
+			}
+		}
+	})
+
+	t.Run("restore replaces the data", func(t *testing.T) {
+		_, err := sqlStore.GetEngine().Exec("DELETE FROM dashboard")
+		require.NoError(t, err)
+		_, err = sqlStore.GetEngine().Exec("DELETE FROM data_keys")
+		require.NoError(t, err)
+		require.NoError(t, os.RemoveAll(cfg.ProvisioningPath))
+		require.NoError(t, os.RemoveAll(filepath.Join(cfg.DataPath, "storage")))
+
+		_, err = s.Restore(context.Background(), bytes.NewReader(archive.Bytes()))
+		require.NoError(t, err)
+
+		var dashboards []struct {
+			Title   string    `xorm:"title"`
+			Created time.Time `xorm:"created"`
+		}
+		require.NoError(t, sqlStore.GetEngine().Table("dashboard").Where("uid = ?", "dash-uid").Find(&dashboards))
+		require.Len(t, dashboards, 1)
+		assert.Equal(t, "Dash", dashboards[0].Title)
+		assert.True(t, now.Equal(dashboards[0].Created))
+
+		decrypted, err := secretsService.Decrypt(context.Background(), encrypted)
+		require.NoError(t, err)
+		assert.Equal(t, "secret", string(decrypted))
+
+		data, err := os.ReadFile(filepath.Join(cfg.ProvisioningPath, "datasources", "datasources.yaml"))
+		require.NoError(t, err)
+		assert.Equal(t, "apiVersion: 1\n", string(data))
+		assert.FileExists(t, filepath.Join(cfg.DataPath, "storage", "storage.json"))
+	})
+
+	t.Run("restore refuses a backup of another version", func(t *testing.T) {
+		other := *manifest
+		other.Sections = nil
+		other.Migrations = append([]string{"migration of a newer version"}, manifest.Migrations...)
+		var buf bytes.Buffer
+		require.NoError(t, writeArchive(&buf, &other, map[string][]byte{}))
+
+		_, err := s.Restore(context.Background(), &buf)
+		require.ErrorIs(t, err, ErrIncompatible)
+		require.ErrorContains(t, err, "migration of a newer version")
+	})
+
+	t.Run("backups do not run concurrently", func(t *testing.T) {
+		err := serverLock.LockExecuteAndRelease(context.Background(), lockActionName, lockMaxInterval, func(ctx context.Context) {
+			_, err := s.Create(ctx, &bytes.Buffer{})
+			require.ErrorIs(t, err, ErrInProgress)
+
+			recorder := httptest.NewRecorder()
+			s.handleCreate(newCreateRequest(recorder))
+			require.Equal(t, http.StatusConflict, recorder.Code)
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestReadArchive(t *testing.T) {
+	manifest := &backup.Manifest{
+		Version: "11.0.0",
+		Sections: []backup.ManifestSection{
+			{UID: "section", Files: []backup.ManifestFile{manifestFile(backup.Item{Filename: "file.json", FileBytes: []byte("{}")})}},
+		},
+	}
+
+	t.Run("valid archive", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeArchive(&buf, manifest, map[string][]byte{"section/file.json": []byte("{}")}))
+
+		read, files := readArchiveFiles(t, &buf)
+		assert.Equal(t, manifest, read)
+		assert.Equal(t, map[string][]byte{"section/file.json": []byte("{}")}, files)
+	})
+
+	t.Run("modified file", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeArchive(&buf, manifest, map[string][]byte{"section/file.json": []byte("[]")}))
+
+		_, _, err := readArchive(&buf, t.TempDir())
+		require.ErrorIs(t, err, ErrInvalid)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeArchive(&buf, manifest, map[string][]byte{}))
+
+		_, _, err := readArchive(&buf, t.TempDir())
+		require.ErrorIs(t, err, ErrInvalid)
+	})
+
+	t.Run("file not in the manifest", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeArchive(&buf, manifest, map[string][]byte{"section/file.json": []byte("{}"), "section/other.json": nil}))
+
+		_, _, err := readArchive(&buf, t.TempDir())
+		require.ErrorIs(t, err, ErrInvalid)
+	})
+
+	t.Run("not an archive", func(t *testing.T) {
+		_, _, err := readArchive(bytes.NewReader([]byte("backup")), t.TempDir())
+		require.ErrorIs(t, err, ErrInvalid)
+	})
+}
+
+func TestWriteFiles(t *testing.T) {
+	dir := t.TempDir()
+	require.Error(t, writeFiles(dir, "", []backup.Item{{Filename: "../outside", FileBytes: []byte("x")}}))
+	require.NoError(t, writeFiles(dir, "files/", []backup.Item{{Filename: "files/a/b.json", FileBytes: []byte("x")}, {Filename: "tables/t.json"}}))
+	assert.FileExists(t, filepath.Join(dir, "a", "b.json"))
+	assert.NoFileExists(t, filepath.Join(dir, "tables", "t.json"))
+}
+
+func TestOpenTableDump(t *testing.T) {
+	t.Run("reads the rows one at a time", func(t *testing.T) {
+		dump, err := openTableDump(backup.Item{FileBytes: []byte(`{"columns":["id","name"],"rows":[[1,"a"],[2,null]]}`)})
+		require.NoError(t, err)
+		defer func() { _ = dump.Close() }()
+		assert.Equal(t, []string{"id", "name"}, dump.columns)
+
+		var rows [][]any
+		var row []any
+		for {
+			ok, err := dump.next(&row)
+			require.NoError(t, err)
+			if !ok {
+				break
+			}
+			rows = append(rows, append([]any{}, row...))
+		}
+		assert.Equal(t, [][]any{{json.Number("1"), "a"}, {json.Number("2"), nil}}, rows)
+	})
+
+	t.Run("rows before the columns", func(t *testing.T) {
+		_, err := openTableDump(backup.Item{FileBytes: []byte(`{"rows":[],"columns":["id"]}`)})
+		require.Error(t, err)
+	})
+
+	t.Run("row with missing values", func(t *testing.T) {
+		dump, err := openTableDump(backup.Item{FileBytes: []byte(`{"columns":["id","name"],"rows":[[1]]}`)})
+		require.NoError(t, err)
+		var row []any
+		_, err = dump.next(&row)
+		require.Error(t, err)
+	})
+}
+
+func newCreateRequest(recorder *httptest.ResponseRecorder) *contextmodel.ReqContext {
+	req := httptest.NewRequest(http.MethodPost, rootUrl, nil)
+	return &contextmodel.ReqContext{
+		Context: &web.Context{Req: req, Resp: web.NewResponseWriter(req.Method, recorder)},
+		Logger:  log.NewNopLogger(),
+	}
+}
+
+func writeArchive(w io.Writer, manifest *backup.Manifest, files map[string][]byte) error {
+	archive := newArchiveWriter(w, manifest.CreatedAt)
+	for name, data := range files {
+		if _, err := archive.writeFile(name, int64(len(data)), bytes.NewReader(data)); err != nil {
+			return err
+		}
+	}
+	return archive.close(manifest)
+}
+
+// readArchiveFiles reads an archive and returns the content of its files by name.
+func readArchiveFiles(t *testing.T, r io.Reader) (*backup.Manifest, map[string][]byte) {
+	t.Helper()
+	manifest, paths, err := readArchive(r, t.TempDir())
+	require.NoError(t, err)
+
+	files := make(map[string][]byte, len(paths))
+	for name, path := range paths {
+		files[name], err = os.ReadFile(path)
+		require.NoError(t, err)
+	}
+	return manifest, files
+}
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func manifestFile(item backup.Item) backup.ManifestFile {
+	sum := sha256.Sum256(item.FileBytes)
+	return backup.ManifestFile{
+		Name:   item.Filename,
+		Size:   int64(len(item.FileBytes)),
+		SHA256: hex.EncodeToString(sum[:]),
+	}
+}
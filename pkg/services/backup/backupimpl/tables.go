@@ -0,0 +1,320 @@
+package backupimpl
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/backup"
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+	"github.com/grafana/grafana/pkg/services/sqlstore/tabledata"
+)
+
+const tablesDir = "tables/"
+
+// tableDumpReader reads the file of a table in a backup, which is a JSON object with the
+// columns of the table followed by its rows. The values are stored in the same way for all
+// databases: booleans as JSON booleans, times as RFC 3339 strings and binary data as base64
+// strings. The rows are decoded one at a time, so that the table is not loaded in memory.
+type tableDumpReader struct {
+	closer  io.Closer
+	decoder *json.Decoder
+	columns []string
+}
+
+// writeTables writes the files of the tables of the database to the sections that own them, the
+// tables that are not owned by a section belong to the database section. The tables are read from
+// one snapshot of the database and written to temporary files, so that the transaction of the
+// snapshot does not stay open while the archive is written.
+func (s *Service) writeTables(ctx context.Context, archive *archiveWriter, sections map[string]*backup.ManifestSection) error {
+	tables, err := tabledata.ReadTables(s.sqlStore)
+	if err != nil {
+		return fmt.Errorf("failed to read the tables of the database: %w", err)
+	}
+	owners := make(map[string]string)
+	for _, section := range s.sections {
+		for _, table := range section.Tables {
+			owners[table] = section.UID
+		}
+	}
+
+	dir, err := os.MkdirTemp("", "grafana-backup-")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	type tableFile struct {
+		section string
+		table   string
+		path    string
+	}
+	var files []tableFile
+	err = func() error {
+		snapshot, err := tabledata.BeginSnapshot(ctx, s.sqlStore)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = snapshot.Close() }()
+
+		for _, t := range tables {
+			owner, ok := owners[t.Name]
+			if !ok {
+				owner = databaseSectionUID
+			}
+			// the tables of disabled sections are not backed up
+			if _, ok := sections[owner]; !ok || excludedTables[t.Name] {
+				continue
+			}
+
+			path := filepath.Join(dir, t.Name+".json")
+			if err := dumpTable(ctx, snapshot, t, path); err != nil {
+				return fmt.Errorf("failed to read table %s: %w", t.Name, err)
+			}
+			files = append(files, tableFile{section: owner, table: t.Name, path: path})
+		}
+		return nil
+	}()
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if err := writeFileFromDisk(archive, sections[file.section], tablesDir+file.table+".json", file.path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dumpTable writes the rows of the table read from the snapshot to a file at path.
+func dumpTable(ctx context.Context, snapshot *tabledata.Snapshot, t *tabledata.Table, path string) (err error) {
+	// nolint:gosec
+	// We can ignore the gosec G304 warning since the path is in the temporary directory of the backup
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	columns, err := json.Marshal(t.Columns)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	if _, err := fmt.Fprintf(w, `{"columns":%s,"rows":[`, columns); err != nil {
+		return err
+	}
+
+	first := true
+	row := make([]any, len(t.Columns))
+	err = snapshot.Scan(ctx, t, func(values []any) error {
+		for i, value := range values {
+			converted, err := tabledata.ConvertValue(value, t.Kinds[i])
+			if err != nil {
+				return fmt.Errorf("column %s: %w", t.Columns[i], err)
+			}
+			row[i] = converted
+		}
+		data, err := json.Marshal(row)
+		if err != nil {
+			return err
+		}
+		if !first {
+			if err := w.WriteByte(','); err != nil {
+				return err
+			}
+		}
+		first = false
+		_, err = w.Write(data)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.WriteString("]}"); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// openTableDump opens the file of a table and reads its columns. Numbers are kept as
+// json.Number so that integers are not converted to floating point numbers.
+func openTableDump(item backup.Item) (*tableDumpReader, error) {
+	f, err := item.Open()
+	if err != nil {
+		return nil, err
+	}
+	d := &tableDumpReader{closer: f, decoder: json.NewDecoder(bufio.NewReader(f))}
+	d.decoder.UseNumber()
+
+	if err := d.readHeader(); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return d, nil
+}
+
+// readHeader reads the object up to the start of the rows, which must come after the columns.
+func (d *tableDumpReader) readHeader() error {
+	if err := d.expectDelim('{'); err != nil {
+		return err
+	}
+	for _, key := range []string{"columns", "rows"} {
+		token, err := d.decoder.Token()
+		if err != nil {
+			return err
+		}
+		if token != key {
+			return fmt.Errorf("expected %q, found %v", key, token)
+		}
+		if key == "columns" {
+			if err := d.decoder.Decode(&d.columns); err != nil {
+				return err
+			}
+		}
+	}
+	return d.expectDelim('[')
+}
+
+// next decodes the next row into row and returns false when there are no more rows.
+func (d *tableDumpReader) next(row *[]any) (bool, error) {
+	if !d.decoder.More() {
+		if err := d.expectDelim(']'); err != nil {
+			return false, err
+		}
+		return false, d.expectDelim('}')
+	}
+	*row = (*row)[:0]
+	if err := d.decoder.Decode(row); err != nil {
+		return false, err
+	}
+	if len(*row) != len(d.columns) {
+		return false, fmt.Errorf("row has %d values, expected %d", len(*row), len(d.columns))
+	}
+	return true, nil
+}
+
+func (d *tableDumpReader) expectDelim(delim json.Delim) error {
+	token, err := d.decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("expected %v, found %v", delim, token)
+	}
+	return nil
+}
+
+func (d *tableDumpReader) Close() error {
+	return d.closer.Close()
+}
+
+// restoreTables replaces the rows of the tables of the database with the rows of the table
+// files, in dependency order and one transaction per table. Columns that no longer exist in
+// the database are ignored.
+func restoreTables(ctx context.Context, store db.DB, items []backup.Item) error {
+	current, err := tabledata.ReadTables(store)
+	if err != nil {
+		return fmt.Errorf("failed to read the tables of the database: %w", err)
+	}
+	currentByName := make(map[string]*tabledata.Table, len(current))
+	for _, t := range current {
+		currentByName[t.Name] = t
+	}
+
+	files := make(map[string]backup.Item)
+	tables := make([]*tabledata.Table, 0, len(items))
+	for _, item := range items {
+		name, ok := strings.CutPrefix(item.Filename, tablesDir)
+		if !ok || path.Ext(name) != ".json" {
+			continue
+		}
+		name = strings.TrimSuffix(name, ".json")
+
+		table, ok := currentByName[name]
+		if !ok {
+			return fmt.Errorf("table %s does not exist in the database", name)
+		}
+		files[name] = item
+		tables = append(tables, table)
+	}
+
+	dialect := store.GetDialect()
+	for _, table := range tabledata.SortTables(tables) {
+		if err := restoreTable(ctx, store, dialect, table, files[table.Name]); err != nil {
+			return fmt.Errorf("failed to restore table %s: %w", table.Name, err)
+		}
+	}
+	return nil
+}
+
+// restoreTable replaces the rows of the table with the rows of its file in one transaction,
+// inserting the rows as they are read from the file.
+func restoreTable(ctx context.Context, store db.DB, dialect migrator.Dialect, table *tabledata.Table, item backup.Item) error {
+	dump, err := openTableDump(item)
+	if err != nil {
+		return fmt.Errorf("failed to read the file: %w", err)
+	}
+	defer func() { _ = dump.Close() }()
+
+	kinds := make(map[string]tabledata.ColumnKind, len(table.Columns))
+	for i, column := range table.Columns {
+		kinds[column] = table.Kinds[i]
+	}
+	t := &tabledata.Table{Name: table.Name, Sequences: table.Sequences}
+	var indexes []int
+	for i, column := range dump.columns {
+		if kind, ok := kinds[column]; ok {
+			t.Columns = append(t.Columns, column)
+			t.Kinds = append(t.Kinds, kind)
+			indexes = append(indexes, i)
+		}
+	}
+
+	return store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if err := tabledata.Truncate(sess, dialect, t); err != nil {
+			return err
+		}
+
+		var row []any
+		values := make([]any, len(t.Columns))
+		for {
+			ok, err := dump.next(&row)
+			if err != nil {
+				return fmt.Errorf("failed to read the file: %w", err)
+			}
+			if !ok {
+				break
+			}
+			for i, index := range indexes {
+				value := row[index]
+				if s, ok := value.(string); ok && t.Kinds[i] == tabledata.KindBlob {
+					decoded, err := base64.StdEncoding.DecodeString(s)
+					if err != nil {
+						return fmt.Errorf("column %s: %w", t.Columns[i], err)
+					}
+					value = decoded
+				}
+				values[i] = value
+			}
+			if err := tabledata.Insert(sess, dialect, t, values); err != nil {
+				return err
+			}
+		}
+		return tabledata.ResetSequences(sess, dialect, t)
+	})
+}
@@ -0,0 +1,128 @@
+package caching
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+const (
+	queryKeyPrefix    = "query-cache:query:"
+	resourceKeyPrefix = "query-cache:resource:"
+)
+
+// volatileQueryFields are query model fields that change between otherwise identical
+// requests and must not be part of the cache key.
+var volatileQueryFields = []string{"requestId", "queryCachingTTL", "datasourceId", "key"}
+
+type dataSourceKey struct {
+	OrgID    int64     `json:"orgId"`
+	PluginID string    `json:"pluginId"`
+	UID      string    `json:"uid,omitempty"`
+	Updated  time.Time `json:"updated,omitempty"`
+}
+
+type queryKey struct {
+	RefID         string          `json:"refId"`
+	QueryType     string          `json:"queryType,omitempty"`
+	MaxDataPoints int64           `json:"maxDataPoints,omitempty"`
+	Interval      time.Duration   `json:"interval,omitempty"`
+	From          int64           `json:"from"`
+	To            int64           `json:"to"`
+	Model         json.RawMessage `json:"model,omitempty"`
+}
+
+// queryCacheKey builds a cache key from the normalized query request. The time range of every query
+// is aligned to its interval so that repeated refreshes within the same interval share a key. The data
+// source's last update time is part of the key, so updating a data source invalidates its cached responses.
+func queryCacheKey(req *backend.QueryDataRequest) (string, error) {
+	queries := make([]queryKey, 0, len(req.Queries))
+	for _, q := range req.Queries {
+		model, err := normalizeQueryModel(q.JSON)
+		if err != nil {
+			return "", err
+		}
+		from, to := alignTimeRange(q.TimeRange, q.Interval)
+		queries = append(queries, queryKey{
+			RefID:         q.RefID,
+			QueryType:     q.QueryType,
+			MaxDataPoints: q.MaxDataPoints,
+			Interval:      q.Interval,
+			From:          from,
+			To:            to,
+			Model:         model,
+		})
+	}
+
+	return hashKey(queryKeyPrefix, struct {
+		DataSource dataSourceKey `json:"datasource"`
+		Queries    []queryKey    `json:"queries"`
+	}{
+		DataSource: newDataSourceKey(req.PluginContext),
+		Queries:    queries,
+	})
+}
+
+// resourceCacheKey builds a cache key from the resource request path, URL and body.
+func resourceCacheKey(req *backend.CallResourceRequest) (string, error) {
+	return hashKey(resourceKeyPrefix, struct {
+		DataSource dataSourceKey `json:"datasource"`
+		Path       string        `json:"path"`
+		Method     string        `json:"method"`
+		URL        string        `json:"url"`
+		Body       []byte        `json:"body,omitempty"`
+	}{
+		DataSource: newDataSourceKey(req.PluginContext),
+		Path:       req.Path,
+		Method:     req.Method,
+		URL:        req.URL,
+		Body:       req.Body,
+	})
+}
+
+func newDataSourceKey(pCtx backend.PluginContext) dataSourceKey {
+	k := dataSourceKey{
+		OrgID:    pCtx.OrgID,
+		PluginID: pCtx.PluginID,
+	}
+	if ds := pCtx.DataSourceInstanceSettings; ds != nil {
+		k.UID = ds.UID
+		k.Updated = ds.Updated.UTC()
+	}
+	return k
+}
+
+// normalizeQueryModel removes volatile fields from the query model. Re-encoding the
+// model as a map also sorts its keys, so equivalent models produce identical output.
+func normalizeQueryModel(raw json.RawMessage) (json.RawMessage, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	model := map[string]any{}
+	if err := json.Unmarshal(raw, &model); err != nil {
+		return nil, err
+	}
+	for _, f := range volatileQueryFields {
+		delete(model, f)
+	}
+	return json.Marshal(model)
+}
+
+func alignTimeRange(tr backend.TimeRange, interval time.Duration) (int64, int64) {
+	if interval <= 0 {
+		return tr.From.UnixMilli(), tr.To.UnixMilli()
+	}
+	return tr.From.Truncate(interval).UnixMilli(), tr.To.Truncate(interval).UnixMilli()
+}
+
+func hashKey(prefix string, v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return prefix + hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,111 @@
+package caching
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+)
+
+// memoryStorage is a remotecache.CacheStorage kept in the memory of the local instance.
+// The total size of the values is limited to maxBytes: when it is exceeded, the least
+// recently used entries are evicted.
+type memoryStorage struct {
+	maxBytes int
+	now      func() time.Time
+
+	mu    sync.Mutex
+	size  int
+	lru   *list.List // of *memoryEntry, most recently used first
+	items map[string]*list.Element
+}
+
+type memoryEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+func newMemoryStorage(maxBytes int) *memoryStorage {
+	return &memoryStorage{
+		maxBytes: maxBytes,
+		now:      time.Now,
+		lru:      list.New(),
+		items:    map[string]*list.Element{},
+	}
+}
+
+func (m *memoryStorage) Get(_ context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	elem, ok := m.items[key]
+	if !ok {
+		return nil, remotecache.ErrCacheItemNotFound
+	}
+	entry := elem.Value.(*memoryEntry)
+	if !entry.expires.IsZero() && !m.now().Before(entry.expires) {
+		m.remove(elem)
+		return nil, remotecache.ErrCacheItemNotFound
+	}
+	m.lru.MoveToFront(elem)
+	return entry.value, nil
+}
+
+func (m *memoryStorage) Set(_ context.Context, key string, value []byte, expire time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if elem, ok := m.items[key]; ok {
+		m.remove(elem)
+	}
+	// a value larger than the cache would evict everything else and then itself
+	if len(value) > m.maxBytes {
+		return nil
+	}
+
+	entry := &memoryEntry{key: key, value: value}
+	if expire > 0 {
+		entry.expires = m.now().Add(expire)
+	}
+	m.items[key] = m.lru.PushFront(entry)
+	m.size += len(value)
+	m.evict()
+	return nil
+}
+
+func (m *memoryStorage) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if elem, ok := m.items[key]; ok {
+		m.remove(elem)
+	}
+	return nil
+}
+
+// evict removes the expired entries, then the least recently used ones, until the values
+// fit in maxBytes.
+func (m *memoryStorage) evict() {
+	if m.size <= m.maxBytes {
+		return
+	}
+	now := m.now()
+	for elem := m.lru.Back(); elem != nil; {
+		prev := elem.Prev()
+		if expires := elem.Value.(*memoryEntry).expires; !expires.IsZero() && !now.Before(expires) {
+			m.remove(elem)
+		}
+		elem = prev
+	}
+	for m.size > m.maxBytes {
+		m.remove(m.lru.Back())
+	}
+}
+
+func (m *memoryStorage) remove(elem *list.Element) {
+	entry := m.lru.Remove(elem).(*memoryEntry)
+	delete(m.items, entry.key)
+	m.size -= len(entry.value)
+}
+
+var _ remotecache.CacheStorage = &memoryStorage{}
@@ -0,0 +1,87 @@
+package caching
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+)
+
+func TestMemoryStorage(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(0, 0)
+	newStorage := func(maxBytes int) *memoryStorage {
+		m := newMemoryStorage(maxBytes)
+		m.now = func() time.Time { return now }
+		return m
+	}
+	requireValue := func(t *testing.T, m *memoryStorage, key string, expected string) {
+		t.Helper()
+		value, err := m.Get(ctx, key)
+		require.NoError(t, err)
+		require.Equal(t, expected, string(value))
+	}
+	requireMissing := func(t *testing.T, m *memoryStorage, key string) {
+		t.Helper()
+		_, err := m.Get(ctx, key)
+		require.ErrorIs(t, err, remotecache.ErrCacheItemNotFound)
+	}
+
+	t.Run("expires values after their TTL", func(t *testing.T) {
+		m := newStorage(100)
+		require.NoError(t, m.Set(ctx, "a", []byte("aaa"), time.Minute))
+		requireValue(t, m, "a", "aaa")
+
+		now = now.Add(time.Minute)
+		requireMissing(t, m, "a")
+		require.Zero(t, m.size)
+	})
+
+	t.Run("evicts the least recently used values over the size limit", func(t *testing.T) {
+		m := newStorage(10)
+		require.NoError(t, m.Set(ctx, "a", []byte("aaaa"), time.Minute))
+		require.NoError(t, m.Set(ctx, "b", []byte("bbbb"), time.Minute))
+		requireValue(t, m, "a", "aaaa")
+
+		require.NoError(t, m.Set(ctx, "c", []byte("cccc"), time.Minute))
+		requireMissing(t, m, "b")
+		requireValue(t, m, "a", "aaaa")
+		requireValue(t, m, "c", "cccc")
+		require.Equal(t, 8, m.size)
+	})
+
+	t.Run("evicts expired values before the least recently used ones", func(t *testing.T) {
+		m := newStorage(10)
+		require.NoError(t, m.Set(ctx, "a", []byte("aaaa"), time.Hour))
+		require.NoError(t, m.Set(ctx, "b", []byte("bbbb"), time.Minute))
+
+		now = now.Add(time.Minute)
+		require.NoError(t, m.Set(ctx, "c", []byte("cccc"), time.Hour))
+		requireValue(t, m, "a", "aaaa")
+		requireValue(t, m, "c", "cccc")
+		require.Len(t, m.items, 2)
+	})
+
+	t.Run("does not keep values larger than the size limit", func(t *testing.T) {
+		m := newStorage(10)
+		require.NoError(t, m.Set(ctx, "a", []byte("aaaa"), time.Minute))
+		require.NoError(t, m.Set(ctx, "a", []byte("aaaaaaaaaaa"), time.Minute))
+		requireMissing(t, m, "a")
+		require.Zero(t, m.size)
+	})
+
+	t.Run("replaces and deletes values", func(t *testing.T) {
+		m := newStorage(10)
+		require.NoError(t, m.Set(ctx, "a", []byte("aaaa"), time.Minute))
+		require.NoError(t, m.Set(ctx, "a", []byte("aa"), time.Minute))
+		requireValue(t, m, "a", "aa")
+		require.Equal(t, 2, m.size)
+
+		require.NoError(t, m.Delete(ctx, "a"))
+		requireMissing(t, m, "a")
+		require.Zero(t, m.size)
+	})
+}
@@ -2,8 +2,18 @@ package caching
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/services/contexthandler"
+	"github.com/grafana/grafana/pkg/setting"
 )
 
 const (
@@ -15,6 +25,9 @@ const (
 	StatusDisabled = "DISABLED"
 )
 
+// XCacheSkipHeader can be set to "true" on an incoming request to bypass the cache.
+const XCacheSkipHeader = "X-Cache-Skip"
+
 type CacheQueryResponseFn func(context.Context, *backend.QueryDataResponse)
 type CacheResourceResponseFn func(context.Context, *backend.CallResourceResponse)
 
@@ -35,10 +48,6 @@ type CachedResourceDataResponse struct {
 	UpdateCacheFn CacheResourceResponseFn
 }
 
-func ProvideCachingService() *OSSCachingService {
-	return &OSSCachingService{}
-}
-
 type CachingService interface {
 	// HandleQueryRequest uses a QueryDataRequest to check the cache for any existing results for that query.
 	// If none are found, it should return false and a CachedQueryDataResponse with an UpdateCacheFn which can be used to update the results cache after the fact.
@@ -49,16 +58,199 @@ type CachingService interface {
 	HandleResourceRequest(context.Context, *backend.CallResourceRequest) (bool, CachedResourceDataResponse)
 }
 
-// Implementation of interface - does nothing
+func ProvideCachingService(cfg *setting.Cfg, remoteCache remotecache.CacheStorage) *OSSCachingService {
+	s := &OSSCachingService{
+		settings: cfg.QueryCaching,
+		log:      log.New("query-caching"),
+	}
+
+	if !s.settings.Enabled {
+		return s
+	}
+
+	switch s.settings.Backend {
+	case setting.QueryCachingBackendRemote:
+		s.storage = remoteCache
+	default:
+		s.storage = newMemoryStorage(s.settings.MaxMemoryBytes)
+	}
+
+	return s
+}
+
+// OSSCachingService caches query and resource responses in either a local in-memory cache
+// or the configured remote cache. The zero value is a disabled cache that always misses.
 type OSSCachingService struct {
+	settings setting.QueryCachingSettings
+	storage  remotecache.CacheStorage
+	log      log.Logger
 }
 
 func (s *OSSCachingService) HandleQueryRequest(ctx context.Context, req *backend.QueryDataRequest) (bool, CachedQueryDataResponse) {
-	return false, CachedQueryDataResponse{}
+	if !s.enabled() {
+		return false, CachedQueryDataResponse{}
+	}
+
+	if shouldBypass(ctx, req.GetHTTPHeaders()) {
+		setCacheStatus(ctx, StatusBypass)
+		return false, CachedQueryDataResponse{}
+	}
+
+	key, err := queryCacheKey(req)
+	if err != nil {
+		s.log.Warn("Failed to build query cache key", "error", err)
+		setCacheStatus(ctx, StatusError)
+		return false, CachedQueryDataResponse{}
+	}
+
+	if cached, err := s.storage.Get(ctx, key); err == nil {
+		resp := &backend.QueryDataResponse{}
+		if err := json.Unmarshal(cached, resp); err == nil {
+			setCacheStatus(ctx, StatusHit)
+			return true, CachedQueryDataResponse{Response: resp}
+		}
+		s.log.Warn("Failed to decode cached query response", "error", err)
+	} else if !errors.Is(err, remotecache.ErrCacheItemNotFound) {
+		s.log.Warn("Failed to read from query cache", "error", err)
+		setCacheStatus(ctx, StatusError)
+		return false, CachedQueryDataResponse{}
+	}
+
+	setCacheStatus(ctx, StatusMiss)
+	ttl := s.queryTTL(req)
+
+	return false, CachedQueryDataResponse{
+		UpdateCacheFn: func(ctx context.Context, resp *backend.QueryDataResponse) {
+			if resp == nil || hasErrors(resp) {
+				return
+			}
+			data, err := json.Marshal(resp)
+			if err != nil {
+				s.log.Warn("Failed to encode query response for caching", "error", err)
+				return
+			}
+			s.set(ctx, key, data, ttl)
+		},
+	}
 }
 
 func (s *OSSCachingService) HandleResourceRequest(ctx context.Context, req *backend.CallResourceRequest) (bool, CachedResourceDataResponse) {
-	return false, CachedResourceDataResponse{}
+	if !s.enabled() {
+		return false, CachedResourceDataResponse{}
+	}
+
+	// Only idempotent requests are safe to serve from the cache.
+	if req.Method != http.MethodGet || shouldBypass(ctx, req.GetHTTPHeaders()) {
+		setCacheStatus(ctx, StatusBypass)
+		return false, CachedResourceDataResponse{}
+	}
+
+	key, err := resourceCacheKey(req)
+	if err != nil {
+		s.log.Warn("Failed to build resource cache key", "error", err)
+		setCacheStatus(ctx, StatusError)
+		return false, CachedResourceDataResponse{}
+	}
+
+	if cached, err := s.storage.Get(ctx, key); err == nil {
+		resp := &backend.CallResourceResponse{}
+		if err := json.Unmarshal(cached, resp); err == nil {
+			setCacheStatus(ctx, StatusHit)
+			return true, CachedResourceDataResponse{Response: resp}
+		}
+		s.log.Warn("Failed to decode cached resource response", "error", err)
+	} else if !errors.Is(err, remotecache.ErrCacheItemNotFound) {
+		s.log.Warn("Failed to read from resource cache", "error", err)
+		setCacheStatus(ctx, StatusError)
+		return false, CachedResourceDataResponse{}
+	}
+
+	setCacheStatus(ctx, StatusMiss)
+
+	var (
+		mu    sync.Mutex
+		calls int
+	)
+	return false, CachedResourceDataResponse{
+		UpdateCacheFn: func(ctx context.Context, resp *backend.CallResourceResponse) {
+			mu.Lock()
+			defer mu.Unlock()
+			calls++
+
+			// Streamed responses are sent in several parts and can't be replayed from a single cache entry.
+			if calls > 1 {
+				if err := s.storage.Delete(ctx, key); err != nil && !errors.Is(err, remotecache.ErrCacheItemNotFound) {
+					s.log.Warn("Failed to delete streamed resource response from cache", "error", err)
+				}
+				return
+			}
+
+			if resp == nil || resp.Status != http.StatusOK {
+				return
+			}
+			data, err := json.Marshal(resp)
+			if err != nil {
+				s.log.Warn("Failed to encode resource response for caching", "error", err)
+				return
+			}
+			s.set(ctx, key, data, s.settings.ResourceTTL)
+		},
+	}
+}
+
+func (s *OSSCachingService) enabled() bool {
+	return s.settings.Enabled && s.storage != nil
+}
+
+func (s *OSSCachingService) set(ctx context.Context, key string, data []byte, ttl time.Duration) {
+	if s.settings.MaxValueBytes > 0 && len(data) > s.settings.MaxValueBytes {
+		s.log.Debug("Response exceeds maximum cache value size, not caching", "size", len(data), "max", s.settings.MaxValueBytes)
+		return
+	}
+	if err := s.storage.Set(ctx, key, data, ttl); err != nil {
+		s.log.Warn("Failed to write response to cache", "error", err)
+	}
+}
+
+// queryTTL returns the TTL for a query request. The data source's `queryCachingTTL` json data
+// field (in milliseconds) takes precedence over the configured default.
+func (s *OSSCachingService) queryTTL(req *backend.QueryDataRequest) time.Duration {
+	if settings := req.PluginContext.DataSourceInstanceSettings; settings != nil && len(settings.JSONData) > 0 {
+		var jsonData struct {
+			QueryCachingTTL int64 `json:"queryCachingTTL"`
+		}
+		if err := json.Unmarshal(settings.JSONData, &jsonData); err == nil && jsonData.QueryCachingTTL > 0 {
+			return time.Duration(jsonData.QueryCachingTTL) * time.Millisecond
+		}
+	}
+	return s.settings.TTL
+}
+
+// shouldBypass reports whether a request must not be cached, either because the
+// caller asked to skip the cache or because it carries user-specific credentials.
+func shouldBypass(ctx context.Context, headers http.Header) bool {
+	if headers.Get("Authorization") != "" || headers.Get("X-Id-Token") != "" {
+		return true
+	}
+	if reqCtx := contexthandler.FromContext(ctx); reqCtx != nil && reqCtx.Req != nil {
+		return reqCtx.Req.Header.Get(XCacheSkipHeader) == "true"
+	}
+	return false
+}
+
+func setCacheStatus(ctx context.Context, status string) {
+	if reqCtx := contexthandler.FromContext(ctx); reqCtx != nil && reqCtx.Resp != nil {
+		reqCtx.Resp.Header().Set(XCacheHeader, status)
+	}
+}
+
+func hasErrors(resp *backend.QueryDataResponse) bool {
+	for _, r := range resp.Responses {
+		if r.Error != nil {
+			return true
+		}
+	}
+	return false
 }
 
 var _ CachingService = &OSSCachingService{}
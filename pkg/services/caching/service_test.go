@@ -0,0 +1,197 @@
+package caching
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/contexthandler/ctxkey"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func newTestService(t *testing.T) *OSSCachingService {
+	t.Helper()
+	cfg := setting.NewCfg()
+	cfg.QueryCaching = setting.QueryCachingSettings{
+		Enabled:        true,
+		Backend:        setting.QueryCachingBackendMemory,
+		TTL:            time.Minute,
+		ResourceTTL:    time.Minute,
+		MaxValueBytes:  1024 * 1024,
+		MaxMemoryBytes: 10 * 1024 * 1024,
+	}
+	return ProvideCachingService(cfg, nil)
+}
+
+func newTestContext(t *testing.T) (context.Context, *contextmodel.ReqContext) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/api/ds/query", nil)
+	reqCtx := &contextmodel.ReqContext{
+		Context: &web.Context{
+			Req:  req,
+			Resp: web.NewResponseWriter(req.Method, httptest.NewRecorder()),
+		},
+	}
+	return ctxkey.Set(context.Background(), reqCtx), reqCtx
+}
+
+func newTestQueryRequest(from time.Time) *backend.QueryDataRequest {
+	return &backend.QueryDataRequest{
+		PluginContext: backend.PluginContext{
+			OrgID:    1,
+			PluginID: "prometheus",
+			DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{
+				UID:     "ds1",
+				Updated: time.Unix(100, 0),
+			},
+		},
+		Queries: []backend.DataQuery{{
+			RefID:     "A",
+			Interval:  time.Minute,
+			TimeRange: backend.TimeRange{From: from, To: from.Add(time.Hour)},
+			JSON:      []byte(`{"expr":"up","requestId":"1"}`),
+		}},
+	}
+}
+
+func TestOSSCachingService_HandleQueryRequest(t *testing.T) {
+	from := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	resp := &backend.QueryDataResponse{Responses: backend.Responses{
+		"A": {Frames: data.Frames{data.NewFrame("A", data.NewField("value", nil, []float64{1, 2}))}},
+	}}
+
+	t.Run("zero value is disabled", func(t *testing.T) {
+		ctx, reqCtx := newTestContext(t)
+		hit, cr := (&OSSCachingService{}).HandleQueryRequest(ctx, newTestQueryRequest(from))
+		assert.False(t, hit)
+		assert.Nil(t, cr.UpdateCacheFn)
+		assert.Empty(t, reqCtx.Resp.Header().Get(XCacheHeader))
+	})
+
+	t.Run("miss then hit within the same interval", func(t *testing.T) {
+		s := newTestService(t)
+		ctx, reqCtx := newTestContext(t)
+		hit, cr := s.HandleQueryRequest(ctx, newTestQueryRequest(from))
+		require.False(t, hit)
+		require.NotNil(t, cr.UpdateCacheFn)
+		assert.Equal(t, StatusMiss, reqCtx.Resp.Header().Get(XCacheHeader))
+		cr.UpdateCacheFn(ctx, resp)
+
+		// a different request id and a time range shifted within the interval share the key
+		req := newTestQueryRequest(from.Add(20 * time.Second))
+		req.Queries[0].JSON = []byte(`{"requestId":"2","expr":"up"}`)
+		ctx, reqCtx = newTestContext(t)
+		hit, cr = s.HandleQueryRequest(ctx, req)
+		require.True(t, hit)
+		assert.Equal(t, StatusHit, reqCtx.Resp.Header().Get(XCacheHeader))
+		require.Contains(t, cr.Response.Responses, "A")
+		assert.Equal(t, 2, cr.Response.Responses["A"].Frames[0].Rows())
+	})
+
+	t.Run("updating the data source invalidates the cache", func(t *testing.T) {
+		s := newTestService(t)
+		ctx, _ := newTestContext(t)
+		_, cr := s.HandleQueryRequest(ctx, newTestQueryRequest(from))
+		cr.UpdateCacheFn(ctx, resp)
+
+		req := newTestQueryRequest(from)
+		req.PluginContext.DataSourceInstanceSettings.Updated = time.Unix(200, 0)
+		hit, _ := s.HandleQueryRequest(ctx, req)
+		assert.False(t, hit)
+	})
+
+	t.Run("responses with errors are not cached", func(t *testing.T) {
+		s := newTestService(t)
+		ctx, _ := newTestContext(t)
+		_, cr := s.HandleQueryRequest(ctx, newTestQueryRequest(from))
+		cr.UpdateCacheFn(ctx, &backend.QueryDataResponse{Responses: backend.Responses{"A": backend.ErrDataResponse(backend.StatusBadRequest, "bad")}})
+
+		hit, _ := s.HandleQueryRequest(ctx, newTestQueryRequest(from))
+		assert.False(t, hit)
+	})
+
+	t.Run("responses over the size limit are not cached", func(t *testing.T) {
+		s := newTestService(t)
+		s.settings.MaxValueBytes = 10
+		ctx, _ := newTestContext(t)
+		_, cr := s.HandleQueryRequest(ctx, newTestQueryRequest(from))
+		cr.UpdateCacheFn(ctx, resp)
+
+		hit, _ := s.HandleQueryRequest(ctx, newTestQueryRequest(from))
+		assert.False(t, hit)
+	})
+
+	t.Run("requests with forwarded credentials bypass the cache", func(t *testing.T) {
+		s := newTestService(t)
+		ctx, reqCtx := newTestContext(t)
+		req := newTestQueryRequest(from)
+		req.SetHTTPHeader("Authorization", "Bearer token")
+		hit, cr := s.HandleQueryRequest(ctx, req)
+		assert.False(t, hit)
+		assert.Nil(t, cr.UpdateCacheFn)
+		assert.Equal(t, StatusBypass, reqCtx.Resp.Header().Get(XCacheHeader))
+	})
+}
+
+func TestOSSCachingService_QueryTTL(t *testing.T) {
+	s := newTestService(t)
+	req := newTestQueryRequest(time.Now())
+	assert.Equal(t, time.Minute, s.queryTTL(req))
+
+	req.PluginContext.DataSourceInstanceSettings.JSONData = []byte(`{"queryCachingTTL":30000}`)
+	assert.Equal(t, 30*time.Second, s.queryTTL(req))
+}
+
+func TestOSSCachingService_HandleResourceRequest(t *testing.T) {
+	newReq := func(method string) *backend.CallResourceRequest {
+		return &backend.CallResourceRequest{
+			PluginContext: backend.PluginContext{OrgID: 1, PluginID: "prometheus"},
+			Path:          "api/v1/labels",
+			Method:        method,
+			URL:           "api/v1/labels?match=up",
+		}
+	}
+
+	t.Run("GET requests are cached", func(t *testing.T) {
+		s := newTestService(t)
+		ctx, _ := newTestContext(t)
+		hit, cr := s.HandleResourceRequest(ctx, newReq(http.MethodGet))
+		require.False(t, hit)
+		cr.UpdateCacheFn(ctx, &backend.CallResourceResponse{Status: http.StatusOK, Body: []byte(`["job"]`)})
+
+		ctx, reqCtx := newTestContext(t)
+		hit, cr = s.HandleResourceRequest(ctx, newReq(http.MethodGet))
+		require.True(t, hit)
+		assert.Equal(t, StatusHit, reqCtx.Resp.Header().Get(XCacheHeader))
+		assert.Equal(t, []byte(`["job"]`), cr.Response.Body)
+	})
+
+	t.Run("streamed responses are not cached", func(t *testing.T) {
+		s := newTestService(t)
+		ctx, _ := newTestContext(t)
+		_, cr := s.HandleResourceRequest(ctx, newReq(http.MethodGet))
+		cr.UpdateCacheFn(ctx, &backend.CallResourceResponse{Status: http.StatusOK, Body: []byte(`1`)})
+		cr.UpdateCacheFn(ctx, &backend.CallResourceResponse{Status: http.StatusOK, Body: []byte(`2`)})
+
+		hit, _ := s.HandleResourceRequest(ctx, newReq(http.MethodGet))
+		assert.False(t, hit)
+	})
+
+	t.Run("non GET requests bypass the cache", func(t *testing.T) {
+		s := newTestService(t)
+		ctx, reqCtx := newTestContext(t)
+		hit, cr := s.HandleResourceRequest(ctx, newReq(http.MethodPost))
+		assert.False(t, hit)
+		assert.Nil(t, cr.UpdateCacheFn)
+		assert.Equal(t, StatusBypass, reqCtx.Resp.Header().Get(XCacheHeader))
+	})
+}
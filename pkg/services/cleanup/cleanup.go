@@ -20,6 +20,8 @@ import (
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
 	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
+	"github.com/grafana/grafana/pkg/services/libraryelements"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
 	"github.com/grafana/grafana/pkg/services/ngalert/image"
 	"github.com/grafana/grafana/pkg/services/queryhistory"
 	"github.com/grafana/grafana/pkg/services/shorturls"
@@ -41,12 +43,14 @@ type CleanUpService struct {
 	tempUserService           tempuser.Service
 	annotationCleaner         annotations.Cleaner
 	dashboardService          dashboards.DashboardService
+	libraryElementService     libraryelements.Service
 }
 
 func ProvideService(cfg *setting.Cfg, serverLockService *serverlock.ServerLockService,
 	shortURLService shorturls.Service, sqlstore db.DB, queryHistoryService queryhistory.Service,
 	dashboardVersionService dashver.Service, dashSnapSvc dashboardsnapshots.Service, deleteExpiredImageService *image.DeleteExpiredService,
-	tempUserService tempuser.Service, tracer tracing.Tracer, annotationCleaner annotations.Cleaner, dashboardService dashboards.DashboardService) *CleanUpService {
+	tempUserService tempuser.Service, tracer tracing.Tracer, annotationCleaner annotations.Cleaner, dashboardService dashboards.DashboardService,
+	libraryElementService libraryelements.Service) *CleanUpService {
 	s := &CleanUpService{
 		Cfg:                       cfg,
 		ServerLockService:         serverLockService,
@@ -61,6 +65,7 @@ func ProvideService(cfg *setting.Cfg, serverLockService *serverlock.ServerLockSe
 		tracer:                    tracer,
 		annotationCleaner:         annotationCleaner,
 		dashboardService:          dashboardService,
+		libraryElementService:     libraryElementService,
 	}
 	return s
 }
@@ -100,8 +105,10 @@ func (srv *CleanUpService) clean(ctx context.Context) {
 		{"clean up temporary files", srv.cleanUpTmpFiles},
 		{"delete expired snapshots", srv.deleteExpiredSnapshots},
 		{"delete expired dashboard versions", srv.deleteExpiredDashboardVersions},
+		{"delete expired library element versions", srv.deleteExpiredLibraryElementVersions},
 		{"delete expired images", srv.deleteExpiredImages},
 		{"cleanup old annotations", srv.cleanUpOldAnnotations},
+		{"apply annotation retention rules", srv.applyAnnotationRetentionRules},
 		{"expire old user invites", srv.expireOldUserInvites},
 		{"delete stale short URLs", srv.deleteStaleShortURLs},
 		{"delete stale query history", srv.deleteStaleQueryHistory},
@@ -135,6 +142,22 @@ func (srv *CleanUpService) cleanUpOldAnnotations(ctx context.Context) {
 	}
 }
 
+func (srv *CleanUpService) applyAnnotationRetentionRules(ctx context.Context) {
+	logger := srv.log.FromContext(ctx)
+	// retention rules may delete many annotations, make sure only one instance applies them at a time
+	err := srv.ServerLockService.LockAndExecute(ctx, "cleanup annotations by retention rules", time.Minute*9, func(ctx context.Context) {
+		affected, affectedTags, err := srv.annotationCleaner.RunRetentionRules(ctx, srv.Cfg)
+		if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+			logger.Error("failed to apply annotation retention rules", "error", err)
+		} else {
+			logger.Debug("Deleted annotations by retention rules", "annotations affected", affected, "annotation tags affected", affectedTags)
+		}
+	})
+	if err != nil {
+		logger.Error("Failed to lock and execute annotation retention rules", "error", err)
+	}
+}
+
 func (srv *CleanUpService) cleanUpTmpFiles(ctx context.Context) {
 	folders := []string{
 		srv.Cfg.ImagesDir,
@@ -216,6 +239,16 @@ func (srv *CleanUpService) deleteExpiredDashboardVersions(ctx context.Context) {
 	}
 }
 
+func (srv *CleanUpService) deleteExpiredLibraryElementVersions(ctx context.Context) {
+	logger := srv.log.FromContext(ctx)
+	cmd := model.DeleteExpiredVersionsCommand{}
+	if err := srv.libraryElementService.DeleteExpiredVersions(ctx, &cmd); err != nil {
+		logger.Error("Failed to delete expired library element versions", "error", err.Error())
+	} else {
+		logger.Debug("Deleted old/expired library element versions", "rows affected", cmd.DeletedRows)
+	}
+}
+
 func (srv *CleanUpService) deleteExpiredImages(ctx context.Context) {
 	logger := srv.log.FromContext(ctx)
 	if !srv.Cfg.UnifiedAlerting.IsEnabled() {
@@ -272,32 +305,12 @@ func (srv *CleanUpService) deleteStaleShortURLs(ctx context.Context) {
 
 func (srv *CleanUpService) deleteStaleQueryHistory(ctx context.Context) {
 	logger := srv.log.FromContext(ctx)
-	// Delete query history from 14+ days ago with exception of starred queries
-	maxQueryHistoryLifetime := time.Hour * 24 * 14
-	olderThan := time.Now().Add(-maxQueryHistoryLifetime).Unix()
-	rowsCount, err := srv.QueryHistoryService.DeleteStaleQueriesInQueryHistory(ctx, olderThan)
-	if err != nil {
-		logger.Error("Problem deleting stale query history", "error", err.Error())
-	} else {
-		logger.Debug("Deleted stale query history", "rows affected", rowsCount)
-	}
-
-	// Enforce 200k limit for query_history table
-	queryHistoryLimit := 200000
-	rowsCount, err = srv.QueryHistoryService.EnforceRowLimitInQueryHistory(ctx, queryHistoryLimit, false)
-	if err != nil {
-		logger.Error("Problem with enforcing row limit for query_history", "error", err.Error())
-	} else {
-		logger.Debug("Enforced row limit for query_history", "rows affected", rowsCount)
-	}
-
-	// Enforce 150k limit for query_history_star table
-	queryHistoryStarLimit := 150000
-	rowsCount, err = srv.QueryHistoryService.EnforceRowLimitInQueryHistory(ctx, queryHistoryStarLimit, true)
+	// Enforce the query history retention and row limits of the quotas with exception of starred queries
+	rowsCount, err := srv.QueryHistoryService.EnforceQuotasInQueryHistory(ctx)
 	if err != nil {
-		logger.Error("Problem with enforcing row limit for query_history_star", "error", err.Error())
+		logger.Error("Problem enforcing query history quotas", "error", err.Error())
 	} else {
-		logger.Debug("Enforced row limit for query_history_star", "rows affected", rowsCount)
+		logger.Debug("Enforced query history quotas", "rows affected", rowsCount)
 	}
 }
 
@@ -169,6 +169,14 @@ func (s *CorrelationsService) updateHandler(c *contextmodel.ReqContext) response
 			return response.Error(http.StatusForbidden, "Correlation can only be edited via provisioning", err)
 		}
 
+		if errors.Is(err, ErrExternalCorrelationReqURL) || errors.Is(err, ErrInvalidExternalURL) {
+			return response.Error(http.StatusBadRequest, "Invalid external correlation", err)
+		}
+
+		if errors.Is(err, ErrQueryCorrelationReqTargetUID) {
+			return response.Error(http.StatusBadRequest, "Invalid query correlation", err)
+		}
+
 		return response.Error(http.StatusInternalServerError, "Failed to update correlation", err)
 	}
 
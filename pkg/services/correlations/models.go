@@ -18,6 +18,14 @@ var (
 	ErrInvalidTransformationType     = errors.New("invalid transformation type")
 	ErrTransformationNotNested       = errors.New("transformations must be nested under config")
 	ErrTransformationRegexReqExp     = errors.New("regex transformations require expression")
+	ErrTransformationJSONPathReqExp  = errors.New("jsonpath transformations require expression")
+	ErrTransformationTemplateReqExp  = errors.New("template transformations require expression")
+	ErrTransformationTemplateReqMap  = errors.New("template transformations require mapValue")
+	ErrInvalidTransformationExp      = errors.New("invalid transformation expression")
+	ErrExternalCorrelationReqURL     = errors.New("external correlations require a target url")
+	ErrExternalCorrelationTargetUID  = errors.New("external correlations must not have a targetUID")
+	ErrQueryCorrelationReqTargetUID  = errors.New("correlations of type \"query\" must have a targetUID")
+	ErrInvalidExternalURL            = errors.New("invalid external correlation url")
 	ErrCorrelationsQuotaFailed       = errors.New("error getting correlations quota")
 	ErrCorrelationsQuotaReached      = errors.New("correlations quota reached")
 )
@@ -30,7 +38,7 @@ const (
 type CorrelationConfigType string
 
 type Transformation struct {
-	//Enum: regex,logfmt
+	//Enum: regex,logfmt,jsonpath,template
 	Type       string `json:"type"`
 	Expression string `json:"expression,omitempty"`
 	Field      string `json:"field,omitempty"`
@@ -39,10 +47,19 @@ type Transformation struct {
 
 const (
 	ConfigTypeQuery CorrelationConfigType = "query"
+	// ConfigTypeExternal links to an external URL. The URL is stored in target.url and may contain variables.
+	ConfigTypeExternal CorrelationConfigType = "external"
+)
+
+const (
+	TransformationTypeRegex    = "regex"
+	TransformationTypeLogfmt   = "logfmt"
+	TransformationTypeJSONPath = "jsonpath"
+	TransformationTypeTemplate = "template"
 )
 
 func (t CorrelationConfigType) Validate() error {
-	if t != ConfigTypeQuery {
+	if t != ConfigTypeQuery && t != ConfigTypeExternal {
 		return fmt.Errorf("%s: \"%s\"", ErrInvalidConfigType, t)
 	}
 	return nil
@@ -50,10 +67,31 @@ func (t CorrelationConfigType) Validate() error {
 
 func (t Transformations) Validate() error {
 	for _, v := range t {
-		if v.Type != "regex" && v.Type != "logfmt" {
+		switch v.Type {
+		case TransformationTypeLogfmt:
+		case TransformationTypeRegex:
+			if len(v.Expression) == 0 {
+				return fmt.Errorf("%s: \"%s\"", ErrTransformationRegexReqExp, t)
+			}
+		case TransformationTypeJSONPath:
+			if len(v.Expression) == 0 {
+				return fmt.Errorf("%s: \"%s\"", ErrTransformationJSONPathReqExp, t)
+			}
+			if err := validateJSONPath(v.Expression); err != nil {
+				return fmt.Errorf("%w: %w", ErrInvalidTransformationExp, err)
+			}
+		case TransformationTypeTemplate:
+			if len(v.Expression) == 0 {
+				return fmt.Errorf("%s: \"%s\"", ErrTransformationTemplateReqExp, t)
+			}
+			if len(v.MapValue) == 0 {
+				return fmt.Errorf("%s: \"%s\"", ErrTransformationTemplateReqMap, t)
+			}
+			if err := validateTemplate(v.Expression); err != nil {
+				return fmt.Errorf("%w: %w", ErrInvalidTransformationExp, err)
+			}
+		default:
 			return fmt.Errorf("%s: \"%s\"", ErrInvalidTransformationType, t)
-		} else if v.Type == "regex" && len(v.Expression) == 0 {
-			return fmt.Errorf("%s: \"%s\"", ErrTransformationRegexReqExp, t)
 		}
 	}
 	return nil
@@ -70,7 +108,7 @@ type CorrelationConfig struct {
 	// Target type
 	// required:true
 	Type CorrelationConfigType `json:"type" binding:"Required"`
-	// Target data query
+	// Target data query, or {"url": "..."} for external correlations
 	// required:true
 	// example: {"prop1":"value1","prop2":"value"}
 	Target map[string]any `json:"target" binding:"Required"`
@@ -80,19 +118,50 @@ type CorrelationConfig struct {
 	Transformations Transformations `json:"transformations,omitempty"`
 }
 
+// validateTarget checks the target of the correlation against its type: query correlations point
+// to the target data source, external correlations to a URL.
+func (c CorrelationConfig) validateTarget(targetUID *string) error {
+	if c.Type != ConfigTypeExternal {
+		if targetUID == nil {
+			return ErrQueryCorrelationReqTargetUID
+		}
+		return nil
+	}
+	if targetUID != nil {
+		return ErrExternalCorrelationTargetUID
+	}
+	return c.validateURL()
+}
+
+// validateURL checks the URL of an external correlation.
+func (c CorrelationConfig) validateURL() error {
+	rawURL, _ := c.Target["url"].(string)
+	if rawURL == "" {
+		return ErrExternalCorrelationReqURL
+	}
+	if err := validateExternalURL(rawURL); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidExternalURL, err)
+	}
+	return nil
+}
+
 func (c CorrelationConfig) MarshalJSON() ([]byte, error) {
 	target := c.Target
 	transformations := c.Transformations
 	if target == nil {
 		target = map[string]any{}
 	}
+	configType := c.Type
+	if configType == "" {
+		configType = ConfigTypeQuery
+	}
 	return json.Marshal(struct {
 		Type            CorrelationConfigType `json:"type"`
 		Field           string                `json:"field"`
 		Target          map[string]any        `json:"target"`
 		Transformations Transformations       `json:"transformations,omitempty"`
 	}{
-		Type:            ConfigTypeQuery,
+		Type:            configType,
 		Field:           c.Field,
 		Target:          target,
 		Transformations: transformations,
@@ -166,8 +235,8 @@ func (c CreateCorrelationCommand) Validate() error {
 	if err := c.Config.Type.Validate(); err != nil {
 		return err
 	}
-	if c.TargetUID == nil && c.Config.Type == ConfigTypeQuery {
-		return fmt.Errorf("correlations of type \"%s\" must have a targetUID", ConfigTypeQuery)
+	if err := c.Config.validateTarget(c.TargetUID); err != nil {
+		return err
 	}
 
 	if err := c.Config.Transformations.Validate(); err != nil {
@@ -204,7 +273,7 @@ type CorrelationConfigUpdateDTO struct {
 	Field *string `json:"field"`
 	// Target type
 	Type *CorrelationConfigType `json:"type"`
-	// Target data query
+	// Target data query, or {"url": "..."} for external correlations
 	// example: {"prop1":"value1","prop2":"value"}
 	Target *map[string]any `json:"target"`
 	// Source data transformations
@@ -218,6 +287,15 @@ func (c CorrelationConfigUpdateDTO) Validate() error {
 			return err
 		}
 	}
+	// the target UID is checked against the type when the update is applied to the stored correlation
+	if c.Type != nil && *c.Type == ConfigTypeExternal && c.Target != nil {
+		if err := (CorrelationConfig{Type: *c.Type, Target: *c.Target}).validateURL(); err != nil {
+			return err
+		}
+	}
+	if err := Transformations(c.Transformations).Validate(); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -55,6 +55,113 @@ func TestCorrelationModels(t *testing.T) {
 
 			require.Error(t, cmd.Validate())
 		})
+
+		t.Run("Successfully validates an external correlation", func(t *testing.T) {
+			cmd := &CreateCorrelationCommand{
+				SourceUID: "some-uid",
+				OrgId:     1,
+				Config: CorrelationConfig{
+					Field:  "traceId",
+					Target: map[string]any{"url": "https://tracing.example.com/trace/${traceId}?org=${org:queryparam}"},
+					Type:   ConfigTypeExternal,
+				},
+			}
+
+			require.NoError(t, cmd.Validate())
+		})
+
+		t.Run("Validates the target of external correlations", func(t *testing.T) {
+			targetUid := "targetUid"
+			tests := []struct {
+				name      string
+				targetUID *string
+				target    map[string]any
+				err       error
+			}{
+				{name: "missing url", target: map[string]any{}, err: ErrExternalCorrelationReqURL},
+				{name: "url is not a string", target: map[string]any{"url": 1}, err: ErrExternalCorrelationReqURL},
+				{name: "unterminated variable", target: map[string]any{"url": "https://example.com/${traceId"}, err: ErrInvalidExternalURL},
+				{name: "invalid variable", target: map[string]any{"url": "https://example.com/${trace id}"}, err: ErrInvalidExternalURL},
+				{name: "relative url", target: map[string]any{"url": "/trace/${traceId}"}, err: ErrInvalidExternalURL},
+				{name: "unsupported scheme", target: map[string]any{"url": "javascript:alert(1)"}, err: ErrInvalidExternalURL},
+				{name: "url starts with a variable", target: map[string]any{"url": "${link}"}, err: ErrInvalidExternalURL},
+				{name: "scheme from a variable", target: map[string]any{"url": "${scheme}://example.com"}, err: ErrInvalidExternalURL},
+				{name: "target UID is set", targetUID: &targetUid, target: map[string]any{"url": "https://example.com"}, err: ErrExternalCorrelationTargetUID},
+			}
+
+			for _, tc := range tests {
+				t.Run(tc.name, func(t *testing.T) {
+					cmd := &CreateCorrelationCommand{
+						SourceUID: "some-uid",
+						OrgId:     1,
+						TargetUID: tc.targetUID,
+						Config: CorrelationConfig{
+							Field:  "traceId",
+							Target: tc.target,
+							Type:   ConfigTypeExternal,
+						},
+					}
+
+					require.ErrorIs(t, cmd.Validate(), tc.err)
+				})
+			}
+		})
+	})
+
+	t.Run("Transformations Validate", func(t *testing.T) {
+		tests := []struct {
+			name           string
+			transformation Transformation
+			err            error
+		}{
+			{name: "logfmt", transformation: Transformation{Type: "logfmt"}},
+			{name: "regex", transformation: Transformation{Type: "regex", Expression: "id=(\\w+)"}},
+			{name: "regex without expression", transformation: Transformation{Type: "regex"}, err: ErrTransformationRegexReqExp},
+			{name: "jsonpath", transformation: Transformation{Type: "jsonpath", Expression: "$.user['first name'].ids[0]"}},
+			{name: "jsonpath without expression", transformation: Transformation{Type: "jsonpath"}, err: ErrTransformationJSONPathReqExp},
+			{name: "jsonpath without root", transformation: Transformation{Type: "jsonpath", Expression: "user.id"}, err: ErrInvalidTransformationExp},
+			{name: "jsonpath with invalid segment", transformation: Transformation{Type: "jsonpath", Expression: "$.user[*]"}, err: ErrInvalidTransformationExp},
+			{name: "template", transformation: Transformation{Type: "template", Expression: "${service}-${env}", MapValue: "app"}},
+			{name: "template without expression", transformation: Transformation{Type: "template", MapValue: "app"}, err: ErrTransformationTemplateReqExp},
+			{name: "template without map value", transformation: Transformation{Type: "template", Expression: "${service}"}, err: ErrTransformationTemplateReqMap},
+			{name: "template with unterminated variable", transformation: Transformation{Type: "template", Expression: "${service", MapValue: "app"}, err: ErrInvalidTransformationExp},
+			{name: "unknown type", transformation: Transformation{Type: "xpath"}, err: ErrInvalidTransformationType},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				err := Transformations{tc.transformation}.Validate()
+				if tc.err == nil {
+					require.NoError(t, err)
+					return
+				}
+				require.ErrorContains(t, err, tc.err.Error())
+			})
+		}
+	})
+
+	t.Run("UpdateCorrelationCommand Validate", func(t *testing.T) {
+		t.Run("Fails if the external target is invalid", func(t *testing.T) {
+			configType := ConfigTypeExternal
+			target := map[string]any{"url": "ftp://example.com"}
+			cmd := &UpdateCorrelationCommand{
+				Config: &CorrelationConfigUpdateDTO{Type: &configType, Target: &target},
+			}
+
+			require.ErrorIs(t, cmd.Validate(), ErrInvalidExternalURL)
+		})
+
+		t.Run("Fails if a transformation is invalid", func(t *testing.T) {
+			field := "field"
+			cmd := &UpdateCorrelationCommand{
+				Config: &CorrelationConfigUpdateDTO{
+					Field:           &field,
+					Transformations: []Transformation{{Type: "template"}},
+				},
+			}
+
+			require.Error(t, cmd.Validate())
+		})
 	})
 
 	t.Run("CorrelationConfigType Validate", func(t *testing.T) {
@@ -66,6 +173,7 @@ func TestCorrelationModels(t *testing.T) {
 
 			tests := []test{
 				{input: "query", assertion: require.NoError},
+				{input: "external", assertion: require.NoError},
 				{input: "link", assertion: require.Error},
 			}
 
@@ -87,5 +195,18 @@ func TestCorrelationModels(t *testing.T) {
 
 			require.Equal(t, `{"type":"query","field":"field","target":{}}`, string(data))
 		})
+
+		t.Run("Keeps the config type", func(t *testing.T) {
+			config := CorrelationConfig{
+				Field:  "field",
+				Type:   ConfigTypeExternal,
+				Target: map[string]any{"url": "https://example.com"},
+			}
+
+			data, err := json.Marshal(config)
+			require.NoError(t, err)
+
+			require.Equal(t, `{"type":"external","field":"field","target":{"url":"https://example.com"}}`, string(data))
+		})
 	})
 }
@@ -0,0 +1,93 @@
+package correlations
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	// templateVariableName matches the content of a ${...} variable, with an optional format, e.g. ${traceId:queryparam}
+	templateVariableName = regexp.MustCompile(`^[\w.\-]+(:[\w]+)?$`)
+	// jsonPathSegment matches one segment of a JSON path following the root, e.g. .name, [0] or ['some key']
+	jsonPathSegment = regexp.MustCompile(`^(\.[\w\-]+|\[\d+\]|\['[^']+'\]|\["[^"]+"\])`)
+)
+
+// validateTemplate checks that every ${...} variable in the template is terminated and has a valid name.
+func validateTemplate(tpl string) error {
+	rest := tpl
+	for {
+		start := strings.Index(rest, "${")
+		if start < 0 {
+			return nil
+		}
+		rest = rest[start+2:]
+		end := strings.Index(rest, "}")
+		if end < 0 {
+			return fmt.Errorf("unterminated variable in template %q", tpl)
+		}
+		if name := rest[:end]; !templateVariableName.MatchString(name) {
+			return fmt.Errorf("invalid variable %q in template %q", name, tpl)
+		}
+		rest = rest[end+1:]
+	}
+}
+
+// interpolatePlaceholder replaces every ${...} variable in the template with a placeholder value.
+func interpolatePlaceholder(tpl string, placeholder string) string {
+	var b strings.Builder
+	rest := tpl
+	for {
+		start := strings.Index(rest, "${")
+		if start < 0 {
+			b.WriteString(rest)
+			return b.String()
+		}
+		end := strings.Index(rest[start:], "}")
+		if end < 0 {
+			b.WriteString(rest)
+			return b.String()
+		}
+		b.WriteString(rest[:start])
+		b.WriteString(placeholder)
+		rest = rest[start+end+1:]
+	}
+}
+
+// validateExternalURL checks that the templated URL of an external correlation is valid.
+// The URL must be an absolute http or https URL, the scheme can't come from a variable so that
+// the interpolated link can't be turned into a javascript: or data: URL.
+func validateExternalURL(rawURL string) error {
+	if err := validateTemplate(rawURL); err != nil {
+		return err
+	}
+	u, err := url.Parse(interpolatePlaceholder(rawURL, "placeholder"))
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("url %q must be an absolute http or https url", rawURL)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("url %q must have a host", rawURL)
+	}
+	return nil
+}
+
+// validateJSONPath checks the expression is a JSON path supported by the jsonpath transformation.
+// Only the root followed by object keys and array indexes is supported, e.g. $.user.ids[0] or $['user id'].
+func validateJSONPath(expr string) error {
+	if !strings.HasPrefix(expr, "$") {
+		return fmt.Errorf("json path %q must start with $", expr)
+	}
+	rest := expr[1:]
+	for rest != "" {
+		segment := jsonPathSegment.FindString(rest)
+		if segment == "" {
+			return fmt.Errorf("invalid json path %q at %q", expr, rest)
+		}
+		rest = rest[len(segment):]
+	}
+	return nil
+}
@@ -0,0 +1,325 @@
+// Package templating interpolates the template variables of a dashboard in the queries of its
+// panels, the way the frontend does before it sends the queries to the data sources.
+package templating
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// AllValue is the value of a variable when all of its options are selected.
+const AllValue = "$__all"
+
+var (
+	ErrUnknownVariable = errors.New("unknown template variable")
+	// ErrNoAllOptions is returned when a variable selects all of its options but the options are
+	// not saved with the dashboard, because they are queried when the dashboard is loaded.
+	ErrNoAllOptions = errors.New("template variable has no saved options to select all of")
+	// ErrUnsupportedFormat is returned when several values of a variable are interpolated without
+	// a format in the query of a data source whose default format is unknown.
+	ErrUnsupportedFormat = errors.New("template variable has several values and no format for the data source")
+)
+
+// variableReference matches $name, [[name]], [[name:format]], ${name} and ${name:format}.
+var variableReference = regexp.MustCompile(`\$(\w+)|\[\[(\w+?)(?::(\w+))?\]\]|\$\{(\w+)(?:\.[^:}]+)?(?::([^}]+))?\}`)
+
+// regexSpecialChars matches the characters escaped by the regex format.
+var regexSpecialChars = regexp.MustCompile(`[\\^$*+?.()|\[\]{}/]`)
+
+// promRegexSpecialChars matches the characters escaped in the values of multi-value variables by
+// the Prometheus and Loki data sources.
+var promRegexSpecialChars = regexp.MustCompile(`[$^*{}\[\]'+?.()|]`)
+
+// promRegexFormat is the format of the values of multi-value variables interpolated without a
+// format by the Prometheus and Loki data sources: a regex escaped for the strings of PromQL and LogQL.
+const promRegexFormat = "__promregex"
+
+// defaultFormats are the formats of multi-value variables interpolated without a format by data
+// source type. Variables with several values are not interpolated without a format in the
+// queries of other data sources, since the format the data source expects is unknown.
+var defaultFormats = map[string]string{
+	"prometheus":                    promRegexFormat,
+	"loki":                          promRegexFormat,
+	"mysql":                         "sqlstring",
+	"postgres":                      "sqlstring",
+	"grafana-postgresql-datasource": "sqlstring",
+	"mssql":                         "sqlstring",
+	"graphite":                      "glob",
+}
+
+// Values is the value of a template variable, which has several values when the variable is a
+// multi-value variable. It can be unmarshaled from a JSON string or array of strings.
+type Values []string
+
+func (v *Values) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err == nil {
+		*v = Values{value}
+		return nil
+	}
+
+	var values []string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return fmt.Errorf("template variable value must be a string or an array of strings")
+	}
+	*v = values
+	return nil
+}
+
+// Variable is a template variable of a dashboard.
+type Variable struct {
+	Name       string
+	Type       string
+	Multi      bool
+	IncludeAll bool
+	// AllValue is the custom value of the variable when all options are selected.
+	AllValue string
+	// Current is the value saved with the dashboard.
+	Current Values
+	// Options are the values of the options saved with the dashboard. Query variables only save
+	// the options when they are not refreshed on load.
+	Options []string
+}
+
+// Variables returns the template variables of the dashboard that can be interpolated in queries.
+// Ad hoc filters and group by variables are not interpolated and are left out.
+func Variables(dashboard *simplejson.Json) []Variable {
+	var variables []Variable
+	for _, obj := range dashboard.GetPath("templating", "list").MustArray() {
+		v := simplejson.NewFromAny(obj)
+		variable := Variable{
+			Name:       v.Get("name").MustString(),
+			Type:       v.Get("type").MustString(),
+			Multi:      v.Get("multi").MustBool(),
+			IncludeAll: v.Get("includeAll").MustBool(),
+			AllValue:   v.Get("allValue").MustString(),
+			Current:    jsonValues(v.GetPath("current", "value")),
+		}
+		if variable.Name == "" || variable.Type == "adhoc" || variable.Type == "groupby" {
+			continue
+		}
+		// constant variables only have a query, textboxes fall back to it
+		if variable.Type == "constant" || (variable.Type == "textbox" && len(variable.Current) == 0) {
+			variable.Current = Values{v.Get("query").MustString()}
+		}
+
+		for _, option := range v.Get("options").MustArray() {
+			for _, value := range jsonValues(simplejson.NewFromAny(option).Get("value")) {
+				if value != AllValue {
+					variable.Options = append(variable.Options, value)
+				}
+			}
+		}
+		variables = append(variables, variable)
+	}
+	return variables
+}
+
+func jsonValues(v *simplejson.Json) Values {
+	if s, err := v.String(); err == nil {
+		return Values{s}
+	}
+	values := Values{}
+	for _, value := range v.MustArray() {
+		if s, ok := value.(string); ok {
+			values = append(values, s)
+		}
+	}
+	return values
+}
+
+type resolvedValue struct {
+	values []string
+	// raw is set when the value is the custom all value, which is not formatted
+	raw bool
+	// multi is set when the variable can have several values, which are formatted by data source
+	multi bool
+	// err is returned when the variable is interpolated
+	err error
+}
+
+// Interpolator replaces references to template variables with their values.
+type Interpolator struct {
+	values map[string]resolvedValue
+}
+
+// NewInterpolator returns an interpolator of the variables with the values of overrides instead of
+// their current values. Overrides of variables that are not in the list return ErrUnknownVariable.
+func NewInterpolator(variables []Variable, overrides map[string]Values) (*Interpolator, error) {
+	known := make(map[string]Variable, len(variables))
+	for _, v := range variables {
+		known[v.Name] = v
+	}
+	for name := range overrides {
+		if _, ok := known[name]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownVariable, name)
+		}
+	}
+
+	i := &Interpolator{values: make(map[string]resolvedValue, len(variables))}
+	for _, v := range variables {
+		values := v.Current
+		if override, ok := overrides[v.Name]; ok {
+			values = override
+		}
+		i.values[v.Name] = resolve(v, values)
+	}
+	return i, nil
+}
+
+func resolve(v Variable, values Values) resolvedValue {
+	multi := v.Multi || v.IncludeAll || len(values) > 1
+	for _, value := range values {
+		if value != AllValue {
+			continue
+		}
+		if v.AllValue != "" {
+			return resolvedValue{values: []string{v.AllValue}, raw: true}
+		}
+		if len(v.Options) == 0 {
+			return resolvedValue{err: fmt.Errorf("%w: %s", ErrNoAllOptions, v.Name)}
+		}
+		return resolvedValue{values: v.Options, multi: multi}
+	}
+	return resolvedValue{values: values, multi: multi}
+}
+
+// Set sets the value of a variable, such as the built-in __from and __to variables.
+func (i *Interpolator) Set(name string, values ...string) {
+	i.values[name] = resolvedValue{values: values}
+}
+
+// Interpolate replaces the references to known variables in s, in a query of a data source of
+// the type. References to other variables are left as they are, since data sources interpolate
+// their own macros.
+func (i *Interpolator) Interpolate(s string, datasourceType string) (string, error) {
+	var err error
+	interpolated := variableReference.ReplaceAllStringFunc(s, func(match string) string {
+		groups := variableReference.FindStringSubmatch(match)
+		name, format := groups[1], ""
+		switch {
+		case groups[2] != "":
+			name, format = groups[2], groups[3]
+		case groups[4] != "":
+			name, format = groups[4], groups[5]
+		}
+
+		value, ok := i.values[name]
+		if !ok {
+			return match
+		}
+		if value.err != nil {
+			err = value.err
+			return match
+		}
+		if value.raw {
+			return strings.Join(value.values, ",")
+		}
+		if format == "" && value.multi {
+			format, ok = defaultFormats[datasourceType]
+			if !ok && len(value.values) > 1 {
+				err = fmt.Errorf("%w: %s", ErrUnsupportedFormat, name)
+				return match
+			}
+		}
+		return formatValues(value.values, format)
+	})
+	return interpolated, err
+}
+
+// InterpolateJSON replaces the references to known variables in all the strings of the JSON value,
+// such as a query of a data source of the type.
+func (i *Interpolator) InterpolateJSON(v *simplejson.Json, datasourceType string) error {
+	interpolated, err := i.interpolateAny(v.Interface(), datasourceType)
+	if err != nil {
+		return err
+	}
+	v.SetPath(nil, interpolated)
+	return nil
+}
+
+func (i *Interpolator) interpolateAny(v any, datasourceType string) (any, error) {
+	var err error
+	switch value := v.(type) {
+	case string:
+		return i.Interpolate(value, datasourceType)
+	case map[string]any:
+		for k, item := range value {
+			if value[k], err = i.interpolateAny(item, datasourceType); err != nil {
+				return nil, err
+			}
+		}
+		return value, nil
+	case []any:
+		for k, item := range value {
+			if value[k], err = i.interpolateAny(item, datasourceType); err != nil {
+				return nil, err
+			}
+		}
+		return value, nil
+	default:
+		return v, nil
+	}
+}
+
+// formatValues formats the values of a variable with one of the formats of the frontend. Without
+// a format, a single value is not formatted and multiple values are formatted as a glob.
+func formatValues(values []string, format string) string {
+	quote := func(quote string, escape string) string {
+		quoted := make([]string, 0, len(values))
+		for _, v := range values {
+			quoted = append(quoted, quote+strings.ReplaceAll(v, quote, escape)+quote)
+		}
+		return strings.Join(quoted, ",")
+	}
+
+	switch format {
+	case "raw", "csv":
+		return strings.Join(values, ",")
+	case "pipe":
+		return strings.Join(values, "|")
+	case "regex":
+		escaped := make([]string, 0, len(values))
+		for _, v := range values {
+			escaped = append(escaped, regexSpecialChars.ReplaceAllString(v, `\$0`))
+		}
+		if len(escaped) == 1 {
+			return escaped[0]
+		}
+		return "(" + strings.Join(escaped, "|") + ")"
+	case "json":
+		var data []byte
+		if len(values) == 1 {
+			data, _ = json.Marshal(values[0])
+		} else {
+			data, _ = json.Marshal(values)
+		}
+		return string(data)
+	case "singlequote":
+		return quote("'", `\'`)
+	case "doublequote":
+		return quote(`"`, `\"`)
+	case "sqlstring":
+		return quote("'", "''")
+	case promRegexFormat:
+		escaped := make([]string, 0, len(values))
+		for _, v := range values {
+			v = strings.ReplaceAll(v, `\`, `\\\\`)
+			escaped = append(escaped, promRegexSpecialChars.ReplaceAllString(v, `\\$0`))
+		}
+		if len(escaped) == 1 {
+			return escaped[0]
+		}
+		return "(" + strings.Join(escaped, "|") + ")"
+	default:
+		if len(values) == 1 {
+			return values[0]
+		}
+		return "{" + strings.Join(values, ",") + "}"
+	}
+}
@@ -0,0 +1,153 @@
+package templating
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+const dashboardJSON = `{
+	"templating": {
+		"list": [
+			{"name": "env", "type": "custom", "current": {"value": "prod"}, "options": [{"value": "prod"}, {"value": "dev"}]},
+			{"name": "host", "type": "query", "multi": true, "includeAll": true, "current": {"value": ["$__all"]},
+				"options": [{"value": "$__all"}, {"value": "a.example.com"}, {"value": "b.example.com"}]},
+			{"name": "job", "type": "query", "includeAll": true, "allValue": ".*", "current": {"value": "$__all"}},
+			{"name": "limit", "type": "constant", "query": "10"},
+			{"name": "pod", "type": "query", "multi": true, "includeAll": true, "refresh": 1, "current": {"value": ["$__all"]}},
+			{"name": "filters", "type": "adhoc"}
+		]
+	}
+}`
+
+func TestVariables(t *testing.T) {
+	dashboard, err := simplejson.NewJson([]byte(dashboardJSON))
+	require.NoError(t, err)
+
+	assert.Equal(t, []Variable{
+		{Name: "env", Type: "custom", Current: Values{"prod"}, Options: []string{"prod", "dev"}},
+		{Name: "host", Type: "query", Multi: true, IncludeAll: true, Current: Values{AllValue}, Options: []string{"a.example.com", "b.example.com"}},
+		{Name: "job", Type: "query", IncludeAll: true, AllValue: ".*", Current: Values{AllValue}},
+		{Name: "limit", Type: "constant", Current: Values{"10"}},
+		{Name: "pod", Type: "query", Multi: true, IncludeAll: true, Current: Values{AllValue}},
+	}, Variables(dashboard))
+}
+
+func TestInterpolator(t *testing.T) {
+	dashboard, err := simplejson.NewJson([]byte(dashboardJSON))
+	require.NoError(t, err)
+	variables := Variables(dashboard)
+
+	t.Run("current values", func(t *testing.T) {
+		i, err := NewInterpolator(variables, nil)
+		require.NoError(t, err)
+		i.Set("__from", "1000")
+
+		tests := map[string]string{
+			"up{env=\"$env\"}":               "up{env=\"prod\"}",
+			"${env}-[[env]]":                 "prod-prod",
+			"host=~\"${host:regex}\"":        `host=~"(a\.example\.com|b\.example\.com)"`,
+			"job=~\"$job\"":                  `job=~".*"`,
+			"$host":                          "{a.example.com,b.example.com}",
+			"${host:csv} ${host:pipe}":       "a.example.com,b.example.com a.example.com|b.example.com",
+			"${host:sqlstring}":              "'a.example.com','b.example.com'",
+			"${host:json}":                   `["a.example.com","b.example.com"]`,
+			"LIMIT $limit FROM $__from":      "LIMIT 10 FROM 1000",
+			"$__timeFilter(time) $unknown":   "$__timeFilter(time) $unknown",
+			"${env:doublequote} [[env:raw]]": `"prod" prod`,
+		}
+		for query, expected := range tests {
+			interpolated, err := i.Interpolate(query, "graphite")
+			require.NoError(t, err)
+			assert.Equal(t, expected, interpolated, query)
+		}
+	})
+
+	t.Run("default formats of data sources", func(t *testing.T) {
+		i, err := NewInterpolator(variables, nil)
+		require.NoError(t, err)
+
+		tests := map[string]string{
+			"prometheus": `(a\\.example\\.com|b\\.example\\.com)`,
+			"loki":       `(a\\.example\\.com|b\\.example\\.com)`,
+			"mysql":      "'a.example.com','b.example.com'",
+			"graphite":   "{a.example.com,b.example.com}",
+		}
+		for datasourceType, expected := range tests {
+			interpolated, err := i.Interpolate("$host", datasourceType)
+			require.NoError(t, err)
+			assert.Equal(t, expected, interpolated, datasourceType)
+		}
+
+		i, err = NewInterpolator(variables, map[string]Values{"host": {`c\d`}})
+		require.NoError(t, err)
+		interpolated, err := i.Interpolate("$host $env", "prometheus")
+		require.NoError(t, err)
+		assert.Equal(t, `c\\\\d prod`, interpolated)
+		interpolated, err = i.Interpolate("$host", "postgres")
+		require.NoError(t, err)
+		assert.Equal(t, `'c\d'`, interpolated)
+	})
+
+	t.Run("several values without a format for an unknown data source", func(t *testing.T) {
+		i, err := NewInterpolator(variables, nil)
+		require.NoError(t, err)
+
+		_, err = i.Interpolate("$host", "elasticsearch")
+		require.ErrorIs(t, err, ErrUnsupportedFormat)
+
+		interpolated, err := i.Interpolate("${host:csv} $env", "elasticsearch")
+		require.NoError(t, err)
+		assert.Equal(t, "a.example.com,b.example.com prod", interpolated)
+
+		i, err = NewInterpolator(variables, map[string]Values{"env": {"prod", "dev"}})
+		require.NoError(t, err)
+		_, err = i.Interpolate("$env", "elasticsearch")
+		require.ErrorIs(t, err, ErrUnsupportedFormat)
+	})
+
+	t.Run("all without saved options", func(t *testing.T) {
+		i, err := NewInterpolator(variables, nil)
+		require.NoError(t, err)
+
+		_, err = i.Interpolate("pod=~\"${pod:regex}\"", "prometheus")
+		require.ErrorIs(t, err, ErrNoAllOptions)
+
+		interpolated, err := i.Interpolate("$env", "prometheus")
+		require.NoError(t, err)
+		assert.Equal(t, "prod", interpolated)
+	})
+
+	t.Run("overrides", func(t *testing.T) {
+		var overrides map[string]Values
+		require.NoError(t, json.Unmarshal([]byte(`{"env": "dev", "host": ["it's"]}`), &overrides))
+
+		i, err := NewInterpolator(variables, overrides)
+		require.NoError(t, err)
+		interpolated, err := i.Interpolate("$env ${host:sqlstring}", "")
+		require.NoError(t, err)
+		assert.Equal(t, "dev 'it''s'", interpolated)
+	})
+
+	t.Run("unknown override", func(t *testing.T) {
+		_, err := NewInterpolator(variables, map[string]Values{"region": {"eu"}})
+		require.ErrorIs(t, err, ErrUnknownVariable)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		i, err := NewInterpolator(variables, nil)
+		require.NoError(t, err)
+
+		query, err := simplejson.NewJson([]byte(`{"refId": "A", "expr": "up{env=\"$env\"}", "tags": [{"value": "$limit"}], "hide": false}`))
+		require.NoError(t, err)
+		require.NoError(t, i.InterpolateJSON(query, "prometheus"))
+
+		data, err := query.MarshalJSON()
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"refId": "A", "expr": "up{env=\"prod\"}", "tags": [{"value": "10"}], "hide": false}`, string(data))
+	})
+}
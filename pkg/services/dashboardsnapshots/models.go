@@ -6,6 +6,7 @@ import (
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
 	dashboardsnapshot "github.com/grafana/grafana/pkg/apis/dashboardsnapshot/v0alpha1"
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboards/templating"
 )
 
 // DashboardSnapshot model
@@ -69,6 +70,50 @@ type CreateDashboardSnapshotCommand struct {
 	DashboardEncrypted []byte `json:"-"`
 }
 
+// swagger:model
+type RenderDashboardSnapshotCommand struct {
+	// UID of the dashboard to snapshot.
+	// required:true
+	DashboardUID string `json:"dashboardUid"`
+
+	// Snapshot name. Defaults to the dashboard title.
+	// required:false
+	Name string `json:"name"`
+
+	// When the snapshot should expire in seconds. Default is never to expire.
+	// required:false
+	// default:0
+	Expires int64 `json:"expires"`
+
+	// Save the snapshot on an external server rather than locally.
+	// required:false
+	// default:false
+	External bool `json:"external"`
+
+	// Time range of the queries. Defaults to the time range of the dashboard.
+	// required:false
+	Time RenderTimeRange `json:"time"`
+
+	// Values of the template variables by name, as a string or an array of strings. Defaults to the
+	// values saved with the dashboard.
+	// required:false
+	Variables map[string]templating.Values `json:"variables"`
+
+	// Maximum number of data points of the panels that do not set it.
+	// required:false
+	// default:1500
+	MaxDataPoints int64 `json:"maxDataPoints"`
+}
+
+type RenderTimeRange struct {
+	// Start of the time range, absolute or relative, for example now-6h.
+	From string `json:"from"`
+	// End of the time range, absolute or relative, for example now.
+	To string `json:"to"`
+	// Time zone of relative times, for example Europe/Paris. Defaults to the dashboard time zone.
+	Timezone string `json:"timezone"`
+}
+
 type DeleteDashboardSnapshotCommand struct {
 	DeleteKey string `json:"-"`
 }
@@ -0,0 +1,362 @@
+package dashboardsnapshots
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	common "github.com/grafana/grafana/pkg/apimachinery/apis/common/v0alpha1"
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboards/templating"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+	"github.com/grafana/grafana/pkg/services/query"
+)
+
+// defaultMaxDataPoints is the number of data points of the panels that do not set it, the
+// frontend uses the width of the panel instead.
+const defaultMaxDataPoints = 1500
+
+var ErrInvalidRenderRequest = errutil.BadRequest("dashboardsnapshots.invalid-render-request")
+
+var rlog = log.New("dashboard-snapshot-render")
+
+// LibraryElementGetter loads the library panels of the panels of a dashboard.
+type LibraryElementGetter interface {
+	GetElement(c context.Context, signedInUser identity.Requester, cmd model.GetLibraryElementCommand) (model.LibraryElementDTO, error)
+}
+
+// RenderDashboard runs the queries of the panels of the dashboard as the user and returns the
+// model of a snapshot of the dashboard with the results embedded in the panels, in the same way
+// as the frontend does when it creates a snapshot. Panel queries and links, variable queries and
+// annotation queries are removed from the model, and library panels are replaced by their model.
+func RenderDashboard(ctx context.Context, queryService query.Service, libraryElements LibraryElementGetter, user identity.Requester, dashboard *simplejson.Json, cmd RenderDashboardSnapshotCommand) (*common.Unstructured, error) {
+	from, to, err := renderTimeRange(dashboard, cmd.Time)
+	if err != nil {
+		return nil, err
+	}
+
+	variables := templating.Variables(dashboard)
+	interpolator, err := templating.NewInterpolator(variables, cmd.Variables)
+	if err != nil {
+		return nil, ErrInvalidRenderRequest.Errorf("RenderDashboard: %w", err)
+	}
+	interpolator.Set("__from", strconv.FormatInt(from.UnixMilli(), 10))
+	interpolator.Set("__to", strconv.FormatInt(to.UnixMilli(), 10))
+
+	maxDataPoints := cmd.MaxDataPoints
+	if maxDataPoints <= 0 {
+		maxDataPoints = defaultMaxDataPoints
+	}
+
+	r := &renderer{
+		queryService:    queryService,
+		libraryElements: libraryElements,
+		user:            user,
+		interpolator:    interpolator,
+		from:            from,
+		to:              to,
+		maxDataPoints:   maxDataPoints,
+	}
+	if err := r.renderPanels(ctx, dashboard.Get("panels").MustArray()); err != nil {
+		return nil, err
+	}
+
+	// freeze the variables at the values the queries were run with
+	resolved := make(map[string]templating.Values, len(variables))
+	for _, v := range variables {
+		resolved[v.Name] = v.Current
+		if override, ok := cmd.Variables[v.Name]; ok {
+			resolved[v.Name] = override
+		}
+	}
+	for _, obj := range dashboard.GetPath("templating", "list").MustArray() {
+		v := simplejson.NewFromAny(obj)
+		if values, ok := resolved[v.Get("name").MustString()]; ok {
+			current := map[string]any{"text": []string(values), "value": []string(values)}
+			if len(values) == 1 && !v.Get("multi").MustBool() {
+				current = map[string]any{"text": values[0], "value": values[0]}
+			}
+			v.Set("current", current)
+			v.Set("options", []any{current})
+		}
+		v.Set("query", "")
+		v.Set("refresh", 0)
+	}
+
+	dashboard.SetPath([]string{"annotations", "list"}, []any{})
+	dashboard.Set("time", map[string]any{"from": from.UTC().Format(time.RFC3339Nano), "to": to.UTC().Format(time.RFC3339Nano)})
+	dashboard.Set("snapshot", map[string]any{"timestamp": time.Now().UTC().Format(time.RFC3339Nano)})
+
+	// round trip through JSON so that the model only has JSON types
+	raw, err := dashboard.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	model := &common.Unstructured{}
+	if err := json.Unmarshal(raw, model); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+func renderTimeRange(dashboard *simplejson.Json, tr RenderTimeRange) (time.Time, time.Time, error) {
+	from := dashboard.GetPath("time", "from").MustString("now-6h")
+	to := dashboard.GetPath("time", "to").MustString("now")
+	if tr.From != "" || tr.To != "" {
+		if tr.From == "" || tr.To == "" {
+			return time.Time{}, time.Time{}, ErrInvalidRenderRequest.Errorf("RenderDashboard: both from and to of the time range must be set")
+		}
+		from, to = tr.From, tr.To
+	}
+
+	timezone := tr.Timezone
+	if timezone == "" {
+		timezone = dashboard.Get("timezone").MustString()
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil || timezone == "browser" {
+		location = time.UTC
+	}
+
+	timeRange := gtime.NewTimeRange(from, to)
+	fromTime, err := timeRange.ParseFrom(gtime.WithLocation(location))
+	if err != nil {
+		return time.Time{}, time.Time{}, ErrInvalidRenderRequest.Errorf("RenderDashboard: invalid time range from: %w", err)
+	}
+	toTime, err := timeRange.ParseTo(gtime.WithLocation(location))
+	if err != nil {
+		return time.Time{}, time.Time{}, ErrInvalidRenderRequest.Errorf("RenderDashboard: invalid time range to: %w", err)
+	}
+	if !fromTime.Before(toTime) {
+		return time.Time{}, time.Time{}, ErrInvalidRenderRequest.Errorf("RenderDashboard: the time range from must be before to")
+	}
+	return fromTime, toTime, nil
+}
+
+type renderer struct {
+	queryService    query.Service
+	libraryElements LibraryElementGetter
+	user            identity.Requester
+	interpolator    *templating.Interpolator
+	from            time.Time
+	to              time.Time
+	maxDataPoints   int64
+}
+
+func (r *renderer) renderPanels(ctx context.Context, panels []any) error {
+	for _, obj := range panels {
+		panel := simplejson.NewFromAny(obj)
+
+		// collapsed rows contain their panels
+		if panel.Get("type").MustString() == "row" {
+			if err := r.renderPanels(ctx, panel.Get("panels").MustArray()); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := r.loadLibraryPanel(ctx, panel); err != nil {
+			return err
+		}
+		frames, err := r.queryPanel(ctx, panel)
+		if err != nil {
+			return err
+		}
+		panel.Set("snapshotData", frames)
+		panel.Set("targets", []any{})
+		panel.Set("links", []any{})
+		panel.Set("datasource", nil)
+	}
+	return nil
+}
+
+// loadLibraryPanel replaces the model of a panel that references a library panel with the model of
+// the library panel, keeping the id and the position of the panel. The reference is removed, so that
+// the snapshot does not depend on the library panel.
+func (r *renderer) loadLibraryPanel(ctx context.Context, panel *simplejson.Json) error {
+	uid := panel.GetPath("libraryPanel", "uid").MustString()
+	if uid == "" {
+		return nil
+	}
+
+	element, err := r.libraryElements.GetElement(ctx, r.user, model.GetLibraryElementCommand{UID: uid, FolderName: dashboards.RootFolderName})
+	if err != nil {
+		if errors.Is(err, model.ErrLibraryElementNotFound) {
+			return ErrInvalidRenderRequest.Errorf("RenderDashboard: library panel %s of panel %d: %w", uid, panel.Get("id").MustInt64(), err)
+		}
+		return fmt.Errorf("failed to load library panel %s of panel %d: %w", uid, panel.Get("id").MustInt64(), err)
+	}
+	libraryPanel, err := simplejson.NewJson(element.Model)
+	if err != nil {
+		return fmt.Errorf("failed to read library panel %s: %w", uid, err)
+	}
+
+	for key, value := range libraryPanel.MustMap() {
+		if key != "id" && key != "gridPos" && key != "libraryPanel" {
+			panel.Set(key, value)
+		}
+	}
+	panel.Del("libraryPanel")
+	return nil
+}
+
+// queryPanel runs the queries of the panel and returns the frames of the responses. Queries that
+// fail are logged and left out, like panels with errors are in snapshots created by the frontend.
+func (r *renderer) queryPanel(ctx context.Context, panel *simplejson.Json) ([]any, error) {
+	datasource := panel.Get("datasource").Interface()
+	hasExpression := false
+	for _, obj := range panel.Get("targets").MustArray() {
+		if expr.NodeTypeFromDatasourceUID(datasourceUID(simplejson.NewFromAny(obj))) == expr.TypeCMDNode {
+			hasExpression = true
+		}
+	}
+
+	maxDataPoints := panel.Get("maxDataPoints").MustInt64()
+	if maxDataPoints <= 0 {
+		maxDataPoints = r.maxDataPoints
+	}
+	interval := gtime.RoundInterval(r.to.Sub(r.from) / time.Duration(maxDataPoints))
+	minInterval, err := r.interpolator.Interpolate(panel.Get("interval").MustString(), "")
+	if err != nil {
+		return nil, ErrInvalidRenderRequest.Errorf("RenderDashboard: interval of panel %d: %w", panel.Get("id").MustInt64(), err)
+	}
+	if minInterval, err := gtime.ParseInterval(minInterval); err == nil && minInterval > interval {
+		interval = minInterval
+	}
+	interval = max(interval, time.Millisecond)
+	r.interpolator.Set("__interval", gtime.FormatInterval(interval))
+	r.interpolator.Set("__interval_ms", strconv.FormatInt(interval.Milliseconds(), 10))
+
+	var queries []*simplejson.Json
+	for _, obj := range panel.Get("targets").MustArray() {
+		q := simplejson.NewFromAny(obj)
+		// hidden queries are needed by the expressions that use them
+		if !hasExpression && q.Get("hide").MustBool() {
+			continue
+		}
+		if _, ok := q.CheckGet("datasource"); !ok && datasource != nil {
+			q.Set("datasource", datasource)
+		}
+		if err := r.interpolator.InterpolateJSON(q, q.Get("datasource").Get("type").MustString()); err != nil {
+			return nil, ErrInvalidRenderRequest.Errorf("RenderDashboard: query %s of panel %d: %w", q.Get("refId").MustString(), panel.Get("id").MustInt64(), err)
+		}
+		q.Set("intervalMs", interval.Milliseconds())
+		q.Set("maxDataPoints", maxDataPoints)
+		queries = append(queries, q)
+	}
+	if len(queries) == 0 {
+		return []any{}, nil
+	}
+
+	resp, err := r.queryService.QueryData(ctx, r.user, false, dtos.MetricRequest{
+		From:    strconv.FormatInt(r.from.UnixMilli(), 10),
+		To:      strconv.FormatInt(r.to.UnixMilli(), 10),
+		Queries: queries,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query panel %d: %w", panel.Get("id").MustInt64(), err)
+	}
+
+	frames := []any{}
+	for _, q := range queries {
+		refID := q.Get("refId").MustString("A")
+		res, ok := resp.Responses[refID]
+		if !ok || q.Get("hide").MustBool() {
+			continue
+		}
+		if res.Error != nil {
+			rlog.Warn("Panel query failed", "panelId", panel.Get("id").MustInt64(), "refId", refID, "error", res.Error)
+			continue
+		}
+		for _, frame := range res.Frames {
+			if frame.RefID == "" {
+				frame.RefID = refID
+			}
+			frames = append(frames, frameDTO(frame))
+		}
+	}
+	return frames, nil
+}
+
+func datasourceUID(q *simplejson.Json) string {
+	uid := q.Get("datasource").Get("uid").MustString()
+	// before 8.3 special types could be sent as datasource (expr)
+	if uid == "" {
+		uid = q.Get("datasource").MustString()
+	}
+	return uid
+}
+
+// frameDTO returns the frame in the format of the snapshot data of the frontend, with the values
+// of the fields in arrays and times in epoch milliseconds.
+func frameDTO(frame *data.Frame) map[string]any {
+	fields := make([]any, 0, len(frame.Fields))
+	for _, field := range frame.Fields {
+		values := make([]any, field.Len())
+		for i := range values {
+			value, ok := field.ConcreteAt(i)
+			if !ok {
+				continue
+			}
+			if t, isTime := value.(time.Time); isTime {
+				value = t.UnixMilli()
+			}
+			values[i] = value
+		}
+
+		dto := map[string]any{
+			"name":   field.Name,
+			"type":   fieldType(field.Type()),
+			"values": values,
+		}
+		if field.Config != nil {
+			dto["config"] = field.Config
+		} else {
+			dto["config"] = map[string]any{}
+		}
+		if len(field.Labels) > 0 {
+			dto["labels"] = field.Labels
+		}
+		fields = append(fields, dto)
+	}
+
+	dto := map[string]any{
+		"name":   frame.Name,
+		"refId":  frame.RefID,
+		"fields": fields,
+	}
+	if frame.Meta != nil {
+		meta := *frame.Meta
+		// the executed queries can contain secrets
+		meta.ExecutedQueryString = ""
+		dto["meta"] = meta
+	}
+	return dto
+}
+
+func fieldType(t data.FieldType) string {
+	switch {
+	case t.Time():
+		return "time"
+	case t.Numeric():
+		return "number"
+	case t == data.FieldTypeString || t == data.FieldTypeNullableString:
+		return "string"
+	case t == data.FieldTypeBool || t == data.FieldTypeNullableBool:
+		return "boolean"
+	default:
+		return "other"
+	}
+}
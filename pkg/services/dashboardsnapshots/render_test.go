@@ -0,0 +1,191 @@
+package dashboardsnapshots
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboards/templating"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+	"github.com/grafana/grafana/pkg/services/query"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+const renderDashboardJSON = `{
+	"uid": "dash",
+	"title": "Dashboard",
+	"time": {"from": "now-6h", "to": "now"},
+	"templating": {
+		"list": [
+			{"name": "env", "type": "custom", "query": "prod,dev", "current": {"text": "prod", "value": "prod"},
+				"options": [{"text": "prod", "value": "prod"}, {"text": "dev", "value": "dev"}]},
+			{"name": "pod", "type": "query", "query": "label_values(pod)", "multi": true, "includeAll": true, "refresh": 1,
+				"current": {"text": "a", "value": ["a"]}}
+		]
+	},
+	"annotations": {"list": [{"name": "Deployments", "datasource": {"uid": "loki"}}]},
+	"panels": [
+		{
+			"id": 1,
+			"type": "timeseries",
+			"datasource": {"type": "prometheus", "uid": "prom"},
+			"links": [{"url": "http://example.com"}],
+			"targets": [
+				{"refId": "A", "expr": "up{env=\"$env\"}[$__interval]"},
+				{"refId": "B", "expr": "down", "hide": true}
+			]
+		},
+		{
+			"id": 2,
+			"type": "row",
+			"collapsed": true,
+			"panels": [
+				{"id": 3, "type": "stat", "maxDataPoints": 100, "datasource": {"type": "prometheus", "uid": "prom"}, "targets": [{"refId": "A", "expr": "failing"}]}
+			]
+		},
+		{"id": 4, "type": "text"},
+		{"id": 5, "gridPos": {"x": 0, "y": 10}, "libraryPanel": {"uid": "lib", "name": "Library panel"}}
+	]
+}`
+
+func TestRenderDashboard(t *testing.T) {
+	signedInUser := &user.SignedInUser{UserID: 1, OrgID: 1}
+	from, to := "2024-03-01T00:00:00Z", "2024-03-01T01:00:00Z"
+
+	var requests []dtos.MetricRequest
+	queryService := &query.FakeQueryService{}
+	queryService.On("QueryData", mock.Anything, signedInUser, false, mock.Anything).Return(
+		func(_ context.Context, _ identity.Requester, _ bool, req dtos.MetricRequest) *backend.QueryDataResponse {
+			requests = append(requests, req)
+			if req.Queries[0].Get("expr").MustString() == "failing" {
+				return &backend.QueryDataResponse{Responses: backend.Responses{"A": {Error: assert.AnError}}}
+			}
+			frame := data.NewFrame("up",
+				data.NewField("time", nil, []time.Time{time.UnixMilli(1000)}),
+				data.NewField("value", data.Labels{"env": "dev"}, []*float64{nil}),
+			)
+			frame.Meta = &data.FrameMeta{ExecutedQueryString: "up"}
+			return &backend.QueryDataResponse{Responses: backend.Responses{"A": {Frames: data.Frames{frame}}}}
+		}, nil)
+
+	libraryElements := fakeLibraryElements{"lib": `{"id": 9, "type": "stat", "title": "Library panel",
+		"gridPos": {"x": 12, "y": 0}, "datasource": {"type": "prometheus", "uid": "prom"},
+		"targets": [{"refId": "A", "expr": "pods{pod=~\"$pod\"}"}]}`}
+
+	dashboard, err := simplejson.NewJson([]byte(renderDashboardJSON))
+	require.NoError(t, err)
+
+	model, err := RenderDashboard(context.Background(), queryService, libraryElements, signedInUser, dashboard, RenderDashboardSnapshotCommand{
+		DashboardUID: "dash",
+		Time:         RenderTimeRange{From: from, To: to},
+		Variables:    map[string]templating.Values{"env": {"dev"}},
+	})
+	require.NoError(t, err)
+
+	t.Run("queries are run with the time range and variables of the command", func(t *testing.T) {
+		require.Len(t, requests, 3)
+		assert.Equal(t, "1709251200000", requests[0].From)
+		assert.Equal(t, "1709254800000", requests[0].To)
+		require.Len(t, requests[0].Queries, 1)
+		assert.Equal(t, `up{env="dev"}[2s]`, requests[0].Queries[0].Get("expr").MustString())
+		assert.Equal(t, "prom", requests[0].Queries[0].GetPath("datasource", "uid").MustString())
+		assert.Equal(t, int64(1500), requests[0].Queries[0].Get("maxDataPoints").MustInt64())
+		assert.Equal(t, int64(100), requests[1].Queries[0].Get("maxDataPoints").MustInt64())
+	})
+
+	t.Run("library panels are replaced by their model", func(t *testing.T) {
+		assert.Equal(t, `pods{pod=~"a"}`, requests[2].Queries[0].Get("expr").MustString())
+	})
+
+	raw, err := model.MarshalJSON()
+	require.NoError(t, err)
+	snapshot, err := simplejson.NewJson(raw)
+	require.NoError(t, err)
+
+	t.Run("results are embedded in the panels", func(t *testing.T) {
+		panel := simplejson.NewFromAny(snapshot.Get("panels").MustArray()[0])
+		assert.Empty(t, panel.Get("targets").MustArray())
+		assert.Empty(t, panel.Get("links").MustArray())
+		assert.Nil(t, panel.Get("datasource").Interface())
+
+		data, err := panel.Get("snapshotData").MarshalJSON()
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{
+			"name": "up",
+			"refId": "A",
+			"meta": {"typeVersion": [0, 0]},
+			"fields": [
+				{"name": "time", "type": "time", "config": {}, "values": [1000]},
+				{"name": "value", "type": "number", "config": {}, "labels": {"env": "dev"}, "values": [null]}
+			]
+		}]`, string(data))
+
+		nested := snapshot.Get("panels").GetIndex(1).Get("panels").GetIndex(0)
+		assert.Empty(t, nested.Get("snapshotData").MustArray())
+		_, ok := snapshot.Get("panels").GetIndex(2).CheckGet("snapshotData")
+		assert.True(t, ok)
+
+		libraryPanel := snapshot.Get("panels").GetIndex(3)
+		assert.Equal(t, int64(5), libraryPanel.Get("id").MustInt64())
+		assert.Equal(t, int64(10), libraryPanel.GetPath("gridPos", "y").MustInt64())
+		assert.Equal(t, "Library panel", libraryPanel.Get("title").MustString())
+		assert.Len(t, libraryPanel.Get("snapshotData").MustArray(), 1)
+		_, ok = libraryPanel.CheckGet("libraryPanel")
+		assert.False(t, ok)
+	})
+
+	t.Run("variables, annotations and time range are frozen", func(t *testing.T) {
+		variable := snapshot.GetPath("templating", "list").GetIndex(0)
+		assert.Equal(t, "dev", variable.GetPath("current", "value").MustString())
+		assert.Equal(t, "", variable.Get("query").MustString())
+		assert.Len(t, variable.Get("options").MustArray(), 1)
+		assert.Empty(t, snapshot.GetPath("annotations", "list").MustArray())
+		assert.Equal(t, from, snapshot.GetPath("time", "from").MustString())
+		assert.Equal(t, to, snapshot.GetPath("time", "to").MustString())
+		assert.NotEmpty(t, snapshot.GetPath("snapshot", "timestamp").MustString())
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		for name, cmd := range map[string]RenderDashboardSnapshotCommand{
+			"unknown variable":          {Variables: map[string]templating.Values{"region": {"eu"}}},
+			"all without saved options": {Variables: map[string]templating.Values{"pod": {templating.AllValue}}},
+			"partial range":             {Time: RenderTimeRange{From: "now-1h"}},
+			"invalid range":             {Time: RenderTimeRange{From: "yesterday", To: "now"}},
+			"inverted range":            {Time: RenderTimeRange{From: "now", To: "now-1h"}},
+		} {
+			dashboard, err := simplejson.NewJson([]byte(renderDashboardJSON))
+			require.NoError(t, err)
+
+			_, err = RenderDashboard(context.Background(), queryService, libraryElements, signedInUser, dashboard, cmd)
+			require.ErrorIs(t, err, ErrInvalidRenderRequest, name)
+		}
+	})
+
+	t.Run("missing library panel", func(t *testing.T) {
+		dashboard, err := simplejson.NewJson([]byte(renderDashboardJSON))
+		require.NoError(t, err)
+
+		_, err = RenderDashboard(context.Background(), queryService, fakeLibraryElements{}, signedInUser, dashboard, RenderDashboardSnapshotCommand{})
+		require.ErrorIs(t, err, ErrInvalidRenderRequest)
+	})
+}
+
+type fakeLibraryElements map[string]string
+
+func (f fakeLibraryElements) GetElement(_ context.Context, _ identity.Requester, cmd model.GetLibraryElementCommand) (model.LibraryElementDTO, error) {
+	panel, ok := f[cmd.UID]
+	if !ok {
+		return model.LibraryElementDTO{}, model.ErrLibraryElementNotFound
+	}
+	return model.LibraryElementDTO{UID: cmd.UID, Model: json.RawMessage(panel)}, nil
+}
@@ -1406,6 +1406,13 @@ var (
 			Stage:       FeatureStageExperimental,
 			Owner:       grafanaDashboardsSquad,
 		},
+		{
+			Name:            "livePipeline",
+			Description:     "Enable a generic live processing pipeline with channel rules and write configs stored in the database",
+			Stage:           FeatureStageExperimental,
+			Owner:           grafanaAppPlatformSquad,
+			RequiresRestart: true,
+		},
 	}
 )
 
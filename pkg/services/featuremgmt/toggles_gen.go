@@ -750,4 +750,8 @@ const (
 	// FlagAdhocFilterOneOf
 	// Exposes a new &#39;one of&#39; operator for ad-hoc filters. This operator allows users to filter by multiple values in a single filter.
 	FlagAdhocFilterOneOf = "adhocFilterOneOf"
+
+	// FlagLivePipeline
+	// Enable a generic live processing pipeline with channel rules and write configs stored in the database
+	FlagLivePipeline = "livePipeline"
 )
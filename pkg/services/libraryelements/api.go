@@ -3,9 +3,12 @@ package libraryelements
 import (
 	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/components/dashdiffs"
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
@@ -30,6 +33,10 @@ func (l *LibraryElementService) registerAPIEndpoints() {
 			entities.Get("/:uid/connections/", authorize(ac.EvalPermission(ActionLibraryPanelsRead, uidScope)), routing.Wrap(l.getConnectionsHandler))
 			entities.Get("/name/:name", routing.Wrap(l.getByNameHandler))
 			entities.Patch("/:uid", authorize(ac.EvalPermission(ActionLibraryPanelsWrite, uidScope)), routing.Wrap(l.patchHandler))
+			entities.Get("/:uid/versions", authorize(ac.EvalPermission(ActionLibraryPanelsRead, uidScope)), routing.Wrap(l.getVersionsHandler))
+			entities.Get("/:uid/versions/diff", authorize(ac.EvalPermission(ActionLibraryPanelsRead, uidScope)), routing.Wrap(l.diffVersionsHandler))
+			entities.Get("/:uid/versions/:version", authorize(ac.EvalPermission(ActionLibraryPanelsRead, uidScope)), routing.Wrap(l.getVersionHandler))
+			entities.Post("/:uid/versions/:version/restore", authorize(ac.EvalPermission(ActionLibraryPanelsWrite, uidScope)), routing.Wrap(l.restoreVersionHandler))
 		} else {
 			entities.Post("/", routing.Wrap(l.createHandler))
 			entities.Delete("/:uid", routing.Wrap(l.deleteHandler))
@@ -38,6 +45,10 @@ func (l *LibraryElementService) registerAPIEndpoints() {
 			entities.Get("/:uid/connections/", routing.Wrap(l.getConnectionsHandler))
 			entities.Get("/name/:name", routing.Wrap(l.getByNameHandler))
 			entities.Patch("/:uid", routing.Wrap(l.patchHandler))
+			entities.Get("/:uid/versions", routing.Wrap(l.getVersionsHandler))
+			entities.Get("/:uid/versions/diff", routing.Wrap(l.diffVersionsHandler))
+			entities.Get("/:uid/versions/:version", routing.Wrap(l.getVersionHandler))
+			entities.Post("/:uid/versions/:version/restore", routing.Wrap(l.restoreVersionHandler))
 		}
 	})
 }
@@ -311,6 +322,187 @@ func (l *LibraryElementService) getByNameHandler(c *contextmodel.ReqContext) res
 	}
 }
 
+// swagger:route GET /library-elements/{library_element_uid}/versions library_elements getLibraryElementVersions
+//
+// Get library element versions.
+//
+// Returns the versions of a library element, starting with the latest.
+// Use the `perPage` query parameter to control the maximum number of versions returned; the default limit is `100`.
+// You can also use the `page` query parameter to fetch versions from any page other than the first one.
+//
+// Responses:
+// 200: getLibraryElementVersionsResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (l *LibraryElementService) getVersionsHandler(c *contextmodel.ReqContext) response.Response {
+	versions, err := l.getLibraryElementVersions(c.Req.Context(), c.SignedInUser, model.ListLibraryElementVersionsQuery{
+		UID:     web.Params(c.Req)[":uid"],
+		PerPage: c.QueryInt("perPage"),
+		Page:    c.QueryInt("page"),
+	})
+	if err != nil {
+		return toLibraryElementError(err, "Failed to get library element versions")
+	}
+
+	return response.JSON(http.StatusOK, model.LibraryElementVersionsResponse{Result: versions})
+}
+
+// swagger:route GET /library-elements/{library_element_uid}/versions/{version} library_elements getLibraryElementVersion
+//
+// Get library element version.
+//
+// Returns a version of a library element.
+//
+// Responses:
+// 200: getLibraryElementVersionResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (l *LibraryElementService) getVersionHandler(c *contextmodel.ReqContext) response.Response {
+	version, err := strconv.ParseInt(web.Params(c.Req)[":version"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "version is invalid", err)
+	}
+
+	result, err := l.getLibraryElementVersion(c.Req.Context(), c.SignedInUser, model.GetLibraryElementVersionQuery{
+		UID:     web.Params(c.Req)[":uid"],
+		Version: version,
+	})
+	if err != nil {
+		return toLibraryElementError(err, "Failed to get library element version")
+	}
+
+	return response.JSON(http.StatusOK, model.LibraryElementVersionResponse{Result: result})
+}
+
+// swagger:route GET /library-elements/{library_element_uid}/versions/diff library_elements diffLibraryElementVersions
+//
+// Diff library element versions.
+//
+// Returns the differences between the name and model of two versions of a library element.
+//
+// Produces:
+// - application/json
+// - text/html
+//
+// Responses:
+// 200: diffLibraryElementVersionsResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (l *LibraryElementService) diffVersionsHandler(c *contextmodel.ReqContext) response.Response {
+	ctx := c.Req.Context()
+	uid := web.Params(c.Req)[":uid"]
+	baseVersion, err := strconv.ParseInt(c.Query("base"), 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "base version is invalid", err)
+	}
+	newVersion, err := strconv.ParseInt(c.Query("new"), 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "new version is invalid", err)
+	}
+
+	baseVer, err := l.getLibraryElementVersion(ctx, c.SignedInUser, model.GetLibraryElementVersionQuery{UID: uid, Version: baseVersion})
+	if err != nil {
+		return toLibraryElementError(err, "Failed to get library element version")
+	}
+	newVer, err := l.getLibraryElementVersion(ctx, c.SignedInUser, model.GetLibraryElementVersionQuery{UID: uid, Version: newVersion})
+	if err != nil {
+		return toLibraryElementError(err, "Failed to get library element version")
+	}
+
+	baseData, err := diffData(baseVer)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Unable to compute diff", err)
+	}
+	newData, err := diffData(newVer)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Unable to compute diff", err)
+	}
+
+	options := dashdiffs.Options{
+		OrgId:    c.SignedInUser.GetOrgID(),
+		DiffType: dashdiffs.ParseDiffType(c.Query("diffType")),
+	}
+	contentType := "text/html"
+	if options.DiffType == dashdiffs.DiffDelta {
+		contentType = "application/json"
+	}
+
+	result, err := dashdiffs.CalculateDiff(ctx, &options, baseData, newData)
+	if errors.Is(err, dashdiffs.ErrNilDiff) {
+		// the versions are identical
+		result = &dashdiffs.Result{Delta: []byte{}}
+		if options.DiffType == dashdiffs.DiffDelta {
+			result.Delta = []byte("{}")
+		}
+	} else if err != nil {
+		return response.Error(http.StatusInternalServerError, "Unable to compute diff", err)
+	}
+
+	return response.Respond(http.StatusOK, result.Delta).SetHeader("Content-Type", contentType)
+}
+
+// diffData returns the fields of a version that are compared by the diff of two versions.
+func diffData(version model.LibraryElementVersionDTO) (*simplejson.Json, error) {
+	versionModel, err := simplejson.NewJson(version.Model)
+	if err != nil {
+		return nil, err
+	}
+	return simplejson.NewFromAny(map[string]any{
+		"name":  version.Name,
+		"model": versionModel.Interface(),
+	}), nil
+}
+
+// swagger:route POST /library-elements/{library_element_uid}/versions/{version}/restore library_elements restoreLibraryElementVersion
+//
+// Restore library element version.
+//
+// Restores the name and model of a version of a library element, which are saved as a new version.
+//
+// Responses:
+// 200: getLibraryElementResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 412: preconditionFailedError
+// 500: internalServerError
+func (l *LibraryElementService) restoreVersionHandler(c *contextmodel.ReqContext) response.Response {
+	version, err := strconv.ParseInt(web.Params(c.Req)[":version"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "version is invalid", err)
+	}
+
+	element, err := l.restoreLibraryElementVersion(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"], version)
+	if err != nil {
+		return toLibraryElementError(err, "Failed to restore library element version")
+	}
+
+	metrics.MFolderIDsServiceCount.WithLabelValues(metrics.LibraryElements).Inc()
+	// nolint:staticcheck
+	if element.FolderID != 0 {
+		metrics.MFolderIDsServiceCount.WithLabelValues(metrics.LibraryElements).Inc()
+		// nolint:staticcheck
+		folder, err := l.folderService.Get(c.Req.Context(), &folder.GetFolderQuery{OrgID: c.SignedInUser.GetOrgID(), ID: &element.FolderID, SignedInUser: c.SignedInUser})
+		if err != nil {
+			return response.Error(http.StatusInternalServerError, "failed to get folder", err)
+		}
+		element.FolderUID = folder.UID
+		element.Meta.FolderUID = folder.UID
+		element.Meta.FolderName = folder.Title
+	}
+
+	return response.JSON(http.StatusOK, model.LibraryElementResponse{Result: element})
+}
+
 func (l *LibraryElementService) filterLibraryPanelsByPermission(c *contextmodel.ReqContext, elements []model.LibraryElementDTO) ([]model.LibraryElementDTO, error) {
 	filteredPanels := make([]model.LibraryElementDTO, 0)
 	for _, p := range elements {
@@ -354,10 +546,13 @@ func toLibraryElementError(err error, message string) response.Response {
 	if errors.Is(err, model.ErrLibraryElementUIDTooLong) {
 		return response.Error(http.StatusBadRequest, model.ErrLibraryElementUIDTooLong.Error(), err)
 	}
+	if errors.Is(err, model.ErrLibraryElementVersionNotFound) {
+		return response.Error(http.StatusNotFound, model.ErrLibraryElementVersionNotFound.Error(), err)
+	}
 	return response.ErrOrFallback(http.StatusInternalServerError, message, err)
 }
 
-// swagger:parameters getLibraryElementByUID getLibraryElementConnections
+// swagger:parameters getLibraryElementByUID getLibraryElementConnections diffLibraryElementVersions
 type LibraryElementByUID struct {
 	// in:path
 	// required:true
@@ -455,6 +650,55 @@ type UpdateLibraryElementParam struct {
 	UID string `json:"library_element_uid"`
 }
 
+// swagger:parameters getLibraryElementVersions
+type GetLibraryElementVersionsParams struct {
+	// in:path
+	// required:true
+	UID string `json:"library_element_uid"`
+	// The number of results per page.
+	// in:query
+	// required:false
+	// default: 100
+	PerPage int `json:"perPage"`
+	// The page for a set of records, given that only perPage records are returned at a time. Numbering starts at 1.
+	// in:query
+	// required:false
+	// default: 1
+	Page int `json:"page"`
+}
+
+// swagger:parameters getLibraryElementVersion restoreLibraryElementVersion
+type LibraryElementVersionParams struct {
+	// in:path
+	// required:true
+	UID string `json:"library_element_uid"`
+	// in:path
+	// required:true
+	Version int64 `json:"version"`
+}
+
+// swagger:parameters diffLibraryElementVersions
+type DiffLibraryElementVersionsParams struct {
+	// The version to compare from.
+	// in:query
+	// required:true
+	Base int64 `json:"base"`
+	// The version to compare to.
+	// in:query
+	// required:true
+	New int64 `json:"new"`
+	// The format of the diff.
+	// in:query
+	// required:false
+	// Description:
+	// * basic - HTML of the changed fields
+	// * json - HTML of the annotated JSON of the base version
+	// * delta - JSON delta
+	// enum: basic,json,delta
+	// default: basic
+	DiffType string `json:"diffType"`
+}
+
 // swagger:response getLibraryElementsResponse
 type GetLibraryElementsResponse struct {
 	// in: body
@@ -478,3 +722,21 @@ type GetLibraryElementConnectionsResponse struct {
 	// in: body
 	Body model.LibraryElementConnectionsResponse `json:"body"`
 }
+
+// swagger:response getLibraryElementVersionsResponse
+type GetLibraryElementVersionsResponse struct {
+	// in: body
+	Body model.LibraryElementVersionsResponse `json:"body"`
+}
+
+// swagger:response getLibraryElementVersionResponse
+type GetLibraryElementVersionResponse struct {
+	// in: body
+	Body model.LibraryElementVersionResponse `json:"body"`
+}
+
+// swagger:response diffLibraryElementVersionsResponse
+type DiffLibraryElementVersionsResponse struct {
+	// in: body
+	Body []byte `json:"body"`
+}
@@ -113,6 +113,25 @@ func GetLibraryElement(dialect migrator.Dialect, session *db.Session, uid string
 	return elements[0], nil
 }
 
+// insertLibraryElementVersion saves the library element as a new version in the version history.
+func insertLibraryElementVersion(session *db.Session, element model.LibraryElement, restoredFrom int64) error {
+	version := model.LibraryElementVersion{
+		OrgID:        element.OrgID,
+		ElementID:    element.ID,
+		Version:      element.Version,
+		RestoredFrom: restoredFrom,
+		Name:         element.Name,
+		Kind:         element.Kind,
+		Type:         element.Type,
+		Description:  element.Description,
+		Model:        element.Model,
+		Created:      element.Updated,
+		CreatedBy:    element.UpdatedBy,
+	}
+	_, err := session.Insert(&version)
+	return err
+}
+
 // createLibraryElement adds a library element.
 func (l *LibraryElementService) createLibraryElement(c context.Context, signedInUser identity.Requester, cmd model.CreateLibraryElementCommand) (model.LibraryElementDTO, error) {
 	if err := l.requireSupportedElementKind(cmd.Kind); err != nil {
@@ -192,7 +211,7 @@ func (l *LibraryElementService) createLibraryElement(c context.Context, signedIn
 			}
 			return err
 		}
-		return nil
+		return insertLibraryElementVersion(session, element, 0)
 	})
 
 	metrics.MFolderIDsServiceCount.WithLabelValues(metrics.LibraryElements).Inc()
@@ -259,6 +278,10 @@ func (l *LibraryElementService) deleteLibraryElement(c context.Context, signedIn
 			return model.ErrLibraryElementHasConnections
 		}
 
+		if _, err := session.Exec("DELETE FROM "+model.LibraryElementVersionTableName+" WHERE element_id=?", element.ID); err != nil {
+			return err
+		}
+
 		result, err := session.Exec("DELETE FROM library_element WHERE id=?", element.ID)
 		if err != nil {
 			return err
@@ -633,6 +656,9 @@ func (l *LibraryElementService) patchLibraryElement(c context.Context, signedInU
 		} else if rowsAffected != 1 {
 			return model.ErrLibraryElementNotFound
 		}
+		if err := insertLibraryElementVersion(session, libraryElement, cmd.RestoredFrom); err != nil {
+			return err
+		}
 
 		metrics.MFolderIDsServiceCount.WithLabelValues(metrics.LibraryElements).Inc()
 		dto = model.LibraryElementDTO{
@@ -877,6 +903,10 @@ func (l *LibraryElementService) deleteLibraryElementsInFolderUID(c context.Conte
 			if err != nil {
 				return err
 			}
+			_, err = session.Exec("DELETE FROM "+model.LibraryElementVersionTableName+" WHERE element_id=?", elementID.ID)
+			if err != nil {
+				return err
+			}
 		}
 		if _, err := session.Exec("DELETE FROM library_element WHERE folder_id=? AND org_id=?", folderID, signedInUser.GetOrgID()); err != nil {
 			return err
@@ -885,3 +915,166 @@ func (l *LibraryElementService) deleteLibraryElementsInFolderUID(c context.Conte
 		return nil
 	})
 }
+
+const (
+	// maxVersionsToDeletePerBatch is the maximum number of library element versions deleted per batch.
+	maxVersionsToDeletePerBatch = 100
+	// maxVersionDeletionBatches is the maximum number of batches of library element versions deleted per run.
+	maxVersionDeletionBatches = 50
+)
+
+func getLibraryElementVersionsSQL(dialect migrator.Dialect) string {
+	return `
+SELECT
+	lev.id, lev.org_id, lev.element_id, lev.version, lev.restored_from, lev.name, lev.kind, lev.type, lev.description, lev.model, lev.created, lev.created_by
+	, u.login AS created_by_name
+	, u.email AS created_by_email
+FROM ` + model.LibraryElementVersionTableName + ` AS lev
+LEFT JOIN ` + dialect.Quote("user") + ` AS u ON lev.created_by = u.id
+WHERE lev.element_id=? AND lev.org_id=?`
+}
+
+func (l *LibraryElementService) toLibraryElementVersionDTO(elementUID string, version model.LibraryElementVersionWithMeta) model.LibraryElementVersionDTO {
+	return model.LibraryElementVersionDTO{
+		ID:           version.ID,
+		ElementUID:   elementUID,
+		Version:      version.Version,
+		RestoredFrom: version.RestoredFrom,
+		Name:         version.Name,
+		Kind:         version.Kind,
+		Type:         version.Type,
+		Description:  version.Description,
+		Model:        version.Model,
+		Created:      version.Created,
+		CreatedBy: librarypanel.LibraryElementDTOMetaUser{
+			Id:        version.CreatedBy,
+			Name:      version.CreatedByName,
+			AvatarUrl: dtos.GetGravatarUrl(l.Cfg, version.CreatedByEmail),
+		},
+	}
+}
+
+// getLibraryElementVersions gets the versions of a Library Element, starting with the latest.
+func (l *LibraryElementService) getLibraryElementVersions(c context.Context, signedInUser identity.Requester, query model.ListLibraryElementVersionsQuery) ([]model.LibraryElementVersionDTO, error) {
+	element, err := l.getLibraryElementByUid(c, signedInUser, model.GetLibraryElementCommand{UID: query.UID, FolderName: dashboards.RootFolderName})
+	if err != nil {
+		return nil, err
+	}
+
+	if query.PerPage <= 0 {
+		query.PerPage = 100
+	}
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+
+	versions := make([]model.LibraryElementVersionWithMeta, 0)
+	err = l.SQLStore.WithDbSession(c, func(session *db.Session) error {
+		dialect := l.SQLStore.GetDialect()
+		sql := getLibraryElementVersionsSQL(dialect) +
+			" ORDER BY lev.version DESC" +
+			dialect.LimitOffset(int64(query.PerPage), int64(query.PerPage*(query.Page-1)))
+		return session.SQL(sql, element.ID, signedInUser.GetOrgID()).Find(&versions)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]model.LibraryElementVersionDTO, 0, len(versions))
+	for _, version := range versions {
+		result = append(result, l.toLibraryElementVersionDTO(element.UID, version))
+	}
+	return result, nil
+}
+
+// getLibraryElementVersion gets a version of a Library Element.
+func (l *LibraryElementService) getLibraryElementVersion(c context.Context, signedInUser identity.Requester, query model.GetLibraryElementVersionQuery) (model.LibraryElementVersionDTO, error) {
+	element, err := l.getLibraryElementByUid(c, signedInUser, model.GetLibraryElementCommand{UID: query.UID, FolderName: dashboards.RootFolderName})
+	if err != nil {
+		return model.LibraryElementVersionDTO{}, err
+	}
+
+	versions := make([]model.LibraryElementVersionWithMeta, 0)
+	err = l.SQLStore.WithDbSession(c, func(session *db.Session) error {
+		sql := getLibraryElementVersionsSQL(l.SQLStore.GetDialect()) + " AND lev.version=?"
+		return session.SQL(sql, element.ID, signedInUser.GetOrgID(), query.Version).Find(&versions)
+	})
+	if err != nil {
+		return model.LibraryElementVersionDTO{}, err
+	}
+	if len(versions) == 0 {
+		return model.LibraryElementVersionDTO{}, model.ErrLibraryElementVersionNotFound
+	}
+
+	return l.toLibraryElementVersionDTO(element.UID, versions[0]), nil
+}
+
+// restoreLibraryElementVersion updates a Library Element with the name and model of one of its versions,
+// which saves them as a new version.
+func (l *LibraryElementService) restoreLibraryElementVersion(c context.Context, signedInUser identity.Requester, uid string, version int64) (model.LibraryElementDTO, error) {
+	element, err := l.getLibraryElementByUid(c, signedInUser, model.GetLibraryElementCommand{UID: uid, FolderName: dashboards.RootFolderName})
+	if err != nil {
+		return model.LibraryElementDTO{}, err
+	}
+	restored, err := l.getLibraryElementVersion(c, signedInUser, model.GetLibraryElementVersionQuery{UID: uid, Version: version})
+	if err != nil {
+		return model.LibraryElementDTO{}, err
+	}
+
+	return l.patchLibraryElement(c, signedInUser, model.PatchLibraryElementCommand{
+		FolderID:     -1, // keep the element in its folder
+		Name:         restored.Name,
+		Model:        restored.Model,
+		Kind:         restored.Kind,
+		Version:      element.Version,
+		RestoredFrom: restored.Version,
+	}, uid)
+}
+
+// deleteExpiredVersions deletes the oldest versions of the Library Elements that have more versions
+// than the number of versions to keep.
+func (l *LibraryElementService) deleteExpiredVersions(c context.Context, cmd *model.DeleteExpiredVersionsCommand) error {
+	versionsToKeep := l.Cfg.LibraryPanelVersionsToKeep
+	if versionsToKeep < 1 {
+		versionsToKeep = 1
+	}
+
+	for batch := 0; batch < maxVersionDeletionBatches; batch++ {
+		var deleted int64
+		err := l.SQLStore.WithDbSession(c, func(session *db.Session) error {
+			var versionIDs []any
+			versionIDsToDeleteQuery := `SELECT id
+				FROM ` + model.LibraryElementVersionTableName + `, (
+					SELECT element_id, max(version) AS max
+					FROM ` + model.LibraryElementVersionTableName + `
+					GROUP BY element_id
+				) AS vtd
+				WHERE ` + model.LibraryElementVersionTableName + `.element_id=vtd.element_id
+				AND version <= vtd.max - ?
+				LIMIT ?`
+			if err := session.SQL(versionIDsToDeleteQuery, versionsToKeep, maxVersionsToDeletePerBatch).Find(&versionIDs); err != nil {
+				return err
+			}
+			if len(versionIDs) == 0 {
+				return nil
+			}
+
+			deleteExpiredSQL := `DELETE FROM ` + model.LibraryElementVersionTableName + ` WHERE id IN (?` + strings.Repeat(",?", len(versionIDs)-1) + `)`
+			result, err := session.Exec(append([]any{deleteExpiredSQL}, versionIDs...)...)
+			if err != nil {
+				return err
+			}
+			deleted, err = result.RowsAffected()
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		cmd.DeletedRows += deleted
+		if deleted < maxVersionsToDeletePerBatch {
+			break
+		}
+	}
+	return nil
+}
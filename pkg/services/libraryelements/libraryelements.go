@@ -40,6 +40,7 @@ type Service interface {
 	ConnectElementsToDashboard(c context.Context, signedInUser identity.Requester, elementUIDs []string, dashboardID int64) error
 	DisconnectElementsFromDashboard(c context.Context, dashboardID int64) error
 	DeleteLibraryElementsInFolder(c context.Context, signedInUser identity.Requester, folderUID string) error
+	DeleteExpiredVersions(c context.Context, cmd *model.DeleteExpiredVersionsCommand) error
 }
 
 // LibraryElementService is the service for the Library Element feature.
@@ -85,6 +86,11 @@ func (l *LibraryElementService) DeleteLibraryElementsInFolder(c context.Context,
 	return l.deleteLibraryElementsInFolderUID(c, signedInUser, folderUID)
 }
 
+// DeleteExpiredVersions deletes the versions of elements that exceed the number of versions to keep.
+func (l *LibraryElementService) DeleteExpiredVersions(c context.Context, cmd *model.DeleteExpiredVersionsCommand) error {
+	return l.deleteExpiredVersions(c, cmd)
+}
+
 func (l *LibraryElementService) addUidToLibraryPanel(model []byte, newUid string) (json.RawMessage, error) {
 	var modelMap map[string]any
 	err := json.Unmarshal(model, &modelMap)
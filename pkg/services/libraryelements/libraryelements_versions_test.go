@@ -0,0 +1,196 @@
+package libraryelements
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestLibraryElementVersions(t *testing.T) {
+	scenarioWithPanel(t, "When an admin gets the versions of a library panel, it should return all versions starting with the latest",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.SignedInUser.Permissions[sc.reqContext.OrgID][dashboards.ActionFoldersRead] = []string{dashboards.ScopeFoldersAll}
+			patchPanelName(t, sc, "Text - Library Panel v2", 1)
+			patchPanelName(t, sc, "Text - Library Panel v3", 2)
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			result := validateAndUnMarshalVersionsResponse(t, sc.service.getVersionsHandler(sc.reqContext))
+			require.Len(t, result.Result, 3)
+			for i, name := range []string{"Text - Library Panel v3", "Text - Library Panel v2", "Text - Library Panel"} {
+				require.Equal(t, int64(3-i), result.Result[i].Version)
+				require.Equal(t, name, result.Result[i].Name)
+				require.Equal(t, sc.initialResult.Result.UID, result.Result[i].ElementUID)
+				require.Equal(t, userInDbName, result.Result[i].CreatedBy.Name)
+			}
+
+			err := sc.reqContext.Req.ParseForm()
+			require.NoError(t, err)
+			sc.reqContext.Req.Form.Add("perPage", "1")
+			sc.reqContext.Req.Form.Add("page", "2")
+			result = validateAndUnMarshalVersionsResponse(t, sc.service.getVersionsHandler(sc.reqContext))
+			require.Len(t, result.Result, 1)
+			require.Equal(t, int64(2), result.Result[0].Version)
+		})
+
+	scenarioWithPanel(t, "When an admin gets a version of a library panel, it should return the version",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.SignedInUser.Permissions[sc.reqContext.OrgID][dashboards.ActionFoldersRead] = []string{dashboards.ScopeFoldersAll}
+			patchPanelName(t, sc, "Text - Library Panel v2", 1)
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID, ":version": "1"})
+			resp := sc.service.getVersionHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+			var result model.LibraryElementVersionResponse
+			require.NoError(t, json.Unmarshal(resp.Body(), &result))
+			require.Equal(t, int64(1), result.Result.Version)
+			require.Equal(t, "Text - Library Panel", result.Result.Name)
+			require.Equal(t, "text", result.Result.Type)
+			var versionModel map[string]any
+			require.NoError(t, json.Unmarshal(result.Result.Model, &versionModel))
+			require.Equal(t, sc.initialResult.Result.Model, versionModel)
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID, ":version": "3"})
+			require.Equal(t, 404, sc.service.getVersionHandler(sc.reqContext).Status())
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID, ":version": "latest"})
+			require.Equal(t, 400, sc.service.getVersionHandler(sc.reqContext).Status())
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": "unknown", ":version": "1"})
+			require.Equal(t, 404, sc.service.getVersionHandler(sc.reqContext).Status())
+		})
+
+	scenarioWithPanel(t, "When an admin diffs two versions of a library panel, it should return the changes",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.SignedInUser.Permissions[sc.reqContext.OrgID][dashboards.ActionFoldersRead] = []string{dashboards.ScopeFoldersAll}
+			patchPanelName(t, sc, "Text - Library Panel v2", 1)
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			err := sc.reqContext.Req.ParseForm()
+			require.NoError(t, err)
+			sc.reqContext.Req.Form.Set("base", "1")
+			sc.reqContext.Req.Form.Set("new", "2")
+			sc.reqContext.Req.Form.Set("diffType", "delta")
+			resp := sc.service.diffVersionsHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+			var delta map[string]any
+			require.NoError(t, json.Unmarshal(resp.Body(), &delta))
+			require.Equal(t, []any{"Text - Library Panel", "Text - Library Panel v2"}, delta["name"])
+			require.NotContains(t, delta, "model")
+
+			sc.reqContext.Req.Form.Set("new", "1")
+			resp = sc.service.diffVersionsHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+			require.JSONEq(t, `{}`, string(resp.Body()))
+
+			sc.reqContext.Req.Form.Set("diffType", "basic")
+			sc.reqContext.Req.Form.Set("new", "2")
+			resp = sc.service.diffVersionsHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+			require.Contains(t, string(resp.Body()), "Text - Library Panel v2")
+
+			sc.reqContext.Req.Form.Set("new", "3")
+			require.Equal(t, 404, sc.service.diffVersionsHandler(sc.reqContext).Status())
+
+			sc.reqContext.Req.Form.Del("base")
+			require.Equal(t, 400, sc.service.diffVersionsHandler(sc.reqContext).Status())
+		})
+
+	scenarioWithPanel(t, "When an admin restores a version of a library panel, it should save it as a new version",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.SignedInUser.Permissions[sc.reqContext.OrgID][dashboards.ActionFoldersRead] = []string{dashboards.ScopeFoldersAll}
+			patchPanelName(t, sc, "Text - Library Panel v2", 1)
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID, ":version": "1"})
+			result := validateAndUnMarshalResponse(t, sc.service.restoreVersionHandler(sc.reqContext))
+			require.Equal(t, int64(3), result.Result.Version)
+			require.Equal(t, "Text - Library Panel", result.Result.Name)
+			require.Equal(t, sc.initialResult.Result.Model, result.Result.Model)
+			require.Equal(t, sc.folder.UID, result.Result.Meta.FolderUID)
+
+			versions := validateAndUnMarshalVersionsResponse(t, sc.service.getVersionsHandler(sc.reqContext))
+			require.Len(t, versions.Result, 3)
+			require.Equal(t, int64(1), versions.Result[0].RestoredFrom)
+			require.Equal(t, int64(0), versions.Result[1].RestoredFrom)
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID, ":version": "4"})
+			require.Equal(t, 404, sc.service.restoreVersionHandler(sc.reqContext).Status())
+		})
+
+	scenarioWithPanel(t, "When an admin deletes a library panel, it should delete its versions",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.SignedInUser.Permissions[sc.reqContext.OrgID][dashboards.ActionFoldersRead] = []string{dashboards.ScopeFoldersAll}
+			patchPanelName(t, sc, "Text - Library Panel v2", 1)
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			require.Equal(t, 200, sc.service.deleteHandler(sc.reqContext).Status())
+
+			var count int64
+			err := sc.sqlStore.WithDbSession(context.Background(), func(session *db.Session) error {
+				var err error
+				count, err = session.Table(model.LibraryElementVersionTableName).Where("element_id=?", sc.initialResult.Result.ID).Count()
+				return err
+			})
+			require.NoError(t, err)
+			require.Equal(t, int64(0), count)
+		})
+
+	scenarioWithPanel(t, "When expired versions are deleted, it should keep the latest versions of each library panel",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.SignedInUser.Permissions[sc.reqContext.OrgID][dashboards.ActionFoldersRead] = []string{dashboards.ScopeFoldersAll}
+			command := getCreatePanelCommand(sc.folder.ID, sc.folder.UID, "Text - Library Panel 2") // nolint:staticcheck
+			sc.reqContext.Req.Body = mockRequestBody(command)
+			other := validateAndUnMarshalResponse(t, sc.service.createHandler(sc.reqContext))
+
+			patchPanelName(t, sc, "Text - Library Panel v2", 1)
+			patchPanelName(t, sc, "Text - Library Panel v3", 2)
+			patchPanelName(t, sc, "Text - Library Panel v4", 3)
+
+			sc.service.Cfg.LibraryPanelVersionsToKeep = 2
+			cmd := model.DeleteExpiredVersionsCommand{}
+			require.NoError(t, sc.service.DeleteExpiredVersions(context.Background(), &cmd))
+			require.Equal(t, int64(2), cmd.DeletedRows)
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			versions := validateAndUnMarshalVersionsResponse(t, sc.service.getVersionsHandler(sc.reqContext))
+			require.Len(t, versions.Result, 2)
+			require.Equal(t, int64(4), versions.Result[0].Version)
+			require.Equal(t, int64(3), versions.Result[1].Version)
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": other.Result.UID})
+			versions = validateAndUnMarshalVersionsResponse(t, sc.service.getVersionsHandler(sc.reqContext))
+			require.Len(t, versions.Result, 1)
+		})
+}
+
+func patchPanelName(t *testing.T, sc scenarioContext, name string, version int64) {
+	t.Helper()
+
+	cmd := model.PatchLibraryElementCommand{
+		FolderID:  sc.folder.ID, // nolint:staticcheck
+		FolderUID: &sc.folder.UID,
+		Name:      name,
+		Kind:      int64(model.PanelElement),
+		Version:   version,
+	}
+	sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+	sc.reqContext.Req.Body = mockRequestBody(cmd)
+	resp := sc.service.patchHandler(sc.reqContext)
+	require.Equal(t, 200, resp.Status())
+}
+
+func validateAndUnMarshalVersionsResponse(t *testing.T, resp response.Response) model.LibraryElementVersionsResponse {
+	t.Helper()
+	require.Equal(t, 200, resp.Status())
+	var result = model.LibraryElementVersionsResponse{}
+	err := json.Unmarshal(resp.Body(), &result)
+	require.NoError(t, err)
+	return result
+}
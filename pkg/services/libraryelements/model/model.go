@@ -137,6 +137,60 @@ type LibraryElementConnectionDTO struct {
 	CreatedBy     librarypanel.LibraryElementDTOMetaUser `json:"createdBy"`
 }
 
+// LibraryElementVersion is the model for the saved versions of a library element.
+type LibraryElementVersion struct {
+	ID           int64 `xorm:"pk autoincr 'id'"`
+	OrgID        int64 `xorm:"org_id"`
+	ElementID    int64 `xorm:"element_id"`
+	Version      int64
+	RestoredFrom int64
+	Name         string
+	Kind         int64
+	Type         string
+	Description  string
+	Model        json.RawMessage
+
+	Created time.Time
+
+	CreatedBy int64
+}
+
+// LibraryElementVersionWithMeta is the model used to retrieve versions with additional meta information.
+type LibraryElementVersionWithMeta struct {
+	ID           int64 `xorm:"pk autoincr 'id'"`
+	OrgID        int64 `xorm:"org_id"`
+	ElementID    int64 `xorm:"element_id"`
+	Version      int64
+	RestoredFrom int64
+	Name         string
+	Kind         int64
+	Type         string
+	Description  string
+	Model        json.RawMessage
+
+	Created time.Time
+
+	CreatedBy      int64
+	CreatedByName  string
+	CreatedByEmail string
+}
+
+// LibraryElementVersionDTO is the frontend DTO for library element versions.
+type LibraryElementVersionDTO struct {
+	ID           int64  `json:"id"`
+	ElementUID   string `json:"elementUid"`
+	Version      int64  `json:"version"`
+	RestoredFrom int64  `json:"restoredFrom"`
+	Name         string `json:"name"`
+	Kind         int64  `json:"kind"`
+	Type         string `json:"type"`
+	Description  string `json:"description"`
+	// swagger:type object
+	Model     json.RawMessage                        `json:"model"`
+	Created   time.Time                              `json:"created"`
+	CreatedBy librarypanel.LibraryElementDTOMetaUser `json:"createdBy"`
+}
+
 var (
 	// errLibraryElementAlreadyExists is an error for when the user tries to add a library element that already exists.
 	ErrLibraryElementAlreadyExists = errors.New("library element with that name or UID already exists")
@@ -156,6 +210,8 @@ var (
 	ErrLibraryElementInvalidUID = errors.New("uid contains illegal characters")
 	// errLibraryElementUIDTooLong is an error for when the uid of a library element is invalid
 	ErrLibraryElementUIDTooLong = errors.New("uid too long, max 40 characters")
+	// ErrLibraryElementVersionNotFound is an error for when a version of a library element can't be found.
+	ErrLibraryElementVersionNotFound = errors.New("library element version could not be found")
 )
 
 // Commands
@@ -206,6 +262,8 @@ type PatchLibraryElementCommand struct {
 	Version int64 `json:"version" binding:"Required"`
 	// required: false
 	UID string `json:"uid"`
+
+	RestoredFrom int64 `json:"-"`
 }
 
 // GetLibraryElementCommand is the command for getting a library element.
@@ -230,6 +288,25 @@ type SearchLibraryElementsQuery struct {
 	FolderFilterUIDs string
 }
 
+// ListLibraryElementVersionsQuery is the query for listing the versions of a library element.
+type ListLibraryElementVersionsQuery struct {
+	UID     string
+	PerPage int
+	Page    int
+}
+
+// GetLibraryElementVersionQuery is the query for getting a version of a library element.
+type GetLibraryElementVersionQuery struct {
+	UID     string
+	Version int64
+}
+
+// DeleteExpiredVersionsCommand is the command for deleting the versions of library elements
+// that exceed the number of versions to keep.
+type DeleteExpiredVersionsCommand struct {
+	DeletedRows int64
+}
+
 // LibraryElementResponse is a response struct for LibraryElementDTO.
 type LibraryElementResponse struct {
 	Result LibraryElementDTO `json:"result"`
@@ -250,6 +327,16 @@ type LibraryElementConnectionsResponse struct {
 	Result []LibraryElementConnectionDTO `json:"result"`
 }
 
+// LibraryElementVersionsResponse is a response struct for an array of LibraryElementVersionDTO.
+type LibraryElementVersionsResponse struct {
+	Result []LibraryElementVersionDTO `json:"result"`
+}
+
+// LibraryElementVersionResponse is a response struct for LibraryElementVersionDTO.
+type LibraryElementVersionResponse struct {
+	Result LibraryElementVersionDTO `json:"result"`
+}
+
 // DeleteLibraryElementResponse is the response struct for deleting a library element.
 type DeleteLibraryElementResponse struct {
 	ID      int64  `json:"id"`
@@ -267,3 +354,5 @@ const (
 )
 
 const LibraryElementConnectionTableName = "library_element_connection"
+
+const LibraryElementVersionTableName = "library_element_version"
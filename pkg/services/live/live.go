@@ -164,6 +164,28 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 
 	g.ManagedStreamRunner = managedStreamRunner
 
+	if g.Features.IsEnabledGlobally(featuremgmt.FlagLivePipeline) {
+		storage := &pipeline.SQLStorage{
+			SQLStore:       sqlStore,
+			SecretsService: secretsService,
+		}
+		g.pipelineStorage = storage
+		g.pipelineRules = pipeline.NewCacheSegmentedTree(&pipeline.StorageRuleBuilder{
+			Node:                 node,
+			ManagedStream:        g.ManagedStreamRunner,
+			FrameStorage:         pipeline.NewFrameStorage(),
+			Storage:              storage,
+			ChannelHandlerGetter: g,
+			SecretsService:       secretsService,
+		})
+		g.Pipeline, err = pipeline.New(g.pipelineRules)
+		if err != nil {
+			return nil, err
+		}
+		// Every instance caches the channel rules, changes are announced to all of them.
+		node.OnNotification(g.handleNotification)
+	}
+
 	g.contextGetter = liveplugin.NewContextGetter(g.PluginContextProvider, g.DataSourceCache)
 	pipelinedChannelLocalPublisher := liveplugin.NewChannelLocalPublisher(node, g.Pipeline)
 	numLocalSubscribersGetter := liveplugin.NewNumLocalSubscribersGetter(node)
@@ -402,6 +424,7 @@ type GrafanaLive struct {
 	ManagedStreamRunner *managedstream.Runner
 	Pipeline            *pipeline.Pipeline
 	pipelineStorage     pipeline.Storage
+	pipelineRules       *pipeline.CacheSegmentedTree
 
 	contextGetter    *liveplugin.ContextGetter
 	runStreamManager *runstream.Manager
@@ -1169,8 +1192,9 @@ func (g *GrafanaLive) HandleChannelRulesPostHTTP(c *contextmodel.ReqContext) res
 	}
 	rule, err := g.pipelineStorage.CreateChannelRule(c.Req.Context(), c.SignedInUser.GetOrgID(), cmd)
 	if err != nil {
-		return response.Error(http.StatusInternalServerError, "Failed to create channel rule", err)
+		return pipelineStorageErrorResponse("Failed to create channel rule", err)
 	}
+	g.invalidatePipelineRules(c.SignedInUser.GetOrgID())
 	return response.JSON(http.StatusOK, util.DynMap{
 		"rule": rule,
 	})
@@ -1192,8 +1216,9 @@ func (g *GrafanaLive) HandleChannelRulesPutHTTP(c *contextmodel.ReqContext) resp
 	}
 	rule, err := g.pipelineStorage.UpdateChannelRule(c.Req.Context(), c.SignedInUser.GetOrgID(), cmd)
 	if err != nil {
-		return response.Error(http.StatusInternalServerError, "Failed to update channel rule", err)
+		return pipelineStorageErrorResponse("Failed to update channel rule", err)
 	}
+	g.invalidatePipelineRules(c.SignedInUser.GetOrgID())
 	return response.JSON(http.StatusOK, util.DynMap{
 		"rule": rule,
 	})
@@ -1215,8 +1240,9 @@ func (g *GrafanaLive) HandleChannelRulesDeleteHTTP(c *contextmodel.ReqContext) r
 	}
 	err = g.pipelineStorage.DeleteChannelRule(c.Req.Context(), c.SignedInUser.GetOrgID(), cmd)
 	if err != nil {
-		return response.Error(http.StatusInternalServerError, "Failed to delete channel rule", err)
+		return pipelineStorageErrorResponse("Failed to delete channel rule", err)
 	}
+	g.invalidatePipelineRules(c.SignedInUser.GetOrgID())
 	return response.JSON(http.StatusOK, util.DynMap{})
 }
 
@@ -1259,8 +1285,9 @@ func (g *GrafanaLive) HandleWriteConfigsPostHTTP(c *contextmodel.ReqContext) res
 	}
 	result, err := g.pipelineStorage.CreateWriteConfig(c.Req.Context(), c.SignedInUser.GetOrgID(), cmd)
 	if err != nil {
-		return response.Error(http.StatusInternalServerError, "Failed to create write config", err)
+		return pipelineStorageErrorResponse("Failed to create write config", err)
 	}
+	g.invalidatePipelineRules(c.SignedInUser.GetOrgID())
 	return response.JSON(http.StatusOK, util.DynMap{
 		"writeConfig": pipeline.WriteConfigToDto(result),
 	})
@@ -1303,8 +1330,9 @@ func (g *GrafanaLive) HandleWriteConfigsPutHTTP(c *contextmodel.ReqContext) resp
 	}
 	result, err := g.pipelineStorage.UpdateWriteConfig(c.Req.Context(), c.SignedInUser.GetOrgID(), cmd)
 	if err != nil {
-		return response.Error(http.StatusInternalServerError, "Failed to update write config", err)
+		return pipelineStorageErrorResponse("Failed to update write config", err)
 	}
+	g.invalidatePipelineRules(c.SignedInUser.GetOrgID())
 	return response.JSON(http.StatusOK, util.DynMap{
 		"writeConfig": pipeline.WriteConfigToDto(result),
 	})
@@ -1326,11 +1354,58 @@ func (g *GrafanaLive) HandleWriteConfigsDeleteHTTP(c *contextmodel.ReqContext) r
 	}
 	err = g.pipelineStorage.DeleteWriteConfig(c.Req.Context(), c.SignedInUser.GetOrgID(), cmd)
 	if err != nil {
-		return response.Error(http.StatusInternalServerError, "Failed to delete write config", err)
+		return pipelineStorageErrorResponse("Failed to delete write config", err)
 	}
+	g.invalidatePipelineRules(c.SignedInUser.GetOrgID())
 	return response.JSON(http.StatusOK, util.DynMap{})
 }
 
+// pipelineStorageErrorResponse maps errors of the pipeline storage to HTTP responses.
+func pipelineStorageErrorResponse(message string, err error) response.Response {
+	switch {
+	case errors.Is(err, pipeline.ErrInvalidChannelRule), errors.Is(err, pipeline.ErrInvalidWriteConfig):
+		return response.Error(http.StatusBadRequest, fmt.Sprintf("%s: %s", message, err), err)
+	case errors.Is(err, pipeline.ErrChannelRuleNotFound), errors.Is(err, pipeline.ErrWriteConfigNotFound):
+		return response.Error(http.StatusNotFound, fmt.Sprintf("%s: %s", message, err), err)
+	case errors.Is(err, pipeline.ErrChannelRuleExists), errors.Is(err, pipeline.ErrWriteConfigExists), errors.Is(err, pipeline.ErrVersionConflict):
+		return response.Error(http.StatusConflict, fmt.Sprintf("%s: %s", message, err), err)
+	default:
+		return response.Error(http.StatusInternalServerError, message, err)
+	}
+}
+
+const pipelineRulesChangedOp = "pipeline_rules_changed"
+
+type pipelineRulesChanged struct {
+	OrgID int64 `json:"orgId"`
+}
+
+// invalidatePipelineRules makes all Grafana instances build the channel rules of an organization again.
+func (g *GrafanaLive) invalidatePipelineRules(orgID int64) {
+	data, err := json.Marshal(pipelineRulesChanged{OrgID: orgID})
+	if err != nil {
+		logger.Error("Error encoding pipeline notification", "error", err)
+		g.pipelineRules.Invalidate(orgID)
+		return
+	}
+	// The notification is handled by this instance as well, even if it fails to reach the others.
+	if err := g.node.Notify(pipelineRulesChangedOp, data, ""); err != nil {
+		logger.Error("Error notifying other instances about changed channel rules", "error", err, "orgId", orgID)
+	}
+}
+
+func (g *GrafanaLive) handleNotification(e centrifuge.NotificationEvent) {
+	switch e.Op {
+	case pipelineRulesChangedOp:
+		var msg pipelineRulesChanged
+		if err := json.Unmarshal(e.Data, &msg); err != nil {
+			logger.Error("Error decoding pipeline notification", "error", err, "node", e.FromNodeID)
+			return
+		}
+		g.pipelineRules.Invalidate(msg.OrgID)
+	}
+}
+
 // Write to the standard log15 logger
 func handleLog(msg centrifuge.LogEntry) {
 	arr := make([]interface{}, 0)
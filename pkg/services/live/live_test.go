@@ -17,6 +17,8 @@ import (
 	"github.com/grafana/grafana/pkg/services/authz/zanzana"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/live/pipeline"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/tests/testsuite"
 )
@@ -43,6 +45,36 @@ func Test_provideLiveService_RedisUnavailable(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func Test_provideLiveService_PipelineRulesInvalidation(t *testing.T) {
+	cfg := setting.NewCfg()
+	features := featuremgmt.WithFeatures(featuremgmt.FlagLivePipeline)
+
+	g, err := ProvideService(nil, cfg,
+		routing.NewRouteRegister(),
+		nil, nil, nil, nil,
+		db.InitTestDB(t),
+		fakes.NewFakeSecretsService(),
+		&usagestats.UsageStatsMock{T: t},
+		nil,
+		features, acimpl.ProvideAccessControl(features, zanzana.NewNoopClient()), &dashboards.FakeDashboardService{}, annotationstest.NewFakeAnnotationsRepo(), nil)
+	require.NoError(t, err)
+	require.NotNil(t, g.Pipeline)
+
+	ctx := context.Background()
+	_, ok, err := g.Pipeline.Get(1, "stream/test/cpu")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	_, err = g.pipelineStorage.CreateChannelRule(ctx, 1, pipeline.ChannelRuleCreateCmd{Pattern: "stream/test/cpu"})
+	require.NoError(t, err)
+	g.invalidatePipelineRules(1)
+
+	rule, ok, err := g.Pipeline.Get(1, "stream/test/cpu")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "stream/test/cpu", rule.Pattern)
+}
+
 func Test_runConcurrentlyIfNeeded_Concurrent(t *testing.T) {
 	doneCh := make(chan struct{})
 	f := func() {
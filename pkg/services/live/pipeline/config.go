@@ -31,6 +31,7 @@ type ChannelRuleSettings struct {
 type ChannelRule struct {
 	OrgId    int64               `json:"-"`
 	Pattern  string              `json:"pattern"`
+	Version  int64               `json:"version,omitempty"`
 	Settings ChannelRuleSettings `json:"settings"`
 }
 
@@ -50,11 +51,40 @@ type KeepFieldsFrameProcessorConfig struct {
 	FieldNames []string `json:"fieldNames"`
 }
 
+type RenameFieldsFrameProcessorConfig struct {
+	// Renames maps current field names to new field names.
+	Renames map[string]string `json:"renames"`
+}
+
+type CastFieldsFrameProcessorConfig struct {
+	// Types maps field names to the type their values should be cast to.
+	Types map[string]data.FieldType `json:"types"`
+}
+
+type DeriveFieldFrameProcessorConfig struct {
+	FieldName string `json:"fieldName"`
+	// Expression to calculate field values, other fields are referenced as $name or ${name}.
+	Expression string `json:"expression"`
+}
+
+type ExtractLabelsFrameProcessorConfig struct {
+	FieldNames []string `json:"fieldNames"`
+}
+
+type RateLimitFrameProcessorConfig struct {
+	SampleMilliseconds int64 `json:"sampleMilliseconds"`
+}
+
 type FrameProcessorConfig struct {
-	Type                      string                          `json:"type" ts_type:"Omit<keyof FrameProcessorConfig, 'type'>"`
-	DropFieldsProcessorConfig *DropFieldsFrameProcessorConfig `json:"dropFields,omitempty"`
-	KeepFieldsProcessorConfig *KeepFieldsFrameProcessorConfig `json:"keepFields,omitempty"`
-	MultipleProcessorConfig   *MultipleFrameProcessorConfig   `json:"multiple,omitempty"`
+	Type                         string                             `json:"type" ts_type:"Omit<keyof FrameProcessorConfig, 'type'>"`
+	DropFieldsProcessorConfig    *DropFieldsFrameProcessorConfig    `json:"dropFields,omitempty"`
+	KeepFieldsProcessorConfig    *KeepFieldsFrameProcessorConfig    `json:"keepFields,omitempty"`
+	MultipleProcessorConfig      *MultipleFrameProcessorConfig      `json:"multiple,omitempty"`
+	RenameFieldsProcessorConfig  *RenameFieldsFrameProcessorConfig  `json:"renameFields,omitempty"`
+	CastFieldsProcessorConfig    *CastFieldsFrameProcessorConfig    `json:"castFields,omitempty"`
+	DeriveFieldProcessorConfig   *DeriveFieldFrameProcessorConfig   `json:"deriveField,omitempty"`
+	ExtractLabelsProcessorConfig *ExtractLabelsFrameProcessorConfig `json:"extractLabels,omitempty"`
+	RateLimitProcessorConfig     *RateLimitFrameProcessorConfig     `json:"rateLimit,omitempty"`
 }
 
 type MultipleFrameProcessorConfig struct {
@@ -0,0 +1,142 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// CastFieldsFrameProcessor can cast values of specified fields of a data.Frame
+// to another type. Numbers are cast to time as milliseconds since epoch, strings
+// are cast to time using RFC3339 format or as milliseconds since epoch.
+type CastFieldsFrameProcessor struct {
+	config CastFieldsFrameProcessorConfig
+}
+
+func NewCastFieldsFrameProcessor(config CastFieldsFrameProcessorConfig) *CastFieldsFrameProcessor {
+	return &CastFieldsFrameProcessor{config: config}
+}
+
+const FrameProcessorTypeCastFields = "castFields"
+
+func (p *CastFieldsFrameProcessor) Type() string {
+	return FrameProcessorTypeCastFields
+}
+
+func (p *CastFieldsFrameProcessor) ProcessFrame(_ context.Context, _ Vars, frame *data.Frame) (*data.Frame, error) {
+	for i, field := range frame.Fields {
+		fieldType, ok := p.config.Types[field.Name]
+		if !ok || fieldType == field.Type() {
+			continue
+		}
+		casted, err := castField(field, fieldType)
+		if err != nil {
+			return nil, err
+		}
+		frame.Fields[i] = casted
+	}
+	return frame, nil
+}
+
+func castField(field *data.Field, fieldType data.FieldType) (*data.Field, error) {
+	casted := data.NewFieldFromFieldType(fieldType, field.Len())
+	casted.Name = field.Name
+	casted.Labels = field.Labels
+	casted.Config = field.Config
+	for i := 0; i < field.Len(); i++ {
+		if _, ok := field.ConcreteAt(i); !ok {
+			if !fieldType.Nullable() {
+				return nil, fmt.Errorf("can not cast null value of field %s to %s", field.Name, fieldType.ItemTypeString())
+			}
+			continue
+		}
+		value, err := castValue(field, i, fieldType.NonNullableType())
+		if err != nil {
+			return nil, fmt.Errorf("can not cast value of field %s to %s: %w", field.Name, fieldType.ItemTypeString(), err)
+		}
+		casted.SetConcrete(i, value)
+	}
+	return casted, nil
+}
+
+// nolint:gocyclo
+func castValue(field *data.Field, idx int, fieldType data.FieldType) (any, error) {
+	value, _ := field.ConcreteAt(idx)
+	switch fieldType {
+	case data.FieldTypeString:
+		switch v := value.(type) {
+		case time.Time:
+			return v.Format(time.RFC3339Nano), nil
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64), nil
+		case float32:
+			return strconv.FormatFloat(float64(v), 'f', -1, 32), nil
+		default:
+			return fmt.Sprint(v), nil
+		}
+	case data.FieldTypeBool:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			return strconv.ParseBool(v)
+		default:
+			f, err := field.FloatAt(idx)
+			if err != nil {
+				return nil, err
+			}
+			return f != 0, nil
+		}
+	case data.FieldTypeTime:
+		switch v := value.(type) {
+		case time.Time:
+			return v, nil
+		case string:
+			if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+				return t, nil
+			}
+		}
+		f, err := field.FloatAt(idx)
+		if err != nil {
+			return nil, err
+		}
+		return time.UnixMilli(int64(f)), nil
+	}
+
+	if !fieldType.Numeric() {
+		return nil, fmt.Errorf("unsupported type")
+	}
+	f, err := field.FloatAt(idx)
+	if err != nil {
+		return nil, err
+	}
+	if math.IsNaN(f) && fieldType != data.FieldTypeFloat64 && fieldType != data.FieldTypeFloat32 {
+		return nil, fmt.Errorf("NaN can not be cast to integer")
+	}
+	switch fieldType {
+	case data.FieldTypeInt8:
+		return int8(f), nil
+	case data.FieldTypeInt16:
+		return int16(f), nil
+	case data.FieldTypeInt32:
+		return int32(f), nil
+	case data.FieldTypeInt64:
+		return int64(f), nil
+	case data.FieldTypeUint8:
+		return uint8(f), nil
+	case data.FieldTypeUint16:
+		return uint16(f), nil
+	case data.FieldTypeUint32:
+		return uint32(f), nil
+	case data.FieldTypeUint64:
+		return uint64(f), nil
+	case data.FieldTypeFloat32:
+		return float32(f), nil
+	default:
+		return f, nil
+	}
+}
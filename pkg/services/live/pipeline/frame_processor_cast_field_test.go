@@ -0,0 +1,69 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCastFieldsFrameProcessor(t *testing.T) {
+	ts := time.Date(2021, 9, 1, 12, 0, 0, 0, time.UTC)
+	processor := NewCastFieldsFrameProcessor(CastFieldsFrameProcessorConfig{
+		Types: map[string]data.FieldType{
+			"time":    data.FieldTypeTime,
+			"value":   data.FieldTypeNullableFloat64,
+			"count":   data.FieldTypeInt64,
+			"enabled": data.FieldTypeBool,
+			"code":    data.FieldTypeString,
+		},
+	})
+
+	frame := data.NewFrame("test",
+		data.NewField("time", nil, []string{ts.Format(time.RFC3339), "1630497600000"}),
+		data.NewField("value", data.Labels{"device": "1"}, []*string{stringPtr("1.5"), nil}),
+		data.NewField("count", nil, []float64{2.0, 3.0}),
+		data.NewField("enabled", nil, []string{"true", "0"}),
+		data.NewField("code", nil, []int64{200, 404}),
+	)
+
+	result, err := processor.ProcessFrame(context.Background(), Vars{}, frame)
+	require.NoError(t, err)
+	require.Equal(t, data.FieldTypeTime, result.Fields[0].Type())
+	require.True(t, ts.Equal(result.Fields[0].At(0).(time.Time)))
+	require.True(t, ts.Equal(result.Fields[0].At(1).(time.Time)))
+
+	require.Equal(t, data.FieldTypeNullableFloat64, result.Fields[1].Type())
+	require.Equal(t, data.Labels{"device": "1"}, result.Fields[1].Labels)
+	require.Equal(t, 1.5, *result.Fields[1].At(0).(*float64))
+	require.Nil(t, result.Fields[1].At(1))
+
+	require.Equal(t, int64(2), result.Fields[2].At(0))
+	require.Equal(t, true, result.Fields[3].At(0))
+	require.Equal(t, false, result.Fields[3].At(1))
+	require.Equal(t, "404", result.Fields[4].At(1))
+
+	t.Run("fails to cast null to non nullable type", func(t *testing.T) {
+		processor := NewCastFieldsFrameProcessor(CastFieldsFrameProcessorConfig{
+			Types: map[string]data.FieldType{"value": data.FieldTypeFloat64},
+		})
+		frame := data.NewFrame("test", data.NewField("value", nil, []*string{nil}))
+		_, err := processor.ProcessFrame(context.Background(), Vars{}, frame)
+		require.Error(t, err)
+	})
+
+	t.Run("fails to cast invalid value", func(t *testing.T) {
+		processor := NewCastFieldsFrameProcessor(CastFieldsFrameProcessorConfig{
+			Types: map[string]data.FieldType{"value": data.FieldTypeFloat64},
+		})
+		frame := data.NewFrame("test", data.NewField("value", nil, []string{"abc"}))
+		_, err := processor.ProcessFrame(context.Background(), Vars{}, frame)
+		require.Error(t, err)
+	})
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,200 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
+)
+
+// DeriveFieldFrameProcessor adds a field to a data.Frame with values calculated
+// from other fields of the same row using a math expression. It supports the
+// operators of server-side math expressions, for example `($temp - 32) / 1.8`.
+// Comparison and logical operators return 1 or 0. If the result can not be
+// calculated, for example because of a null value, the derived value is null.
+type DeriveFieldFrameProcessor struct {
+	fieldName string
+	tree      *parse.Tree
+}
+
+var deriveFieldFuncs = map[string]parse.Func{
+	"abs":   deriveFieldFunc(math.Abs),
+	"ceil":  deriveFieldFunc(math.Ceil),
+	"floor": deriveFieldFunc(math.Floor),
+	"log":   deriveFieldFunc(math.Log),
+	"round": deriveFieldFunc(math.Round),
+	"sqrt":  deriveFieldFunc(math.Sqrt),
+}
+
+func deriveFieldFunc(f func(float64) float64) parse.Func {
+	return parse.Func{
+		Args:   []parse.ReturnType{parse.TypeVariantSet},
+		Return: parse.TypeScalar,
+		F:      f,
+	}
+}
+
+func NewDeriveFieldFrameProcessor(config DeriveFieldFrameProcessorConfig) (*DeriveFieldFrameProcessor, error) {
+	if config.FieldName == "" {
+		return nil, fmt.Errorf("field name is required")
+	}
+	tree, err := parse.Parse(config.Expression, deriveFieldFuncs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression: %w", err)
+	}
+	if err := checkDeriveNode(tree.Root); err != nil {
+		return nil, fmt.Errorf("invalid expression: %w", err)
+	}
+	return &DeriveFieldFrameProcessor{fieldName: config.FieldName, tree: tree}, nil
+}
+
+const FrameProcessorTypeDeriveField = "deriveField"
+
+func (p *DeriveFieldFrameProcessor) Type() string {
+	return FrameProcessorTypeDeriveField
+}
+
+func (p *DeriveFieldFrameProcessor) ProcessFrame(_ context.Context, _ Vars, frame *data.Frame) (*data.Frame, error) {
+	fields := make(map[string]*data.Field, len(frame.Fields))
+	for _, field := range frame.Fields {
+		fields[field.Name] = field
+	}
+	for _, name := range p.tree.VarNames {
+		if _, ok := fields[name]; !ok {
+			return nil, fmt.Errorf("field %s referenced in expression not found", name)
+		}
+	}
+
+	rows, err := frame.RowLen()
+	if err != nil {
+		return nil, err
+	}
+	derived := data.NewFieldFromFieldType(data.FieldTypeNullableFloat64, rows)
+	derived.Name = p.fieldName
+	for i := 0; i < rows; i++ {
+		value, err := evalDeriveNode(p.tree.Root, fields, i)
+		if err != nil {
+			return nil, err
+		}
+		if !math.IsNaN(value) && !math.IsInf(value, 0) {
+			derived.SetConcrete(i, value)
+		}
+	}
+
+	for i, field := range frame.Fields {
+		if field.Name == p.fieldName {
+			frame.Fields[i] = derived
+			return frame, nil
+		}
+	}
+	frame.Fields = append(frame.Fields, derived)
+	return frame, nil
+}
+
+func checkDeriveNode(node parse.Node) error {
+	switch n := node.(type) {
+	case *parse.ScalarNode, *parse.VarNode:
+		return nil
+	case *parse.FuncNode:
+		return checkDeriveNode(n.Args[0])
+	case *parse.UnaryNode:
+		return checkDeriveNode(n.Arg)
+	case *parse.BinaryNode:
+		if err := checkDeriveNode(n.Args[0]); err != nil {
+			return err
+		}
+		return checkDeriveNode(n.Args[1])
+	default:
+		return fmt.Errorf("unsupported expression: %s", node)
+	}
+}
+
+// nolint:gocyclo
+func evalDeriveNode(node parse.Node, fields map[string]*data.Field, idx int) (float64, error) {
+	switch n := node.(type) {
+	case *parse.ScalarNode:
+		return n.Float64, nil
+	case *parse.VarNode:
+		v, err := fields[n.Name].FloatAt(idx)
+		if err != nil {
+			return 0, fmt.Errorf("field %s: %w", n.Name, err)
+		}
+		return v, nil
+	case *parse.FuncNode:
+		arg, err := evalDeriveNode(n.Args[0], fields, idx)
+		if err != nil {
+			return 0, err
+		}
+		return n.F.F.(func(float64) float64)(arg), nil
+	case *parse.UnaryNode:
+		arg, err := evalDeriveNode(n.Arg, fields, idx)
+		if err != nil {
+			return 0, err
+		}
+		if math.IsNaN(arg) {
+			return arg, nil
+		}
+		switch n.OpStr {
+		case "-":
+			return -arg, nil
+		case "!":
+			return boolToFloat(arg == 0), nil
+		}
+		return 0, fmt.Errorf("unsupported operator: %s", n.OpStr)
+	case *parse.BinaryNode:
+		a, err := evalDeriveNode(n.Args[0], fields, idx)
+		if err != nil {
+			return 0, err
+		}
+		b, err := evalDeriveNode(n.Args[1], fields, idx)
+		if err != nil {
+			return 0, err
+		}
+		if math.IsNaN(a) || math.IsNaN(b) {
+			return math.NaN(), nil
+		}
+		switch n.OpStr {
+		case "+":
+			return a + b, nil
+		case "-":
+			return a - b, nil
+		case "*":
+			return a * b, nil
+		case "/":
+			return a / b, nil
+		case "%":
+			return math.Mod(a, b), nil
+		case "**":
+			return math.Pow(a, b), nil
+		case "==":
+			return boolToFloat(a == b), nil
+		case "!=":
+			return boolToFloat(a != b), nil
+		case ">":
+			return boolToFloat(a > b), nil
+		case ">=":
+			return boolToFloat(a >= b), nil
+		case "<":
+			return boolToFloat(a < b), nil
+		case "<=":
+			return boolToFloat(a <= b), nil
+		case "&&":
+			return boolToFloat(a != 0 && b != 0), nil
+		case "||":
+			return boolToFloat(a != 0 || b != 0), nil
+		}
+		return 0, fmt.Errorf("unsupported operator: %s", n.OpStr)
+	default:
+		return 0, fmt.Errorf("unsupported expression: %s", node)
+	}
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
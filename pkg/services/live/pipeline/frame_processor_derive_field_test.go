@@ -0,0 +1,65 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeriveFieldFrameProcessor(t *testing.T) {
+	frame := data.NewFrame("test",
+		data.NewField("temp", nil, []*float64{float64Ptr(212), nil, float64Ptr(32)}),
+		data.NewField("offset", nil, []int64{0, 1, 2}),
+	)
+
+	processor, err := NewDeriveFieldFrameProcessor(DeriveFieldFrameProcessorConfig{
+		FieldName:  "celsius",
+		Expression: "round(($temp - 32) / 1.8) + ${offset}",
+	})
+	require.NoError(t, err)
+
+	result, err := processor.ProcessFrame(context.Background(), Vars{}, frame)
+	require.NoError(t, err)
+	require.Len(t, result.Fields, 3)
+	derived := result.Fields[2]
+	require.Equal(t, "celsius", derived.Name)
+	require.Equal(t, []*float64{float64Ptr(100), nil, float64Ptr(2)}, []*float64{
+		derived.At(0).(*float64), derived.At(1).(*float64), derived.At(2).(*float64),
+	})
+
+	t.Run("comparisons return 1 or 0", func(t *testing.T) {
+		processor, err := NewDeriveFieldFrameProcessor(DeriveFieldFrameProcessorConfig{
+			FieldName:  "offset",
+			Expression: "$offset >= 1 && !($offset == 2)",
+		})
+		require.NoError(t, err)
+		result, err := processor.ProcessFrame(context.Background(), Vars{}, frame)
+		require.NoError(t, err)
+		require.Len(t, result.Fields, 3)
+		require.Equal(t, 0.0, *result.Fields[1].At(0).(*float64))
+		require.Equal(t, 1.0, *result.Fields[1].At(1).(*float64))
+		require.Equal(t, 0.0, *result.Fields[1].At(2).(*float64))
+	})
+
+	t.Run("fails on unknown field", func(t *testing.T) {
+		processor, err := NewDeriveFieldFrameProcessor(DeriveFieldFrameProcessorConfig{FieldName: "x", Expression: "$unknown * 2"})
+		require.NoError(t, err)
+		_, err = processor.ProcessFrame(context.Background(), Vars{}, frame)
+		require.Error(t, err)
+	})
+
+	t.Run("fails on invalid expression", func(t *testing.T) {
+		_, err := NewDeriveFieldFrameProcessor(DeriveFieldFrameProcessorConfig{FieldName: "x", Expression: "$temp +"})
+		require.Error(t, err)
+		_, err = NewDeriveFieldFrameProcessor(DeriveFieldFrameProcessorConfig{FieldName: "x", Expression: "unknown($temp)"})
+		require.Error(t, err)
+		_, err = NewDeriveFieldFrameProcessor(DeriveFieldFrameProcessorConfig{FieldName: "x", Expression: `abs("test")`})
+		require.Error(t, err)
+	})
+}
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
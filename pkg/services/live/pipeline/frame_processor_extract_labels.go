@@ -0,0 +1,80 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// ExtractLabelsFrameProcessor turns values of specified fields into labels
+// of other fields of a data.Frame. Extracted fields are removed from the frame.
+// Time fields do not get labels.
+type ExtractLabelsFrameProcessor struct {
+	config ExtractLabelsFrameProcessorConfig
+}
+
+func NewExtractLabelsFrameProcessor(config ExtractLabelsFrameProcessorConfig) *ExtractLabelsFrameProcessor {
+	return &ExtractLabelsFrameProcessor{config: config}
+}
+
+const FrameProcessorTypeExtractLabels = "extractLabels"
+
+func (p *ExtractLabelsFrameProcessor) Type() string {
+	return FrameProcessorTypeExtractLabels
+}
+
+func (p *ExtractLabelsFrameProcessor) ProcessFrame(_ context.Context, _ Vars, frame *data.Frame) (*data.Frame, error) {
+	labels := data.Labels{}
+	var fields []*data.Field
+	for _, field := range frame.Fields {
+		if !stringInSlice(field.Name, p.config.FieldNames) {
+			fields = append(fields, field)
+			continue
+		}
+		value, ok, err := labelValue(field)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			labels[field.Name] = value
+		}
+	}
+	// extracted fields are removed even if they have no value, so the frame
+	// schema does not depend on the data.
+	frame.Fields = fields
+	if len(labels) == 0 {
+		return frame, nil
+	}
+	for _, field := range fields {
+		if field.Type().Time() {
+			continue
+		}
+		if field.Labels == nil {
+			field.Labels = data.Labels{}
+		}
+		for k, v := range labels {
+			field.Labels[k] = v
+		}
+	}
+	return frame, nil
+}
+
+// labelValue returns the value of the field as a string. All field rows must
+// have the same value since a label is applied to the whole field.
+func labelValue(field *data.Field) (string, bool, error) {
+	var value string
+	var found bool
+	for i := 0; i < field.Len(); i++ {
+		v, ok := field.ConcreteAt(i)
+		if !ok {
+			continue
+		}
+		s := fmt.Sprint(v)
+		if found && s != value {
+			return "", false, fmt.Errorf("can not extract label from field %s with different values", field.Name)
+		}
+		value, found = s, true
+	}
+	return value, found, nil
+}
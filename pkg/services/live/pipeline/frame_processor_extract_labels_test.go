@@ -0,0 +1,55 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractLabelsFrameProcessor(t *testing.T) {
+	processor := NewExtractLabelsFrameProcessor(ExtractLabelsFrameProcessorConfig{
+		FieldNames: []string{"device", "room"},
+	})
+
+	frame := data.NewFrame("test",
+		data.NewField("time", nil, []time.Time{time.Now(), time.Now()}),
+		data.NewField("device", nil, []string{"sensor-1", "sensor-1"}),
+		data.NewField("room", nil, []*int64{nil, int64Ptr(12)}),
+		data.NewField("value", data.Labels{"unit": "C"}, []float64{1, 2}),
+	)
+
+	result, err := processor.ProcessFrame(context.Background(), Vars{}, frame)
+	require.NoError(t, err)
+	require.Len(t, result.Fields, 2)
+	require.Nil(t, result.Fields[0].Labels)
+	require.Equal(t, "value", result.Fields[1].Name)
+	require.Equal(t, data.Labels{"device": "sensor-1", "room": "12", "unit": "C"}, result.Fields[1].Labels)
+
+	t.Run("fails if field has different values", func(t *testing.T) {
+		frame := data.NewFrame("test",
+			data.NewField("device", nil, []string{"sensor-1", "sensor-2"}),
+			data.NewField("value", nil, []float64{1, 2}),
+		)
+		_, err := processor.ProcessFrame(context.Background(), Vars{}, frame)
+		require.Error(t, err)
+	})
+
+	t.Run("removes fields without values", func(t *testing.T) {
+		frame := data.NewFrame("test",
+			data.NewField("room", nil, []*int64{nil, nil}),
+			data.NewField("value", nil, []float64{1, 2}),
+		)
+		result, err := processor.ProcessFrame(context.Background(), Vars{}, frame)
+		require.NoError(t, err)
+		require.Len(t, result.Fields, 1)
+		require.Equal(t, "value", result.Fields[0].Name)
+		require.Nil(t, result.Fields[0].Labels)
+	})
+}
+
+func int64Ptr(i int64) *int64 {
+	return &i
+}
@@ -0,0 +1,75 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// RateLimitFrameProcessor passes at most one frame per channel during
+// SampleMilliseconds and drops the rest. This allows down-sampling streams
+// from devices which push data more often than it is needed.
+type RateLimitFrameProcessor struct {
+	config RateLimitFrameProcessorConfig
+	now    func() time.Time
+
+	mu        sync.Mutex
+	lastSent  map[int64]map[string]time.Time
+	lastPrune time.Time
+}
+
+func NewRateLimitFrameProcessor(config RateLimitFrameProcessorConfig) *RateLimitFrameProcessor {
+	return &RateLimitFrameProcessor{
+		config:   config,
+		now:      time.Now,
+		lastSent: map[int64]map[string]time.Time{},
+	}
+}
+
+const FrameProcessorTypeRateLimit = "rateLimit"
+
+func (p *RateLimitFrameProcessor) Type() string {
+	return FrameProcessorTypeRateLimit
+}
+
+func (p *RateLimitFrameProcessor) ProcessFrame(_ context.Context, vars Vars, frame *data.Frame) (*data.Frame, error) {
+	interval := time.Duration(p.config.SampleMilliseconds) * time.Millisecond
+	if interval <= 0 {
+		return frame, nil
+	}
+	now := p.now()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if now.Sub(p.lastPrune) >= interval {
+		p.prune(now, interval)
+	}
+	channels, ok := p.lastSent[vars.OrgID]
+	if !ok {
+		channels = map[string]time.Time{}
+		p.lastSent[vars.OrgID] = channels
+	}
+	if last, ok := channels[vars.Channel]; ok && now.Sub(last) < interval {
+		return nil, nil
+	}
+	channels[vars.Channel] = now
+	return frame, nil
+}
+
+// prune removes the channels which did not send a frame during the interval,
+// they would pass the next frame anyway.
+func (p *RateLimitFrameProcessor) prune(now time.Time, interval time.Duration) {
+	for orgID, channels := range p.lastSent {
+		for channel, last := range channels {
+			if now.Sub(last) >= interval {
+				delete(channels, channel)
+			}
+		}
+		if len(channels) == 0 {
+			delete(p.lastSent, orgID)
+		}
+	}
+	p.lastPrune = now
+}
@@ -0,0 +1,48 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitFrameProcessor(t *testing.T) {
+	now := time.Unix(0, 0)
+	processor := NewRateLimitFrameProcessor(RateLimitFrameProcessorConfig{SampleMilliseconds: 1000})
+	processor.now = func() time.Time { return now }
+
+	frame := data.NewFrame("test", data.NewField("value", nil, []float64{1}))
+	process := func(orgID int64, channel string) *data.Frame {
+		result, err := processor.ProcessFrame(context.Background(), Vars{OrgID: orgID, Channel: channel}, frame)
+		require.NoError(t, err)
+		return result
+	}
+
+	require.NotNil(t, process(1, "stream/test/1"))
+	require.Nil(t, process(1, "stream/test/1"))
+	require.NotNil(t, process(1, "stream/test/2"))
+	require.NotNil(t, process(2, "stream/test/1"))
+
+	now = now.Add(999 * time.Millisecond)
+	require.Nil(t, process(1, "stream/test/1"))
+
+	now = now.Add(time.Millisecond)
+	require.NotNil(t, process(1, "stream/test/1"))
+	require.Nil(t, process(1, "stream/test/1"))
+
+	t.Run("expires channels after the interval", func(t *testing.T) {
+		now = now.Add(500 * time.Millisecond)
+		require.NotNil(t, process(3, "stream/test/3"))
+
+		now = now.Add(500 * time.Millisecond)
+		require.NotNil(t, process(1, "stream/test/2"))
+		require.Equal(t, map[int64]map[string]time.Time{
+			1: {"stream/test/2": now},
+			3: {"stream/test/3": now.Add(-500 * time.Millisecond)},
+		}, processor.lastSent)
+		require.Nil(t, process(3, "stream/test/3"))
+	})
+}
@@ -57,6 +57,7 @@ func WriteConfigToDto(b WriteConfig) WriteConfigDto {
 	}
 	return WriteConfigDto{
 		UID:          b.UID,
+		Version:      b.Version,
 		Settings:     b.Settings,
 		SecureFields: secureFields,
 	}
@@ -64,6 +65,7 @@ func WriteConfigToDto(b WriteConfig) WriteConfigDto {
 
 type WriteConfigDto struct {
 	UID          string          `json:"uid"`
+	Version      int64           `json:"version,omitempty"`
 	Settings     WriteSettings   `json:"settings"`
 	SecureFields map[string]bool `json:"secureFields"`
 }
@@ -78,9 +80,11 @@ type WriteConfigCreateCmd struct {
 	SecureSettings map[string]string `json:"secureSettings"`
 }
 
-// TODO: add version field later.
 type WriteConfigUpdateCmd struct {
-	UID            string            `json:"uid"`
+	UID string `json:"uid"`
+	// Version is the version of the write config the update is based on. If set, the update
+	// is rejected when the stored write config has been changed since.
+	Version        int64             `json:"version,omitempty"`
 	Settings       WriteSettings     `json:"settings"`
 	SecureSettings map[string]string `json:"secureSettings"`
 }
@@ -92,6 +96,7 @@ type WriteConfigDeleteCmd struct {
 type WriteConfig struct {
 	OrgId          int64             `json:"-"`
 	UID            string            `json:"uid"`
+	Version        int64             `json:"version,omitempty"`
 	Settings       WriteSettings     `json:"settings"`
 	SecureSettings map[string][]byte `json:"secureSettings,omitempty"`
 }
@@ -152,7 +157,10 @@ type ChannelRuleCreateCmd struct {
 }
 
 type ChannelRuleUpdateCmd struct {
-	Pattern  string              `json:"pattern"`
+	Pattern string `json:"pattern"`
+	// Version is the version of the channel rule the update is based on. If set, the update
+	// is rejected when the stored channel rule has been changed since.
+	Version  int64               `json:"version,omitempty"`
 	Settings ChannelRuleSettings `json:"settings"`
 }
 
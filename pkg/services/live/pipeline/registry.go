@@ -1,5 +1,7 @@
 package pipeline
 
+import "github.com/grafana/grafana-plugin-sdk-go/data"
+
 type EntityInfo struct {
 	Type        string `json:"type"`
 	Description string `json:"description"`
@@ -79,6 +81,42 @@ var FrameProcessorsRegistry = []EntityInfo{
 		Description: "list the fields that should be removed",
 		Example:     DropFieldsFrameProcessorConfig{},
 	},
+	{
+		Type:        FrameProcessorTypeRenameFields,
+		Description: "rename fields",
+		Example: RenameFieldsFrameProcessorConfig{
+			Renames: map[string]string{"temp": "temperature"},
+		},
+	},
+	{
+		Type:        FrameProcessorTypeCastFields,
+		Description: "cast field values to another type",
+		Example: CastFieldsFrameProcessorConfig{
+			Types: map[string]data.FieldType{"temperature": data.FieldTypeNullableFloat64},
+		},
+	},
+	{
+		Type:        FrameProcessorTypeDeriveField,
+		Description: "add a field calculated from other fields with a math expression",
+		Example: DeriveFieldFrameProcessorConfig{
+			FieldName:  "temperatureCelsius",
+			Expression: "($temperature - 32) / 1.8",
+		},
+	},
+	{
+		Type:        FrameProcessorTypeExtractLabels,
+		Description: "use field values as labels of other fields",
+		Example: ExtractLabelsFrameProcessorConfig{
+			FieldNames: []string{"device"},
+		},
+	},
+	{
+		Type:        FrameProcessorTypeRateLimit,
+		Description: "pass at most one frame per channel in an interval",
+		Example: RateLimitFrameProcessorConfig{
+			SampleMilliseconds: 1000,
+		},
+	},
 }
 
 var DataOutputsRegistry = []EntityInfo{
@@ -90,6 +90,31 @@ func (f *StorageRuleBuilder) extractFrameProcessor(config *FrameProcessorConfig)
 			return nil, missingConfiguration
 		}
 		return NewKeepFieldsFrameProcessor(*config.KeepFieldsProcessorConfig), nil
+	case FrameProcessorTypeRenameFields:
+		if config.RenameFieldsProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		return NewRenameFieldsFrameProcessor(*config.RenameFieldsProcessorConfig), nil
+	case FrameProcessorTypeCastFields:
+		if config.CastFieldsProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		return NewCastFieldsFrameProcessor(*config.CastFieldsProcessorConfig), nil
+	case FrameProcessorTypeDeriveField:
+		if config.DeriveFieldProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		return NewDeriveFieldFrameProcessor(*config.DeriveFieldProcessorConfig)
+	case FrameProcessorTypeExtractLabels:
+		if config.ExtractLabelsProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		return NewExtractLabelsFrameProcessor(*config.ExtractLabelsProcessorConfig), nil
+	case FrameProcessorTypeRateLimit:
+		if config.RateLimitProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		return NewRateLimitFrameProcessor(*config.RateLimitProcessorConfig), nil
 	case FrameProcessorTypeMultiple:
 		if config.MultipleProcessorConfig == nil {
 			return nil, missingConfiguration
@@ -59,6 +59,13 @@ func (s *CacheSegmentedTree) fillOrg(orgID int64) error {
 	return nil
 }
 
+// Invalidate drops the cached channel rules of an organization, so they are built again on next access.
+func (s *CacheSegmentedTree) Invalidate(orgID int64) {
+	s.radixMu.Lock()
+	defer s.radixMu.Unlock()
+	delete(s.radix, orgID)
+}
+
 func (s *CacheSegmentedTree) Get(orgID int64, channel string) (*LiveChannelRule, bool, error) {
 	s.radixMu.RLock()
 	_, ok := s.radix[orgID]
@@ -53,6 +53,39 @@ func TestStorage_Get(t *testing.T) {
 	require.Equal(t, "stream/boom:er", rule.Pattern)
 }
 
+type countingBuilder struct {
+	builds  int
+	pattern string
+}
+
+func (b *countingBuilder) BuildRules(_ context.Context, orgID int64) ([]*LiveChannelRule, error) {
+	b.builds++
+	return []*LiveChannelRule{{OrgId: orgID, Pattern: b.pattern}}, nil
+}
+
+func TestStorage_Invalidate(t *testing.T) {
+	builder := &countingBuilder{pattern: "stream/telegraf/cpu"}
+	s := NewCacheSegmentedTree(builder)
+	_, ok, err := s.Get(1, "stream/telegraf/cpu")
+	require.NoError(t, err)
+	require.True(t, ok)
+	_, _, err = s.Get(1, "stream/telegraf/cpu")
+	require.NoError(t, err)
+	require.Equal(t, 1, builder.builds)
+
+	builder.pattern = "stream/telegraf/mem"
+	s.Invalidate(1)
+
+	_, ok, err = s.Get(1, "stream/telegraf/cpu")
+	require.NoError(t, err)
+	require.False(t, ok)
+	rule, ok, err := s.Get(1, "stream/telegraf/mem")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "stream/telegraf/mem", rule.Pattern)
+	require.Equal(t, 2, builder.builds)
+}
+
 func BenchmarkRuleGet(b *testing.B) {
 	s := NewCacheSegmentedTree(&testBuilder{})
 	for i := 0; i < b.N; i++ {
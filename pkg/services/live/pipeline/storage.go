@@ -1,6 +1,20 @@
 package pipeline
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrChannelRuleNotFound = errors.New("channel rule not found")
+	ErrChannelRuleExists   = errors.New("channel rule already exists")
+	ErrInvalidChannelRule  = errors.New("invalid channel rule")
+	ErrWriteConfigNotFound = errors.New("write config not found")
+	ErrWriteConfigExists   = errors.New("write config already exists")
+	ErrInvalidWriteConfig  = errors.New("invalid write config")
+	// ErrVersionConflict is returned when an update is based on an outdated version of a channel rule or a write config.
+	ErrVersionConflict = errors.New("version conflict")
+)
 
 // Storage describes all methods to manage Live pipeline persistent data.
 type Storage interface {
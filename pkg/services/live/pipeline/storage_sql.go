@@ -0,0 +1,401 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// SQLStorage stores channel rules and write configs in the database. Every change of
+// a channel rule or a write config increments its version.
+type SQLStorage struct {
+	SQLStore       db.DB
+	SecretsService secrets.Service
+}
+
+type channelRuleRow struct {
+	ID       int64  `xorm:"pk autoincr 'id'"`
+	OrgID    int64  `xorm:"org_id"`
+	Pattern  string `xorm:"pattern"`
+	Settings string `xorm:"settings"`
+	Version  int64  `xorm:"'version'"`
+	Created  time.Time
+	Updated  time.Time
+}
+
+func (channelRuleRow) TableName() string {
+	return "live_channel_rule"
+}
+
+type writeConfigRow struct {
+	ID             int64  `xorm:"pk autoincr 'id'"`
+	OrgID          int64  `xorm:"org_id"`
+	UID            string `xorm:"uid"`
+	Settings       string `xorm:"settings"`
+	SecureSettings string `xorm:"secure_settings"`
+	Version        int64  `xorm:"'version'"`
+	Created        time.Time
+	Updated        time.Time
+}
+
+func (writeConfigRow) TableName() string {
+	return "live_write_config"
+}
+
+func (s *SQLStorage) ListWriteConfigs(ctx context.Context, orgID int64) ([]WriteConfig, error) {
+	var rows []writeConfigRow
+	err := s.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ?", orgID).Asc("uid").Find(&rows)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't read write configs: %w", err)
+	}
+	result := make([]WriteConfig, 0, len(rows))
+	for _, row := range rows {
+		writeConfig, err := row.toWriteConfig()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, writeConfig)
+	}
+	return result, nil
+}
+
+func (s *SQLStorage) GetWriteConfig(ctx context.Context, orgID int64, cmd WriteConfigGetCmd) (WriteConfig, bool, error) {
+	var row writeConfigRow
+	var ok bool
+	err := s.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		ok, err = sess.Where("org_id = ? AND uid = ?", orgID, cmd.UID).Get(&row)
+		return err
+	})
+	if err != nil {
+		return WriteConfig{}, false, fmt.Errorf("can't read write config: %w", err)
+	}
+	if !ok {
+		return WriteConfig{}, false, nil
+	}
+	writeConfig, err := row.toWriteConfig()
+	if err != nil {
+		return WriteConfig{}, false, err
+	}
+	return writeConfig, true, nil
+}
+
+func (s *SQLStorage) CreateWriteConfig(ctx context.Context, orgID int64, cmd WriteConfigCreateCmd) (WriteConfig, error) {
+	if cmd.UID == "" {
+		cmd.UID = util.GenerateShortUID()
+	}
+	writeConfig, err := s.newWriteConfig(ctx, orgID, cmd.UID, cmd.Settings, cmd.SecureSettings)
+	if err != nil {
+		return WriteConfig{}, err
+	}
+	writeConfig.Version = 1
+	row, err := writeConfigToRow(writeConfig)
+	if err != nil {
+		return WriteConfig{}, err
+	}
+	row.Created = time.Now()
+	row.Updated = row.Created
+
+	err = s.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Where("org_id = ? AND uid = ?", orgID, cmd.UID).Exist(&writeConfigRow{})
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("%w in org: %s", ErrWriteConfigExists, cmd.UID)
+		}
+		_, err = sess.Insert(&row)
+		return err
+	})
+	if err != nil {
+		return WriteConfig{}, err
+	}
+	return writeConfig, nil
+}
+
+// UpdateWriteConfig replaces the settings of a write config. If the write config does not exist, it is created.
+func (s *SQLStorage) UpdateWriteConfig(ctx context.Context, orgID int64, cmd WriteConfigUpdateCmd) (WriteConfig, error) {
+	writeConfig, err := s.newWriteConfig(ctx, orgID, cmd.UID, cmd.Settings, cmd.SecureSettings)
+	if err != nil {
+		return WriteConfig{}, err
+	}
+
+	created := false
+	err = s.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		var existing writeConfigRow
+		ok, err := sess.Where("org_id = ? AND uid = ?", orgID, cmd.UID).Get(&existing)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			if cmd.Version != 0 {
+				return ErrWriteConfigNotFound
+			}
+			created = true
+			return nil
+		}
+		if cmd.Version != 0 && cmd.Version != existing.Version {
+			return fmt.Errorf("%w: write config %s has version %d", ErrVersionConflict, cmd.UID, existing.Version)
+		}
+
+		writeConfig.Version = existing.Version + 1
+		row, err := writeConfigToRow(writeConfig)
+		if err != nil {
+			return err
+		}
+		row.Updated = time.Now()
+		affected, err := sess.ID(existing.ID).Where("version = ?", existing.Version).
+			Cols("settings", "secure_settings", "version", "updated").Update(&row)
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return fmt.Errorf("%w: write config %s was changed concurrently", ErrVersionConflict, cmd.UID)
+		}
+		return nil
+	})
+	if err != nil {
+		return WriteConfig{}, err
+	}
+	if created {
+		return s.CreateWriteConfig(ctx, orgID, WriteConfigCreateCmd{
+			UID:            cmd.UID,
+			Settings:       cmd.Settings,
+			SecureSettings: cmd.SecureSettings,
+		})
+	}
+	return writeConfig, nil
+}
+
+func (s *SQLStorage) DeleteWriteConfig(ctx context.Context, orgID int64, cmd WriteConfigDeleteCmd) error {
+	return s.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		affected, err := sess.Where("org_id = ? AND uid = ?", orgID, cmd.UID).Delete(&writeConfigRow{})
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return ErrWriteConfigNotFound
+		}
+		return nil
+	})
+}
+
+func (s *SQLStorage) ListChannelRules(ctx context.Context, orgID int64) ([]ChannelRule, error) {
+	var rows []channelRuleRow
+	err := s.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ?", orgID).Asc("pattern").Find(&rows)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't read channel rules: %w", err)
+	}
+	return rowsToChannelRules(rows)
+}
+
+func (s *SQLStorage) CreateChannelRule(ctx context.Context, orgID int64, cmd ChannelRuleCreateCmd) (ChannelRule, error) {
+	rule := ChannelRule{
+		OrgId:    orgID,
+		Pattern:  cmd.Pattern,
+		Version:  1,
+		Settings: cmd.Settings,
+	}
+	ok, reason := rule.Valid()
+	if !ok {
+		return ChannelRule{}, fmt.Errorf("%w: %s", ErrInvalidChannelRule, reason)
+	}
+	row, err := channelRuleToRow(rule)
+	if err != nil {
+		return ChannelRule{}, err
+	}
+	row.Created = time.Now()
+	row.Updated = row.Created
+
+	err = s.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		var rows []channelRuleRow
+		if err := sess.Where("org_id = ?", orgID).Find(&rows); err != nil {
+			return err
+		}
+		rules, err := rowsToChannelRules(rows)
+		if err != nil {
+			return err
+		}
+		for _, existingRule := range rules {
+			if existingRule.Pattern == rule.Pattern {
+				return fmt.Errorf("%w in org: %s", ErrChannelRuleExists, rule.Pattern)
+			}
+		}
+		ok, reason := checkRulesValid(orgID, append(rules, rule))
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrInvalidChannelRule, reason)
+		}
+		_, err = sess.Insert(&row)
+		return err
+	})
+	if err != nil {
+		return ChannelRule{}, err
+	}
+	return rule, nil
+}
+
+// UpdateChannelRule replaces the settings of a channel rule. If the channel rule does not exist, it is created.
+func (s *SQLStorage) UpdateChannelRule(ctx context.Context, orgID int64, cmd ChannelRuleUpdateCmd) (ChannelRule, error) {
+	rule := ChannelRule{
+		OrgId:    orgID,
+		Pattern:  cmd.Pattern,
+		Settings: cmd.Settings,
+	}
+	ok, reason := rule.Valid()
+	if !ok {
+		return ChannelRule{}, fmt.Errorf("%w: %s", ErrInvalidChannelRule, reason)
+	}
+
+	created := false
+	err := s.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		var existing channelRuleRow
+		ok, err := sess.Where("org_id = ? AND pattern = ?", orgID, cmd.Pattern).Get(&existing)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			if cmd.Version != 0 {
+				return ErrChannelRuleNotFound
+			}
+			created = true
+			return nil
+		}
+		if cmd.Version != 0 && cmd.Version != existing.Version {
+			return fmt.Errorf("%w: channel rule %s has version %d", ErrVersionConflict, cmd.Pattern, existing.Version)
+		}
+
+		rule.Version = existing.Version + 1
+		row, err := channelRuleToRow(rule)
+		if err != nil {
+			return err
+		}
+		row.Updated = time.Now()
+		affected, err := sess.ID(existing.ID).Where("version = ?", existing.Version).
+			Cols("settings", "version", "updated").Update(&row)
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return fmt.Errorf("%w: channel rule %s was changed concurrently", ErrVersionConflict, cmd.Pattern)
+		}
+		return nil
+	})
+	if err != nil {
+		return ChannelRule{}, err
+	}
+	if created {
+		return s.CreateChannelRule(ctx, orgID, ChannelRuleCreateCmd{
+			Pattern:  cmd.Pattern,
+			Settings: cmd.Settings,
+		})
+	}
+	return rule, nil
+}
+
+func (s *SQLStorage) DeleteChannelRule(ctx context.Context, orgID int64, cmd ChannelRuleDeleteCmd) error {
+	return s.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		affected, err := sess.Where("org_id = ? AND pattern = ?", orgID, cmd.Pattern).Delete(&channelRuleRow{})
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return ErrChannelRuleNotFound
+		}
+		return nil
+	})
+}
+
+// newWriteConfig validates a write config and encrypts its secure settings.
+func (s *SQLStorage) newWriteConfig(ctx context.Context, orgID int64, uid string, settings WriteSettings, secureSettings map[string]string) (WriteConfig, error) {
+	writeConfig := WriteConfig{
+		OrgId:    orgID,
+		UID:      uid,
+		Settings: settings,
+	}
+	ok, reason := writeConfig.Valid()
+	if !ok {
+		return WriteConfig{}, fmt.Errorf("%w: %s", ErrInvalidWriteConfig, reason)
+	}
+	encrypted, err := s.SecretsService.EncryptJsonData(ctx, secureSettings, secrets.WithoutScope())
+	if err != nil {
+		return WriteConfig{}, fmt.Errorf("error encrypting data: %w", err)
+	}
+	writeConfig.SecureSettings = encrypted
+	return writeConfig, nil
+}
+
+func writeConfigToRow(writeConfig WriteConfig) (writeConfigRow, error) {
+	settings, err := json.Marshal(writeConfig.Settings)
+	if err != nil {
+		return writeConfigRow{}, fmt.Errorf("can't marshal write config settings: %w", err)
+	}
+	row := writeConfigRow{
+		OrgID:    writeConfig.OrgId,
+		UID:      writeConfig.UID,
+		Settings: string(settings),
+		Version:  writeConfig.Version,
+	}
+	if len(writeConfig.SecureSettings) > 0 {
+		secureSettings, err := json.Marshal(writeConfig.SecureSettings)
+		if err != nil {
+			return writeConfigRow{}, fmt.Errorf("can't marshal write config secure settings: %w", err)
+		}
+		row.SecureSettings = string(secureSettings)
+	}
+	return row, nil
+}
+
+func (r writeConfigRow) toWriteConfig() (WriteConfig, error) {
+	writeConfig := WriteConfig{
+		OrgId:   r.OrgID,
+		UID:     r.UID,
+		Version: r.Version,
+	}
+	if err := json.Unmarshal([]byte(r.Settings), &writeConfig.Settings); err != nil {
+		return WriteConfig{}, fmt.Errorf("can't unmarshal settings of write config %s: %w", r.UID, err)
+	}
+	if r.SecureSettings != "" {
+		if err := json.Unmarshal([]byte(r.SecureSettings), &writeConfig.SecureSettings); err != nil {
+			return WriteConfig{}, fmt.Errorf("can't unmarshal secure settings of write config %s: %w", r.UID, err)
+		}
+	}
+	return writeConfig, nil
+}
+
+func channelRuleToRow(rule ChannelRule) (channelRuleRow, error) {
+	settings, err := json.Marshal(rule.Settings)
+	if err != nil {
+		return channelRuleRow{}, fmt.Errorf("can't marshal channel rule settings: %w", err)
+	}
+	return channelRuleRow{
+		OrgID:    rule.OrgId,
+		Pattern:  rule.Pattern,
+		Settings: string(settings),
+		Version:  rule.Version,
+	}, nil
+}
+
+func rowsToChannelRules(rows []channelRuleRow) ([]ChannelRule, error) {
+	rules := make([]ChannelRule, 0, len(rows))
+	for _, row := range rows {
+		rule := ChannelRule{
+			OrgId:   row.OrgID,
+			Pattern: row.Pattern,
+			Version: row.Version,
+		}
+		if err := json.Unmarshal([]byte(row.Settings), &rule.Settings); err != nil {
+			return nil, fmt.Errorf("can't unmarshal settings of channel rule %s: %w", row.Pattern, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
@@ -0,0 +1,190 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+func setupSQLStorage(t *testing.T) *SQLStorage {
+	t.Helper()
+	return &SQLStorage{
+		SQLStore:       db.InitTestDB(t),
+		SecretsService: fakes.NewFakeSecretsService(),
+	}
+}
+
+func TestIntegrationSQLStorage_ChannelRules(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	storage := setupSQLStorage(t)
+
+	settings := ChannelRuleSettings{
+		Converter: &ConverterConfig{Type: ConverterTypeJsonAuto},
+	}
+	rule, err := storage.CreateChannelRule(ctx, 1, ChannelRuleCreateCmd{Pattern: "stream/test/:metric", Settings: settings})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), rule.Version)
+
+	t.Run("should not create rule with existing pattern", func(t *testing.T) {
+		_, err := storage.CreateChannelRule(ctx, 1, ChannelRuleCreateCmd{Pattern: "stream/test/:metric", Settings: settings})
+		require.ErrorIs(t, err, ErrChannelRuleExists)
+	})
+
+	t.Run("should not create rule with conflicting pattern", func(t *testing.T) {
+		_, err := storage.CreateChannelRule(ctx, 1, ChannelRuleCreateCmd{Pattern: "stream/test/:other", Settings: settings})
+		require.ErrorIs(t, err, ErrInvalidChannelRule)
+	})
+
+	t.Run("should not create invalid rule", func(t *testing.T) {
+		_, err := storage.CreateChannelRule(ctx, 1, ChannelRuleCreateCmd{
+			Pattern:  "stream/test/other",
+			Settings: ChannelRuleSettings{Converter: &ConverterConfig{Type: "unknown"}},
+		})
+		require.ErrorIs(t, err, ErrInvalidChannelRule)
+	})
+
+	t.Run("should list rules of org only", func(t *testing.T) {
+		_, err := storage.CreateChannelRule(ctx, 2, ChannelRuleCreateCmd{Pattern: "stream/test/:metric", Settings: settings})
+		require.NoError(t, err)
+
+		rules, err := storage.ListChannelRules(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, rules, 1)
+		require.Equal(t, "stream/test/:metric", rules[0].Pattern)
+		require.Equal(t, int64(1), rules[0].OrgId)
+		require.Equal(t, settings, rules[0].Settings)
+	})
+
+	t.Run("should increment version on update", func(t *testing.T) {
+		updated, err := storage.UpdateChannelRule(ctx, 1, ChannelRuleUpdateCmd{
+			Pattern:  "stream/test/:metric",
+			Version:  1,
+			Settings: ChannelRuleSettings{Converter: &ConverterConfig{Type: ConverterTypeJsonFrame}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(2), updated.Version)
+
+		rules, err := storage.ListChannelRules(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, rules, 1)
+		require.Equal(t, int64(2), rules[0].Version)
+		require.Equal(t, ConverterTypeJsonFrame, rules[0].Settings.Converter.Type)
+	})
+
+	t.Run("should reject update based on outdated version", func(t *testing.T) {
+		_, err := storage.UpdateChannelRule(ctx, 1, ChannelRuleUpdateCmd{Pattern: "stream/test/:metric", Version: 1, Settings: settings})
+		require.ErrorIs(t, err, ErrVersionConflict)
+	})
+
+	t.Run("should create rule on update if it does not exist", func(t *testing.T) {
+		created, err := storage.UpdateChannelRule(ctx, 1, ChannelRuleUpdateCmd{Pattern: "stream/other", Settings: settings})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), created.Version)
+	})
+
+	t.Run("should delete rule", func(t *testing.T) {
+		require.NoError(t, storage.DeleteChannelRule(ctx, 1, ChannelRuleDeleteCmd{Pattern: "stream/other"}))
+		require.ErrorIs(t, storage.DeleteChannelRule(ctx, 1, ChannelRuleDeleteCmd{Pattern: "stream/other"}), ErrChannelRuleNotFound)
+
+		rules, err := storage.ListChannelRules(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, rules, 1)
+	})
+}
+
+func TestIntegrationSQLStorage_WriteConfigs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	storage := setupSQLStorage(t)
+
+	writeConfig, err := storage.CreateWriteConfig(ctx, 1, WriteConfigCreateCmd{
+		Settings: WriteSettings{
+			Endpoint:  "http://localhost:9090/api/v1/write",
+			BasicAuth: &BasicAuth{User: "user"},
+		},
+		SecureSettings: map[string]string{"basicAuthPassword": "secret"},
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, writeConfig.UID)
+	require.Equal(t, int64(1), writeConfig.Version)
+
+	t.Run("should store encrypted secure settings", func(t *testing.T) {
+		stored, ok, err := storage.GetWriteConfig(ctx, 1, WriteConfigGetCmd{UID: writeConfig.UID})
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, writeConfig.Settings, stored.Settings)
+		require.Contains(t, stored.SecureSettings, "basicAuthPassword")
+
+		decrypted, err := storage.SecretsService.DecryptJsonData(ctx, stored.SecureSettings)
+		require.NoError(t, err)
+		require.Equal(t, "secret", decrypted["basicAuthPassword"])
+	})
+
+	t.Run("should not find write config of other org", func(t *testing.T) {
+		_, ok, err := storage.GetWriteConfig(ctx, 2, WriteConfigGetCmd{UID: writeConfig.UID})
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		configs, err := storage.ListWriteConfigs(ctx, 2)
+		require.NoError(t, err)
+		require.Empty(t, configs)
+	})
+
+	t.Run("should not create write config with existing uid", func(t *testing.T) {
+		_, err := storage.CreateWriteConfig(ctx, 1, WriteConfigCreateCmd{
+			UID:      writeConfig.UID,
+			Settings: WriteSettings{Endpoint: "http://localhost:9090/api/v1/write"},
+		})
+		require.ErrorIs(t, err, ErrWriteConfigExists)
+	})
+
+	t.Run("should not create invalid write config", func(t *testing.T) {
+		_, err := storage.CreateWriteConfig(ctx, 1, WriteConfigCreateCmd{UID: "invalid"})
+		require.ErrorIs(t, err, ErrInvalidWriteConfig)
+	})
+
+	t.Run("should increment version on update", func(t *testing.T) {
+		updated, err := storage.UpdateWriteConfig(ctx, 1, WriteConfigUpdateCmd{
+			UID:      writeConfig.UID,
+			Version:  1,
+			Settings: WriteSettings{Endpoint: "http://localhost:9091/api/v1/write"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(2), updated.Version)
+		require.Empty(t, updated.SecureSettings)
+
+		configs, err := storage.ListWriteConfigs(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, configs, 1)
+		require.Equal(t, "http://localhost:9091/api/v1/write", configs[0].Settings.Endpoint)
+		require.Equal(t, int64(2), configs[0].Version)
+	})
+
+	t.Run("should reject update based on outdated version", func(t *testing.T) {
+		_, err := storage.UpdateWriteConfig(ctx, 1, WriteConfigUpdateCmd{
+			UID:      writeConfig.UID,
+			Version:  1,
+			Settings: WriteSettings{Endpoint: "http://localhost:9092/api/v1/write"},
+		})
+		require.ErrorIs(t, err, ErrVersionConflict)
+	})
+
+	t.Run("should delete write config", func(t *testing.T) {
+		require.NoError(t, storage.DeleteWriteConfig(ctx, 1, WriteConfigDeleteCmd{UID: writeConfig.UID}))
+		require.ErrorIs(t, storage.DeleteWriteConfig(ctx, 1, WriteConfigDeleteCmd{UID: writeConfig.UID}), ErrWriteConfigNotFound)
+	})
+}
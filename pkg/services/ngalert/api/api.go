@@ -78,6 +78,9 @@ type API struct {
 	Tracer               tracing.Tracer
 	AppUrl               *url.URL
 
+	// RuleShardingEnabled is true when the rules are sharded across the instances of the high availability cluster.
+	RuleShardingEnabled bool
+
 	// Hooks can be used to replace API handlers for specific paths.
 	Hooks *Hooks
 }
@@ -114,7 +117,7 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 	api.RegisterPrometheusApiEndpoints(NewForkingProm(
 		api.DatasourceCache,
 		NewLotexProm(proxy, logger),
-		&PrometheusSrv{log: logger, manager: api.StateManager, store: api.RuleStore, authz: ruleAuthzService},
+		&PrometheusSrv{log: logger, manager: api.StateManager, store: api.RuleStore, authz: ruleAuthzService, sharded: api.RuleShardingEnabled},
 	), m)
 	// Register endpoints for proxying to Cortex Ruler-compatible backends.
 	api.RegisterRulerApiEndpoints(NewForkingRuler(
@@ -29,6 +29,9 @@ type PrometheusSrv struct {
 	manager state.AlertInstanceManager
 	store   RuleStore
 	authz   RuleAccessControlService
+	// sharded is true when the rules are sharded across the instances of the cluster,
+	// then the manager only has the state of the rules evaluated by this instance.
+	sharded bool
 }
 
 const queryIncludeInternalLabels = "includeInternalLabels"
@@ -61,8 +64,9 @@ func (srv PrometheusSrv) RouteGetAlertStatuses(c *contextmodel.ReqContext) respo
 	c.Query("")
 
 	resp := PrepareAlertStatuses(srv.manager, AlertStatusesOptions{
-		OrgID: c.SignedInUser.GetOrgID(),
-		Query: c.Req.Form,
+		OrgID:   c.SignedInUser.GetOrgID(),
+		Query:   c.Req.Form,
+		Sharded: srv.sharded,
 	})
 
 	return response.JSON(resp.HTTPStatusCode(), resp)
@@ -71,6 +75,8 @@ func (srv PrometheusSrv) RouteGetAlertStatuses(c *contextmodel.ReqContext) respo
 type AlertStatusesOptions struct {
 	OrgID int64
 	Query url.Values
+	// Sharded is true when the manager only has the state of the rules evaluated by this instance.
+	Sharded bool
 }
 
 func PrepareAlertStatuses(manager state.AlertInstanceManager, opts AlertStatusesOptions) apimodels.AlertResponse {
@@ -79,7 +85,8 @@ func PrepareAlertStatuses(manager state.AlertInstanceManager, opts AlertStatuses
 			Status: "success",
 		},
 		Data: apimodels.AlertDiscovery{
-			Alerts: []*apimodels.Alert{},
+			Alerts:  []*apimodels.Alert{},
+			Sharded: opts.Sharded,
 		},
 	}
 
@@ -105,6 +112,7 @@ func PrepareAlertStatuses(manager state.AlertInstanceManager, opts AlertStatuses
 			State:    state.FormatStateAndReason(alertState.State, alertState.StateReason),
 			ActiveAt: &startsAt,
 			Value:    valString,
+			Flapping: alertState.Flapping,
 		})
 	}
 
@@ -190,6 +198,8 @@ type RuleGroupStatusesOptions struct {
 	Query              url.Values
 	Namespaces         map[string]string
 	AuthorizeRuleGroup func(rules []*ngmodels.AlertRule) (bool, error)
+	// Sharded is true when the manager only has the state of the rules evaluated by this instance.
+	Sharded bool
 }
 
 type ListAlertRulesStore interface {
@@ -206,6 +216,7 @@ func (srv PrometheusSrv) RouteGetRuleStatuses(c *contextmodel.ReqContext) respon
 		},
 		Data: apimodels.RuleDiscovery{
 			RuleGroups: []apimodels.RuleGroup{},
+			Sharded:    srv.sharded,
 		},
 	}
 
@@ -230,6 +241,7 @@ func (srv PrometheusSrv) RouteGetRuleStatuses(c *contextmodel.ReqContext) respon
 		AuthorizeRuleGroup: func(rules []*ngmodels.AlertRule) (bool, error) {
 			return srv.authz.HasAccessToRuleGroup(c.Req.Context(), c.SignedInUser, rules)
 		},
+		Sharded: srv.sharded,
 	})
 
 	return response.JSON(ruleResponse.HTTPStatusCode(), ruleResponse)
@@ -242,6 +254,7 @@ func PrepareRuleGroupStatuses(log log.Logger, manager state.AlertInstanceManager
 		},
 		Data: apimodels.RuleDiscovery{
 			RuleGroups: []apimodels.RuleGroup{},
+			Sharded:    opts.Sharded,
 		},
 	}
 
@@ -483,6 +496,7 @@ func toRuleGroup(log log.Logger, manager state.AlertInstanceManager, groupKey ng
 				State:    state.FormatStateAndReason(alertState.State, alertState.StateReason),
 				ActiveAt: &activeAt,
 				Value:    valString,
+				Flapping: alertState.Flapping,
 			}
 
 			if alertState.LastEvaluationTime.After(newRule.LastEvaluation) {
@@ -106,6 +106,26 @@ func TestRouteGetAlertStatuses(t *testing.T) {
 `, string(r.Body()))
 	})
 
+	t.Run("with sharded rule evaluation", func(t *testing.T) {
+		_, _, api := setupAPI(t)
+		api.sharded = true
+		req, err := http.NewRequest("GET", "/api/v1/alerts", nil)
+		require.NoError(t, err)
+		c := &contextmodel.ReqContext{Context: &web.Context{Req: req}, SignedInUser: &user.SignedInUser{OrgID: orgID}}
+
+		r := api.RouteGetAlertStatuses(c)
+		require.Equal(t, http.StatusOK, r.Status())
+		require.JSONEq(t, `
+{
+	"status": "success",
+	"data": {
+		"alerts": [],
+		"sharded": true
+	}
+}
+`, string(r.Body()))
+	})
+
 	t.Run("with two alerts", func(t *testing.T) {
 		_, fakeAIM, api := setupAPI(t)
 		fakeAIM.GenerateAlertInstances(1, util.GenerateShortUID(), 2)
@@ -190,6 +210,40 @@ func TestRouteGetAlertStatuses(t *testing.T) {
 }`, string(r.Body()))
 	})
 
+	t.Run("with a flapping alert", func(t *testing.T) {
+		_, fakeAIM, api := setupAPI(t)
+		fakeAIM.GenerateAlertInstances(1, util.GenerateShortUID(), 1, withAlertingState(), func(s *state.State) *state.State {
+			s.Flapping = true
+			return s
+		})
+		req, err := http.NewRequest("GET", "/api/v1/alerts", nil)
+		require.NoError(t, err)
+		c := &contextmodel.ReqContext{Context: &web.Context{Req: req}, SignedInUser: &user.SignedInUser{OrgID: orgID}}
+
+		r := api.RouteGetAlertStatuses(c)
+		require.Equal(t, http.StatusOK, r.Status())
+		require.JSONEq(t, `
+{
+	"status": "success",
+	"data": {
+		"alerts": [{
+			"labels": {
+				"alertname": "test_title_0",
+				"instance_label": "test",
+				"label": "test"
+			},
+			"annotations": {
+				"annotation": "test"
+			},
+			"state": "Alerting",
+			"activeAt": "0001-01-01T00:00:00Z",
+			"value": "1.1e+00",
+			"flapping": true
+		}]
+	}
+}`, string(r.Body()))
+	})
+
 	t.Run("with the inclusion of internal labels", func(t *testing.T) {
 		_, fakeAIM, api := setupAPI(t)
 		fakeAIM.GenerateAlertInstances(orgID, util.GenerateShortUID(), 2)
@@ -586,6 +640,26 @@ func TestRouteGetRuleStatuses(t *testing.T) {
 			require.Equal(t, "rule-group-3", result.Data.RuleGroups[1].Name)
 		})
 
+		t.Run("should report that the state is partial when rule evaluation is sharded", func(t *testing.T) {
+			r, err := http.NewRequest("GET", "/api/v1/rules", nil)
+			require.NoError(t, err)
+
+			c.Context = &web.Context{Req: r}
+
+			shardedAPI := api
+			shardedAPI.sharded = true
+			resp := shardedAPI.RouteGetRuleStatuses(c)
+			require.Equal(t, http.StatusOK, resp.Status())
+			result := &apimodels.RuleResponse{}
+			require.NoError(t, json.Unmarshal(resp.Body(), result))
+			require.True(t, result.Data.Sharded)
+
+			resp = api.RouteGetRuleStatuses(c)
+			result = &apimodels.RuleResponse{}
+			require.NoError(t, json.Unmarshal(resp.Body(), result))
+			require.False(t, result.Data.Sharded)
+		})
+
 		t.Run("should only return rule groups under given rule_group list", func(t *testing.T) {
 			r, err := http.NewRequest("GET", "/api/v1/rules?rule_group=rule-group-1&rule_group=rule-group-2", nil)
 			require.NoError(t, err)
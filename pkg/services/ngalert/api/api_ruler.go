@@ -537,16 +537,19 @@ func toGettableRuleGroupConfig(groupName string, rules ngmodels.RulesGroup, prov
 	rules.SortByGroupIndex()
 	ruleNodes := make([]apimodels.GettableExtendedRuleNode, 0, len(rules))
 	var interval time.Duration
+	var sequential bool
 	if len(rules) > 0 {
 		interval = time.Duration(rules[0].IntervalSeconds) * time.Second
+		sequential = rules[0].SequentialEvaluation
 	}
 	for _, r := range rules {
 		ruleNodes = append(ruleNodes, toGettableExtendedRuleNode(*r, provenanceRecords))
 	}
 	return apimodels.GettableRuleGroupConfig{
-		Name:     groupName,
-		Interval: model.Duration(interval),
-		Rules:    ruleNodes,
+		Name:       groupName,
+		Interval:   model.Duration(interval),
+		Sequential: sequential,
+		Rules:      ruleNodes,
 	}
 }
 
@@ -575,6 +578,7 @@ func toGettableExtendedRuleNode(r ngmodels.AlertRule, provenanceRecords map[stri
 			IsPaused:             r.IsPaused,
 			NotificationSettings: AlertRuleNotificationSettingsFromNotificationSettings(r.NotificationSettings),
 			Record:               ApiRecordFromModelRecord(r.Record),
+			DependsOn:            r.DependsOn,
 		},
 	}
 	forDuration := model.Duration(r.For)
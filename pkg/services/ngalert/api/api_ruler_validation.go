@@ -3,6 +3,7 @@ package api
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -11,6 +12,7 @@ import (
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/ngalert/writer"
 	"github.com/grafana/grafana/pkg/setting"
 	prommodels "github.com/prometheus/common/model"
 )
@@ -22,6 +24,8 @@ type RuleLimits struct {
 	BaseInterval time.Duration
 	// Whether recording rules are allowed.
 	RecordingRulesAllowed bool
+	// The types of the writers that recording rules can select.
+	RecordingRuleWriters []string
 }
 
 func RuleLimitsFromConfig(cfg *setting.UnifiedAlertingSettings, toggles featuremgmt.FeatureToggles) RuleLimits {
@@ -29,6 +33,7 @@ func RuleLimitsFromConfig(cfg *setting.UnifiedAlertingSettings, toggles featurem
 		DefaultRuleEvaluationInterval: cfg.DefaultRuleEvaluationInterval,
 		BaseInterval:                  cfg.BaseInterval,
 		RecordingRulesAllowed:         toggles.IsEnabledGlobally(featuremgmt.FlagGrafanaManagedRecordingRules),
+		RecordingRuleWriters:          writer.EnabledTypes(cfg.RecordingRules),
 	}
 }
 
@@ -149,6 +154,8 @@ func validateAlertingRuleFields(in *apimodels.PostableExtendedRuleNode, newRule
 		return ngmodels.AlertRule{}, err
 	}
 
+	newRule.DependsOn = in.GrafanaManagedAlert.DependsOn
+
 	return newRule, nil
 }
 
@@ -164,6 +171,10 @@ func validateRecordingRuleFields(in *apimodels.PostableExtendedRuleNode, newRule
 		return ngmodels.AlertRule{}, fmt.Errorf("%w: %s", ngmodels.ErrAlertRuleFailedValidation, err.Error())
 	}
 
+	if in.GrafanaManagedAlert.DependsOn != "" {
+		return ngmodels.AlertRule{}, fmt.Errorf("%w: recording rules cannot depend on other rules", ngmodels.ErrAlertRuleFailedValidation)
+	}
+
 	metricName := prommodels.LabelValue(in.GrafanaManagedAlert.Record.Metric)
 	if !metricName.IsValid() {
 		return ngmodels.AlertRule{}, fmt.Errorf("%w: %s", ngmodels.ErrAlertRuleFailedValidation, "metric name for recording rule must be a valid utf8 string")
@@ -171,6 +182,9 @@ func validateRecordingRuleFields(in *apimodels.PostableExtendedRuleNode, newRule
 	if !prommodels.IsValidMetricName(metricName) {
 		return ngmodels.AlertRule{}, fmt.Errorf("%w: %s", ngmodels.ErrAlertRuleFailedValidation, "metric name for recording rule must be a valid Prometheus metric name")
 	}
+	if w := in.GrafanaManagedAlert.Record.Writer; w != "" && !slices.Contains(limits.RecordingRuleWriters, w) {
+		return ngmodels.AlertRule{}, fmt.Errorf("%w: writer %q for recording rule is not enabled, enabled writers are %v", ngmodels.ErrAlertRuleFailedValidation, w, limits.RecordingRuleWriters)
+	}
 	newRule.Record = ModelRecordFromApiRecord(in.GrafanaManagedAlert.Record)
 
 	newRule.NoDataState = ""
@@ -319,11 +333,20 @@ func ValidateRuleGroup(
 		ruleWithOptionals := ngmodels.AlertRuleWithOptionals{}
 		rule.IsPaused = isPaused
 		rule.RuleGroupIndex = idx + 1
+		rule.SequentialEvaluation = ruleGroupConfig.Sequential
 		ruleWithOptionals.AlertRule = *rule
 		ruleWithOptionals.HasPause = hasPause
 
 		result = append(result, &ruleWithOptionals)
 	}
+
+	rules := make([]*ngmodels.AlertRule, 0, len(result))
+	for _, r := range result {
+		rules = append(rules, &r.AlertRule)
+	}
+	if err := ngmodels.ValidateRuleGroupDependencies(rules); err != nil {
+		return nil, err
+	}
 	return result, nil
 }
 
@@ -239,6 +239,23 @@ func TestValidateRuleGroup(t *testing.T) {
 			require.True(t, alert.HasPause)
 		}
 	})
+
+	t.Run("should set sequential evaluation and dependencies", func(t *testing.T) {
+		r1 := validRule()
+		r1.GrafanaManagedAlert.UID = util.GenerateShortUID()
+		r2 := validRule()
+		r2.GrafanaManagedAlert.DependsOn = r1.GrafanaManagedAlert.UID
+		g := validGroup(cfg, r1, r2)
+		g.Sequential = true
+		alerts, err := ValidateRuleGroup(&g, orgId, folder.UID, limits)
+		require.NoError(t, err)
+		require.Len(t, alerts, 2)
+		for _, alert := range alerts {
+			require.True(t, alert.SequentialEvaluation)
+		}
+		require.Empty(t, alerts[0].DependsOn)
+		require.Equal(t, r1.GrafanaManagedAlert.UID, alerts[1].DependsOn)
+	})
 }
 
 func TestValidateRuleGroupFailures(t *testing.T) {
@@ -301,6 +318,20 @@ func TestValidateRuleGroupFailures(t *testing.T) {
 				require.Contains(t, err.Error(), apiModel.Rules[0].GrafanaManagedAlert.UID)
 			},
 		},
+		{
+			name: "fail if rule depends on a rule that follows it",
+			group: func() *apimodels.PostableRuleGroupConfig {
+				r1 := validRule()
+				r2 := validRule()
+				r2.GrafanaManagedAlert.UID = util.GenerateShortUID()
+				r1.GrafanaManagedAlert.DependsOn = r2.GrafanaManagedAlert.UID
+				g := validGroup(cfg, r1, r2)
+				return &g
+			},
+			assert: func(t *testing.T, apiModel *apimodels.PostableRuleGroupConfig, err error) {
+				require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -449,6 +480,18 @@ func TestValidateRuleNode_NoUID(t *testing.T) {
 				require.Equal(t, api.GrafanaManagedAlert.Record.Metric, alert.Record.Metric)
 			},
 		},
+		{
+			name:   "accepts recording rule with enabled writer",
+			limits: allowRecording(limits),
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				r.GrafanaManagedAlert.Record = &apimodels.Record{Metric: "some_metric", From: "A", Writer: "prometheus"}
+				return &r
+			},
+			assert: func(t *testing.T, api *apimodels.PostableExtendedRuleNode, alert *models.AlertRule) {
+				require.Equal(t, "prometheus", alert.Record.Writer)
+			},
+		},
 		{
 			name:   "recording rules ignore fields that only make sense for Alerting rules",
 			limits: allowRecording(limits),
@@ -690,6 +733,21 @@ func TestValidateRuleNodeFailures_NoUID(t *testing.T) {
 			},
 			expErr: "NOTEXIST does not exist",
 		},
+		{
+			name:   "rejects recording rule with writer that is not enabled",
+			limits: allowRecording(limits),
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				r.GrafanaManagedAlert.Record = &apimodels.Record{Metric: "my_metric", From: "A", Writer: "influxdb"}
+				r.GrafanaManagedAlert.Condition = ""
+				r.GrafanaManagedAlert.NoDataState = ""
+				r.GrafanaManagedAlert.ExecErrState = ""
+				r.GrafanaManagedAlert.NotificationSettings = nil
+				r.ApiRuleNode.For = nil
+				return &r
+			},
+			expErr: `writer "influxdb" for recording rule is not enabled`,
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -33,6 +33,7 @@ func AlertRuleFromProvisionedAlertRule(a definitions.ProvisionedAlertRule) (mode
 		IsPaused:             a.IsPaused,
 		NotificationSettings: NotificationSettingsFromAlertRuleNotificationSettings(a.NotificationSettings),
 		Record:               ModelRecordFromApiRecord(a.Record),
+		DependsOn:            a.DependsOn,
 	}, nil
 }
 
@@ -57,6 +58,7 @@ func ProvisionedAlertRuleFromAlertRule(rule models.AlertRule, provenance models.
 		IsPaused:             rule.IsPaused,
 		NotificationSettings: AlertRuleNotificationSettingsFromNotificationSettings(rule.NotificationSettings),
 		Record:               ApiRecordFromModelRecord(rule.Record),
+		DependsOn:            rule.DependsOn,
 	}
 }
 
@@ -107,9 +109,10 @@ func ApiAlertQueriesFromAlertQueries(queries []models.AlertQuery) []definitions.
 
 func AlertRuleGroupFromApiAlertRuleGroup(a definitions.AlertRuleGroup) (models.AlertRuleGroup, error) {
 	ruleGroup := models.AlertRuleGroup{
-		Title:     a.Title,
-		FolderUID: a.FolderUID,
-		Interval:  a.Interval,
+		Title:                a.Title,
+		FolderUID:            a.FolderUID,
+		Interval:             a.Interval,
+		SequentialEvaluation: a.Sequential,
 	}
 	for i := range a.Rules {
 		converted, err := AlertRuleFromProvisionedAlertRule(a.Rules[i])
@@ -127,10 +130,11 @@ func ApiAlertRuleGroupFromAlertRuleGroup(d models.AlertRuleGroup) definitions.Al
 		rules = append(rules, ProvisionedAlertRuleFromAlertRule(d.Rules[i], d.Provenance))
 	}
 	return definitions.AlertRuleGroup{
-		Title:     d.Title,
-		FolderUID: d.FolderUID,
-		Interval:  d.Interval,
-		Rules:     rules,
+		Title:      d.Title,
+		FolderUID:  d.FolderUID,
+		Interval:   d.Interval,
+		Rules:      rules,
+		Sequential: d.SequentialEvaluation,
 	}
 }
 
@@ -157,7 +161,7 @@ func AlertRuleGroupExportFromAlertRuleGroupWithFolderFullpath(d models.AlertRule
 		}
 		rules = append(rules, alert)
 	}
-	return definitions.AlertRuleGroupExport{
+	result := definitions.AlertRuleGroupExport{
 		OrgID:           d.OrgID,
 		Name:            d.Title,
 		Folder:          d.FolderFullpath,
@@ -165,7 +169,11 @@ func AlertRuleGroupExportFromAlertRuleGroupWithFolderFullpath(d models.AlertRule
 		Interval:        model.Duration(time.Duration(d.Interval) * time.Second),
 		IntervalSeconds: d.Interval,
 		Rules:           rules,
-	}, nil
+	}
+	if d.SequentialEvaluation {
+		result.Sequential = util.Pointer(true)
+	}
+	return result, nil
 }
 
 // AlertRuleExportFromAlertRule creates a definitions.AlertRuleExport DTO from models.AlertRule.
@@ -202,6 +210,9 @@ func AlertRuleExportFromAlertRule(rule models.AlertRule) (definitions.AlertRuleE
 	if rule.Labels != nil {
 		result.Labels = &rule.Labels
 	}
+	if rule.DependsOn != "" {
+		result.DependsOn = &rule.DependsOn
+	}
 	return result, nil
 }
 
@@ -460,10 +471,14 @@ func AlertRuleRecordExportFromRecord(r *models.Record) *definitions.AlertRuleRec
 	if r == nil {
 		return nil
 	}
-	return &definitions.AlertRuleRecordExport{
+	result := &definitions.AlertRuleRecordExport{
 		Metric: r.Metric,
 		From:   r.From,
 	}
+	if r.Writer != "" {
+		result.Writer = &r.Writer
+	}
+	return result
 }
 
 func ModelRecordFromApiRecord(r *definitions.Record) *models.Record {
@@ -473,6 +488,7 @@ func ModelRecordFromApiRecord(r *definitions.Record) *models.Record {
 	return &models.Record{
 		Metric: r.Metric,
 		From:   r.From,
+		Writer: r.Writer,
 	}
 }
 
@@ -483,5 +499,6 @@ func ApiRecordFromModelRecord(r *models.Record) *definitions.Record {
 	return &definitions.Record{
 		Metric: r.Metric,
 		From:   r.From,
+		Writer: r.Writer,
 	}
 }
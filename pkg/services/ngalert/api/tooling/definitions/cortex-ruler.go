@@ -234,9 +234,12 @@ type NamespaceConfigResponse map[string][]GettableRuleGroupConfig
 
 // swagger:model
 type PostableRuleGroupConfig struct {
-	Name     string                     `yaml:"name" json:"name"`
-	Interval model.Duration             `yaml:"interval,omitempty" json:"interval,omitempty"`
-	Rules    []PostableExtendedRuleNode `yaml:"rules" json:"rules"`
+	Name     string         `yaml:"name" json:"name"`
+	Interval model.Duration `yaml:"interval,omitempty" json:"interval,omitempty"`
+	// Sequential makes the rules of the group evaluate one after another in the order they are defined.
+	// Only Grafana-managed rule groups can be evaluated sequentially.
+	Sequential bool                       `yaml:"sequential,omitempty" json:"sequential,omitempty"`
+	Rules      []PostableExtendedRuleNode `yaml:"rules" json:"rules"`
 }
 
 func (c *PostableRuleGroupConfig) UnmarshalJSON(b []byte) error {
@@ -275,15 +278,21 @@ func (c *PostableRuleGroupConfig) validate() error {
 	if hasGrafRules && hasLotexRules {
 		return fmt.Errorf("cannot mix Grafana & Prometheus style rules")
 	}
+
+	if hasLotexRules && c.Sequential {
+		return fmt.Errorf("only Grafana-managed rule groups can be evaluated sequentially")
+	}
 	return nil
 }
 
 // swagger:model
 type GettableRuleGroupConfig struct {
-	Name          string                     `yaml:"name" json:"name"`
-	Interval      model.Duration             `yaml:"interval,omitempty" json:"interval,omitempty"`
-	SourceTenants []string                   `yaml:"source_tenants,omitempty" json:"source_tenants,omitempty"`
-	Rules         []GettableExtendedRuleNode `yaml:"rules" json:"rules"`
+	Name          string         `yaml:"name" json:"name"`
+	Interval      model.Duration `yaml:"interval,omitempty" json:"interval,omitempty"`
+	SourceTenants []string       `yaml:"source_tenants,omitempty" json:"source_tenants,omitempty"`
+	// Sequential is true if the rules of the group are evaluated one after another in the order they are defined.
+	Sequential bool                       `yaml:"sequential,omitempty" json:"sequential,omitempty"`
+	Rules      []GettableExtendedRuleNode `yaml:"rules" json:"rules"`
 }
 
 func (c *GettableRuleGroupConfig) UnmarshalJSON(b []byte) error {
@@ -487,6 +496,10 @@ type Record struct {
 	// required: true
 	// example: A
 	From string `json:"from" yaml:"from"`
+	// Type of the writer the recorded metric is written with: prometheus, influxdb, otlp or sql.
+	// If empty, the writer configured for the organization is used.
+	// example: prometheus
+	Writer string `json:"writer,omitempty" yaml:"writer,omitempty"`
 }
 
 // swagger:model
@@ -500,6 +513,8 @@ type PostableGrafanaRule struct {
 	IsPaused             *bool                          `json:"is_paused" yaml:"is_paused"`
 	NotificationSettings *AlertRuleNotificationSettings `json:"notification_settings" yaml:"notification_settings"`
 	Record               *Record                        `json:"record" yaml:"record"`
+	// UID of an alert rule that precedes this rule in the group. The notifications of this rule are suppressed while that rule is firing.
+	DependsOn string `json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
 }
 
 // swagger:model
@@ -521,6 +536,7 @@ type GettableGrafanaRule struct {
 	IsPaused             bool                           `json:"is_paused" yaml:"is_paused"`
 	NotificationSettings *AlertRuleNotificationSettings `json:"notification_settings,omitempty" yaml:"notification_settings,omitempty"`
 	Record               *Record                        `json:"record,omitempty" yaml:"record,omitempty"`
+	DependsOn            string                         `json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
 }
 
 // AlertQuery represents a single query associated with an alert definition.
@@ -73,6 +73,10 @@ type RuleDiscovery struct {
 	// required: true
 	RuleGroups []RuleGroup      `json:"groups"`
 	Totals     map[string]int64 `json:"totals,omitempty"`
+	// Sharded is true when the evaluation of rules is sharded across the instances of the high availability
+	// cluster. The state of the alerts is then only known for the rules evaluated by the instance that served the request.
+	// required: false
+	Sharded bool `json:"sharded,omitempty"`
 }
 
 // AlertDiscovery has info for all active alerts.
@@ -80,6 +84,10 @@ type RuleDiscovery struct {
 type AlertDiscovery struct {
 	// required: true
 	Alerts []*Alert `json:"alerts"`
+	// Sharded is true when the evaluation of rules is sharded across the instances of the high availability
+	// cluster. The alerts are then only the ones of the rules evaluated by the instance that served the request.
+	// required: false
+	Sharded bool `json:"sharded,omitempty"`
 }
 
 // swagger:model
@@ -190,6 +198,8 @@ type Alert struct {
 	ActiveAt *time.Time `json:"activeAt"`
 	// required: true
 	Value string `json:"value"`
+	// Flapping is true if the alert instance changes state too often.
+	Flapping bool `json:"flapping,omitempty"`
 }
 
 type StateByImportance int
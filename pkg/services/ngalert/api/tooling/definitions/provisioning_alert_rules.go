@@ -169,6 +169,9 @@ type ProvisionedAlertRule struct {
 	NotificationSettings *AlertRuleNotificationSettings `json:"notification_settings"`
 	//example: {"metric":"grafana_alerts_ratio", "from":"A"}
 	Record *Record `json:"record"`
+	// UID of an alert rule that precedes this rule in the rule group. The notifications of this rule are suppressed while that rule is firing.
+	// example: bd3e7a5f-1c2b-4d5e-8f90-123456789abc
+	DependsOn string `json:"dependsOn,omitempty"`
 }
 
 // swagger:route GET /v1/provisioning/folder/{FolderUID}/rule-groups/{Group} provisioning stable RouteGetAlertRuleGroup
@@ -243,6 +246,8 @@ type AlertRuleGroup struct {
 	FolderUID string                 `json:"folderUid"`
 	Interval  int64                  `json:"interval"`
 	Rules     []ProvisionedAlertRule `json:"rules"`
+	// Evaluate the rules one after another in the order they are defined.
+	Sequential bool `json:"sequential,omitempty"`
 }
 
 // AlertRuleGroupExport is the provisioned file export of AlertRuleGroupV1.
@@ -253,6 +258,7 @@ type AlertRuleGroupExport struct {
 	FolderUID       string            `json:"-" yaml:"-" hcl:"folder_uid"`
 	Interval        model.Duration    `json:"interval" yaml:"interval"`
 	IntervalSeconds int64             `json:"-" yaml:"-" hcl:"interval_seconds"`
+	Sequential      *bool             `json:"sequential,omitempty" yaml:"sequential,omitempty" hcl:"sequential"`
 	Rules           []AlertRuleExport `json:"rules" yaml:"rules" hcl:"rule,block"`
 }
 
@@ -276,6 +282,7 @@ type AlertRuleExport struct {
 	IsPaused             bool                                 `json:"isPaused" yaml:"isPaused" hcl:"is_paused"`
 	NotificationSettings *AlertRuleNotificationSettingsExport `json:"notification_settings,omitempty" yaml:"notification_settings,omitempty" hcl:"notification_settings,block"`
 	Record               *AlertRuleRecordExport               `json:"record,omitempty" yaml:"record,omitempty" hcl:"record"`
+	DependsOn            *string                              `json:"dependsOn,omitempty" yaml:"dependsOn,omitempty" hcl:"depends_on"`
 }
 
 // AlertQueryExport is the provisioned export of models.AlertQuery.
@@ -307,6 +314,7 @@ type AlertRuleNotificationSettingsExport struct {
 
 // Record is the provisioned export of models.Record.
 type AlertRuleRecordExport struct {
-	Metric string `json:"metric" yaml:"metric" hcl:"metric"`
-	From   string `json:"from" yaml:"from" hcl:"from"`
+	Metric string  `json:"metric" yaml:"metric" hcl:"metric"`
+	From   string  `json:"from" yaml:"from" hcl:"from"`
+	Writer *string `json:"writer,omitempty" yaml:"writer,omitempty" hcl:"writer,optional"`
 }
@@ -132,6 +132,9 @@ const (
 	// StateReasonAnnotation is the name of the annotation that explains the difference between evaluation state and alert state (i.e. changing state when NoData or Error).
 	StateReasonAnnotation = GrafanaReservedLabelPrefix + "state_reason"
 
+	// FlappingAnnotation is the name of the annotation that is added to alerts of alert instances that change state too often.
+	FlappingAnnotation = GrafanaReservedLabelPrefix + "flapping"
+
 	// MigratedLabelPrefix is a label prefix for all labels created during legacy migration.
 	MigratedLabelPrefix = "__legacy_"
 	// MigratedUseLegacyChannelsLabel is created during legacy migration to route to separate nested policies for migrated channels.
@@ -197,6 +200,8 @@ type AlertRuleGroup struct {
 	Interval   int64
 	Provenance Provenance
 	Rules      []AlertRule
+	// SequentialEvaluation is true if the rules are evaluated one after another in the order they are defined.
+	SequentialEvaluation bool
 }
 
 // AlertRuleGroupWithFolderFullpath extends AlertRuleGroup with orgID and folder title
@@ -209,15 +214,18 @@ type AlertRuleGroupWithFolderFullpath struct {
 func NewAlertRuleGroupWithFolderFullpath(groupKey AlertRuleGroupKey, rules []AlertRule, folderFullpath string) AlertRuleGroupWithFolderFullpath {
 	SortAlertRulesByGroupIndex(rules)
 	var interval int64
+	var sequential bool
 	if len(rules) > 0 {
 		interval = rules[0].IntervalSeconds
+		sequential = rules[0].SequentialEvaluation
 	}
 	var result = AlertRuleGroupWithFolderFullpath{
 		AlertRuleGroup: &AlertRuleGroup{
-			Title:     groupKey.RuleGroup,
-			FolderUID: groupKey.NamespaceUID,
-			Interval:  interval,
-			Rules:     rules,
+			Title:                groupKey.RuleGroup,
+			FolderUID:            groupKey.NamespaceUID,
+			Interval:             interval,
+			Rules:                rules,
+			SequentialEvaluation: sequential,
 		},
 		FolderFullpath: folderFullpath,
 		OrgID:          groupKey.OrgID,
@@ -269,6 +277,11 @@ type AlertRule struct {
 	Labels               map[string]string
 	IsPaused             bool
 	NotificationSettings []NotificationSettings `xorm:"notification_settings"` // we use slice to workaround xorm mapping that does not serialize a struct to JSON unless it's a slice
+	// SequentialEvaluation is true if the rules of the group are evaluated one after another in the order of RuleGroupIndex.
+	// It is a property of the rule group and is the same for all rules of the group.
+	SequentialEvaluation bool `xorm:"sequential_evaluation"`
+	// DependsOn is the UID of an alert rule of the same group. The notifications of this rule are suppressed while that rule is firing.
+	DependsOn string `xorm:"depends_on"`
 }
 
 // Namespaced describes a class of resources that are stored in a specific namespace.
@@ -570,6 +583,15 @@ func (alertRule *AlertRule) ValidateAlertRule(cfg setting.UnifiedAlertingSetting
 		}
 	}
 
+	if alertRule.DependsOn != "" {
+		if alertRule.Type() == RuleTypeRecording {
+			return fmt.Errorf("%w: recording rules cannot depend on other rules", ErrAlertRuleFailedValidation)
+		}
+		if alertRule.DependsOn == alertRule.UID {
+			return fmt.Errorf("%w: rule cannot depend on itself", ErrAlertRuleFailedValidation)
+		}
+	}
+
 	if len(alertRule.NotificationSettings) > 0 {
 		if len(alertRule.NotificationSettings) != 1 {
 			return fmt.Errorf("%w: only one notification settings entry is allowed", ErrAlertRuleFailedValidation)
@@ -657,6 +679,8 @@ type AlertRuleVersion struct {
 	Labels               map[string]string
 	IsPaused             bool
 	NotificationSettings []NotificationSettings `xorm:"notification_settings"` // we use slice to workaround xorm mapping that does not serialize a struct to JSON unless it's a slice
+	SequentialEvaluation bool                   `xorm:"sequential_evaluation"`
+	DependsOn            string                 `xorm:"depends_on"`
 }
 
 // GetAlertRuleByUIDQuery is the query for retrieving/deleting an alert rule by UID and organisation ID.
@@ -808,6 +832,28 @@ func ValidateRuleGroupInterval(intervalSeconds, baseIntervalSeconds int64) error
 	return nil
 }
 
+// ValidateRuleGroupDependencies checks that the rules of the group depend only on the alert rules that precede them
+// in the group. It makes the dependencies acyclic, and, if the group is evaluated sequentially, the state of the rule
+// a rule depends on is always from the same evaluation. The rules must be in the order they are defined in the group.
+func ValidateRuleGroupDependencies(rules []*AlertRule) error {
+	preceding := make(map[string]*AlertRule, len(rules))
+	for _, rule := range rules {
+		if rule.DependsOn != "" {
+			dependency, ok := preceding[rule.DependsOn]
+			if !ok {
+				return fmt.Errorf("%w: rule '%s' depends on rule with UID '%s' that does not precede it in the group", ErrAlertRuleFailedValidation, rule.Title, rule.DependsOn)
+			}
+			if dependency.Type() != RuleTypeAlerting {
+				return fmt.Errorf("%w: rule '%s' depends on rule '%s' that is not an alert rule", ErrAlertRuleFailedValidation, rule.Title, dependency.Title)
+			}
+		}
+		if rule.UID != "" {
+			preceding[rule.UID] = rule
+		}
+	}
+	return nil
+}
+
 type RulesGroup []*AlertRule
 
 func (g RulesGroup) SortByGroupIndex() {
@@ -862,6 +908,8 @@ type Record struct {
 	Metric string
 	// From contains a query RefID, indicating which expression node is the output of the recording rule.
 	From string
+	// Writer is the type of the writer the results are written with. If empty, the writer of the organization is used.
+	Writer string
 }
 
 func (r *Record) Fingerprint() data.Fingerprint {
@@ -876,6 +924,10 @@ func (r *Record) Fingerprint() data.Fingerprint {
 
 	writeString(r.Metric)
 	writeString(r.From)
+	// the writer is optional, so only include it if set to keep the fingerprint of existing rules
+	if r.Writer != "" {
+		writeString(r.Writer)
+	}
 	return data.Fingerprint(h.Sum64())
 }
 
@@ -864,6 +864,34 @@ func TestSortByGroupIndex(t *testing.T) {
 	})
 }
 
+func TestValidateRuleGroupDependencies(t *testing.T) {
+	t.Run("should accept rules that depend on preceding alert rules", func(t *testing.T) {
+		rules := RuleGen.GenerateManyRef(3)
+		rules[1].DependsOn = rules[0].UID
+		rules[2].DependsOn = rules[0].UID
+		require.NoError(t, ValidateRuleGroupDependencies(rules))
+	})
+
+	t.Run("should reject rule that depends on a rule that follows it", func(t *testing.T) {
+		rules := RuleGen.GenerateManyRef(2)
+		rules[0].DependsOn = rules[1].UID
+		require.ErrorIs(t, ValidateRuleGroupDependencies(rules), ErrAlertRuleFailedValidation)
+	})
+
+	t.Run("should reject rule that depends on a rule outside of the group", func(t *testing.T) {
+		rules := RuleGen.GenerateManyRef(2)
+		rules[1].DependsOn = RuleGen.GenerateRef().UID
+		require.ErrorIs(t, ValidateRuleGroupDependencies(rules), ErrAlertRuleFailedValidation)
+	})
+
+	t.Run("should reject rule that depends on a recording rule", func(t *testing.T) {
+		rules := RuleGen.GenerateManyRef(2)
+		ConvertToRecordingRule(rules[0])
+		rules[1].DependsOn = rules[0].UID
+		require.ErrorIs(t, ValidateRuleGroupDependencies(rules), ErrAlertRuleFailedValidation)
+	})
+}
+
 func TestTimeRangeYAML(t *testing.T) {
 	yamlRaw := "from: 600\nto: 0\n"
 	var rtr RelativeTimeRange
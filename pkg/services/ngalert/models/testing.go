@@ -490,6 +490,18 @@ func (a *AlertRuleMutators) WithIsPaused(paused bool) AlertRuleMutator {
 	}
 }
 
+func (a *AlertRuleMutators) WithSequentialEvaluation(sequential bool) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.SequentialEvaluation = sequential
+	}
+}
+
+func (a *AlertRuleMutators) WithDependsOn(uid string) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.DependsOn = uid
+	}
+}
+
 func (a *AlertRuleMutators) WithRandomRecordingRules() AlertRuleMutator {
 	return func(rule *AlertRule) {
 		if rand.Int63()%2 == 0 {
@@ -605,6 +617,9 @@ func CopyRule(r *AlertRule, mutators ...AlertRuleMutator) *AlertRule {
 		ExecErrState:    r.ExecErrState,
 		For:             r.For,
 		Record:          r.Record,
+
+		SequentialEvaluation: r.SequentialEvaluation,
+		DependsOn:            r.DependsOn,
 	}
 
 	if r.DashboardUID != nil {
@@ -646,6 +661,7 @@ func CopyRule(r *AlertRule, mutators ...AlertRuleMutator) *AlertRule {
 		result.Record = &Record{
 			From:   r.Record.From,
 			Metric: r.Record.Metric,
+			Writer: r.Record.Writer,
 		}
 	}
 
@@ -346,7 +346,7 @@ func (ng *AlertNG) init() error {
 	evalFactory := eval.NewEvaluatorFactory(ng.Cfg.UnifiedAlerting, ng.DataSourceCache, ng.ExpressionService)
 	conditionValidator := eval.NewConditionValidator(ng.DataSourceCache, ng.ExpressionService, ng.pluginsStore)
 
-	recordingWriter, err := createRecordingWriter(ng.FeatureToggles, ng.Cfg.UnifiedAlerting.RecordingRules, ng.httpClientProvider, clk, ng.Metrics.GetRemoteWriterMetrics())
+	recordingWriter, err := createRecordingWriter(ng.FeatureToggles, ng.Cfg.UnifiedAlerting.RecordingRules, ng.httpClientProvider, ng.SQLStore, clk, ng.Metrics.GetRemoteWriterMetrics())
 	if err != nil {
 		return fmt.Errorf("failed to initialize recording writer: %w", err)
 	}
@@ -370,6 +370,17 @@ func (ng *AlertNG) init() error {
 		RecordingWriter:      ng.RecordingWriter,
 	}
 
+	if ng.Cfg.UnifiedAlerting.HARuleShardingEnabled {
+		if membership := ng.MultiOrgAlertmanager.ClusterMembership(); membership == nil {
+			ng.Log.Warn("Rule sharding is enabled but high availability is not configured, all rules are evaluated by this instance")
+		} else if ng.FeatureToggles.IsEnabledGlobally(featuremgmt.FlagAlertingSaveStatePeriodic) {
+			// the periodic save replaces the state of all rules in the database with the state of this instance.
+			ng.Log.Warn("Rule sharding cannot be used when the state is saved periodically, all rules are evaluated by this instance")
+		} else {
+			schedCfg.ClusterMembership = membership
+		}
+	}
+
 	// There are a set of feature toggles available that act as short-circuits for common configurations.
 	// If any are set, override the config accordingly.
 	ApplyStateHistoryFeatureToggles(&ng.Cfg.UnifiedAlerting.StateHistory, ng.FeatureToggles, ng.Log)
@@ -392,6 +403,13 @@ func (ng *AlertNG) init() error {
 		Tracer:                         ng.tracer,
 		Log:                            log.New("ngalert.state.manager"),
 		ResolvedRetention:              ng.Cfg.UnifiedAlerting.ResolvedAlertRetention,
+		FlapDetection: state.FlapDetectionConfig{
+			Enabled:               ng.Cfg.UnifiedAlerting.FlapDetection.Enabled,
+			MinEvaluations:        ng.Cfg.UnifiedAlerting.FlapDetection.MinEvaluations,
+			HighThreshold:         ng.Cfg.UnifiedAlerting.FlapDetection.HighThreshold,
+			LowThreshold:          ng.Cfg.UnifiedAlerting.FlapDetection.LowThreshold,
+			SuppressNotifications: ng.Cfg.UnifiedAlerting.FlapDetection.SuppressNotifications,
+		},
 	}
 	logger := log.New("ngalert.state.manager.persist")
 	statePersister := state.NewSyncStatePersisiter(logger, cfg)
@@ -453,6 +471,7 @@ func (ng *AlertNG) init() error {
 		ProvenanceStore:      ng.store,
 		MultiOrgAlertmanager: ng.MultiOrgAlertmanager,
 		StateManager:         ng.stateManager,
+		RuleShardingEnabled:  schedCfg.ClusterMembership != nil,
 		AccessControl:        ng.accesscontrol,
 		Policies:             policyService,
 		ReceiverService:      receiverService,
@@ -664,12 +683,12 @@ func createRemoteAlertmanager(cfg remote.AlertmanagerConfig, kvstore kvstore.KVS
 	return remote.NewAlertmanager(cfg, notifier.NewFileStore(cfg.OrgID, kvstore), decryptFn, autogenFn, m, tracer)
 }
 
-func createRecordingWriter(featureToggles featuremgmt.FeatureToggles, settings setting.RecordingRuleSettings, httpClientProvider httpclient.Provider, clock clock.Clock, m *metrics.RemoteWriter) (schedule.RecordingWriter, error) {
+func createRecordingWriter(featureToggles featuremgmt.FeatureToggles, settings setting.RecordingRuleSettings, httpClientProvider httpclient.Provider, store db.DB, clock clock.Clock, m *metrics.RemoteWriter) (schedule.RecordingWriter, error) {
 	logger := log.New("ngalert.writer")
 
 	if featureToggles.IsEnabledGlobally(featuremgmt.FlagGrafanaManagedRecordingRules) {
-		return writer.NewPrometheusWriter(settings, httpClientProvider, clock, logger, m)
+		return writer.NewRegistryFromSettings(settings, httpClientProvider, store, clock, logger, m)
 	}
 
-	return writer.NoopWriter{}, nil
+	return writer.NoopRegistry{}, nil
 }
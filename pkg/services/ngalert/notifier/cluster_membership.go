@@ -0,0 +1,50 @@
+package notifier
+
+import (
+	alertingCluster "github.com/grafana/alerting/cluster"
+)
+
+// ClusterMembership provides the members of the high availability cluster of the Alertmanagers.
+// It is backed by the memberlist or the Redis peer, so other components can share the cluster.
+type ClusterMembership struct {
+	members func() []string
+	self    func() string
+}
+
+// Members returns the names of the active members of the cluster, including this instance.
+func (m *ClusterMembership) Members() []string {
+	return m.members()
+}
+
+// Self returns the name of this instance in the cluster.
+func (m *ClusterMembership) Self() string {
+	return m.self()
+}
+
+// ClusterMembership returns the membership of the high availability cluster.
+// It returns nil if clustering is not enabled.
+func (moa *MultiOrgAlertmanager) ClusterMembership() *ClusterMembership {
+	switch p := moa.peer.(type) {
+	case *alertingCluster.Peer:
+		return &ClusterMembership{
+			members: func() []string {
+				peers := p.Peers()
+				names := make([]string, 0, len(peers))
+				for _, peer := range peers {
+					names = append(names, peer.Name())
+				}
+				return names
+			},
+			self: p.Name,
+		}
+	case *redisPeer:
+		return &ClusterMembership{
+			members: p.Members,
+			self: func() string {
+				return p.withPrefix(p.name)
+			},
+		}
+	default:
+		return nil
+	}
+}
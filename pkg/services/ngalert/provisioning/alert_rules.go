@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
@@ -185,17 +186,19 @@ func (service *AlertRuleService) CreateAlertRule(ctx context.Context, user ident
 		return models.AlertRule{}, errors.Join(models.ErrAlertRuleFailedValidation, fmt.Errorf("cannot create rule with UID '%s': %w", rule.UID, err))
 	}
 	var interval = service.defaultIntervalSeconds
+	var existingGroup models.RulesGroup
 	// check if user can bypass fine-grained rule authorization checks. If it cannot, verfiy that the user can add rules to the group
 	canWriteAllRules, err := service.authz.CanWriteAllRules(ctx, user)
 	if err != nil {
 		return models.AlertRule{}, err
 	}
 	if canWriteAllRules {
-		groupInterval, err := service.ruleStore.GetRuleGroupInterval(ctx, rule.OrgID, rule.NamespaceUID, rule.RuleGroup)
-		// if the alert group does not exist we just use the default interval
-		if err == nil {
-			interval = groupInterval
-		} else if !errors.Is(err, models.ErrAlertRuleGroupNotFound) {
+		existingGroup, err = service.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{
+			OrgID:         rule.OrgID,
+			NamespaceUIDs: []string{rule.NamespaceUID},
+			RuleGroups:    []string{rule.RuleGroup},
+		})
+		if err != nil {
 			return models.AlertRule{}, err
 		}
 	} else {
@@ -206,12 +209,22 @@ func (service *AlertRuleService) CreateAlertRule(ctx context.Context, user ident
 		if err := service.authz.AuthorizeRuleGroupWrite(ctx, user, delta); err != nil {
 			return models.AlertRule{}, err
 		}
-		existingGroup := delta.AffectedGroups[rule.GetGroupKey()]
-		if len(existingGroup) > 0 {
-			interval = existingGroup[0].IntervalSeconds
+		existingGroup = delta.AffectedGroups[rule.GetGroupKey()]
+	}
+	// if the alert group does not exist we just use the default interval
+	if len(existingGroup) > 0 {
+		existingGroup.SortByGroupIndex()
+		interval = existingGroup[0].IntervalSeconds
+		rule.SequentialEvaluation = existingGroup[0].SequentialEvaluation
+		if rule.SequentialEvaluation && rule.RuleGroupIndex == 0 {
+			// the rule is evaluated after all existing rules of the group
+			rule.RuleGroupIndex = existingGroup[len(existingGroup)-1].RuleGroupIndex + 1
 		}
 	}
 	rule.IntervalSeconds = interval
+	if err := models.ValidateRuleGroupDependencies(append(slices.Clone(existingGroup), &rule)); err != nil {
+		return models.AlertRule{}, err
+	}
 	err = rule.SetDashboardAndPanelFromAnnotations()
 	if err != nil {
 		return models.AlertRule{}, err
@@ -286,10 +299,11 @@ func (service *AlertRuleService) GetRuleGroup(ctx context.Context, user identity
 		}
 	}
 	res := models.AlertRuleGroup{
-		Title:     ruleList[0].RuleGroup,
-		FolderUID: ruleList[0].NamespaceUID,
-		Interval:  ruleList[0].IntervalSeconds,
-		Rules:     make([]models.AlertRule, 0, len(ruleList)),
+		Title:                ruleList[0].RuleGroup,
+		FolderUID:            ruleList[0].NamespaceUID,
+		Interval:             ruleList[0].IntervalSeconds,
+		Rules:                make([]models.AlertRule, 0, len(ruleList)),
+		SequentialEvaluation: ruleList[0].SequentialEvaluation,
 	}
 	for _, r := range ruleList {
 		if r != nil {
@@ -299,8 +313,8 @@ func (service *AlertRuleService) GetRuleGroup(ctx context.Context, user identity
 	return res, nil
 }
 
-// UpdateRuleGroup will update the interval for all rules in the group.
-func (service *AlertRuleService) UpdateRuleGroup(ctx context.Context, user identity.Requester, namespaceUID string, ruleGroup string, intervalSeconds int64) error {
+// UpdateRuleGroup will update the interval and the evaluation mode for all rules in the group.
+func (service *AlertRuleService) UpdateRuleGroup(ctx context.Context, user identity.Requester, namespaceUID string, ruleGroup string, intervalSeconds int64, sequential bool) error {
 	if err := models.ValidateRuleGroupInterval(intervalSeconds, service.baseIntervalSeconds); err != nil {
 		return err
 	}
@@ -316,11 +330,12 @@ func (service *AlertRuleService) UpdateRuleGroup(ctx context.Context, user ident
 		}
 		updateRules := make([]models.UpdateRule, 0, len(ruleList))
 		for _, rule := range ruleList {
-			if rule.IntervalSeconds == intervalSeconds {
+			if rule.IntervalSeconds == intervalSeconds && rule.SequentialEvaluation == sequential {
 				continue
 			}
 			newRule := *rule
 			newRule.IntervalSeconds = intervalSeconds
+			newRule.SequentialEvaluation = sequential
 			updateRules = append(updateRules, models.UpdateRule{
 				Existing: rule,
 				New:      newRule,
@@ -442,6 +457,10 @@ func (service *AlertRuleService) calcDelta(ctx context.Context, user identity.Re
 		if err != nil {
 			return nil, fmt.Errorf("failed to list alert rules: %w", err)
 		}
+		ruleList.SortByGroupIndex()
+		if len(ruleList) > 0 {
+			group.SequentialEvaluation = ruleList[0].SequentialEvaluation
+		}
 		group.Rules = make([]models.AlertRule, 0, len(ruleList))
 		for _, r := range ruleList {
 			if r != nil {
@@ -461,11 +480,16 @@ func (service *AlertRuleService) calcDelta(ctx context.Context, user identity.Re
 	}
 	rules := make([]*models.AlertRuleWithOptionals, 0, len(group.Rules))
 	group = *syncGroupRuleFields(&group, user.GetOrgID())
+	dependencies := make([]*models.AlertRule, 0, len(group.Rules))
 	for i := range group.Rules {
 		if err := group.Rules[i].SetDashboardAndPanelFromAnnotations(); err != nil {
 			return nil, err
 		}
 		rules = append(rules, &models.AlertRuleWithOptionals{AlertRule: group.Rules[i], HasPause: true})
+		dependencies = append(dependencies, &group.Rules[i])
+	}
+	if err := models.ValidateRuleGroupDependencies(dependencies); err != nil {
+		return nil, err
 	}
 	delta, err := store.CalculateChanges(ctx, service.ruleStore, key, rules)
 	if err != nil {
@@ -598,6 +622,16 @@ func (service *AlertRuleService) UpdateAlertRule(ctx context.Context, user ident
 	rule.Updated = time.Now()
 	rule.ID = storedRule.ID
 	rule.IntervalSeconds = storedRule.IntervalSeconds
+	rule.SequentialEvaluation = storedRule.SequentialEvaluation
+	if rule.SequentialEvaluation && rule.RuleGroupIndex == 0 {
+		// keep the position of the rule in the group, as it defines the order of evaluation
+		rule.RuleGroupIndex = storedRule.RuleGroupIndex
+	}
+	if rule.DependsOn != "" {
+		if err := service.validateRuleDependency(ctx, rule); err != nil {
+			return models.AlertRule{}, err
+		}
+	}
 	err = rule.SetDashboardAndPanelFromAnnotations()
 	if err != nil {
 		return models.AlertRule{}, err
@@ -795,6 +829,33 @@ func (service *AlertRuleService) GetAlertGroupsWithFolderFullpath(ctx context.Co
 	return result, nil
 }
 
+// validateRuleDependency checks that the rule a single rule depends on precedes it in its group.
+func (service *AlertRuleService) validateRuleDependency(ctx context.Context, rule models.AlertRule) error {
+	ruleList, err := service.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{
+		OrgID:         rule.OrgID,
+		NamespaceUIDs: []string{rule.NamespaceUID},
+		RuleGroups:    []string{rule.RuleGroup},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list alert rules: %w", err)
+	}
+	ruleList.SortByGroupIndex()
+	group := make([]*models.AlertRule, 0, len(ruleList)+1)
+	found := false
+	for _, r := range ruleList {
+		if r.UID == rule.UID {
+			r = &rule
+			found = true
+		}
+		group = append(group, r)
+	}
+	if !found {
+		// the rule is moved to another group, where it is added at the end.
+		group = append(group, &rule)
+	}
+	return models.ValidateRuleGroupDependencies(group)
+}
+
 // syncRuleGroupFields synchronizes calculated fields across multiple rules in a group.
 func syncGroupRuleFields(group *models.AlertRuleGroup, orgID int64) *models.AlertRuleGroup {
 	for i := range group.Rules {
@@ -802,6 +863,11 @@ func syncGroupRuleFields(group *models.AlertRuleGroup, orgID int64) *models.Aler
 		group.Rules[i].RuleGroup = group.Title
 		group.Rules[i].NamespaceUID = group.FolderUID
 		group.Rules[i].OrgID = orgID
+		group.Rules[i].SequentialEvaluation = group.SequentialEvaluation
+		if group.SequentialEvaluation {
+			// the rules of a sequential group are evaluated in the order they are defined.
+			group.Rules[i].RuleGroupIndex = i + 1
+		}
 	}
 	return group
 }
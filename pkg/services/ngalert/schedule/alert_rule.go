@@ -337,6 +337,9 @@ func (a *alertRule) Run() error {
 					}
 				}
 			}()
+			if ctx.afterEval != nil {
+				ctx.afterEval()
+			}
 
 		case <-grafanaCtx.Done():
 			// clean up the state only if the reason for stopping the evaluation loop is that the rule was deleted
@@ -349,6 +352,10 @@ func (a *alertRule) Run() error {
 				states := a.stateManager.DeleteStateByRuleUID(ngmodels.WithRuleKey(ctx, a.key), a.key, ngmodels.StateReasonRuleDeleted)
 				a.expireAndSend(grafanaCtx, states)
 			}
+			// the rule is handed over to another instance, which continues from the state saved in the database
+			if errors.Is(grafanaCtx.Err(), errRuleNotOwned) {
+				a.stateManager.ForgetStateByRuleUID(ngmodels.WithRuleKey(context.Background(), a.key), a.key)
+			}
 			a.logger.Debug("Stopping alert rule routine")
 			return nil
 		}
@@ -439,6 +446,10 @@ func (a *alertRule) evaluate(ctx context.Context, e *Evaluation, span trace.Span
 		results,
 		state.GetRuleExtraLabels(logger, e.rule, e.folderTitle, !a.disableGrafanaFolder),
 		func(ctx context.Context, statesToSend state.StateTransitions) {
+			if a.dependencyFiring(e.rule) {
+				logger.Debug("Suppressing firing alerts because the rule it depends on is firing", "dependsOn", e.rule.DependsOn)
+				statesToSend = withoutFiring(statesToSend)
+			}
 			start := a.clock.Now()
 			alerts := a.send(ctx, logger, statesToSend)
 			span.AddEvent("results sent", trace.WithAttributes(
@@ -452,6 +463,32 @@ func (a *alertRule) evaluate(ctx context.Context, e *Evaluation, span trace.Span
 	return nil
 }
 
+// dependencyFiring returns true if the alert rule the rule depends on has firing alerts.
+func (a *alertRule) dependencyFiring(rule *ngmodels.AlertRule) bool {
+	if rule.DependsOn == "" {
+		return false
+	}
+	for _, s := range a.stateManager.GetStatesForRuleUID(rule.OrgID, rule.DependsOn) {
+		if s.State == eval.Alerting {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutFiring returns the transitions that are not firing. Resolved alerts are still sent, so the alerts sent before
+// the rule the rule depends on started firing are resolved.
+func withoutFiring(states state.StateTransitions) state.StateTransitions {
+	result := make(state.StateTransitions, 0, len(states))
+	for _, s := range states {
+		if s.State.State == eval.Alerting {
+			continue
+		}
+		result = append(result, s)
+	}
+	return result
+}
+
 // send sends alerts for the given state transitions.
 func (a *alertRule) send(ctx context.Context, logger log.Logger, states state.StateTransitions) definitions.PostableAlerts {
 	alerts := definitions.PostableAlerts{PostableAlerts: make([]models.PostableAlert, 0, len(states))}
@@ -738,6 +738,55 @@ func TestRuleRoutine(t *testing.T) {
 		})
 	})
 
+	t.Run("when the rule it depends on is firing it should not send firing alerts", func(t *testing.T) {
+		upstream := gen.GenerateRef()
+		rule := gen.With(withQueryForState(t, eval.Alerting), gen.WithOrgID(upstream.OrgID), gen.WithDependsOn(upstream.UID)).GenerateRef()
+
+		evalAppliedChan := make(chan time.Time)
+
+		sender := NewSyncAlertsSenderMock()
+		sender.EXPECT().Send(mock.Anything, rule.GetKey(), mock.Anything).Return()
+
+		sch, ruleStore, _, _ := createSchedule(evalAppliedChan, sender)
+		ruleStore.PutRule(context.Background(), rule)
+		sch.stateManager.Put([]*state.State{
+			stateForRule(upstream, sch.clock.Now(), eval.Alerting),
+		})
+		factory := ruleFactoryFromScheduler(sch)
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		ruleInfo := factory.new(ctx, rule)
+
+		go func() {
+			_ = ruleInfo.Run()
+		}()
+
+		ruleInfo.Eval(&Evaluation{
+			scheduledAt: sch.clock.Now(),
+			rule:        rule,
+		})
+		waitForTimeChannel(t, evalAppliedChan)
+
+		sender.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+		states := sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID)
+		require.Len(t, states, 1)
+		require.Equal(t, eval.Alerting, states[0].State)
+
+		// the firing alert is sent once the rule it depends on stops firing
+		sch.stateManager.ForgetStateByRuleUID(ctx, upstream.GetKey())
+		sch.stateManager.ResendDelay = 0
+		ruleInfo.Eval(&Evaluation{
+			scheduledAt: sch.clock.Now().Add(time.Duration(rule.IntervalSeconds) * time.Second),
+			rule:        rule,
+		})
+		waitForTimeChannel(t, evalAppliedChan)
+
+		sender.AssertNumberOfCalls(t, "Send", 1)
+		args, ok := sender.Calls()[0].Arguments[2].(definitions.PostableAlerts)
+		require.Truef(t, ok, fmt.Sprintf("expected argument of function was supposed to be 'definitions.PostableAlerts' but got %T", sender.Calls()[0].Arguments[2]))
+		require.Len(t, args.PostableAlerts, 1)
+	})
+
 	t.Run("when there are no alerts to send it should not call notifiers", func(t *testing.T) {
 		rule := gen.With(withQueryForState(t, eval.Normal)).GenerateRef()
 
@@ -40,6 +40,10 @@ func jitterOffsetInTicks(r *ngmodels.AlertRule, baseInterval time.Duration, stra
 	if strategy == JitterNever {
 		return 0
 	}
+	// The rules of a sequential group must be ready to run at the same tick, so they can be chained.
+	if strategy == JitterByRule && r.SequentialEvaluation {
+		strategy = JitterByGroup
+	}
 
 	itemFrequency := r.IntervalSeconds / int64(baseInterval.Seconds())
 	offset := jitterHash(r, strategy) % uint64(itemFrequency)
@@ -91,5 +91,14 @@ func TestJitter(t *testing.T) {
 				require.Less(t, offset, upperLimit, "offset cannot be equal to or greater than interval/baseInterval of %d", upperLimit)
 			}
 		})
+
+		t.Run("offset for rules of a sequential group is the offset of the group", func(t *testing.T) {
+			baseInterval := 10 * time.Second
+			rules := gen.With(gen.WithInterval(1*time.Hour), gen.WithGroupKey(ngmodels.AlertRuleGroupKey{}), gen.WithSequentialEvaluation(true)).GenerateManyRef(100)
+
+			for _, r := range rules {
+				require.Equal(t, jitterOffsetInTicks(r, baseInterval, JitterByGroup), jitterOffsetInTicks(r, baseInterval, JitterByRule))
+			}
+		})
 	})
 }
@@ -18,6 +18,7 @@ import (
 var (
 	errRuleDeleted   = errors.New("rule deleted")
 	errRuleRestarted = errors.New("rule restarted")
+	errRuleNotOwned  = errors.New("rule is evaluated by another instance")
 )
 
 type ruleFactory interface {
@@ -88,6 +89,8 @@ type Evaluation struct {
 	scheduledAt time.Time
 	rule        *models.AlertRule
 	folderTitle string
+	// afterEval is called when the evaluation is done. It starts the evaluation of the next rule of a sequential group.
+	afterEval func()
 }
 
 func (e *Evaluation) Fingerprint() fingerprint {
@@ -246,10 +246,12 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 		}
 
 		excludedFields := map[string]struct{}{
-			"Version":         {},
-			"Updated":         {},
-			"IntervalSeconds": {},
-			"Annotations":     {},
+			"Version":              {},
+			"Updated":              {},
+			"IntervalSeconds":      {},
+			"Annotations":          {},
+			"SequentialEvaluation": {},
+			"DependsOn":            {},
 		}
 
 		tp := reflect.TypeOf(rule).Elem()
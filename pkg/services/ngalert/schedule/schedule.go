@@ -2,7 +2,6 @@ package schedule
 
 import (
 	"context"
-	"fmt"
 	"net/url"
 	"slices"
 	"strings"
@@ -11,8 +10,6 @@ import (
 	"github.com/benbjohnson/clock"
 	"golang.org/x/sync/errgroup"
 
-	"github.com/grafana/grafana-plugin-sdk-go/data"
-
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
@@ -21,6 +18,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/writer"
 	"github.com/grafana/grafana/pkg/util/ticker"
 )
 
@@ -48,8 +46,9 @@ type RulesStore interface {
 	GetAlertRulesForScheduling(ctx context.Context, query *ngmodels.GetAlertRulesForSchedulingQuery) error
 }
 
+// RecordingWriter selects the writer of a recording rule.
 type RecordingWriter interface {
-	Write(ctx context.Context, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error
+	Writer(orgID int64, writerType string) (writer.Writer, error)
 }
 
 type schedule struct {
@@ -104,6 +103,11 @@ type schedule struct {
 	tracer tracing.Tracer
 
 	recordingWriter RecordingWriter
+
+	// sharder decides which rules are evaluated by this instance if the rules are sharded across the HA cluster.
+	sharder *ruleSharder
+	// disownedRules contains the rules that are evaluated by other instances of the HA cluster.
+	disownedRules map[ngmodels.AlertRuleKey]struct{}
 }
 
 // SchedulerCfg is the scheduler configuration.
@@ -123,6 +127,8 @@ type SchedulerCfg struct {
 	Tracer               tracing.Tracer
 	Log                  log.Logger
 	RecordingWriter      RecordingWriter
+	// ClusterMembership shards the rules across the members of the HA cluster. If nil, all rules are evaluated.
+	ClusterMembership ClusterMembership
 }
 
 // NewScheduler returns a new scheduler.
@@ -153,6 +159,11 @@ func NewScheduler(cfg SchedulerCfg, stateManager *state.Manager) *schedule {
 		alertsSender:                       cfg.AlertSender,
 		tracer:                             cfg.Tracer,
 		recordingWriter:                    cfg.RecordingWriter,
+		disownedRules:                      make(map[ngmodels.AlertRuleKey]struct{}),
+	}
+
+	if cfg.ClusterMembership != nil {
+		sch.sharder = newRuleSharder(cfg.ClusterMembership, cfg.Log)
 	}
 
 	return &sch
@@ -274,9 +285,30 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		sch.evalAppliedFunc,
 		sch.stopAppliedFunc,
 	)
+	if sch.sharder != nil {
+		sch.sharder.refresh()
+	}
+	disownedRules := make([]Rule, 0)
+	chainedGroups := chainedRuleGroups(alertRules)
 	for _, item := range alertRules {
-		ruleRoutine, newRoutine := sch.registry.getOrCreate(ctx, item, ruleFactory)
 		key := item.GetKey()
+		if sch.sharder != nil && !sch.sharder.owns(shardKey(item, chainedGroups)) {
+			// The rule is evaluated by another instance. Its state is kept in the database by that instance.
+			if ruleRoutine, ok := sch.registry.del(key); ok {
+				disownedRules = append(disownedRules, ruleRoutine)
+			} else if _, ok := sch.disownedRules[key]; !ok {
+				// The state was loaded when the state cache was warmed up.
+				sch.stateManager.ForgetStateByRuleUID(ctx, key)
+			}
+			sch.disownedRules[key] = struct{}{}
+			delete(registeredDefinitions, key)
+			continue
+		}
+		// The rule was evaluated by another instance, continue from the state it saved.
+		_, restoreState := sch.disownedRules[key]
+		delete(sch.disownedRules, key)
+
+		ruleRoutine, newRoutine := sch.registry.getOrCreate(ctx, item, ruleFactory)
 		logger := sch.log.FromContext(ctx).New(key.LogContext()...)
 
 		// enforce minimum evaluation interval
@@ -297,6 +329,11 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 
 		if newRoutine && !invalidInterval {
 			dispatcherGroup.Go(func() error {
+				if restoreState && item.Type() == ngmodels.RuleTypeAlerting {
+					if err := sch.stateManager.RestoreStateByRuleUID(ngmodels.WithRuleKey(ctx, key), item); err != nil {
+						logger.Error("Failed to restore the state of the rule handed over by another instance", "error", err)
+					}
+				}
 				return ruleRoutine.Run()
 			})
 		}
@@ -353,29 +390,20 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		sch.log.Warn("Unable to obtain folder titles for some rules", "missingFolderUIDToRuleUID", missingFolder)
 	}
 
-	var step int64 = 0
-	if len(readyToRun) > 0 {
-		step = sch.baseInterval.Nanoseconds() / int64(len(readyToRun))
-	}
-
 	slices.SortFunc(readyToRun, func(a, b readyToRunItem) int {
 		return strings.Compare(a.rule.UID, b.rule.UID)
 	})
-	for i := range readyToRun {
-		item := readyToRun[i]
+	sequences := buildSequences(readyToRun, sch.runJob)
+	var step int64 = 0
+	if len(sequences) > 0 {
+		step = sch.baseInterval.Nanoseconds() / int64(len(sequences))
+	}
+
+	for i := range sequences {
+		item := sequences[i]
 
 		time.AfterFunc(time.Duration(int64(i)*step), func() {
-			key := item.rule.GetKey()
-			success, dropped := item.ruleRoutine.Eval(&item.Evaluation)
-			if !success {
-				sch.log.Debug("Scheduled evaluation was canceled because evaluation routine was stopped", append(key.LogContext(), "time", tick)...)
-				return
-			}
-			if dropped != nil {
-				sch.log.Warn("Tick dropped because alert rule evaluation is too slow", append(key.LogContext(), "time", tick, "droppedTick", dropped.scheduledAt)...)
-				orgID := fmt.Sprint(key.OrgID)
-				sch.metrics.EvaluationMissed.WithLabelValues(orgID, item.rule.Title).Inc()
-			}
+			sch.runJob(item)
 		})
 	}
 
@@ -384,6 +412,16 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		oldRoutine.Stop(errRuleRestarted)
 	}
 
+	// Stop routines for rules that are now evaluated by another instance.
+	for _, oldRoutine := range disownedRules {
+		oldRoutine.Stop(errRuleNotOwned)
+	}
+	for key := range sch.disownedRules {
+		if sch.schedulableAlertRules.get(key) == nil {
+			delete(sch.disownedRules, key)
+		}
+	}
+
 	// unregister and stop routines of the deleted alert rules
 	toDelete := make([]ngmodels.AlertRuleKey, 0, len(registeredDefinitions))
 	for key := range registeredDefinitions {
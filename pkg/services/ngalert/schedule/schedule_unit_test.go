@@ -856,7 +856,7 @@ func setupScheduler(t *testing.T, rs *fakeRulesStore, is *state.FakeInstanceStor
 		MaxAttempts:  1,
 	}
 
-	fakeRecordingWriter := writer.FakeWriter{}
+	fakeRecordingWriter := writer.FakeRegistry{}
 
 	schedCfg := SchedulerCfg{
 		BaseInterval:     cfg.BaseInterval,
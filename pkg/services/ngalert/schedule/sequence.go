@@ -0,0 +1,82 @@
+package schedule
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// buildSequences returns the items that start the evaluations of the tick. The rules of a sequential group are chained
+// in the order of their index: only the first rule of the group is returned, and each rule starts the evaluation
+// of the next one when it is done, so a rule can use the results of the rules that precede it in the same tick.
+func buildSequences(items []readyToRunItem, runJob func(readyToRunItem)) []readyToRunItem {
+	result := make([]readyToRunItem, 0, len(items))
+	groups := make(map[ngmodels.AlertRuleGroupKey][]readyToRunItem)
+	for _, item := range items {
+		if !item.rule.SequentialEvaluation {
+			result = append(result, item)
+			continue
+		}
+		key := item.rule.GetGroupKey()
+		groups[key] = append(groups[key], item)
+	}
+
+	for _, group := range groups {
+		slices.SortFunc(group, func(a, b readyToRunItem) int {
+			if c := cmp.Compare(a.rule.RuleGroupIndex, b.rule.RuleGroupIndex); c != 0 {
+				return c
+			}
+			return strings.Compare(a.rule.UID, b.rule.UID)
+		})
+		for i := len(group) - 2; i >= 0; i-- {
+			next := group[i+1]
+			group[i].afterEval = func() {
+				// do not block the routine of the rule that is done.
+				go runJob(next)
+			}
+		}
+		result = append(result, group[0])
+	}
+
+	slices.SortFunc(result, func(a, b readyToRunItem) int {
+		return strings.Compare(a.rule.UID, b.rule.UID)
+	})
+	return result
+}
+
+// runJob sends the evaluation to the rule routine. If the routine is stopped, it continues with the next rule of the sequence.
+func (sch *schedule) runJob(item readyToRunItem) {
+	key := item.rule.GetKey()
+	success, dropped := item.ruleRoutine.Eval(&item.Evaluation)
+	if !success {
+		sch.log.Debug("Scheduled evaluation was canceled because evaluation routine was stopped", append(key.LogContext(), "time", item.scheduledAt)...)
+		if item.afterEval != nil {
+			item.afterEval()
+		}
+		return
+	}
+	if dropped != nil {
+		sch.log.Warn("Tick dropped because alert rule evaluation is too slow", append(key.LogContext(), "time", item.scheduledAt, "droppedTick", dropped.scheduledAt)...)
+		orgID := fmt.Sprint(key.OrgID)
+		sch.metrics.EvaluationMissed.WithLabelValues(orgID, item.rule.Title).Inc()
+		// the dropped evaluation will never run, continue its sequence with the next rule.
+		if dropped.afterEval != nil {
+			dropped.afterEval()
+		}
+	}
+}
+
+// chainedRuleGroups returns the rule groups whose rules are evaluated sequentially or depend on each other.
+// The rules of such groups must be evaluated by the same instance.
+func chainedRuleGroups(rules []*ngmodels.AlertRule) map[ngmodels.AlertRuleGroupKey]struct{} {
+	result := make(map[ngmodels.AlertRuleGroupKey]struct{})
+	for _, rule := range rules {
+		if rule.SequentialEvaluation || rule.DependsOn != "" {
+			result[rule.GetGroupKey()] = struct{}{}
+		}
+	}
+	return result
+}
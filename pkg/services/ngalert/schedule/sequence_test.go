@@ -0,0 +1,133 @@
+package schedule
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestBuildSequences(t *testing.T) {
+	gen := models.RuleGen
+	groupKey := models.GenerateGroupKey(1)
+	sequential := gen.With(gen.WithGroupKey(groupKey), gen.WithSequentialEvaluation(true), gen.WithSequentialGroupIndex()).GenerateManyRef(5)
+	independent := gen.With(gen.WithOrgID(1)).GenerateManyRef(3)
+
+	var items []readyToRunItem
+	// add the rules of the group in reverse order to make sure they are chained by index
+	for i := len(sequential) - 1; i >= 0; i-- {
+		items = append(items, readyToRunItem{Evaluation: Evaluation{rule: sequential[i]}})
+	}
+	for _, rule := range independent {
+		items = append(items, readyToRunItem{Evaluation: Evaluation{rule: rule}})
+	}
+
+	started := make(chan readyToRunItem, len(items))
+	result := buildSequences(items, func(item readyToRunItem) {
+		started <- item
+	})
+
+	t.Run("returns independent rules and the first rule of each sequential group", func(t *testing.T) {
+		require.Len(t, result, len(independent)+1)
+		var first *readyToRunItem
+		for i, item := range result {
+			if item.rule.SequentialEvaluation {
+				first = &result[i]
+				continue
+			}
+			assert.Nil(t, item.afterEval)
+		}
+		require.NotNil(t, first)
+		require.Equal(t, sequential[0].UID, first.rule.UID)
+	})
+
+	t.Run("each rule of the group starts the next one", func(t *testing.T) {
+		var current readyToRunItem
+		for _, item := range result {
+			if item.rule.SequentialEvaluation {
+				current = item
+			}
+		}
+		for _, expected := range sequential[1:] {
+			require.NotNil(t, current.afterEval)
+			current.afterEval()
+			current = <-started
+			require.Equal(t, expected.UID, current.rule.UID)
+		}
+		require.Nil(t, current.afterEval, "last rule of the group should not start anything")
+	})
+}
+
+func TestRunJob(t *testing.T) {
+	gen := models.RuleGen
+	sch := &schedule{
+		log:     log.NewNopLogger(),
+		metrics: metrics.NewSchedulerMetrics(prometheus.NewRegistry()),
+	}
+
+	t.Run("starts the next rule of the sequence of a dropped evaluation", func(t *testing.T) {
+		rule := gen.GenerateRef()
+		afterDropped := 0
+		dropped := &Evaluation{rule: rule, afterEval: func() { afterDropped++ }}
+		afterEval := 0
+		sch.runJob(readyToRunItem{
+			ruleRoutine: &fakeEvalRule{success: true, dropped: dropped},
+			Evaluation:  Evaluation{rule: rule, afterEval: func() { afterEval++ }},
+		})
+		require.Equal(t, 1, afterDropped)
+		require.Zero(t, afterEval, "the evaluation that was sent should start the next rule when it is done")
+	})
+
+	t.Run("starts the next rule of the sequence when the routine is stopped", func(t *testing.T) {
+		rule := gen.GenerateRef()
+		afterEval := 0
+		sch.runJob(readyToRunItem{
+			ruleRoutine: &fakeEvalRule{success: false},
+			Evaluation:  Evaluation{rule: rule, afterEval: func() { afterEval++ }},
+		})
+		require.Equal(t, 1, afterEval)
+	})
+}
+
+// fakeEvalRule is a rule that returns the configured result of Eval.
+type fakeEvalRule struct {
+	success bool
+	dropped *Evaluation
+}
+
+func (r *fakeEvalRule) Run() error { return nil }
+
+func (r *fakeEvalRule) Stop(error) {}
+
+func (r *fakeEvalRule) Eval(*Evaluation) (bool, *Evaluation) { return r.success, r.dropped }
+
+func (r *fakeEvalRule) Update(RuleVersionAndPauseStatus) bool { return true }
+
+func (r *fakeEvalRule) Type() models.RuleType { return models.RuleTypeAlerting }
+
+func TestChainedRuleGroups(t *testing.T) {
+	gen := models.RuleGen
+	sequential := gen.With(gen.WithGroupKey(models.GenerateGroupKey(1)), gen.WithSequentialEvaluation(true)).GenerateManyRef(2)
+	independent := gen.With(gen.WithGroupKey(models.GenerateGroupKey(1))).GenerateManyRef(2)
+	dependentGroup := gen.With(gen.WithGroupKey(models.GenerateGroupKey(1))).GenerateManyRef(2)
+	dependentGroup[1].DependsOn = dependentGroup[0].UID
+
+	rules := append(append(append([]*models.AlertRule{}, sequential...), independent...), dependentGroup...)
+	result := chainedRuleGroups(rules)
+
+	require.Len(t, result, 2)
+	require.Contains(t, result, sequential[0].GetGroupKey())
+	require.Contains(t, result, dependentGroup[0].GetGroupKey())
+	require.NotContains(t, result, independent[0].GetGroupKey())
+
+	t.Run("rules of chained groups share the shard key", func(t *testing.T) {
+		require.Equal(t, shardKey(sequential[0], result), shardKey(sequential[1], result))
+		require.Equal(t, shardKey(dependentGroup[0], result), shardKey(dependentGroup[1], result))
+		require.NotEqual(t, shardKey(independent[0], result), shardKey(independent[1], result))
+	})
+}
@@ -0,0 +1,149 @@
+package schedule
+
+import (
+	"hash/fnv"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// ringTokensPerMember is the number of tokens each member gets on the hash ring.
+// More tokens spread the rules more evenly between the members.
+const ringTokensPerMember = 128
+
+// ClusterMembership provides the members of the high availability cluster the rules are sharded across.
+type ClusterMembership interface {
+	// Members returns the names of the active members of the cluster, including this instance.
+	Members() []string
+	// Self returns the name of this instance in the cluster.
+	Self() string
+}
+
+type ringToken struct {
+	hash   uint64
+	member string
+}
+
+// hashRing assigns alert rules to members with consistent hashing, so a membership change only moves
+// the rules of the members that joined or left.
+type hashRing struct {
+	tokens []ringToken
+}
+
+func newHashRing(members []string) *hashRing {
+	tokens := make([]ringToken, 0, len(members)*ringTokensPerMember)
+	for _, m := range members {
+		for i := 0; i < ringTokensPerMember; i++ {
+			tokens = append(tokens, ringToken{hash: ringHash(m + "#" + strconv.Itoa(i)), member: m})
+		}
+	}
+	slices.SortFunc(tokens, func(a, b ringToken) int {
+		if a.hash != b.hash {
+			if a.hash < b.hash {
+				return -1
+			}
+			return 1
+		}
+		// members can collide on a token, make the order deterministic.
+		if a.member < b.member {
+			return -1
+		}
+		if a.member > b.member {
+			return 1
+		}
+		return 0
+	})
+	return &hashRing{tokens: tokens}
+}
+
+// owner returns the member the key is assigned to, that is the member of the first token at or after the hash of the key.
+func (r *hashRing) owner(key string) string {
+	if len(r.tokens) == 0 {
+		return ""
+	}
+	h := ringHash(key)
+	i := sort.Search(len(r.tokens), func(i int) bool {
+		return r.tokens[i].hash >= h
+	})
+	if i == len(r.tokens) {
+		i = 0
+	}
+	return r.tokens[i].member
+}
+
+// ringHash hashes the string with FNV-1a and mixes the bits, as FNV-1a of similar strings differs mostly in the low bits.
+func ringHash(s string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(s))
+	x := h.Sum64()
+	// finalizer of MurmurHash3
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
+
+// ruleSharder decides which rules are evaluated by this instance.
+// If this instance is not a member of the cluster, for example while the cluster settles, it evaluates all rules,
+// as evaluating a rule twice is better than not evaluating it at all.
+type ruleSharder struct {
+	membership ClusterMembership
+	logger     log.Logger
+
+	mtx     sync.RWMutex
+	self    string
+	members []string
+	ring    *hashRing
+}
+
+func newRuleSharder(membership ClusterMembership, logger log.Logger) *ruleSharder {
+	return &ruleSharder{
+		membership: membership,
+		logger:     logger,
+	}
+}
+
+// refresh rebuilds the hash ring if the members of the cluster changed, and reports whether they changed.
+func (s *ruleSharder) refresh() bool {
+	self := s.membership.Self()
+	members := slices.Clone(s.membership.Members())
+	slices.Sort(members)
+	members = slices.Compact(members)
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.ring != nil && self == s.self && slices.Equal(members, s.members) {
+		return false
+	}
+	s.logger.Info("Cluster membership changed, rebalancing alert rules", "self", self, "members", members, "previous", s.members)
+	s.self = self
+	s.members = members
+	s.ring = newHashRing(members)
+	return true
+}
+
+// owns returns true if the rules with the shard key are evaluated by this instance.
+func (s *ruleSharder) owns(key string) bool {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	if s.ring == nil || !slices.Contains(s.members, s.self) {
+		return true
+	}
+	return s.ring.owner(key) == s.self
+}
+
+// shardKey returns the key that assigns the rule to a member. The rules of chained groups share the key of the group,
+// so they are evaluated by the same instance.
+func shardKey(rule *ngmodels.AlertRule, chainedGroups map[ngmodels.AlertRuleGroupKey]struct{}) string {
+	orgID := strconv.FormatInt(rule.OrgID, 10)
+	if _, ok := chainedGroups[rule.GetGroupKey()]; ok {
+		return orgID + "/" + rule.NamespaceUID + "/" + rule.RuleGroup
+	}
+	return orgID + "/" + rule.UID
+}
@@ -0,0 +1,202 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+type fakeClusterMembership struct {
+	mtx     sync.Mutex
+	self    string
+	members []string
+}
+
+func (f *fakeClusterMembership) Members() []string {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return slices.Clone(f.members)
+}
+
+func (f *fakeClusterMembership) Self() string {
+	return f.self
+}
+
+func (f *fakeClusterMembership) setMembers(members ...string) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.members = members
+}
+
+func generateShardKeys(n int) []string {
+	keys := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		keys = append(keys, fmt.Sprintf("%d/rule-%d", i%3+1, i))
+	}
+	return keys
+}
+
+func TestHashRing(t *testing.T) {
+	keys := generateShardKeys(3000)
+
+	t.Run("rules are spread between members", func(t *testing.T) {
+		ring := newHashRing([]string{"a", "b", "c"})
+		counts := map[string]int{}
+		for _, key := range keys {
+			counts[ring.owner(key)]++
+		}
+		require.Len(t, counts, 3)
+		for member, count := range counts {
+			require.Greaterf(t, count, len(keys)/5, "member %s owns too few rules", member)
+			require.Lessf(t, count, len(keys)/2, "member %s owns too many rules", member)
+		}
+	})
+
+	t.Run("owner does not depend on the order of members", func(t *testing.T) {
+		r1 := newHashRing([]string{"a", "b", "c"})
+		r2 := newHashRing([]string{"c", "a", "b"})
+		for _, key := range keys {
+			require.Equal(t, r1.owner(key), r2.owner(key))
+		}
+	})
+
+	t.Run("only rules of the new member move when a member joins", func(t *testing.T) {
+		before := newHashRing([]string{"a", "b", "c"})
+		after := newHashRing([]string{"a", "b", "c", "d"})
+		moved := 0
+		for _, key := range keys {
+			if before.owner(key) != after.owner(key) {
+				require.Equal(t, "d", after.owner(key))
+				moved++
+			}
+		}
+		require.Greater(t, moved, 0)
+		require.Less(t, moved, len(keys)/2)
+	})
+
+	t.Run("empty ring has no owner", func(t *testing.T) {
+		require.Empty(t, newHashRing(nil).owner(keys[0]))
+	})
+}
+
+func TestRuleSharder(t *testing.T) {
+	keys := generateShardKeys(100)
+	membership := &fakeClusterMembership{self: "a"}
+	sharder := newRuleSharder(membership, log.NewNopLogger())
+
+	t.Run("owns all rules if it is not a member of the cluster", func(t *testing.T) {
+		membership.setMembers("b", "c")
+		require.True(t, sharder.refresh())
+		for _, key := range keys {
+			require.True(t, sharder.owns(key))
+		}
+	})
+
+	t.Run("owns the rules assigned to it by the ring", func(t *testing.T) {
+		membership.setMembers("c", "a", "b")
+		require.True(t, sharder.refresh())
+		ring := newHashRing([]string{"a", "b", "c"})
+		owned := 0
+		for _, key := range keys {
+			require.Equal(t, ring.owner(key) == "a", sharder.owns(key))
+			if sharder.owns(key) {
+				owned++
+			}
+		}
+		require.Greater(t, owned, 0)
+		require.Less(t, owned, len(keys))
+	})
+
+	t.Run("refresh reports no change if members are the same", func(t *testing.T) {
+		membership.setMembers("b", "a", "c", "a")
+		require.False(t, sharder.refresh())
+	})
+}
+
+func TestProcessTick_Sharding(t *testing.T) {
+	ctx := context.Background()
+	dispatcherGroup, ctx := errgroup.WithContext(ctx)
+
+	ruleStore := newFakeRulesStore()
+	instanceStore := &state.FakeInstanceStore{}
+	sch := setupScheduler(t, ruleStore, instanceStore, nil, nil, nil)
+	membership := &fakeClusterMembership{self: "a", members: []string{"a"}}
+	sch.sharder = newRuleSharder(membership, log.NewNopLogger())
+
+	gen := models.RuleGen
+	// the interval is long enough for the rules not to be evaluated during the test
+	rules := gen.With(gen.WithInterval(time.Hour), gen.WithOrgID(1)).GenerateManyRef(20)
+	ruleStore.PutRule(ctx, rules...)
+
+	ring := newHashRing([]string{"a", "b"})
+	var owned, disowned *models.AlertRule
+	for _, rule := range rules {
+		if ring.owner(shardKey(rule, nil)) == "a" && owned == nil {
+			owned = rule
+		}
+		if ring.owner(shardKey(rule, nil)) == "b" && disowned == nil {
+			disowned = rule
+		}
+	}
+	require.NotNil(t, owned)
+	require.NotNil(t, disowned)
+
+	tick := time.Unix(1, 0)
+
+	t.Run("evaluates all rules when it is the only member", func(t *testing.T) {
+		sch.processTick(ctx, dispatcherGroup, tick)
+		for _, rule := range rules {
+			require.True(t, sch.registry.exists(rule.GetKey()))
+		}
+	})
+
+	t.Run("stops the rules of the new member and forgets their state", func(t *testing.T) {
+		sch.stateManager.Put([]*state.State{
+			{OrgID: disowned.OrgID, AlertRuleUID: disowned.UID, CacheID: 1, State: eval.Alerting},
+			{OrgID: owned.OrgID, AlertRuleUID: owned.UID, CacheID: 1, State: eval.Pending},
+		})
+		sch.registry.mu.Lock()
+		routine, ok := sch.registry.rules[disowned.GetKey()]
+		sch.registry.mu.Unlock()
+		require.True(t, ok)
+
+		membership.setMembers("a", "b")
+		tick = tick.Add(sch.baseInterval)
+		_, stopped, _ := sch.processTick(ctx, dispatcherGroup, tick)
+
+		require.Empty(t, stopped, "rules of other members must not be deleted")
+		require.False(t, sch.registry.exists(disowned.GetKey()))
+		require.True(t, sch.registry.exists(owned.GetKey()))
+		require.ErrorIs(t, routine.(*alertRule).ctx.Err(), errRuleNotOwned)
+		require.Eventually(t, func() bool {
+			return len(sch.stateManager.GetStatesForRuleUID(disowned.OrgID, disowned.UID)) == 0
+		}, time.Second, 10*time.Millisecond)
+		require.Len(t, sch.stateManager.GetStatesForRuleUID(owned.OrgID, owned.UID), 1)
+	})
+
+	t.Run("restores the state of the rules handed back", func(t *testing.T) {
+		membership.setMembers("a")
+		tick = tick.Add(sch.baseInterval)
+		sch.processTick(ctx, dispatcherGroup, tick)
+
+		require.True(t, sch.registry.exists(disowned.GetKey()))
+		require.Eventually(t, func() bool {
+			return slices.ContainsFunc(instanceStore.RecordedOps(), func(op any) bool {
+				q, ok := op.(models.ListAlertInstancesQuery)
+				return ok && q.RuleOrgID == disowned.OrgID && q.RuleUID == disowned.UID
+			})
+		}, time.Second, 10*time.Millisecond)
+		require.Empty(t, sch.disownedRules)
+	})
+}
@@ -17,6 +17,7 @@ import (
 
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngModels "github.com/grafana/grafana/pkg/services/ngalert/models"
 )
 
 const (
@@ -56,6 +57,10 @@ func StateToPostableAlert(transition StateTransition, appURL *url.URL) *models.P
 		nA[alertingModels.StateReasonAnnotation] = alertState.StateReason
 	}
 
+	if alertState.Flapping {
+		nA[ngModels.FlappingAnnotation] = "true"
+	}
+
 	if alertState.OrgID != 0 {
 		nA[alertingModels.OrgIDAnnotation] = strconv.FormatInt(alertState.OrgID, 10)
 	}
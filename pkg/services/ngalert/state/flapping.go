@@ -0,0 +1,72 @@
+package state
+
+import (
+	"math/bits"
+)
+
+// FlapHistorySize is the number of most recent evaluations of a state over which the percentage
+// of evaluations that changed the state is calculated. It is the same as in Nagios.
+const FlapHistorySize = 21
+
+// FlapDetectionConfig configures the detection of states that change too often, for example
+// between Normal and Alerting. The detection is similar to the flap detection in Nagios: the
+// percentage of evaluations that changed the state is calculated over the last FlapHistorySize
+// evaluations, and a state starts flapping when the percentage reaches the high threshold and
+// stops flapping when it drops below the low threshold.
+type FlapDetectionConfig struct {
+	Enabled bool
+	// MinEvaluations is the number of evaluations in the history required before a state can be flapping.
+	MinEvaluations int
+	// HighThreshold is the percentage of evaluations changing the state at which a state starts flapping.
+	HighThreshold float64
+	// LowThreshold is the percentage of evaluations changing the state below which a state stops flapping.
+	LowThreshold float64
+	// SuppressNotifications stops sending flapping states to the Alertmanager until they stop flapping.
+	SuppressNotifications bool
+}
+
+// FlapHistory records which of the last FlapHistorySize evaluations of a state changed the state.
+type FlapHistory struct {
+	// Changed has the lowest bit set if the most recent evaluation changed the state,
+	// the next bit set if the evaluation before changed the state, and so on.
+	Changed uint32
+	// Evaluations is the number of evaluations recorded, up to FlapHistorySize.
+	Evaluations int
+}
+
+// add records an evaluation, forgetting the oldest one if the history is full.
+func (h *FlapHistory) add(changed bool) {
+	h.Changed = (h.Changed << 1) & (1<<FlapHistorySize - 1)
+	if changed {
+		h.Changed |= 1
+	}
+	if h.Evaluations < FlapHistorySize {
+		h.Evaluations++
+	}
+}
+
+// Changes returns the number of evaluations in the history that changed the state.
+func (h FlapHistory) Changes() int {
+	return bits.OnesCount32(h.Changed)
+}
+
+// update records an evaluation of the state and updates its flap rate and whether it is flapping.
+func (c FlapDetectionConfig) update(s *State, changed bool) {
+	if !c.Enabled {
+		return
+	}
+
+	s.FlapHistory.add(changed)
+	s.FlapRate = 100 * float64(s.FlapHistory.Changes()) / float64(s.FlapHistory.Evaluations)
+
+	if s.Flapping {
+		s.Flapping = s.FlapRate >= c.LowThreshold
+	} else {
+		s.Flapping = s.FlapHistory.Evaluations >= c.MinEvaluations && s.FlapRate >= c.HighThreshold
+	}
+}
+
+// suppresses returns true if notifications for the state must not be sent because it is flapping.
+func (c FlapDetectionConfig) suppresses(s *State) bool {
+	return c.Enabled && c.SuppressNotifications && s.Flapping
+}
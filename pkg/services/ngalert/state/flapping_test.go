@@ -0,0 +1,146 @@
+package state
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestFlapDetectionConfig_update(t *testing.T) {
+	cfg := FlapDetectionConfig{
+		Enabled:        true,
+		MinEvaluations: 4,
+		HighThreshold:  50,
+		LowThreshold:   25,
+	}
+
+	t.Run("does nothing if disabled", func(t *testing.T) {
+		s := &State{}
+		FlapDetectionConfig{}.update(s, true)
+		assert.False(t, s.Flapping)
+		assert.Zero(t, s.FlapRate)
+		assert.Zero(t, s.FlapHistory.Evaluations)
+	})
+
+	t.Run("does not flap before minimum number of evaluations", func(t *testing.T) {
+		s := &State{}
+		for i := 0; i < 3; i++ {
+			cfg.update(s, true)
+			assert.False(t, s.Flapping)
+			assert.Equal(t, 100.0, s.FlapRate)
+		}
+		cfg.update(s, true)
+		assert.True(t, s.Flapping)
+	})
+
+	t.Run("stops flapping below low threshold", func(t *testing.T) {
+		s := &State{}
+		for i := 0; i < 4; i++ {
+			cfg.update(s, true)
+		}
+		require.True(t, s.Flapping)
+
+		// 4 of 16 evaluations in the history changed the state, it is not below the low threshold
+		for i := 0; i < 12; i++ {
+			cfg.update(s, false)
+		}
+		assert.True(t, s.Flapping)
+		assert.InDelta(t, 100.0*4/16, s.FlapRate, 0.001)
+		assert.Equal(t, 16, s.FlapHistory.Evaluations)
+
+		cfg.update(s, false)
+		assert.InDelta(t, 100.0*4/17, s.FlapRate, 0.001)
+		assert.False(t, s.Flapping)
+	})
+
+	t.Run("forgets evaluations older than the history", func(t *testing.T) {
+		s := &State{}
+		cfg.update(s, true)
+		for i := 0; i < FlapHistorySize-1; i++ {
+			cfg.update(s, false)
+		}
+		assert.Equal(t, FlapHistorySize, s.FlapHistory.Evaluations)
+		assert.Equal(t, 1, s.FlapHistory.Changes())
+
+		cfg.update(s, false)
+		assert.Equal(t, FlapHistorySize, s.FlapHistory.Evaluations)
+		assert.Zero(t, s.FlapHistory.Changes())
+		assert.Zero(t, s.FlapRate)
+	})
+}
+
+func TestProcessEvalResults_FlapDetection(t *testing.T) {
+	clk := clock.NewMock()
+	cfg := ManagerCfg{
+		Metrics:       metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+		Tracer:        tracing.InitializeTracerForTest(),
+		Log:           log.New("ngalert.state.manager"),
+		InstanceStore: &FakeInstanceStore{},
+		Images:        &NotAvailableImageService{},
+		Clock:         clk,
+		Historian:     &FakeHistorian{},
+		FlapDetection: FlapDetectionConfig{
+			Enabled:               true,
+			MinEvaluations:        4,
+			HighThreshold:         50,
+			LowThreshold:          25,
+			SuppressNotifications: true,
+		},
+	}
+	st := NewManager(cfg, NewNoopPersister())
+
+	gen := ngmodels.RuleGen
+	rule := gen.With(gen.WithFor(0), gen.WithIntervalSeconds(60)).GenerateRef()
+	result := eval.ResultGen()()
+
+	evaluate := func(s eval.State) (StateTransition, StateTransitions) {
+		t.Helper()
+		clk.Add(time.Minute)
+		r := result
+		r.State = s
+		r.EvaluatedAt = clk.Now()
+		var sent StateTransitions
+		transitions := st.ProcessEvalResults(context.Background(), clk.Now(), rule, eval.Results{r}, nil, func(_ context.Context, states StateTransitions) {
+			sent = states
+		})
+		require.Len(t, transitions, 1)
+		return transitions[0], sent
+	}
+
+	// The first three evaluations change the state and are sent.
+	for _, s := range []eval.State{eval.Alerting, eval.Normal, eval.Alerting} {
+		transition, sent := evaluate(s)
+		require.False(t, transition.Flapping)
+		require.Len(t, sent, 1)
+	}
+
+	transition, sent := evaluate(eval.Normal)
+	require.True(t, transition.Flapping)
+	require.True(t, transition.FlappingChanged())
+	require.Empty(t, sent, "notifications for flapping states should be suppressed")
+	require.Equal(t, "true", StateToPostableAlert(transition, nil).Annotations[ngmodels.FlappingAnnotation])
+
+	for i := 0; i < 12; i++ {
+		transition, sent = evaluate(eval.Normal)
+		require.True(t, transition.Flapping)
+		require.False(t, transition.FlappingChanged())
+		require.Empty(t, sent)
+	}
+
+	transition, sent = evaluate(eval.Normal)
+	require.False(t, transition.Flapping)
+	require.True(t, transition.FlappingChanged())
+	require.Len(t, sent, 1, "resolved notification should be sent once the state stops flapping")
+	require.NotContains(t, StateToPostableAlert(transition, nil).Annotations, ngmodels.FlappingAnnotation)
+}
@@ -252,6 +252,10 @@ func BuildAnnotationTextAndData(rule history_model.RuleMeta, currentState *state
 		value = strings.Join(values, ", ")
 	}
 
+	if currentState.Flapping {
+		jsonData.Set("flapping", true)
+	}
+
 	labels := removePrivateLabels(currentState.Labels)
 	return fmt.Sprintf("%s {%s} - %s", rule.Title, labels.String(), value), jsonData
 }
@@ -18,6 +18,10 @@ import (
 const StateHistoryWriteTimeout = time.Minute
 
 func shouldRecord(transition state.StateTransition) bool {
+	// Starting and stopping flapping is recorded even if the state does not change
+	if transition.FlappingChanged() {
+		return true
+	}
 	if !transition.Changed() {
 		return false
 	}
@@ -97,6 +97,35 @@ func TestShouldRecord(t *testing.T) {
 	}
 }
 
+func TestShouldRecordFlapping(t *testing.T) {
+	t.Run("start of flapping is recorded", func(t *testing.T) {
+		trans := state.StateTransition{
+			State:         &state.State{State: eval.Alerting, Flapping: true},
+			PreviousState: eval.Normal,
+		}
+		require.True(t, shouldRecord(trans))
+	})
+
+	t.Run("end of flapping is recorded if state does not change", func(t *testing.T) {
+		trans := state.StateTransition{
+			State:            &state.State{State: eval.Normal},
+			PreviousState:    eval.Normal,
+			PreviousFlapping: true,
+		}
+		require.True(t, shouldRecord(trans))
+		require.True(t, ShouldRecordAnnotation(trans))
+	})
+
+	t.Run("flapping without change is not recorded", func(t *testing.T) {
+		trans := state.StateTransition{
+			State:            &state.State{State: eval.Normal, Flapping: true},
+			PreviousState:    eval.Normal,
+			PreviousFlapping: true,
+		}
+		require.False(t, shouldRecord(trans))
+	})
+}
+
 func TestShouldRecordAnnotation(t *testing.T) {
 	transition := func(from eval.State, fromReason string, to eval.State, toReason string) state.StateTransition {
 		return state.StateTransition{
@@ -297,6 +297,7 @@ func StatesToStream(rule history_model.RuleMeta, states []state.StateTransition,
 			RuleID:         rule.ID,
 			RuleUID:        rule.UID,
 			InstanceLabels: sanitizedLabels,
+			Flapping:       state.Flapping,
 		}
 		if state.State.State == eval.Error {
 			entry.Error = state.Error.Error()
@@ -335,6 +336,7 @@ type LokiEntry struct {
 	Previous      string           `json:"previous"`
 	Current       string           `json:"current"`
 	Error         string           `json:"error,omitempty"`
+	Flapping      bool             `json:"flapping,omitempty"`
 	Values        *simplejson.Json `json:"values"`
 	Condition     string           `json:"condition"`
 	DashboardUID  string           `json:"dashboardUID"`
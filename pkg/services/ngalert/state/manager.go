@@ -2,6 +2,7 @@ package state
 
 import (
 	"context"
+	"fmt"
 	"net/url"
 	"strconv"
 	"strings"
@@ -56,6 +57,7 @@ type Manager struct {
 	doNotSaveNormalState           bool
 	applyNoDataAndErrorToAllStates bool
 	rulesPerRuleGroupLimit         int64
+	flapDetection                  FlapDetectionConfig
 
 	persister StatePersister
 }
@@ -81,6 +83,9 @@ type ManagerCfg struct {
 	// Duration for which a resolved alert state transition will continue to be sent to the Alertmanager.
 	ResolvedRetention time.Duration
 
+	// FlapDetection configures the detection of states that change too often.
+	FlapDetection FlapDetectionConfig
+
 	Tracer tracing.Tracer
 	Log    log.Logger
 }
@@ -107,6 +112,7 @@ func NewManager(cfg ManagerCfg, statePersister StatePersister) *Manager {
 		doNotSaveNormalState:           cfg.DoNotSaveNormalState,
 		applyNoDataAndErrorToAllStates: cfg.ApplyNoDataAndErrorToAllStates,
 		rulesPerRuleGroupLimit:         cfg.RulesPerRuleGroupLimit,
+		flapDetection:                  cfg.FlapDetection,
 		persister:                      statePersister,
 		tracer:                         cfg.Tracer,
 	}
@@ -187,44 +193,13 @@ func (st *Manager) Warm(ctx context.Context, rulesReader RuleReader) {
 				continue
 			}
 
-			// nil safety.
-			annotations := ruleForEntry.Annotations
-			if annotations == nil {
-				annotations = make(map[string]string)
-			}
-
 			rulesStates, ok := orgStates[entry.RuleUID]
 			if !ok {
 				rulesStates = &ruleStates{states: make(map[data.Fingerprint]*State)}
 				orgStates[entry.RuleUID] = rulesStates
 			}
-
-			lbs := map[string]string(entry.Labels)
-			cacheID := entry.Labels.Fingerprint()
-			var resultFp data.Fingerprint
-			if entry.ResultFingerprint != "" {
-				fp, err := strconv.ParseUint(entry.ResultFingerprint, 16, 64)
-				if err != nil {
-					st.log.Error("Failed to parse result fingerprint of alert instance", "error", err, "ruleUID", entry.RuleUID)
-				}
-				resultFp = data.Fingerprint(fp)
-			}
-			rulesStates.states[cacheID] = &State{
-				AlertRuleUID:         entry.RuleUID,
-				OrgID:                entry.RuleOrgID,
-				CacheID:              cacheID,
-				Labels:               lbs,
-				State:                translateInstanceState(entry.CurrentState),
-				StateReason:          entry.CurrentReason,
-				LastEvaluationString: "",
-				StartsAt:             entry.CurrentStateSince,
-				EndsAt:               entry.CurrentStateEnd,
-				LastEvaluationTime:   entry.LastEvalTime,
-				Annotations:          annotations,
-				ResultFingerprint:    resultFp,
-				ResolvedAt:           entry.ResolvedAt,
-				LastSentAt:           entry.LastSentAt,
-			}
+			s := st.stateFromAlertInstance(entry, ruleForEntry)
+			rulesStates.states[s.CacheID] = s
 			statesCount++
 		}
 	}
@@ -237,6 +212,75 @@ func (st *Manager) Get(orgID int64, alertRuleUID string, stateId data.Fingerprin
 	return st.cache.get(orgID, alertRuleUID, stateId)
 }
 
+// stateFromAlertInstance creates the state of a rule from an alert instance saved in the database.
+func (st *Manager) stateFromAlertInstance(entry *ngModels.AlertInstance, rule *ngModels.AlertRule) *State {
+	// nil safety.
+	annotations := rule.Annotations
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+
+	var resultFp data.Fingerprint
+	if entry.ResultFingerprint != "" {
+		fp, err := strconv.ParseUint(entry.ResultFingerprint, 16, 64)
+		if err != nil {
+			st.log.Error("Failed to parse result fingerprint of alert instance", "error", err, "ruleUID", entry.RuleUID)
+		}
+		resultFp = data.Fingerprint(fp)
+	}
+	return &State{
+		AlertRuleUID:         entry.RuleUID,
+		OrgID:                entry.RuleOrgID,
+		CacheID:              entry.Labels.Fingerprint(),
+		Labels:               map[string]string(entry.Labels),
+		State:                translateInstanceState(entry.CurrentState),
+		StateReason:          entry.CurrentReason,
+		LastEvaluationString: "",
+		StartsAt:             entry.CurrentStateSince,
+		EndsAt:               entry.CurrentStateEnd,
+		LastEvaluationTime:   entry.LastEvalTime,
+		Annotations:          annotations,
+		ResultFingerprint:    resultFp,
+		ResolvedAt:           entry.ResolvedAt,
+		LastSentAt:           entry.LastSentAt,
+	}
+}
+
+// ForgetStateByRuleUID removes the rule instances from cache but keeps them in the instanceStore.
+// It is used when the rule is handed over to another instance of the cluster, which continues from the saved state.
+func (st *Manager) ForgetStateByRuleUID(ctx context.Context, ruleKey ngModels.AlertRuleKey) []*State {
+	states := st.cache.removeByRuleUID(ruleKey.OrgID, ruleKey.UID)
+	if len(states) > 0 {
+		st.log.FromContext(ctx).Debug("Rule state was removed from cache", append(ruleKey.LogContext(), "states", len(states))...)
+	}
+	return states
+}
+
+// RestoreStateByRuleUID replaces the rule instances in cache with the ones saved in the instanceStore.
+// It is used when the rule is handed over from another instance of the cluster, so pending and firing alerts are not reset.
+func (st *Manager) RestoreStateByRuleUID(ctx context.Context, rule *ngModels.AlertRule) error {
+	if st.instanceStore == nil {
+		return nil
+	}
+	ruleKey := rule.GetKey()
+	alertInstances, err := st.instanceStore.ListAlertInstances(ctx, &ngModels.ListAlertInstancesQuery{
+		RuleOrgID: ruleKey.OrgID,
+		RuleUID:   ruleKey.UID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch state of the rule: %w", err)
+	}
+
+	states := make(map[data.Fingerprint]*State, len(alertInstances))
+	for _, entry := range alertInstances {
+		s := st.stateFromAlertInstance(entry, rule)
+		states[s.CacheID] = s
+	}
+	st.cache.setRuleStates(ruleKey, states)
+	st.log.FromContext(ctx).Debug("Rule state was restored from database", append(ruleKey.LogContext(), "states", len(states))...)
+	return nil
+}
+
 // DeleteStateByRuleUID removes the rule instances from cache and instanceStore. A closed channel is returned to be able
 // to gracefully handle the clear state step in scheduler in case we do not need to use the historian to save state
 // history.
@@ -362,10 +406,14 @@ func (st *Manager) ProcessEvalResults(
 }
 
 // updateLastSentAt returns the subset StateTransitions that need sending and updates their LastSentAt field.
+// States that are flapping are not sent if flap detection suppresses notifications.
 // Note: This is not idempotent, running this twice can (and usually will) return different results.
 func (st *Manager) updateLastSentAt(states StateTransitions, evaluatedAt time.Time) StateTransitions {
 	var result StateTransitions
 	for _, t := range states {
+		if st.flapDetection.suppresses(t.State) {
+			continue
+		}
 		if t.NeedsSending(st.ResendDelay, st.ResolvedRetention) {
 			t.LastSentAt = &evaluatedAt
 			result = append(result, t)
@@ -452,6 +500,7 @@ func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRu
 	currentState.LastEvaluationString = result.EvaluationString
 	oldState := currentState.State
 	oldReason := currentState.StateReason
+	oldFlapping := currentState.Flapping
 
 	// Add the instance to the log context to help correlate log lines for a state
 	logger = logger.New("instance", result.Instance)
@@ -525,12 +574,18 @@ func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRu
 		}
 	}
 
+	st.flapDetection.update(currentState, currentState.State != oldState)
+	if currentState.Flapping != oldFlapping {
+		logger.Debug("Flapping changed", "flapping", currentState.Flapping, "flap_rate", currentState.FlapRate)
+	}
+
 	st.cache.set(currentState)
 
 	nextState := StateTransition{
 		State:               currentState,
 		PreviousState:       oldState,
 		PreviousStateReason: oldReason,
+		PreviousFlapping:    oldFlapping,
 	}
 
 	if st.metrics != nil {
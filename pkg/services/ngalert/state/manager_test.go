@@ -2115,6 +2115,68 @@ func TestDeleteStateByRuleUID(t *testing.T) {
 	}
 }
 
+func TestForgetAndRestoreStateByRuleUID(t *testing.T) {
+	interval := time.Minute
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, 1)
+
+	const mainOrgID int64 = 1
+	rule := tests.CreateTestAlertRule(t, ctx, dbstore, int64(interval.Seconds()), mainOrgID)
+
+	labels1 := models.InstanceLabels{"test1": "testValue1"}
+	_, hash1, _ := labels1.StringAndHash()
+	labels2 := models.InstanceLabels{"test2": "testValue2"}
+	_, hash2, _ := labels2.StringAndHash()
+	instances := []models.AlertInstance{
+		{
+			AlertInstanceKey: models.AlertInstanceKey{RuleOrgID: rule.OrgID, RuleUID: rule.UID, LabelsHash: hash1},
+			CurrentState:     models.InstanceStatePending,
+			Labels:           labels1,
+		},
+		{
+			AlertInstanceKey: models.AlertInstanceKey{RuleOrgID: rule.OrgID, RuleUID: rule.UID, LabelsHash: hash2},
+			CurrentState:     models.InstanceStateFiring,
+			Labels:           labels2,
+		},
+	}
+	for _, instance := range instances {
+		require.NoError(t, dbstore.SaveAlertInstance(ctx, instance))
+	}
+
+	cfg := state.ManagerCfg{
+		Metrics:       metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+		InstanceStore: dbstore,
+		Images:        &state.NoopImageService{},
+		Clock:         clock.NewMock(),
+		Historian:     &state.FakeHistorian{},
+		Tracer:        tracing.InitializeTracerForTest(),
+		Log:           log.New("ngalert.state.manager"),
+	}
+	st := state.NewManager(cfg, state.NewNoopPersister())
+	st.Warm(ctx, dbstore)
+	require.Len(t, st.GetStatesForRuleUID(rule.OrgID, rule.UID), 2)
+
+	forgotten := st.ForgetStateByRuleUID(ctx, rule.GetKey())
+	require.Len(t, forgotten, 2)
+	require.Empty(t, st.GetStatesForRuleUID(rule.OrgID, rule.UID))
+
+	// the instances must be kept in the database for the instance that takes over the rule
+	alertInstances, err := dbstore.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{RuleOrgID: rule.OrgID, RuleUID: rule.UID})
+	require.NoError(t, err)
+	require.Len(t, alertInstances, 2)
+
+	require.NoError(t, st.RestoreStateByRuleUID(ctx, rule))
+	restored := st.GetStatesForRuleUID(rule.OrgID, rule.UID)
+	require.Len(t, restored, 2)
+	byState := make(map[eval.State]*state.State, len(restored))
+	for _, s := range restored {
+		byState[s.State] = s
+	}
+	require.Contains(t, byState, eval.Pending)
+	require.Contains(t, byState, eval.Alerting)
+	require.Equal(t, data.Labels(labels2), byState[eval.Alerting].Labels)
+}
+
 func TestResetStateByRuleUID(t *testing.T) {
 	interval := time.Minute
 	ctx := context.Background()
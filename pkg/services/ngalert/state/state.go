@@ -73,6 +73,13 @@ type State struct {
 	LastEvaluationString string
 	LastEvaluationTime   time.Time
 	EvaluationDuration   time.Duration
+
+	// Flapping is true if the state changes too often. It is only set when flap detection is enabled.
+	Flapping bool
+	// FlapRate is the percentage of evaluations in the flap detection window that changed the state.
+	FlapRate float64
+	// FlapHistory records which of the last evaluations changed the state.
+	FlapHistory FlapHistory
 }
 
 func (a *State) GetRuleKey() models.AlertRuleKey {
@@ -212,6 +219,7 @@ type StateTransition struct {
 	*State
 	PreviousState       eval.State
 	PreviousStateReason string
+	PreviousFlapping    bool
 }
 
 func (c StateTransition) Formatted() string {
@@ -226,6 +234,11 @@ func (c StateTransition) Changed() bool {
 	return c.PreviousState != c.State.State || c.PreviousStateReason != c.State.StateReason
 }
 
+// FlappingChanged returns true if the state started or stopped flapping.
+func (c StateTransition) FlappingChanged() bool {
+	return c.PreviousFlapping != c.State.Flapping
+}
+
 type StateTransitions []StateTransition
 
 // StaleStates returns the subset of StateTransitions that are stale.
@@ -189,6 +189,8 @@ func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRu
 				Labels:               r.Labels,
 				Record:               r.Record,
 				NotificationSettings: r.NotificationSettings,
+				SequentialEvaluation: r.SequentialEvaluation,
+				DependsOn:            r.DependsOn,
 			})
 		}
 		if len(newRules) > 0 {
@@ -270,6 +272,8 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, rules []ngmodels.UpdateR
 				Annotations:          r.New.Annotations,
 				Labels:               r.New.Labels,
 				NotificationSettings: r.New.NotificationSettings,
+				SequentialEvaluation: r.New.SequentialEvaluation,
+				DependsOn:            r.New.DependsOn,
 			})
 		}
 		if len(ruleVersions) > 0 {
@@ -495,22 +499,6 @@ func (st DBstore) Count(ctx context.Context, orgID int64) (int64, error) {
 	return r.Count, err
 }
 
-func (st DBstore) GetRuleGroupInterval(ctx context.Context, orgID int64, namespaceUID string, ruleGroup string) (int64, error) {
-	var interval int64 = 0
-	return interval, st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
-		ruleGroups := make([]ngmodels.AlertRule, 0)
-		err := sess.Find(
-			&ruleGroups,
-			ngmodels.AlertRule{OrgID: orgID, RuleGroup: ruleGroup, NamespaceUID: namespaceUID},
-		)
-		if len(ruleGroups) == 0 {
-			return ngmodels.ErrAlertRuleGroupNotFound.Errorf("")
-		}
-		interval = ruleGroups[0].IntervalSeconds
-		return err
-	})
-}
-
 // GetUserVisibleNamespaces returns the folders that are visible to the user
 func (st DBstore) GetUserVisibleNamespaces(ctx context.Context, orgID int64, user identity.Requester) (map[string]*folder.Folder, error) {
 	folders, err := st.FolderService.GetFolders(ctx, folder.GetFoldersQuery{
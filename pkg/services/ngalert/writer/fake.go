@@ -18,3 +18,12 @@ func (w FakeWriter) Write(ctx context.Context, name string, t time.Time, frames
 
 	return w.WriteFunc(ctx, name, t, frames, orgID, extraLabels)
 }
+
+// FakeRegistry returns its FakeWriter for every recording rule.
+type FakeRegistry struct {
+	FakeWriter FakeWriter
+}
+
+func (r FakeRegistry) Writer(orgID int64, writerType string) (Writer, error) {
+	return r.FakeWriter, nil
+}
@@ -0,0 +1,105 @@
+package writer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
+)
+
+// maxErrorBodySize is the number of bytes of the response body that are included in a write error.
+const maxErrorBodySize = 1024
+
+// WriteError is an error of a write request with the status code of the response.
+// The status code is 0 if no response was received.
+type WriteError interface {
+	error
+	StatusCode() int
+}
+
+type httpWriteError struct {
+	statusCode int
+	err        error
+}
+
+func (e httpWriteError) Error() string {
+	return e.err.Error()
+}
+
+func (e httpWriteError) StatusCode() int {
+	return e.statusCode
+}
+
+func (e httpWriteError) Unwrap() error {
+	return e.err
+}
+
+// newHTTPClient creates a client for write requests with the headers and the timeout.
+func newHTTPClient(httpClientProvider HttpClientProvider, headers map[string]string, timeout time.Duration) (*http.Client, error) {
+	h := make(http.Header)
+	for k, v := range headers {
+		h.Add(k, v)
+	}
+	cl, err := httpClientProvider.New(httpclient.Options{
+		Header: h,
+	})
+	if err != nil {
+		return nil, err
+	}
+	cl.Timeout = timeout
+	return cl, nil
+}
+
+// validateWriteURL checks that the URL is an absolute http or https URL.
+func validateWriteURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid URL %q: scheme must be http or https", rawURL)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("invalid URL %q: host is required", rawURL)
+	}
+	return nil
+}
+
+// doWriteRequest posts the body and returns the status code and the response body.
+// A WriteError is returned if the request fails or the status code is not 2xx.
+func doWriteRequest(ctx context.Context, client *http.Client, writeURL string, header http.Header, body []byte) (int, []byte, WriteError) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, writeURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, httpWriteError{err: err}
+	}
+	req.Header = header
+	req.Header.Set("User-Agent", "grafana-recording-rule")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return 0, nil, httpWriteError{err: err}
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return res.StatusCode, nil, httpWriteError{statusCode: res.StatusCode, err: fmt.Errorf("failed to read response: %w", err)}
+	}
+	if res.StatusCode/100 != 2 {
+		if len(resBody) > maxErrorBodySize {
+			resBody = resBody[:maxErrorBodySize]
+		}
+		return res.StatusCode, resBody, httpWriteError{
+			statusCode: res.StatusCode,
+			err:        fmt.Errorf("server returned HTTP status %s: %s", res.Status, bytes.TrimSpace(resBody)),
+		}
+	}
+	return res.StatusCode, resBody, nil
+}
@@ -0,0 +1,167 @@
+package writer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// influxDBValueField is the name of the field that holds the value of a point.
+const influxDBValueField = "value"
+
+var (
+	influxDBMeasurementEscaper = strings.NewReplacer(",", `\,`, " ", `\ `)
+	influxDBTagEscaper         = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+)
+
+// InfluxDBWriter writes the result of recording rules to the InfluxDB 2.x write API with line protocol.
+// The name of the recording rule is the measurement, the labels are tags and the value is the "value" field.
+type InfluxDBWriter struct {
+	client   *http.Client
+	writeURL string
+	token    string
+	clock    clock.Clock
+	logger   log.Logger
+	metrics  *metrics.RemoteWriter
+}
+
+func NewInfluxDBWriter(
+	settings setting.RecordingRuleInfluxDBSettings,
+	httpClientProvider HttpClientProvider,
+	clock clock.Clock,
+	l log.Logger,
+	metrics *metrics.RemoteWriter,
+) (*InfluxDBWriter, error) {
+	if err := validateInfluxDBSettings(settings); err != nil {
+		return nil, err
+	}
+
+	cl, err := newHTTPClient(httpClientProvider, nil, settings.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("org", settings.Organization)
+	params.Set("bucket", settings.Bucket)
+	params.Set("precision", "ms")
+
+	return &InfluxDBWriter{
+		client:   cl,
+		writeURL: strings.TrimSuffix(settings.URL, "/") + "/api/v2/write?" + params.Encode(),
+		token:    settings.Token,
+		clock:    clock,
+		logger:   l,
+		metrics:  metrics,
+	}, nil
+}
+
+func validateInfluxDBSettings(settings setting.RecordingRuleInfluxDBSettings) error {
+	if err := validateWriteURL(settings.URL); err != nil {
+		return err
+	}
+
+	if settings.Organization == "" {
+		return fmt.Errorf("organization is required")
+	}
+
+	if settings.Bucket == "" {
+		return fmt.Errorf("bucket is required")
+	}
+
+	if settings.Timeout <= 0 {
+		return fmt.Errorf("timeout must be greater than 0")
+	}
+
+	return nil
+}
+
+// Write writes the given frames to InfluxDB.
+func (w *InfluxDBWriter) Write(ctx context.Context, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error {
+	l := w.logger.FromContext(ctx)
+	lvs := []string{fmt.Sprint(orgID), TypeInfluxDB}
+
+	points, err := PointsFromFrames(name, t, frames, extraLabels)
+	if err != nil {
+		return err
+	}
+
+	body := encodeLineProtocol(points)
+	if len(body) == 0 {
+		l.Debug("No points to write", "name", name)
+		return nil
+	}
+
+	header := make(http.Header)
+	header.Set("Content-Type", "text/plain; charset=utf-8")
+	if w.token != "" {
+		header.Set("Authorization", "Token "+w.token)
+	}
+
+	l.Debug("Writing metric", "name", name)
+	writeStart := w.clock.Now()
+	statusCode, _, writeErr := doWriteRequest(ctx, w.client, w.writeURL, header, body)
+	w.metrics.WriteDuration.WithLabelValues(lvs...).Observe(w.clock.Now().Sub(writeStart).Seconds())
+
+	lvs = append(lvs, fmt.Sprint(statusCode))
+	w.metrics.WritesTotal.WithLabelValues(lvs...).Inc()
+
+	if err, ignored := checkWriteError(writeErr); err != nil {
+		return fmt.Errorf("failed to write points: %w", err)
+	} else if ignored {
+		l.Debug("Ignored write error", "error", err, "status_code", statusCode)
+	}
+
+	return nil
+}
+
+// encodeLineProtocol encodes the points as line protocol with millisecond precision, one line per point.
+// Points with NaN or infinite values are skipped as they cannot be represented as a float field.
+func encodeLineProtocol(points []Point) []byte {
+	var b strings.Builder
+	for _, p := range points {
+		if math.IsNaN(p.Metric.V) || math.IsInf(p.Metric.V, 0) {
+			continue
+		}
+
+		b.WriteString(influxDBMeasurementEscaper.Replace(p.Name))
+
+		keys := make([]string, 0, len(p.Labels))
+		for k := range p.Labels {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			// tags with empty values are not allowed
+			if k == "" || p.Labels[k] == "" {
+				continue
+			}
+			b.WriteByte(',')
+			b.WriteString(influxDBTagEscaper.Replace(k))
+			b.WriteByte('=')
+			b.WriteString(influxDBTagEscaper.Replace(p.Labels[k]))
+		}
+
+		b.WriteByte(' ')
+		b.WriteString(influxDBValueField)
+		b.WriteByte('=')
+		b.WriteString(strconv.FormatFloat(p.Metric.V, 'g', -1, 64))
+		b.WriteByte(' ')
+		b.WriteString(strconv.FormatInt(p.Metric.T.UnixMilli(), 10))
+		b.WriteByte('\n')
+	}
+	return []byte(b.String())
+}
@@ -0,0 +1,139 @@
+package writer
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestValidateInfluxDBSettings(t *testing.T) {
+	valid := setting.RecordingRuleInfluxDBSettings{
+		URL:          "http://localhost:8086",
+		Organization: "org",
+		Bucket:       "bucket",
+		Timeout:      10 * time.Second,
+	}
+	require.NoError(t, validateInfluxDBSettings(valid))
+
+	for _, tc := range []struct {
+		name   string
+		mutate func(*setting.RecordingRuleInfluxDBSettings)
+	}{
+		{name: "invalid url", mutate: func(s *setting.RecordingRuleInfluxDBSettings) { s.URL = "invalid url" }},
+		{name: "relative url", mutate: func(s *setting.RecordingRuleInfluxDBSettings) { s.URL = "/api/v2/write" }},
+		{name: "missing organization", mutate: func(s *setting.RecordingRuleInfluxDBSettings) { s.Organization = "" }},
+		{name: "missing bucket", mutate: func(s *setting.RecordingRuleInfluxDBSettings) { s.Bucket = "" }},
+		{name: "timeout is 0", mutate: func(s *setting.RecordingRuleInfluxDBSettings) { s.Timeout = 0 }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := valid
+			tc.mutate(&s)
+			require.Error(t, validateInfluxDBSettings(s))
+		})
+	}
+}
+
+func TestEncodeLineProtocol(t *testing.T) {
+	ts := time.UnixMilli(1700000000123)
+	points := []Point{
+		{Name: "test metric", Labels: map[string]string{"b": "x y", "a": "1,2", "empty": ""}, Metric: Metric{T: ts, V: 1.5}},
+		{Name: "test", Labels: map[string]string{}, Metric: Metric{T: ts, V: 2}},
+		{Name: "test", Labels: map[string]string{"a": "nan"}, Metric: Metric{T: ts, V: math.NaN()}},
+		{Name: "test", Labels: map[string]string{"a": "inf"}, Metric: Metric{T: ts, V: math.Inf(1)}},
+	}
+
+	expected := "test\\ metric,a=1\\,2,b=x\\ y value=1.5 1700000000123\n" +
+		"test value=2 1700000000123\n"
+	require.Equal(t, expected, string(encodeLineProtocol(points)))
+}
+
+func TestInfluxDBWriter_Write(t *testing.T) {
+	var (
+		lastRequest *http.Request
+		lastBody    string
+		status      = http.StatusNoContent
+		resBody     = ""
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		lastRequest = r
+		lastBody = string(b)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(resBody))
+	}))
+	t.Cleanup(srv.Close)
+
+	writer, err := NewInfluxDBWriter(setting.RecordingRuleInfluxDBSettings{
+		URL:          srv.URL + "/",
+		Organization: "my org",
+		Bucket:       "bucket",
+		Token:        "secret",
+		Timeout:      time.Second,
+	}, testClientProvider{}, clock.New(), log.New("test"), metrics.NewRemoteWriterMetrics(prometheus.NewRegistry()))
+	require.NoError(t, err)
+
+	now := time.UnixMilli(1700000000000)
+	series := []map[string]string{{"foo": "1"}}
+	frames := frameGenFromLabels(t, data.FrameTypeNumericWide, series)
+	ctx := ngmodels.WithRuleKey(context.Background(), ngmodels.GenerateRuleKey(1))
+
+	t.Run("writes expected points", func(t *testing.T) {
+		err := writer.Write(ctx, "test", now, frames, 1, map[string]string{"extra": "label"})
+		require.NoError(t, err)
+
+		require.Equal(t, "/api/v2/write", lastRequest.URL.Path)
+		require.Equal(t, "my org", lastRequest.URL.Query().Get("org"))
+		require.Equal(t, "bucket", lastRequest.URL.Query().Get("bucket"))
+		require.Equal(t, "ms", lastRequest.URL.Query().Get("precision"))
+		require.Equal(t, "Token secret", lastRequest.Header.Get("Authorization"))
+		v := extractValue(t, frames, series[0], data.FrameTypeNumericWide)
+		require.Equal(t, string(encodeLineProtocol([]Point{{
+			Name:   "test",
+			Labels: map[string]string{"extra": "label", "foo": "1"},
+			Metric: Metric{T: now, V: v},
+		}})), lastBody)
+	})
+
+	t.Run("include status code when server fails", func(t *testing.T) {
+		status = http.StatusInternalServerError
+		resBody = "internal error"
+
+		err := writer.Write(ctx, "test", now, frames, 1, nil)
+		require.ErrorContains(t, err, "500")
+		require.ErrorContains(t, err, "internal error")
+
+		var writeErr WriteError
+		require.ErrorAs(t, err, &writeErr)
+		require.Equal(t, http.StatusInternalServerError, writeErr.StatusCode())
+	})
+
+	t.Run("ignores error when status code is 400 and message contains duplicate timestamp error", func(t *testing.T) {
+		status = http.StatusBadRequest
+		resBody = PrometheusDuplicateTimestampError
+
+		err := writer.Write(ctx, "test", now, frames, 1, nil)
+		require.NoError(t, err)
+	})
+}
+
+type testClientProvider struct{}
+
+func (testClientProvider) New(...httpclient.Options) (*http.Client, error) {
+	return &http.Client{}, nil
+}
@@ -0,0 +1,144 @@
+package writer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	otlpServiceName = "grafana"
+	otlpScopeName   = "grafana-recording-rule"
+)
+
+// OTLPWriter exports the result of recording rules as gauges to an OpenTelemetry OTLP/HTTP metrics endpoint.
+type OTLPWriter struct {
+	client   *http.Client
+	writeURL string
+	clock    clock.Clock
+	logger   log.Logger
+	metrics  *metrics.RemoteWriter
+}
+
+func NewOTLPWriter(
+	settings setting.RecordingRuleOTLPSettings,
+	httpClientProvider HttpClientProvider,
+	clock clock.Clock,
+	l log.Logger,
+	metrics *metrics.RemoteWriter,
+) (*OTLPWriter, error) {
+	if err := validateOTLPSettings(settings); err != nil {
+		return nil, err
+	}
+
+	cl, err := newHTTPClient(httpClientProvider, settings.CustomHeaders, settings.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OTLPWriter{
+		client:   cl,
+		writeURL: settings.URL,
+		clock:    clock,
+		logger:   l,
+		metrics:  metrics,
+	}, nil
+}
+
+func validateOTLPSettings(settings setting.RecordingRuleOTLPSettings) error {
+	if err := validateWriteURL(settings.URL); err != nil {
+		return err
+	}
+
+	if settings.Timeout <= 0 {
+		return fmt.Errorf("timeout must be greater than 0")
+	}
+
+	return nil
+}
+
+// Write exports the given frames to the OTLP endpoint.
+func (w *OTLPWriter) Write(ctx context.Context, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error {
+	l := w.logger.FromContext(ctx)
+	lvs := []string{fmt.Sprint(orgID), TypeOTLP}
+
+	points, err := PointsFromFrames(name, t, frames, extraLabels)
+	if err != nil {
+		return err
+	}
+	if len(points) == 0 {
+		l.Debug("No points to write", "name", name)
+		return nil
+	}
+
+	body, err := pmetricotlp.NewExportRequestFromMetrics(metricsFromPoints(name, points)).MarshalProto()
+	if err != nil {
+		return fmt.Errorf("failed to encode metrics: %w", err)
+	}
+
+	header := make(http.Header)
+	header.Set("Content-Type", "application/x-protobuf")
+
+	l.Debug("Writing metric", "name", name)
+	writeStart := w.clock.Now()
+	statusCode, resBody, writeErr := doWriteRequest(ctx, w.client, w.writeURL, header, body)
+	w.metrics.WriteDuration.WithLabelValues(lvs...).Observe(w.clock.Now().Sub(writeStart).Seconds())
+
+	lvs = append(lvs, fmt.Sprint(statusCode))
+	w.metrics.WritesTotal.WithLabelValues(lvs...).Inc()
+
+	if err, ignored := checkWriteError(writeErr); err != nil {
+		return fmt.Errorf("failed to export metrics: %w", err)
+	} else if ignored {
+		l.Debug("Ignored write error", "error", err, "status_code", statusCode)
+		return nil
+	}
+
+	// The server can accept the request but reject some of the data points.
+	res := pmetricotlp.NewExportResponse()
+	if len(resBody) > 0 {
+		if err := res.UnmarshalProto(resBody); err != nil {
+			l.Debug("Failed to decode export response", "error", err)
+			return nil
+		}
+	}
+	if ps := res.PartialSuccess(); ps.RejectedDataPoints() > 0 {
+		return fmt.Errorf("failed to export metrics: %d data points were rejected: %s", ps.RejectedDataPoints(), ps.ErrorMessage())
+	}
+
+	return nil
+}
+
+// metricsFromPoints creates a gauge with a data point for each point.
+func metricsFromPoints(name string, points []Point) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", otlpServiceName)
+	sm := rm.ScopeMetrics().AppendEmpty()
+	sm.Scope().SetName(otlpScopeName)
+
+	m := sm.Metrics().AppendEmpty()
+	m.SetName(name)
+	dps := m.SetEmptyGauge().DataPoints()
+	dps.EnsureCapacity(len(points))
+	for _, p := range points {
+		dp := dps.AppendEmpty()
+		dp.SetTimestamp(pcommon.NewTimestampFromTime(p.Metric.T))
+		dp.SetDoubleValue(p.Metric.V)
+		for k, v := range p.Labels {
+			dp.Attributes().PutStr(k, v)
+		}
+	}
+	return md
+}
@@ -0,0 +1,131 @@
+package writer
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestValidateOTLPSettings(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		settings setting.RecordingRuleOTLPSettings
+		err      bool
+	}{
+		{
+			name:     "invalid url",
+			settings: setting.RecordingRuleOTLPSettings{URL: "invalid url", Timeout: time.Second},
+			err:      true,
+		},
+		{
+			name:     "timeout is 0",
+			settings: setting.RecordingRuleOTLPSettings{URL: "http://localhost:4318/v1/metrics"},
+			err:      true,
+		},
+		{
+			name:     "valid settings",
+			settings: setting.RecordingRuleOTLPSettings{URL: "http://localhost:4318/v1/metrics", Timeout: time.Second},
+			err:      false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateOTLPSettings(tc.settings)
+			if tc.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestOTLPWriter_Write(t *testing.T) {
+	var (
+		lastRequest *http.Request
+		lastBody    []byte
+		status      = http.StatusOK
+		response    = pmetricotlp.NewExportResponse()
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		lastRequest = r
+		lastBody = b
+		w.WriteHeader(status)
+		res, err := response.MarshalProto()
+		require.NoError(t, err)
+		_, _ = w.Write(res)
+	}))
+	t.Cleanup(srv.Close)
+
+	writer, err := NewOTLPWriter(setting.RecordingRuleOTLPSettings{
+		URL:           srv.URL + "/v1/metrics",
+		CustomHeaders: map[string]string{"X-Scope-OrgID": "tenant"},
+		Timeout:       time.Second,
+	}, testClientProvider{}, clock.New(), log.New("test"), metrics.NewRemoteWriterMetrics(prometheus.NewRegistry()))
+	require.NoError(t, err)
+
+	now := time.UnixMilli(1700000000000)
+	series := []map[string]string{{"foo": "1"}, {"foo": "2"}}
+	frames := frameGenFromLabels(t, data.FrameTypeNumericWide, series)
+	ctx := ngmodels.WithRuleKey(context.Background(), ngmodels.GenerateRuleKey(1))
+
+	t.Run("writes expected gauge", func(t *testing.T) {
+		err := writer.Write(ctx, "test", now, frames, 1, map[string]string{"extra": "label"})
+		require.NoError(t, err)
+
+		require.Equal(t, "/v1/metrics", lastRequest.URL.Path)
+		require.Equal(t, "application/x-protobuf", lastRequest.Header.Get("Content-Type"))
+
+		req := pmetricotlp.NewExportRequest()
+		require.NoError(t, req.UnmarshalProto(lastBody))
+		require.Equal(t, 1, req.Metrics().MetricCount())
+		m := req.Metrics().ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0)
+		require.Equal(t, "test", m.Name())
+		require.Equal(t, pmetric.MetricTypeGauge, m.Type())
+		require.Equal(t, len(series), m.Gauge().DataPoints().Len())
+		for i := 0; i < m.Gauge().DataPoints().Len(); i++ {
+			dp := m.Gauge().DataPoints().At(i)
+			require.Equal(t, now.UnixNano(), dp.Timestamp().AsTime().UnixNano())
+			extra, _ := dp.Attributes().Get("extra")
+			require.Equal(t, "label", extra.Str())
+			foo, _ := dp.Attributes().Get("foo")
+			require.Equal(t, extractValue(t, frames, map[string]string{"foo": foo.Str()}, data.FrameTypeNumericWide), dp.DoubleValue())
+		}
+	})
+
+	t.Run("error when data points are rejected", func(t *testing.T) {
+		response = pmetricotlp.NewExportResponse()
+		response.PartialSuccess().SetRejectedDataPoints(1)
+		response.PartialSuccess().SetErrorMessage("invalid metric")
+		t.Cleanup(func() { response = pmetricotlp.NewExportResponse() })
+
+		err := writer.Write(ctx, "test", now, frames, 1, nil)
+		require.ErrorContains(t, err, "invalid metric")
+	})
+
+	t.Run("include status code when server fails", func(t *testing.T) {
+		status = http.StatusServiceUnavailable
+		t.Cleanup(func() { status = http.StatusOK })
+
+		err := writer.Write(ctx, "test", now, frames, 1, nil)
+		var writeErr WriteError
+		require.ErrorAs(t, err, &writeErr)
+		require.Equal(t, http.StatusServiceUnavailable, writeErr.StatusCode())
+	})
+}
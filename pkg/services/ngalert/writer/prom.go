@@ -19,8 +19,6 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 )
 
-const backendType = "prometheus"
-
 const (
 	// Fixed error messages
 	MimirDuplicateTimestampError = "err-mimir-sample-duplicate-timestamp"
@@ -175,7 +173,7 @@ func createAuthOpts(username, password string) *httpclient.BasicAuthOptions {
 // Write writes the given frames to the Prometheus remote write endpoint.
 func (w PrometheusWriter) Write(ctx context.Context, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error {
 	l := w.logger.FromContext(ctx)
-	lvs := []string{fmt.Sprint(orgID), backendType}
+	lvs := []string{fmt.Sprint(orgID), TypePrometheus}
 
 	points, err := PointsFromFrames(name, t, frames, extraLabels)
 	if err != nil {
@@ -225,7 +223,8 @@ func promremoteLabelsFromPoint(point Point) []promremote.Label {
 	return labels
 }
 
-func checkWriteError(writeErr promremote.WriteError) (err error, ignored bool) {
+// checkWriteError returns the error of a write, unless it can be ignored.
+func checkWriteError(writeErr WriteError) (err error, ignored bool) {
 	if writeErr == nil {
 		return nil, false
 	}
@@ -0,0 +1,135 @@
+package writer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	// TypePrometheus writes to a Prometheus remote write endpoint.
+	TypePrometheus = "prometheus"
+	// TypeInfluxDB writes to InfluxDB with line protocol.
+	TypeInfluxDB = "influxdb"
+	// TypeOTLP exports to an OpenTelemetry OTLP/HTTP metrics endpoint.
+	TypeOTLP = "otlp"
+	// TypeSQL writes to a table in the Grafana database.
+	TypeSQL = "sql"
+)
+
+var ErrUnknownWriter = errors.New("unknown recording rule writer")
+
+// Writer writes the result of a recording rule evaluation.
+type Writer interface {
+	Write(ctx context.Context, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error
+}
+
+// Registry holds the configured writers and selects the writer of a recording rule.
+// A rule can select a writer by type, otherwise the writer of its organization or the default writer is used.
+type Registry struct {
+	writers     map[string]Writer
+	defaultType string
+	orgTypes    map[int64]string
+}
+
+// NewRegistry creates a registry of the writers. The default type and the types of the organizations must be registered.
+func NewRegistry(defaultType string, orgTypes map[int64]string, writers map[string]Writer) (*Registry, error) {
+	if _, ok := writers[defaultType]; !ok {
+		return nil, fmt.Errorf("%w: default writer %q is not configured", ErrUnknownWriter, defaultType)
+	}
+	for orgID, t := range orgTypes {
+		if _, ok := writers[t]; !ok {
+			return nil, fmt.Errorf("%w: writer %q of organization %d is not configured", ErrUnknownWriter, t, orgID)
+		}
+	}
+	return &Registry{
+		writers:     writers,
+		defaultType: defaultType,
+		orgTypes:    orgTypes,
+	}, nil
+}
+
+// Writer returns the writer of the given type. If the type is empty, the writer of the organization is returned.
+func (r *Registry) Writer(orgID int64, writerType string) (Writer, error) {
+	if writerType == "" {
+		writerType = r.defaultType
+		if t, ok := r.orgTypes[orgID]; ok {
+			writerType = t
+		}
+	}
+	w, ok := r.writers[writerType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q, configured writers are %v", ErrUnknownWriter, writerType, r.Types())
+	}
+	return w, nil
+}
+
+// Types returns the sorted types of the registered writers.
+func (r *Registry) Types() []string {
+	types := make([]string, 0, len(r.writers))
+	for t := range r.writers {
+		types = append(types, t)
+	}
+	slices.Sort(types)
+	return types
+}
+
+// EnabledTypes returns the sorted types of the writers that are enabled by the settings.
+// The Prometheus writer is always enabled.
+func EnabledTypes(settings setting.RecordingRuleSettings) []string {
+	types := []string{TypePrometheus}
+	if settings.InfluxDB.URL != "" {
+		types = append(types, TypeInfluxDB)
+	}
+	if settings.OTLP.URL != "" {
+		types = append(types, TypeOTLP)
+	}
+	if settings.SQL.Enabled {
+		types = append(types, TypeSQL)
+	}
+	slices.Sort(types)
+	return types
+}
+
+// NewRegistryFromSettings creates the writers that are enabled by the settings and a registry of them.
+func NewRegistryFromSettings(
+	settings setting.RecordingRuleSettings,
+	httpClientProvider HttpClientProvider,
+	store db.DB,
+	clock clock.Clock,
+	l log.Logger,
+	m *metrics.RemoteWriter,
+) (*Registry, error) {
+	writers := make(map[string]Writer, 4)
+	for _, t := range EnabledTypes(settings) {
+		var (
+			w   Writer
+			err error
+		)
+		switch t {
+		case TypePrometheus:
+			w, err = NewPrometheusWriter(settings, httpClientProvider, clock, l, m)
+		case TypeInfluxDB:
+			w, err = NewInfluxDBWriter(settings.InfluxDB, httpClientProvider, clock, l, m)
+		case TypeOTLP:
+			w, err = NewOTLPWriter(settings.OTLP, httpClientProvider, clock, l, m)
+		case TypeSQL:
+			w, err = NewSQLWriter(store, clock, l, m)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s recording rule writer: %w", t, err)
+		}
+		writers[t] = w
+	}
+	return NewRegistry(settings.Writer, settings.OrgWriters, writers)
+}
@@ -0,0 +1,97 @@
+package writer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestNewRegistry(t *testing.T) {
+	writers := map[string]Writer{
+		TypePrometheus: FakeWriter{},
+		TypeSQL:        NoopWriter{},
+	}
+
+	t.Run("error when default writer is not configured", func(t *testing.T) {
+		_, err := NewRegistry(TypeInfluxDB, nil, writers)
+		require.ErrorIs(t, err, ErrUnknownWriter)
+	})
+
+	t.Run("error when writer of organization is not configured", func(t *testing.T) {
+		_, err := NewRegistry(TypePrometheus, map[int64]string{2: TypeOTLP}, writers)
+		require.ErrorIs(t, err, ErrUnknownWriter)
+	})
+
+	t.Run("valid registry", func(t *testing.T) {
+		r, err := NewRegistry(TypePrometheus, map[int64]string{2: TypeSQL}, writers)
+		require.NoError(t, err)
+		require.Equal(t, []string{TypePrometheus, TypeSQL}, r.Types())
+	})
+}
+
+func TestRegistry_Writer(t *testing.T) {
+	writers := map[string]Writer{
+		TypePrometheus: FakeWriter{},
+		TypeSQL:        NoopWriter{},
+	}
+	r, err := NewRegistry(TypePrometheus, map[int64]string{2: TypeSQL}, writers)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name       string
+		orgID      int64
+		writerType string
+		expected   Writer
+		err        bool
+	}{
+		{
+			name:     "default writer",
+			orgID:    1,
+			expected: FakeWriter{},
+		},
+		{
+			name:     "writer of organization",
+			orgID:    2,
+			expected: NoopWriter{},
+		},
+		{
+			name:       "writer of rule",
+			orgID:      1,
+			writerType: TypeSQL,
+			expected:   NoopWriter{},
+		},
+		{
+			name:       "writer of rule overrides organization",
+			orgID:      2,
+			writerType: TypePrometheus,
+			expected:   FakeWriter{},
+		},
+		{
+			name:       "unknown writer",
+			orgID:      1,
+			writerType: TypeOTLP,
+			err:        true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w, err := r.Writer(tc.orgID, tc.writerType)
+			if tc.err {
+				require.ErrorIs(t, err, ErrUnknownWriter)
+				return
+			}
+			require.NoError(t, err)
+			require.IsType(t, tc.expected, w)
+		})
+	}
+}
+
+func TestEnabledTypes(t *testing.T) {
+	require.Equal(t, []string{TypePrometheus}, EnabledTypes(setting.RecordingRuleSettings{}))
+	require.Equal(t, []string{TypeInfluxDB, TypeOTLP, TypePrometheus, TypeSQL}, EnabledTypes(setting.RecordingRuleSettings{
+		InfluxDB: setting.RecordingRuleInfluxDBSettings{URL: "http://localhost:8086"},
+		OTLP:     setting.RecordingRuleOTLPSettings{URL: "http://localhost:4318/v1/metrics"},
+		SQL:      setting.RecordingRuleSQLSettings{Enabled: true},
+	}))
+}
@@ -0,0 +1,128 @@
+package writer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+)
+
+const (
+	sqlStatusOK    = "ok"
+	sqlStatusError = "error"
+)
+
+// RecordingRuleSeries is a sample of a series written by a recording rule to the recording_rule_series table.
+type RecordingRuleSeries struct {
+	ID          int64   `xorm:"pk autoincr 'id'"`
+	OrgID       int64   `xorm:"org_id"`
+	Metric      string  `xorm:"metric"`
+	Labels      string  `xorm:"labels"`
+	LabelsHash  string  `xorm:"labels_hash"`
+	TimestampMs int64   `xorm:"timestamp_ms"`
+	Value       float64 `xorm:"value"`
+}
+
+func (RecordingRuleSeries) TableName() string {
+	return "recording_rule_series"
+}
+
+// SQLWriter writes the result of recording rules to the recording_rule_series table of the Grafana database.
+type SQLWriter struct {
+	store   db.DB
+	clock   clock.Clock
+	logger  log.Logger
+	metrics *metrics.RemoteWriter
+}
+
+func NewSQLWriter(store db.DB, clock clock.Clock, l log.Logger, metrics *metrics.RemoteWriter) (*SQLWriter, error) {
+	if store == nil {
+		return nil, errors.New("database is required")
+	}
+
+	return &SQLWriter{
+		store:   store,
+		clock:   clock,
+		logger:  l,
+		metrics: metrics,
+	}, nil
+}
+
+// Write inserts the given frames into the recording_rule_series table.
+// Samples that already exist for the series and timestamp are ignored, as HA may write the same sample more than once.
+func (w *SQLWriter) Write(ctx context.Context, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error {
+	l := w.logger.FromContext(ctx)
+	lvs := []string{fmt.Sprint(orgID), TypeSQL}
+
+	points, err := PointsFromFrames(name, t, frames, extraLabels)
+	if err != nil {
+		return err
+	}
+
+	rows := make([]RecordingRuleSeries, 0, len(points))
+	for _, p := range points {
+		// NaN and infinite values cannot be stored in all databases.
+		if math.IsNaN(p.Metric.V) || math.IsInf(p.Metric.V, 0) {
+			continue
+		}
+		labels, err := json.Marshal(p.Labels)
+		if err != nil {
+			return fmt.Errorf("failed to encode labels: %w", err)
+		}
+		rows = append(rows, RecordingRuleSeries{
+			OrgID:       orgID,
+			Metric:      p.Name,
+			Labels:      string(labels),
+			LabelsHash:  data.Labels(p.Labels).Fingerprint().String(),
+			TimestampMs: p.Metric.T.UnixMilli(),
+			Value:       p.Metric.V,
+		})
+	}
+	if len(rows) == 0 {
+		l.Debug("No points to write", "name", name)
+		return nil
+	}
+
+	l.Debug("Writing metric", "name", name)
+	ignored := 0
+	writeStart := w.clock.Now()
+	// Rows are inserted one by one, outside of a transaction, so that a duplicate does not fail the other rows.
+	writeErr := w.store.WithDbSession(ctx, func(sess *db.Session) error {
+		for i := range rows {
+			if _, err := sess.Insert(&rows[i]); err != nil {
+				if w.store.GetDialect().IsUniqueConstraintViolation(err) {
+					ignored++
+					continue
+				}
+				return err
+			}
+		}
+		return nil
+	})
+	w.metrics.WriteDuration.WithLabelValues(lvs...).Observe(w.clock.Now().Sub(writeStart).Seconds())
+
+	status := sqlStatusOK
+	if writeErr != nil {
+		status = sqlStatusError
+	}
+	lvs = append(lvs, status)
+	w.metrics.WritesTotal.WithLabelValues(lvs...).Inc()
+
+	if writeErr != nil {
+		return fmt.Errorf("failed to insert series: %w", writeErr)
+	}
+	if ignored > 0 {
+		l.Debug("Ignored duplicate samples", "count", ignored)
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package writer
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+func TestIntegrationSQLWriter_Write(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	store := db.InitTestDB(t)
+	writer, err := NewSQLWriter(store, clock.New(), log.New("test"), metrics.NewRemoteWriterMetrics(prometheus.NewRegistry()))
+	require.NoError(t, err)
+
+	now := time.UnixMilli(1700000000000)
+	series := []map[string]string{{"foo": "1"}, {"foo": "2"}}
+	frames := frameGenFromLabels(t, data.FrameTypeNumericWide, series)
+	ctx := ngmodels.WithRuleKey(context.Background(), ngmodels.GenerateRuleKey(1))
+
+	getRows := func(t *testing.T) []RecordingRuleSeries {
+		t.Helper()
+		var rows []RecordingRuleSeries
+		err := store.WithDbSession(ctx, func(sess *db.Session) error {
+			return sess.OrderBy("id").Find(&rows)
+		})
+		require.NoError(t, err)
+		return rows
+	}
+
+	t.Run("writes expected rows", func(t *testing.T) {
+		err := writer.Write(ctx, "test", now, frames, 1, map[string]string{"extra": "label"})
+		require.NoError(t, err)
+
+		rows := getRows(t)
+		require.Len(t, rows, len(series))
+		for _, row := range rows {
+			var labels map[string]string
+			require.NoError(t, json.Unmarshal([]byte(row.Labels), &labels))
+			require.Equal(t, "label", labels["extra"])
+			require.Equal(t, int64(1), row.OrgID)
+			require.Equal(t, "test", row.Metric)
+			require.Equal(t, data.Labels(labels).Fingerprint().String(), row.LabelsHash)
+			require.Equal(t, now.UnixMilli(), row.TimestampMs)
+			require.Equal(t, extractValue(t, frames, map[string]string{"foo": labels["foo"]}, data.FrameTypeNumericWide), row.Value)
+		}
+	})
+
+	t.Run("ignores duplicate samples", func(t *testing.T) {
+		err := writer.Write(ctx, "test", now, frames, 1, map[string]string{"extra": "label"})
+		require.NoError(t, err)
+		require.Len(t, getRows(t), len(series))
+
+		err = writer.Write(ctx, "test", now.Add(time.Minute), frames, 1, map[string]string{"extra": "label"})
+		require.NoError(t, err)
+		require.Len(t, getRows(t), 2*len(series))
+	})
+
+	t.Run("skips NaN values", func(t *testing.T) {
+		nanFrames := frameGenFromLabels(t, data.FrameTypeNumericWide, []map[string]string{{"foo": "nan"}})
+		nanFrames[0].Fields[1].Set(0, math.NaN())
+
+		err := writer.Write(ctx, "nan", now, nanFrames, 1, nil)
+		require.NoError(t, err)
+		for _, row := range getRows(t) {
+			require.NotEqual(t, "nan", row.Metric)
+		}
+	})
+}
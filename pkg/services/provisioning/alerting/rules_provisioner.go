@@ -56,15 +56,19 @@ func (prov *defaultAlertRuleProvisioner) Provision(ctx context.Context,
 				"folder", group.FolderFullpath,
 				"folderUID", folderUID,
 				"name", group.Title)
-			for _, rule := range group.Rules {
+			for i, rule := range group.Rules {
 				rule.NamespaceUID = folderUID
 				rule.RuleGroup = group.Title
+				if group.SequentialEvaluation {
+					// the rules of a sequential group are evaluated in the order they are defined in the file.
+					rule.RuleGroupIndex = i + 1
+				}
 				err = prov.provisionRule(ctx, u, rule)
 				if err != nil {
 					return err
 				}
 			}
-			err = prov.ruleService.UpdateRuleGroup(ctx, u, folderUID, group.Title, group.Interval)
+			err = prov.ruleService.UpdateRuleGroup(ctx, u, folderUID, group.Title, group.Interval, group.SequentialEvaluation)
 			if err != nil {
 				return err
 			}
@@ -29,7 +29,9 @@ type AlertRuleGroupV1 struct {
 	Name     values.StringValue `json:"name" yaml:"name"`
 	Folder   values.StringValue `json:"folder" yaml:"folder"`
 	Interval values.StringValue `json:"interval" yaml:"interval"`
-	Rules    []AlertRuleV1      `json:"rules" yaml:"rules"`
+	// Sequential makes the rules of the group evaluate one after another in the order they are defined.
+	Sequential values.BoolValue `json:"sequential" yaml:"sequential"`
+	Rules      []AlertRuleV1    `json:"rules" yaml:"rules"`
 }
 
 func (ruleGroupV1 *AlertRuleGroupV1) MapToModel() (models.AlertRuleGroupWithFolderFullpath, error) {
@@ -51,13 +53,22 @@ func (ruleGroupV1 *AlertRuleGroupV1) MapToModel() (models.AlertRuleGroupWithFold
 	if strings.TrimSpace(ruleGroup.FolderFullpath) == "" {
 		return models.AlertRuleGroupWithFolderFullpath{}, errors.New("rule group has no folder set")
 	}
+	ruleGroup.SequentialEvaluation = ruleGroupV1.Sequential.Value()
 	for _, ruleV1 := range ruleGroupV1.Rules {
 		rule, err := ruleV1.mapToModel(ruleGroup.OrgID)
 		if err != nil {
 			return models.AlertRuleGroupWithFolderFullpath{}, err
 		}
+		rule.SequentialEvaluation = ruleGroup.SequentialEvaluation
 		ruleGroup.Rules = append(ruleGroup.Rules, rule)
 	}
+	dependencies := make([]*models.AlertRule, 0, len(ruleGroup.Rules))
+	for i := range ruleGroup.Rules {
+		dependencies = append(dependencies, &ruleGroup.Rules[i])
+	}
+	if err := models.ValidateRuleGroupDependencies(dependencies); err != nil {
+		return models.AlertRuleGroupWithFolderFullpath{}, fmt.Errorf("rule group '%s' failed to parse: %w", ruleGroup.Title, err)
+	}
 	return ruleGroup, nil
 }
 
@@ -76,6 +87,7 @@ type AlertRuleV1 struct {
 	IsPaused             values.BoolValue        `json:"isPaused" yaml:"isPaused"`
 	NotificationSettings *NotificationSettingsV1 `json:"notification_settings" yaml:"notification_settings"`
 	Record               *RecordV1               `json:"record" yaml:"record"`
+	DependsOn            values.StringValue      `json:"dependsOn" yaml:"dependsOn"`
 }
 
 func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
@@ -147,6 +159,10 @@ func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
 		}
 		alertRule.Record = &record
 	}
+	alertRule.DependsOn = rule.DependsOn.Value()
+	if alertRule.DependsOn != "" && alertRule.Record != nil {
+		return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: recording rules cannot depend on other rules", alertRule.Title)
+	}
 	return alertRule, nil
 }
 
@@ -258,11 +274,13 @@ func (nsV1 *NotificationSettingsV1) mapToModel() (models.NotificationSettings, e
 type RecordV1 struct {
 	Metric values.StringValue `json:"metric" yaml:"metric"`
 	From   values.StringValue `json:"from" yaml:"from"`
+	Writer values.StringValue `json:"writer" yaml:"writer"`
 }
 
 func (record *RecordV1) mapToModel() (models.Record, error) {
 	return models.Record{
 		Metric: record.Metric.Value(),
 		From:   record.From.Value(),
+		Writer: record.Writer.Value(),
 	}, nil
 }
@@ -82,6 +82,33 @@ func TestRuleGroup(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, int64(1), rgMapped.OrgID)
 	})
+	t.Run("a sequential rule group should map it to all rules", func(t *testing.T) {
+		rg := validRuleGroupV1(t)
+		err := yaml.Unmarshal([]byte("true"), &rg.Sequential)
+		require.NoError(t, err)
+		first := validRuleV1(t)
+		second := validRuleV1(t)
+		second.UID = stringToStringValue("test_uid_2")
+		second.DependsOn = stringToStringValue("test_uid")
+		rg.Rules = []AlertRuleV1{first, second}
+		rgMapped, err := rg.MapToModel()
+		require.NoError(t, err)
+		require.True(t, rgMapped.SequentialEvaluation)
+		for _, rule := range rgMapped.Rules {
+			require.True(t, rule.SequentialEvaluation)
+		}
+		require.Equal(t, "test_uid", rgMapped.Rules[1].DependsOn)
+	})
+	t.Run("a rule group with a rule that depends on a following rule should error", func(t *testing.T) {
+		rg := validRuleGroupV1(t)
+		first := validRuleV1(t)
+		first.DependsOn = stringToStringValue("test_uid_2")
+		second := validRuleV1(t)
+		second.UID = stringToStringValue("test_uid_2")
+		rg.Rules = []AlertRuleV1{first, second}
+		_, err := rg.MapToModel()
+		require.Error(t, err)
+	})
 }
 
 func TestRules(t *testing.T) {
@@ -0,0 +1,24 @@
+package annotations
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/annotations"
+)
+
+// Provision scans a directory for provisioning config files and sets the
+// annotation retention rules in those files. The rules are applied by the
+// cleanup job.
+func Provision(_ context.Context, configDirectory string, cleaner annotations.Cleaner) error {
+	logger := log.New("provisioning.annotations")
+	cr := &configReader{log: logger}
+	rules, err := cr.readConfig(configDirectory)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Provisioned annotation retention rules", "count", len(rules))
+	cleaner.SetProvisionedRetentionRules(rules)
+	return nil
+}
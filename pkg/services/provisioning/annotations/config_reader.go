@@ -0,0 +1,71 @@
+package annotations
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type configReader struct {
+	log log.Logger
+}
+
+// readConfig reads the retention rules of all provisioning files in the directory.
+func (cr *configReader) readConfig(path string) ([]setting.AnnotationRetentionRule, error) {
+	rules := make([]setting.AnnotationRetentionRule, 0)
+	cr.log.Debug("Looking for annotations provisioning files", "path", path)
+
+	files, err := os.ReadDir(path)
+	if err != nil {
+		cr.log.Error("Failed to read annotations provisioning files from directory", "path", path, "error", err)
+		return rules, nil
+	}
+
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".yaml") && !strings.HasSuffix(file.Name(), ".yml") {
+			continue
+		}
+
+		cr.log.Debug("Parsing annotations provisioning file", "path", path, "file.Name", file.Name())
+		cfg, err := cr.parseConfig(filepath.Join(path, file.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file.Name(), err)
+		}
+		rules = append(rules, cfg.RetentionRules...)
+	}
+
+	names := make(map[string]struct{}, len(rules))
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return nil, err
+		}
+		if _, ok := names[rule.Name]; ok {
+			return nil, fmt.Errorf("retention rule %q is provisioned more than once", rule.Name)
+		}
+		names[rule.Name] = struct{}{}
+	}
+
+	return rules, nil
+}
+
+func (cr *configReader) parseConfig(filename string) (*annotationsAsConfig, error) {
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `filename` comes from ps.Cfg.ProvisioningPath
+	yamlFile, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg *annotationsAsConfigV1
+	if err := yaml.Unmarshal(yamlFile, &cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg.mapToAnnotationsFromConfig()
+}
@@ -0,0 +1,74 @@
+package annotations
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	correctProperties = "./testdata/correct-properties"
+	brokenYaml        = "./testdata/broken-yaml"
+	invalidRule       = "./testdata/invalid-rule"
+	duplicateNames    = "./testdata/duplicate-names"
+	missingFolder     = "./testdata/missing-folder"
+)
+
+func TestConfigReader(t *testing.T) {
+	reader := &configReader{log: log.New("test logger")}
+
+	t.Run("Can read correct properties", func(t *testing.T) {
+		t.Setenv("ENV", "prod")
+
+		rules, err := reader.readConfig(correctProperties)
+		require.NoError(t, err)
+		require.Equal(t, []setting.AnnotationRetentionRule{
+			{
+				Name:  "org-3",
+				OrgID: 3,
+				Tags:  []string{},
+				AnnotationCleanupSettings: setting.AnnotationCleanupSettings{
+					MaxAge:   7 * 24 * time.Hour,
+					MaxCount: 1000,
+				},
+			},
+			{
+				Name:                      "deployments",
+				Tags:                      []string{"deploy", "env:prod"},
+				AnnotationCleanupSettings: setting.AnnotationCleanupSettings{MaxAge: 30 * 24 * time.Hour},
+			},
+			{
+				Name:                      "noisy-dashboard",
+				OrgID:                     2,
+				DashboardUID:              "noisy",
+				Tags:                      []string{},
+				AnnotationCleanupSettings: setting.AnnotationCleanupSettings{MaxCount: 100},
+			},
+		}, rules)
+	})
+
+	t.Run("Broken yaml should return error", func(t *testing.T) {
+		_, err := reader.readConfig(brokenYaml)
+		require.Error(t, err)
+	})
+
+	t.Run("Rule without matchers should return error", func(t *testing.T) {
+		_, err := reader.readConfig(invalidRule)
+		require.ErrorContains(t, err, `retention rule "everything" must match on org, dashboard or tags`)
+	})
+
+	t.Run("Rules with the same name should return error", func(t *testing.T) {
+		_, err := reader.readConfig(duplicateNames)
+		require.ErrorContains(t, err, `retention rule "deployments" is provisioned more than once`)
+	})
+
+	t.Run("Skip missing directory", func(t *testing.T) {
+		rules, err := reader.readConfig(missingFolder)
+		require.NoError(t, err)
+		require.Empty(t, rules)
+	})
+}
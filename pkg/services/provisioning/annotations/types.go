@@ -0,0 +1,69 @@
+package annotations
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// annotationsAsConfig is a normalized data object for annotations config data. Any config version should be mappable
+// to this type.
+type annotationsAsConfig struct {
+	RetentionRules []setting.AnnotationRetentionRule
+}
+
+type retentionRuleFromConfigV1 struct {
+	Name                 values.StringValue   `json:"name" yaml:"name"`
+	OrgID                values.Int64Value    `json:"orgId" yaml:"orgId"`
+	DashboardUID         values.StringValue   `json:"dashboardUid" yaml:"dashboardUid"`
+	Tags                 []values.StringValue `json:"tags" yaml:"tags"`
+	MaxAge               values.StringValue   `json:"maxAge" yaml:"maxAge"`
+	MaxAnnotationsToKeep values.Int64Value    `json:"maxAnnotationsToKeep" yaml:"maxAnnotationsToKeep"`
+}
+
+// annotationsAsConfigV1 is a mapping for version 1 configs. This is mapped to its normalised version.
+type annotationsAsConfigV1 struct {
+	RetentionRules []*retentionRuleFromConfigV1 `json:"retentionRules" yaml:"retentionRules"`
+}
+
+// mapToAnnotationsFromConfig maps config syntax to a normalized annotationsAsConfig object. Every version
+// of the config syntax should have this function.
+func (cfg *annotationsAsConfigV1) mapToAnnotationsFromConfig() (*annotationsAsConfig, error) {
+	r := &annotationsAsConfig{}
+	if cfg == nil {
+		return r, nil
+	}
+
+	for _, rule := range cfg.RetentionRules {
+		var maxAge time.Duration
+		if rule.MaxAge.Value() != "" {
+			d, err := gtime.ParseDuration(rule.MaxAge.Value())
+			if err != nil {
+				return nil, fmt.Errorf("retention rule %q has invalid max age: %w", rule.Name.Value(), err)
+			}
+			maxAge = d
+		}
+
+		tags := make([]string, 0, len(rule.Tags))
+		for _, tag := range rule.Tags {
+			tags = append(tags, tag.Value())
+		}
+
+		r.RetentionRules = append(r.RetentionRules, setting.AnnotationRetentionRule{
+			Name:         rule.Name.Value(),
+			OrgID:        rule.OrgID.Value(),
+			DashboardUID: rule.DashboardUID.Value(),
+			Tags:         tags,
+			AnnotationCleanupSettings: setting.AnnotationCleanupSettings{
+				MaxAge:   maxAge,
+				MaxCount: rule.MaxAnnotationsToKeep.Value(),
+			},
+		})
+	}
+
+	return r, nil
+}
@@ -33,6 +33,8 @@ var (
 
 	oneDatasourceWithTwoCorrelations   = "testdata/one-datasource-two-correlations"
 	correlationsDifferentOrganizations = "testdata/correlations-different-organizations"
+	externalCorrelation                = "testdata/external-correlation"
+	invalidExternalCorrelation         = "testdata/invalid-external-correlation"
 )
 
 func TestDatasourceAsConfig(t *testing.T) {
@@ -308,6 +310,35 @@ func TestDatasourceAsConfig(t *testing.T) {
 			require.Equal(t, true, correlationsStore.deletedBySourceUID[0].OnlyProvisioned)
 		})
 
+		t.Run("Creates an external correlation with transformations", func(t *testing.T) {
+			store := &spyStore{}
+			orgFake := &orgtest.FakeOrgService{}
+			correlationsStore := &mockCorrelationsStore{}
+			dc := newDatasourceProvisioner(logger, store, correlationsStore, orgFake)
+			err := dc.applyChanges(context.Background(), externalCorrelation)
+			require.NoError(t, err)
+
+			require.Equal(t, 1, len(correlationsStore.created))
+			created := correlationsStore.created[0]
+			require.Nil(t, created.TargetUID)
+			require.Equal(t, correlations.ConfigTypeExternal, created.Config.Type)
+			require.Equal(t, "https://tracing.example.com/trace/${traceId}?service=${app}", created.Config.Target["url"])
+			require.Equal(t, correlations.Transformations{
+				{Type: "jsonpath", Expression: "$.trace.id", MapValue: "traceId"},
+				{Type: "template", Expression: "${service}-${env}", MapValue: "app"},
+			}, created.Config.Transformations)
+		})
+
+		t.Run("Fails to provision an external correlation with an invalid url", func(t *testing.T) {
+			store := &spyStore{}
+			orgFake := &orgtest.FakeOrgService{}
+			correlationsStore := &mockCorrelationsStore{}
+			dc := newDatasourceProvisioner(logger, store, correlationsStore, orgFake)
+			err := dc.applyChanges(context.Background(), invalidExternalCorrelation)
+			require.ErrorIs(t, err, correlations.ErrInvalidExternalURL)
+			require.Equal(t, 0, len(correlationsStore.created))
+		})
+
 		t.Run("Deleting datasource deletes existing correlations", func(t *testing.T) {
 			store := &spyStore{items: []*datasources.DataSource{{Name: "old-data-source", OrgID: 1, ID: 1, UID: "some-uid"}}}
 			orgFake := &orgtest.FakeOrgService{}
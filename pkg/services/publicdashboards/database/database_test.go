@@ -478,6 +478,7 @@ func TestIntegrationCreatePublicDashboard(t *testing.T) {
 				DashboardUid:         savedDashboard.UID,
 				OrgId:                savedDashboard.OrgID,
 				TimeSettings:         DefaultTimeSettings,
+				TemplateVariables:    TemplateVariableSettings{{Name: "env", AllowedValues: []string{"prod", "dev"}}},
 				CreatedAt:            DefaultTime,
 				CreatedBy:            7,
 				AccessToken:          "NOTAREALUUID",
@@ -494,6 +495,7 @@ func TestIntegrationCreatePublicDashboard(t *testing.T) {
 		assert.True(t, pubdash.AnnotationsEnabled)
 		assert.True(t, pubdash.TimeSelectionEnabled)
 		assert.Equal(t, cmd.PublicDashboard.Share, pubdash.Share)
+		assert.Equal(t, cmd.PublicDashboard.TemplateVariables, pubdash.TemplateVariables)
 
 		// verify we didn't update all dashboards
 		pubdash2, err := publicdashboardStore.FindByDashboardUid(context.Background(), savedDashboard2.OrgID, savedDashboard2.UID)
@@ -595,6 +597,7 @@ func TestIntegrationUpdatePublicDashboard(t *testing.T) {
 			TimeSelectionEnabled: true,
 			Share:                EmailShareType,
 			TimeSettings:         &TimeSettings{From: "now-8", To: "now"},
+			TemplateVariables:    TemplateVariableSettings{{Name: "host", Pattern: "[a-z0-9-]+"}},
 			UpdatedAt:            time.Now().UTC().Round(time.Second),
 			UpdatedBy:            8,
 		}
@@ -616,6 +619,7 @@ func TestIntegrationUpdatePublicDashboard(t *testing.T) {
 		assert.Equal(t, updatedPublicDashboard.AnnotationsEnabled, pdRetrieved.AnnotationsEnabled)
 		assert.Equal(t, updatedPublicDashboard.TimeSelectionEnabled, pdRetrieved.TimeSelectionEnabled)
 		assert.Equal(t, updatedPublicDashboard.Share, pdRetrieved.Share)
+		assert.Equal(t, updatedPublicDashboard.TemplateVariables, pdRetrieved.TemplateVariables)
 
 		// not updated dashboard shouldn't have changed
 		pdNotUpdatedRetrieved, err := publicdashboardStore.FindByDashboardUid(context.Background(), anotherSavedDashboard.OrgID, anotherSavedDashboard.UID)
@@ -624,6 +628,7 @@ func TestIntegrationUpdatePublicDashboard(t *testing.T) {
 		assert.NotEqual(t, updatedPublicDashboard.IsEnabled, pdNotUpdatedRetrieved.IsEnabled)
 		assert.NotEqual(t, updatedPublicDashboard.AnnotationsEnabled, pdNotUpdatedRetrieved.AnnotationsEnabled)
 		assert.NotEqual(t, updatedPublicDashboard.Share, pdNotUpdatedRetrieved.Share)
+		assert.Empty(t, pdNotUpdatedRetrieved.TemplateVariables)
 	})
 }
 
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana/pkg/kinds/dashboard"
+	"github.com/grafana/grafana/pkg/services/dashboards/templating"
 	"github.com/grafana/grafana/pkg/services/user"
 )
 
@@ -54,6 +55,8 @@ type PublicDashboard struct {
 	AnnotationsEnabled   bool          `json:"annotationsEnabled" xorm:"annotations_enabled"`
 	Share                ShareType     `json:"share" xorm:"share"`
 	Recipients           []EmailDTO    `json:"recipients,omitempty" xorm:"-"`
+	// TemplateVariables are the template variables that viewers can change
+	TemplateVariables TemplateVariableSettings `json:"templateVariables" xorm:"template_variables"`
 }
 
 type PublicDashboardDTO struct {
@@ -63,6 +66,8 @@ type PublicDashboardDTO struct {
 	IsEnabled            *bool     `json:"isEnabled"`
 	AnnotationsEnabled   *bool     `json:"annotationsEnabled"`
 	Share                ShareType `json:"share"`
+	// TemplateVariables are left unchanged when not set
+	TemplateVariables *TemplateVariableSettings `json:"templateVariables"`
 }
 
 type EmailDTO struct {
@@ -134,6 +139,41 @@ func (ts *TimeSettings) ToDB() ([]byte, error) {
 	return json.Marshal(ts)
 }
 
+// TemplateVariableSetting allows viewers of the public dashboard to change the value of a template
+// variable of the dashboard. The values of the other variables are the ones saved with the dashboard.
+type TemplateVariableSetting struct {
+	Name string `json:"name"`
+	// AllowedValues are the values viewers can select. When empty, the options saved with the
+	// dashboard are allowed.
+	AllowedValues []string `json:"allowedValues,omitempty"`
+	// Pattern is a regular expression that the values must match entirely, for variables without
+	// options such as textboxes. It is ignored when AllowedValues are set.
+	Pattern string `json:"pattern,omitempty"`
+}
+
+type TemplateVariableSettings []TemplateVariableSetting
+
+// Find returns the setting of the template variable, or nil if viewers cannot change it.
+func (s TemplateVariableSettings) Find(name string) *TemplateVariableSetting {
+	for i := range s {
+		if s[i].Name == name {
+			return &s[i]
+		}
+	}
+	return nil
+}
+
+func (s *TemplateVariableSettings) FromDB(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, s)
+}
+
+func (s *TemplateVariableSettings) ToDB() ([]byte, error) {
+	return json.Marshal(s)
+}
+
 // DTO for transforming user input in the api
 type SavePublicDashboardDTO struct {
 	Uid             string
@@ -154,6 +194,8 @@ type PublicDashboardQueryDTO struct {
 	MaxDataPoints   int64
 	QueryCachingTTL int64
 	TimeRange       TimeRangeDTO
+	// Variables are the values of the template variables selected by the viewer
+	Variables map[string]templating.Values
 }
 
 type AnnotationsQueryDTO struct {
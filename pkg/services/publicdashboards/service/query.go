@@ -13,6 +13,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/annotations"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboards/templating"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/models"
@@ -105,7 +106,7 @@ func (pd *PublicDashboardServiceImpl) FindAnnotations(ctx context.Context, reqDT
 
 // GetMetricRequest returns a metric request for the given panel and query
 func (pd *PublicDashboardServiceImpl) GetMetricRequest(ctx context.Context, dashboard *dashboards.Dashboard, publicDashboard *models.PublicDashboard, panelId int64, queryDto models.PublicDashboardQueryDTO) (dtos.MetricRequest, error) {
-	err := validation.ValidateQueryPublicDashboardRequest(queryDto, publicDashboard)
+	err := validation.ValidateQueryPublicDashboardRequest(queryDto, publicDashboard, templating.Variables(dashboard.Data))
 	if err != nil {
 		return dtos.MetricRequest{}, err
 	}
@@ -167,7 +168,23 @@ func (pd *PublicDashboardServiceImpl) buildMetricRequest(dashboard *dashboards.D
 
 	// determine safe resolution to query data at
 	safeInterval, safeResolution := pd.getSafeIntervalAndMaxDataPoints(reqDTO, ts)
+
+	interpolator, err := buildInterpolator(dashboard, publicDashboard, reqDTO, ts, safeInterval)
+	if err != nil {
+		return dtos.MetricRequest{}, err
+	}
+
+	panelDatasourceType := getPanelDatasourceType(dashboard.Data, panelId)
 	for i := range queries {
+		// the variables are formatted for the type of the data source, the queries without their own
+		// data source use the data source of the panel
+		datasourceType := queries[i].Get("datasource").Get("type").MustString()
+		if datasourceType == "" || datasourceType == "public-ds" {
+			datasourceType = panelDatasourceType
+		}
+		if err := interpolator.InterpolateJSON(queries[i], datasourceType); err != nil {
+			return dtos.MetricRequest{}, models.ErrInvalidTemplateVariable.Errorf("buildMetricRequest: %w", err)
+		}
 		queries[i].Set("intervalMs", safeInterval)
 		queries[i].Set("maxDataPoints", safeResolution)
 		queries[i].Set("queryCachingTTL", reqDTO.QueryCachingTTL)
@@ -180,6 +197,30 @@ func (pd *PublicDashboardServiceImpl) buildMetricRequest(dashboard *dashboards.D
 	}, nil
 }
 
+// buildInterpolator returns an interpolator of the template variables of the dashboard with the values
+// selected by the viewer for the variables they can change, and the values saved with the dashboard
+// for the others
+func buildInterpolator(dashboard *dashboards.Dashboard, publicDashboard *models.PublicDashboard, reqDTO models.PublicDashboardQueryDTO, ts models.TimeSettings, intervalMs int64) (*templating.Interpolator, error) {
+	overrides := make(map[string]templating.Values, len(reqDTO.Variables))
+	for name, values := range reqDTO.Variables {
+		if publicDashboard.TemplateVariables.Find(name) != nil {
+			overrides[name] = values
+		}
+	}
+
+	interpolator, err := templating.NewInterpolator(templating.Variables(dashboard.Data), overrides)
+	if err != nil {
+		return nil, models.ErrInvalidTemplateVariable.Errorf("buildInterpolator: %w", err)
+	}
+
+	interpolator.Set("__from", ts.From)
+	interpolator.Set("__to", ts.To)
+	interpolator.Set("__interval", gtime.FormatInterval(time.Duration(intervalMs)*time.Millisecond))
+	interpolator.Set("__interval_ms", strconv.FormatInt(intervalMs, 10))
+
+	return interpolator, nil
+}
+
 // buildAnonymousUser creates a user with permissions to read from all datasources used in the dashboard
 func buildAnonymousUser(ctx context.Context, dashboard *dashboards.Dashboard, features featuremgmt.FeatureToggles) *user.SignedInUser {
 	datasourceUids := getUniqueDashboardDatasourceUids(dashboard.Data)
@@ -266,6 +307,17 @@ func getFlattenedPanels(dashboard *simplejson.Json) []any {
 	return flatPanels
 }
 
+// getPanelDatasourceType returns the type of the data source of the panel
+func getPanelDatasourceType(dashboard *simplejson.Json, panelId int64) string {
+	for _, panelObj := range getFlattenedPanels(dashboard) {
+		panel := simplejson.NewFromAny(panelObj)
+		if panel.Get("id").MustInt64() == panelId {
+			return panel.Get("datasource").Get("type").MustString()
+		}
+	}
+	return ""
+}
+
 func groupQueriesByPanelId(dashboard *simplejson.Json) map[int64][]*simplejson.Json {
 	result := make(map[int64][]*simplejson.Json)
 
@@ -368,6 +420,41 @@ func sanitizeData(data *simplejson.Json) {
 	}
 }
 
+// sanitizeTemplateVariables removes the queries of the template variables from the dashboard data,
+// hides the variables viewers cannot change and restricts the options of the others to the allowed values
+func sanitizeTemplateVariables(data *simplejson.Json, settings models.TemplateVariableSettings) {
+	for _, variableObj := range data.GetPath("templating", "list").MustArray() {
+		variable := simplejson.NewFromAny(variableObj)
+
+		// the options are not refreshed in public dashboards
+		if variable.Get("type").MustString() == "query" {
+			variable.Set("query", "")
+			variable.Del("definition")
+			variable.Set("refresh", 0)
+		}
+
+		setting := settings.Find(variable.Get("name").MustString())
+		if setting == nil {
+			variable.Set("hide", 2)
+			options := []any{}
+			if current, ok := variable.CheckGet("current"); ok {
+				options = append(options, current.Interface())
+			}
+			variable.Set("options", options)
+			continue
+		}
+
+		if len(setting.AllowedValues) > 0 {
+			options := make([]any, 0, len(setting.AllowedValues))
+			for _, value := range setting.AllowedValues {
+				options = append(options, map[string]any{"text": value, "value": value, "selected": false})
+			}
+			variable.Set("options", options)
+			variable.Set("includeAll", false)
+		}
+	}
+}
+
 // NewTimeRange declared to be able to stub this function in tests
 var NewTimeRange = gtime.NewTimeRange
 
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strconv"
 	"testing"
 	"time"
@@ -20,6 +21,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/annotations"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	dashboardsDB "github.com/grafana/grafana/pkg/services/dashboards/database"
+	"github.com/grafana/grafana/pkg/services/dashboards/templating"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/internal"
@@ -1316,6 +1318,86 @@ func TestBuildMetricRequest(t *testing.T) {
 			reqDTO.Queries[0],
 		)
 	})
+
+	t.Run("metric request built with template variables", func(t *testing.T) {
+		templateVars := []map[string]any{
+			{"name": "env", "type": "custom", "current": map[string]any{"value": "prod"}, "options": []any{map[string]any{"value": "prod"}, map[string]any{"value": "dev"}}},
+			{"name": "host", "type": "query", "multi": true, "current": map[string]any{"value": []any{"a"}}},
+		}
+		customPanels := []any{
+			map[string]any{
+				"id":         1,
+				"datasource": map[string]any{"uid": "ds1"},
+				"targets": []any{
+					map[string]any{
+						"datasource": map[string]any{"type": "prometheus", "uid": "ds1"},
+						"expr":       `up{env="$env", host=~"${host:regex}"}[$__interval_ms]`,
+						"refId":      "A",
+					},
+				},
+			}}
+		dashboardWithVariables := insertTestDashboard(t, dashboardStore, "testDashWithVariables", 1, 0, "", true, templateVars, customPanels)
+		// load the data like it is loaded from the database
+		raw, err := dashboardWithVariables.Data.MarshalJSON()
+		require.NoError(t, err)
+		dashboardWithVariables.Data, err = simplejson.NewJson(raw)
+		require.NoError(t, err)
+		pd := &PublicDashboard{TemplateVariables: TemplateVariableSettings{{Name: "host", AllowedValues: []string{"a", "b"}}}}
+
+		reqDTO, err := service.buildMetricRequest(
+			dashboardWithVariables,
+			pd,
+			1,
+			PublicDashboardQueryDTO{
+				IntervalMs:    publicDashboardQueryDTO.IntervalMs,
+				MaxDataPoints: publicDashboardQueryDTO.MaxDataPoints,
+				// env cannot be changed by viewers
+				Variables: map[string]templating.Values{"env": {"dev"}, "host": {"a", "b"}},
+			},
+		)
+		require.NoError(t, err)
+
+		require.Len(t, reqDTO.Queries, 1)
+		intervalMs := reqDTO.Queries[0].Get("intervalMs").MustInt64()
+		require.Equal(t, fmt.Sprintf(`up{env="prod", host=~"(a|b)"}[%d]`, intervalMs), reqDTO.Queries[0].Get("expr").MustString())
+	})
+
+	t.Run("metric request built with the default format of the data source of the panel", func(t *testing.T) {
+		templateVars := []map[string]any{
+			{"name": "host", "type": "query", "multi": true, "current": map[string]any{"value": []any{"a"}}},
+		}
+		customPanels := []any{
+			map[string]any{
+				"id":         1,
+				"datasource": map[string]any{"type": "prometheus", "uid": "ds1"},
+				"targets":    []any{map[string]any{"expr": `up{host=~"$host"}`, "refId": "A"}},
+			},
+			map[string]any{
+				"id":         2,
+				"datasource": map[string]any{"type": "elasticsearch", "uid": "ds2"},
+				"targets":    []any{map[string]any{"query": `host:$host`, "refId": "A"}},
+			}}
+		dashboardWithVariables := insertTestDashboard(t, dashboardStore, "testDashWithDefaultFormats", 1, 0, "", true, templateVars, customPanels)
+		raw, err := dashboardWithVariables.Data.MarshalJSON()
+		require.NoError(t, err)
+		dashboardWithVariables.Data, err = simplejson.NewJson(raw)
+		require.NoError(t, err)
+		pd := &PublicDashboard{TemplateVariables: TemplateVariableSettings{{Name: "host", AllowedValues: []string{"a.example.com", "b"}}}}
+		queryDTO := PublicDashboardQueryDTO{
+			IntervalMs:    publicDashboardQueryDTO.IntervalMs,
+			MaxDataPoints: publicDashboardQueryDTO.MaxDataPoints,
+			Variables:     map[string]templating.Values{"host": {"a.example.com", "b"}},
+		}
+
+		reqDTO, err := service.buildMetricRequest(dashboardWithVariables, pd, 1, queryDTO)
+		require.NoError(t, err)
+		require.Len(t, reqDTO.Queries, 1)
+		require.Equal(t, `up{host=~"(a\\.example\\.com|b)"}`, reqDTO.Queries[0].Get("expr").MustString())
+
+		// the format of several values is unknown for the data source
+		_, err = service.buildMetricRequest(dashboardWithVariables, pd, 2, queryDTO)
+		require.ErrorIs(t, err, ErrInvalidTemplateVariable)
+	})
 }
 
 func TestBuildAnonymousUser(t *testing.T) {
@@ -1553,6 +1635,41 @@ func TestGroupQueriesByDataSource(t *testing.T) {
 	})
 }
 
+func TestSanitizeTemplateVariables(t *testing.T) {
+	data, err := simplejson.NewJson([]byte(`{
+		"templating": {
+			"list": [
+				{"name": "env", "type": "query", "query": "label_values(env)", "definition": "label_values(env)", "refresh": 1,
+					"current": {"text": "prod", "value": "prod"}, "options": [{"text": "prod", "value": "prod"}, {"text": "dev", "value": "dev"}]},
+				{"name": "host", "type": "custom", "includeAll": true, "current": {"text": "a", "value": "a"},
+					"options": [{"text": "a", "value": "a"}, {"text": "b", "value": "b"}, {"text": "c", "value": "c"}]},
+				{"name": "secret", "type": "constant", "query": "s3cr3t", "current": {"text": "s3cr3t", "value": "s3cr3t"}}
+			]
+		}
+	}`))
+	require.NoError(t, err)
+
+	sanitizeTemplateVariables(data, TemplateVariableSettings{{Name: "env"}, {Name: "host", AllowedValues: []string{"a", "b"}}})
+
+	env := data.GetPath("templating", "list").GetIndex(0)
+	assert.Equal(t, "", env.Get("query").MustString())
+	assert.Equal(t, 0, env.Get("refresh").MustInt())
+	_, hasDefinition := env.CheckGet("definition")
+	assert.False(t, hasDefinition)
+	assert.Len(t, env.Get("options").MustArray(), 2)
+	_, hidden := env.CheckGet("hide")
+	assert.False(t, hidden)
+
+	host := data.GetPath("templating", "list").GetIndex(1)
+	assert.False(t, host.Get("includeAll").MustBool())
+	assert.Equal(t, "b", host.Get("options").GetIndex(1).Get("value").MustString())
+	assert.Len(t, host.Get("options").MustArray(), 2)
+
+	secret := data.GetPath("templating", "list").GetIndex(2)
+	assert.Equal(t, 2, secret.Get("hide").MustInt())
+	assert.Len(t, secret.Get("options").MustArray(), 1)
+}
+
 func TestSanitizeMetadataFromQueryData(t *testing.T) {
 	t.Run("can remove ExecutedQueryString from metadata", func(t *testing.T) {
 		fakeResponse := &backend.QueryDataResponse{
@@ -16,6 +16,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/annotations"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboards/templating"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/licensing"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
@@ -104,6 +105,7 @@ func (pd *PublicDashboardServiceImpl) GetPublicDashboardForView(ctx context.Cont
 	dash.Data.Get("timepicker").Set("hidden", !pubdash.TimeSelectionEnabled)
 
 	sanitizeData(dash.Data)
+	sanitizeTemplateVariables(dash.Data, pubdash.TemplateVariables)
 
 	return &dtos.DashboardFullWithMeta{Meta: meta, Dashboard: dash.Data}, nil
 }
@@ -197,11 +199,19 @@ func (pd *PublicDashboardServiceImpl) Create(ctx context.Context, u *user.Signed
 	}
 
 	// ensure dashboard exists
-	_, err = pd.FindDashboard(ctx, u.OrgID, dto.DashboardUid)
+	dashboard, err := pd.FindDashboard(ctx, u.OrgID, dto.DashboardUid)
 	if err != nil {
 		return nil, err
 	}
 
+	// validate the template variables viewers can change are variables of the dashboard
+	if dto.PublicDashboard.TemplateVariables != nil {
+		err = validation.ValidateTemplateVariableSettings(*dto.PublicDashboard.TemplateVariables, templating.Variables(dashboard.Data))
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// validate the dashboard does not already have a public dashboard
 	existingPubdash, err := pd.FindByDashboardUid(ctx, u.OrgID, dto.DashboardUid)
 	if err != nil && !errors.Is(err, ErrPublicDashboardNotFound) {
@@ -254,11 +264,19 @@ func (pd *PublicDashboardServiceImpl) Update(ctx context.Context, u *user.Signed
 	}
 
 	// validate dashboard exists
-	_, err = pd.FindDashboard(ctx, u.OrgID, dto.DashboardUid)
+	dashboard, err := pd.FindDashboard(ctx, u.OrgID, dto.DashboardUid)
 	if err != nil {
 		return nil, err
 	}
 
+	// validate the template variables viewers can change are variables of the dashboard
+	if dto.PublicDashboard.TemplateVariables != nil {
+		err = validation.ValidateTemplateVariableSettings(*dto.PublicDashboard.TemplateVariables, templating.Variables(dashboard.Data))
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// get existing public dashboard if exists
 	existingPubdash, err := pd.store.Find(ctx, dto.Uid)
 	if err != nil {
@@ -503,6 +521,11 @@ func (pd *PublicDashboardServiceImpl) newCreatePublicDashboard(ctx context.Conte
 		share = PublicShareType
 	}
 
+	templateVariables := TemplateVariableSettings{}
+	if dto.PublicDashboard.TemplateVariables != nil {
+		templateVariables = *dto.PublicDashboard.TemplateVariables
+	}
+
 	now := time.Now()
 
 	return &PublicDashboard{
@@ -514,6 +537,7 @@ func (pd *PublicDashboardServiceImpl) newCreatePublicDashboard(ctx context.Conte
 		TimeSelectionEnabled: timeSelectionEnabled,
 		TimeSettings:         &TimeSettings{},
 		Share:                share,
+		TemplateVariables:    templateVariables,
 		CreatedBy:            dto.UserId,
 		CreatedAt:            now,
 		UpdatedBy:            dto.UserId,
@@ -533,6 +557,11 @@ func newUpdatePublicDashboard(dto *SavePublicDashboardDTO, pd *PublicDashboard)
 		share = pd.Share
 	}
 
+	templateVariables := pd.TemplateVariables
+	if pubdashDTO.TemplateVariables != nil {
+		templateVariables = *pubdashDTO.TemplateVariables
+	}
+
 	return &PublicDashboard{
 		Uid:                  pd.Uid,
 		IsEnabled:            isEnabled,
@@ -540,6 +569,7 @@ func newUpdatePublicDashboard(dto *SavePublicDashboardDTO, pd *PublicDashboard)
 		TimeSelectionEnabled: timeSelectionEnabled,
 		TimeSettings:         pd.TimeSettings,
 		Share:                share,
+		TemplateVariables:    templateVariables,
 		UpdatedBy:            dto.UserId,
 		UpdatedAt:            time.Now(),
 	}
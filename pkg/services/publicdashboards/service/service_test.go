@@ -958,6 +958,31 @@ func TestCreatePublicDashboard(t *testing.T) {
 		// if share type is empty should be populated with public by default
 		assert.Equal(t, PublicShareType, pubdash.Share)
 	})
+
+	t.Run("Returns error when a template variable is not a variable of the dashboard", func(t *testing.T) {
+		fakeDashboardService := &dashboards.FakeDashboardService{}
+		service, sqlStore, cfg := newPublicDashboardServiceImpl(t, nil, fakeDashboardService, nil)
+
+		dashboardStore, err := dashboardsDB.ProvideDashboardStore(sqlStore, cfg, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore.DB()), quotatest.New(false, nil))
+		require.NoError(t, err)
+		dashboard := insertTestDashboard(t, dashboardStore, "testDashie", 1, 0, "", true, []map[string]any{}, nil)
+		fakeDashboardService.On("GetDashboard", mock.Anything, mock.Anything, mock.Anything).Return(dashboard, nil)
+
+		isEnabled := true
+		dto := &SavePublicDashboardDTO{
+			DashboardUid: dashboard.UID,
+			OrgID:        dashboard.OrgID,
+			UserId:       7,
+			PublicDashboard: &PublicDashboardDTO{
+				IsEnabled:         &isEnabled,
+				TemplateVariables: &TemplateVariableSettings{{Name: "env"}},
+			},
+		}
+
+		savedPubdash, err := service.Create(context.Background(), SignedInUser, dto)
+		assert.Nil(t, savedPubdash)
+		assert.True(t, ErrInvalidTemplateVariable.Is(err))
+	})
 }
 
 func assertFalseIfNull(t *testing.T, expectedValue bool, nullableValue *bool) {
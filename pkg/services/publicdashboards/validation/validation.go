@@ -1,8 +1,12 @@
 package validation
 
 import (
+	"regexp"
+	"slices"
+
 	"github.com/google/uuid"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana/pkg/services/dashboards/templating"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -13,10 +17,42 @@ func ValidatePublicDashboard(dto *SavePublicDashboardDTO) error {
 		return ErrInvalidShareType.Errorf("ValidateSavePublicDashboard: invalid share type")
 	}
 
+	if dto.PublicDashboard.TemplateVariables != nil {
+		names := make(map[string]bool, len(*dto.PublicDashboard.TemplateVariables))
+		for _, setting := range *dto.PublicDashboard.TemplateVariables {
+			if setting.Name == "" {
+				return ErrInvalidTemplateVariable.Errorf("ValidateSavePublicDashboard: template variable name is empty")
+			}
+			if names[setting.Name] {
+				return ErrInvalidTemplateVariable.Errorf("ValidateSavePublicDashboard: template variable %s is set more than once", setting.Name)
+			}
+			names[setting.Name] = true
+
+			if setting.Pattern != "" {
+				if _, err := regexp.Compile(setting.Pattern); err != nil {
+					return ErrInvalidTemplateVariable.Errorf("ValidateSavePublicDashboard: invalid pattern of template variable %s: %w", setting.Name, err)
+				}
+			}
+		}
+	}
+
 	return nil
 }
 
-func ValidateQueryPublicDashboardRequest(req PublicDashboardQueryDTO, pd *PublicDashboard) error {
+// ValidateTemplateVariableSettings checks that the template variables viewers can change are
+// variables of the dashboard
+func ValidateTemplateVariableSettings(settings TemplateVariableSettings, variables []templating.Variable) error {
+	for _, setting := range settings {
+		if !slices.ContainsFunc(variables, func(v templating.Variable) bool { return v.Name == setting.Name }) {
+			return ErrInvalidTemplateVariable.Errorf("ValidateTemplateVariableSettings: dashboard has no template variable %s", setting.Name)
+		}
+	}
+	return nil
+}
+
+// ValidateQueryPublicDashboardRequest validates the query request of a panel of the public dashboard,
+// variables are the template variables of the dashboard
+func ValidateQueryPublicDashboardRequest(req PublicDashboardQueryDTO, pd *PublicDashboard, variables []templating.Variable) error {
 	if req.IntervalMs < 0 {
 		return ErrInvalidInterval.Errorf("ValidateQueryPublicDashboardRequest: intervalMS should be greater than 0")
 	}
@@ -38,9 +74,47 @@ func ValidateQueryPublicDashboardRequest(req PublicDashboardQueryDTO, pd *Public
 		}
 	}
 
+	for name, values := range req.Variables {
+		setting := pd.TemplateVariables.Find(name)
+		if setting == nil {
+			return ErrInvalidTemplateVariable.Errorf("ValidateQueryPublicDashboardRequest: template variable %s cannot be changed", name)
+		}
+		i := slices.IndexFunc(variables, func(v templating.Variable) bool { return v.Name == name })
+		if i < 0 {
+			return ErrInvalidTemplateVariable.Errorf("ValidateQueryPublicDashboardRequest: dashboard has no template variable %s", name)
+		}
+		variable := variables[i]
+
+		if len(values) == 0 || (len(values) > 1 && !variable.Multi) {
+			return ErrInvalidTemplateVariable.Errorf("ValidateQueryPublicDashboardRequest: invalid number of values of template variable %s", name)
+		}
+		for _, value := range values {
+			if !isAllowedValue(setting, variable, value) {
+				return ErrInvalidTemplateVariable.Errorf("ValidateQueryPublicDashboardRequest: value of template variable %s is not allowed", name)
+			}
+		}
+	}
+
 	return nil
 }
 
+func isAllowedValue(setting *TemplateVariableSetting, variable templating.Variable, value string) bool {
+	// all the options are only allowed when the values are not restricted by the public dashboard,
+	// and when the options are saved with the dashboard or the variable has a custom all value
+	if value == templating.AllValue {
+		return variable.IncludeAll && len(setting.AllowedValues) == 0 && setting.Pattern == "" &&
+			(len(variable.Options) > 0 || variable.AllValue != "")
+	}
+	if len(setting.AllowedValues) > 0 {
+		return slices.Contains(setting.AllowedValues, value)
+	}
+	if setting.Pattern != "" {
+		matched, err := regexp.MatchString("^(?:"+setting.Pattern+")$", value)
+		return err == nil && matched
+	}
+	return slices.Contains(variable.Options, value) || slices.Contains(variable.Current, value)
+}
+
 // IsValidAccessToken asserts that an accessToken is a valid uuid
 func IsValidAccessToken(token string) bool {
 	_, err := uuid.Parse(token)
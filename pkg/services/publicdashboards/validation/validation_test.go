@@ -3,6 +3,7 @@ package validation
 import (
 	"testing"
 
+	"github.com/grafana/grafana/pkg/services/dashboards/templating"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -22,6 +23,26 @@ func TestValidatePublicDashboard(t *testing.T) {
 		err := ValidatePublicDashboard(dto)
 		require.Error(t, err)
 	})
+
+	t.Run("Returns error when template variable settings are invalid", func(t *testing.T) {
+		for name, settings := range map[string]TemplateVariableSettings{
+			"empty name":      {{AllowedValues: []string{"prod"}}},
+			"duplicate names": {{Name: "env"}, {Name: "env"}},
+			"invalid pattern": {{Name: "host", Pattern: "[a-z"}},
+		} {
+			dto := &SavePublicDashboardDTO{DashboardUid: "abc123", UserId: 1, PublicDashboard: &PublicDashboardDTO{TemplateVariables: &settings}}
+
+			err := ValidatePublicDashboard(dto)
+			require.ErrorIs(t, err, ErrInvalidTemplateVariable, name)
+		}
+	})
+}
+
+func TestValidateTemplateVariableSettings(t *testing.T) {
+	variables := []templating.Variable{{Name: "env"}, {Name: "host"}}
+
+	require.NoError(t, ValidateTemplateVariableSettings(TemplateVariableSettings{{Name: "env"}}, variables))
+	require.ErrorIs(t, ValidateTemplateVariableSettings(TemplateVariableSettings{{Name: "region"}}, variables), ErrInvalidTemplateVariable)
 }
 
 func TestValidateQueryPublicDashboardRequest(t *testing.T) {
@@ -132,13 +153,69 @@ func TestValidateQueryPublicDashboardRequest(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := ValidateQueryPublicDashboardRequest(tt.args.req, tt.args.pd); (err != nil) != tt.wantErr {
+			if err := ValidateQueryPublicDashboardRequest(tt.args.req, tt.args.pd, nil); (err != nil) != tt.wantErr {
 				t.Errorf("ValidateQueryPublicDashboardRequest() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
 
+func TestValidateQueryPublicDashboardRequestTemplateVariables(t *testing.T) {
+	variables := []templating.Variable{
+		{Name: "env", Current: templating.Values{"prod"}, Options: []string{"prod", "dev"}},
+		{Name: "host", Multi: true, IncludeAll: true, Current: templating.Values{templating.AllValue}, Options: []string{"a", "b", "c"}},
+		{Name: "job", IncludeAll: true, Options: []string{"api", "db"}},
+		{Name: "filter", Type: "textbox", Current: templating.Values{"web"}},
+		{Name: "secret", Type: "constant", Current: templating.Values{"s3cr3t"}},
+		// query variables refreshed on load do not save their options
+		{Name: "pod", Type: "query", Multi: true, IncludeAll: true, Current: templating.Values{"web-1"}},
+		{Name: "namespace", Type: "query", IncludeAll: true, AllValue: ".*", Current: templating.Values{"default"}},
+	}
+	pd := &PublicDashboard{
+		TemplateVariables: TemplateVariableSettings{
+			{Name: "env"},
+			{Name: "host", AllowedValues: []string{"a", "b"}},
+			{Name: "job"},
+			{Name: "filter", Pattern: "[a-z]+"},
+			{Name: "pod"},
+			{Name: "namespace"},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		variables map[string]templating.Values
+		wantErr   bool
+	}{
+		{name: "no variables", variables: nil},
+		{name: "saved option", variables: map[string]templating.Values{"env": {"dev"}}},
+		{name: "allowed values", variables: map[string]templating.Values{"host": {"a", "b"}}},
+		{name: "all option", variables: map[string]templating.Values{"job": {templating.AllValue}}},
+		{name: "all option with custom all value", variables: map[string]templating.Values{"namespace": {templating.AllValue}}},
+		{name: "value matching pattern", variables: map[string]templating.Values{"filter": {"api"}}},
+		{name: "variable that cannot be changed", variables: map[string]templating.Values{"secret": {"other"}}, wantErr: true},
+		{name: "unknown variable", variables: map[string]templating.Values{"region": {"eu"}}, wantErr: true},
+		{name: "value that is not an option", variables: map[string]templating.Values{"env": {"staging"}}, wantErr: true},
+		{name: "value that is not allowed", variables: map[string]templating.Values{"host": {"c"}}, wantErr: true},
+		{name: "all option with allowed values", variables: map[string]templating.Values{"host": {templating.AllValue}}, wantErr: true},
+		{name: "all option without saved options", variables: map[string]templating.Values{"pod": {templating.AllValue}}, wantErr: true},
+		{name: "value not matching pattern", variables: map[string]templating.Values{"filter": {"' OR 1=1 --"}}, wantErr: true},
+		{name: "several values of a single value variable", variables: map[string]templating.Values{"env": {"prod", "dev"}}, wantErr: true},
+		{name: "no values", variables: map[string]templating.Values{"env": {}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := PublicDashboardQueryDTO{IntervalMs: 1000, MaxDataPoints: 1000, Variables: tt.variables}
+			err := ValidateQueryPublicDashboardRequest(req, pd, variables)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidTemplateVariable)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestValidAccessToken(t *testing.T) {
 	t.Run("true", func(t *testing.T) {
 		uuid := "da82510c2aa64d78a2e87fef36c58e89"
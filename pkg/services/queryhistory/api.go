@@ -1,7 +1,10 @@
 package queryhistory
 
 import (
+	"bytes"
+	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
 	"github.com/grafana/grafana/pkg/api/response"
@@ -22,7 +25,15 @@ func (s *QueryHistoryService) registerAPIEndpoints() {
 		entities.Post("/star/:uid", middleware.ReqSignedIn, routing.Wrap(s.permissionsMiddleware(s.starHandler, "Failed to star query history")))
 		entities.Delete("/star/:uid", middleware.ReqSignedIn, routing.Wrap(s.permissionsMiddleware(s.unstarHandler, "Failed to unstar query history")))
 		entities.Patch("/:uid", middleware.ReqSignedIn, routing.Wrap(s.permissionsMiddleware(s.patchCommentHandler, "Failed to update comment of query in query history")))
+		entities.Post("/share/:uid", middleware.ReqSignedIn, routing.Wrap(s.permissionsMiddleware(s.shareHandler, "Failed to share query history")))
+		entities.Delete("/share/:uid", middleware.ReqSignedIn, routing.Wrap(s.permissionsMiddleware(s.unshareHandler, "Failed to unshare query history")))
+		entities.Get("/export", middleware.ReqSignedIn, routing.Wrap(s.permissionsMiddleware(s.exportHandler, "Failed to export query history")))
+		entities.Post("/import", middleware.ReqSignedIn, routing.Wrap(s.permissionsMiddleware(s.importHandler, "Failed to import query history")))
 	})
+
+	s.RouteRegister.Group("/api/admin/query-history", func(entities routing.RouteRegister) {
+		entities.Get("/usage", routing.Wrap(s.usageHandler))
+	}, middleware.ReqGrafanaAdmin)
 }
 
 type CallbackHandler func(c *contextmodel.ReqContext) response.Response
@@ -81,6 +92,7 @@ func (s *QueryHistoryService) searchHandler(c *contextmodel.ReqContext) response
 		DatasourceUIDs: c.QueryStrings("datasourceUid"),
 		SearchString:   c.Query("searchString"),
 		OnlyStarred:    c.QueryBoolWithDefault("onlyStarred", false),
+		OnlyShared:     c.QueryBoolWithDefault("onlyShared", false),
 		Sort:           c.Query("sort"),
 		Page:           c.QueryInt("page"),
 		Limit:          c.QueryInt("limit"),
@@ -201,7 +213,197 @@ func (s *QueryHistoryService) unstarHandler(c *contextmodel.ReqContext) response
 	return response.JSON(http.StatusOK, QueryHistoryResponse{Result: query})
 }
 
-// swagger:parameters starQuery patchQueryComment deleteQuery unstarQuery
+// swagger:route POST /query-history/share/{query_history_uid} query_history shareQuery
+//
+// Share query in query history.
+//
+// Shares query in query history as specified by the UID with a team or, if no team is provided, with the whole organization.
+// Shared queries are read-only for other users and can be found with the `onlyShared` search parameter.
+//
+// Responses:
+// 200: getQueryHistoryShareResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 409: conflictError
+// 500: internalServerError
+func (s *QueryHistoryService) shareHandler(c *contextmodel.ReqContext) response.Response {
+	queryUID := web.Params(c.Req)[":uid"]
+	if len(queryUID) > 0 && !util.IsValidShortUID(queryUID) {
+		return response.Error(http.StatusNotFound, "Query in query history not found", nil)
+	}
+
+	cmd := ShareQueryInQueryHistoryCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	share, err := s.ShareQueryInQueryHistory(c.Req.Context(), c.SignedInUser, queryUID, cmd)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrQueryNotFound):
+			return response.Error(http.StatusNotFound, "Query in query history not found", err)
+		case errors.Is(err, ErrTeamNotFound):
+			return response.Error(http.StatusBadRequest, "Team not found", err)
+		case errors.Is(err, ErrTeamAccessDenied):
+			return response.Error(http.StatusForbidden, "Query can only be shared with teams the user is a member of", err)
+		case errors.Is(err, ErrQueryAlreadyShared):
+			return response.Error(http.StatusConflict, "Query was already shared", err)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to share query in query history", err)
+	}
+
+	return response.JSON(http.StatusOK, QueryHistoryShareResponse{Result: share})
+}
+
+// swagger:route DELETE /query-history/share/{query_history_uid} query_history unshareQuery
+//
+// Stop sharing query in query history.
+//
+// Stops sharing query in query history as specified by the UID with a team or, if no team is provided, with the whole organization.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 404: notFoundError
+// 500: internalServerError
+func (s *QueryHistoryService) unshareHandler(c *contextmodel.ReqContext) response.Response {
+	queryUID := web.Params(c.Req)[":uid"]
+	if len(queryUID) > 0 && !util.IsValidShortUID(queryUID) {
+		return response.Error(http.StatusNotFound, "Query in query history not found", nil)
+	}
+
+	err := s.UnshareQueryInQueryHistory(c.Req.Context(), c.SignedInUser, queryUID, c.QueryInt64("teamId"))
+	if err != nil {
+		if errors.Is(err, ErrQueryNotFound) || errors.Is(err, ErrSharedQueryNotFound) {
+			return response.Error(http.StatusNotFound, "Shared query in query history not found", err)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to unshare query in query history", err)
+	}
+
+	return response.Success("Query unshared")
+}
+
+// swagger:route GET /query-history/export query_history exportQueryHistory
+//
+// Export query history.
+//
+// Exports all queries in the query history of the signed in user as JSON or CSV.
+// The export can be imported into query history on any Grafana instance.
+//
+// Produces:
+// - application/json
+// - text/csv
+//
+// Responses:
+// 200: getQueryHistoryExportResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 500: internalServerError
+func (s *QueryHistoryService) exportHandler(c *contextmodel.ReqContext) response.Response {
+	exportFormat := c.Query("format")
+	if exportFormat == "" {
+		exportFormat = exportFormatJSON
+	}
+	if exportFormat != exportFormatJSON && exportFormat != exportFormatCSV {
+		return response.Error(http.StatusBadRequest, "Invalid export format", ErrInvalidExportFormat)
+	}
+
+	export, err := s.ExportQueryHistory(c.Req.Context(), c.SignedInUser)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to export query history", err)
+	}
+
+	if exportFormat == exportFormatJSON {
+		return response.JSON(http.StatusOK, export)
+	}
+
+	var buf bytes.Buffer
+	if err := writeExportCSV(&buf, export); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to export query history", err)
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", "text/csv")
+	header.Set("Content-Disposition", `attachment; filename="query-history.csv"`)
+	return response.CreateNormalResponse(header, buf.Bytes(), http.StatusOK)
+}
+
+// swagger:route POST /query-history/import query_history importQueryHistory
+//
+// Import query history.
+//
+// Imports queries exported from query history into the query history of the signed in user.
+// The body is either the JSON export or, when the `Content-Type` header is `text/csv`, the CSV export.
+// Imported queries get new UIDs and are owned by the signed in user.
+//
+// Consumes:
+// - application/json
+// - text/csv
+//
+// Responses:
+// 200: getQueryHistoryImportResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 500: internalServerError
+func (s *QueryHistoryService) importHandler(c *contextmodel.ReqContext) response.Response {
+	var export QueryHistoryExport
+	if strings.HasPrefix(c.Req.Header.Get("Content-Type"), "text/csv") {
+		var err error
+		if export, err = readExportCSV(c.Req.Body); err != nil {
+			return response.Error(http.StatusBadRequest, "bad request data", err)
+		}
+	} else if err := web.Bind(c.Req, &export); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	imported, err := s.ImportQueryHistory(c.Req.Context(), c.SignedInUser, export)
+	if err != nil {
+		if errors.Is(err, ErrInvalidImport) {
+			return response.Error(http.StatusBadRequest, err.Error(), err)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to import query history", err)
+	}
+
+	return response.JSON(http.StatusOK, QueryHistoryImportResult{
+		Imported: imported,
+		Message:  "Query history imported",
+	})
+}
+
+// swagger:route GET /admin/query-history/usage admin getQueryHistoryUsage
+//
+// Get query history usage per user.
+//
+// Returns the number of queries, the number of starred queries and the time range of queries in query history per user and organization.
+// Users with most queries are returned first. Only available to Grafana server administrators.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: getQueryHistoryUsageResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *QueryHistoryService) usageHandler(c *contextmodel.ReqContext) response.Response {
+	query := GetQueryHistoryUsageQuery{
+		OrgID:   c.QueryInt64("orgId"),
+		UserID:  c.QueryInt64("userId"),
+		Page:    c.QueryInt("page"),
+		PerPage: c.QueryInt("perpage"),
+	}
+
+	result, err := s.GetQueryHistoryUsage(c.Req.Context(), query)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get query history usage", err)
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:parameters starQuery patchQueryComment deleteQuery unstarQuery shareQuery unshareQuery
 type QueryHistoryByUID struct {
 	// in:path
 	// required:true
@@ -224,6 +426,10 @@ type SearchQueriesParams struct {
 	// in:query
 	// required: false
 	OnlyStarred bool `json:"onlyStarred"`
+	// Flag indicating if only queries shared by other users with the organization or a team of the user should be returned
+	// in:query
+	// required: false
+	OnlyShared bool `json:"onlyShared"`
 	// Sort method
 	// in:query
 	// required: false
@@ -262,6 +468,58 @@ type PatchQueryCommentParams struct {
 	Body PatchQueryCommentInQueryHistoryCommand `json:"body"`
 }
 
+// swagger:parameters shareQuery
+type ShareQueryParams struct {
+	// in:body
+	// required:true
+	Body ShareQueryInQueryHistoryCommand `json:"body"`
+}
+
+// swagger:parameters unshareQuery
+type UnshareQueryParams struct {
+	// ID of the team the query is no longer shared with. The organization wide share is removed when omitted.
+	// in:query
+	// required: false
+	TeamID int64 `json:"teamId"`
+}
+
+// swagger:parameters exportQueryHistory
+type ExportQueryHistoryParams struct {
+	// Format of the export
+	// in:query
+	// required: false
+	// default: json
+	// Enum: json,csv
+	Format string `json:"format"`
+}
+
+// swagger:parameters importQueryHistory
+type ImportQueryHistoryParams struct {
+	// in:body
+	// required:true
+	Body QueryHistoryExport `json:"body"`
+}
+
+// swagger:parameters getQueryHistoryUsage
+type GetQueryHistoryUsageParams struct {
+	// Only return usage in the organization
+	// in:query
+	// required: false
+	OrgID int64 `json:"orgId"`
+	// Only return usage of the user
+	// in:query
+	// required: false
+	UserID int64 `json:"userId"`
+	// Page number, starts at 1
+	// in:query
+	// required: false
+	Page int `json:"page"`
+	// Number of users per page, the default is 100
+	// in:query
+	// required: false
+	PerPage int `json:"perpage"`
+}
+
 //swagger:response getQueryHistorySearchResponse
 type GetQueryHistorySearchResponse struct {
 	// in: body
@@ -279,3 +537,27 @@ type GetQueryHistoryDeleteQueryResponse struct {
 	// in: body
 	Body QueryHistoryDeleteQueryResponse `json:"body"`
 }
+
+// swagger:response getQueryHistoryShareResponse
+type GetQueryHistoryShareResponse struct {
+	// in: body
+	Body QueryHistoryShareResponse `json:"body"`
+}
+
+// swagger:response getQueryHistoryExportResponse
+type GetQueryHistoryExportResponse struct {
+	// in: body
+	Body QueryHistoryExport `json:"body"`
+}
+
+// swagger:response getQueryHistoryImportResponse
+type GetQueryHistoryImportResponse struct {
+	// in: body
+	Body QueryHistoryImportResult `json:"body"`
+}
+
+// swagger:response getQueryHistoryUsageResponse
+type GetQueryHistoryUsageResponse struct {
+	// in: body
+	Body QueryHistoryUsageResult `json:"body"`
+}
@@ -2,9 +2,11 @@ package queryhistory
 
 import (
 	"context"
+	"fmt"
 	"strconv"
 
 	"github.com/grafana/grafana/pkg/infra/db"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -102,7 +104,7 @@ func (s QueryHistoryService) searchQueries(ctx context.Context, user *user.Signe
 			query_history.comment,
 			query_history.queries,
 		`)
-		writeStarredSQL(query, s.store, &dtosBuilder, false)
+		writeStarredSQL(query, user, s.store, &dtosBuilder, false)
 		writeFiltersSQL(query, user, s.store, &dtosBuilder)
 		writeSortSQL(query, s.store, &dtosBuilder)
 		writeLimitSQL(query, s.store, &dtosBuilder)
@@ -116,7 +118,7 @@ func (s QueryHistoryService) searchQueries(ctx context.Context, user *user.Signe
 		countBuilder := db.SQLBuilder{}
 		countBuilder.Write(`SELECT
 		`)
-		writeStarredSQL(query, s.store, &countBuilder, true)
+		writeStarredSQL(query, user, s.store, &countBuilder, true)
 		writeFiltersSQL(query, user, s.store, &countBuilder)
 		_, err = session.SQL(countBuilder.GetSQLString(), countBuilder.GetParams()...).Get(&totalCount)
 		return err
@@ -160,6 +162,11 @@ func (s QueryHistoryService) deleteQuery(ctx context.Context, user *user.SignedI
 			return ErrQueryNotFound
 		}
 
+		// Stop sharing the deleted query
+		if _, err := session.Where("org_id = ? AND query_uid = ?", user.OrgID, UID).Delete(QueryHistoryShare{}); err != nil {
+			return err
+		}
+
 		queryID = id
 		return nil
 	})
@@ -306,45 +313,7 @@ func (s QueryHistoryService) unstarQuery(ctx context.Context, user *user.SignedI
 }
 
 func (s QueryHistoryService) deleteStaleQueries(ctx context.Context, olderThan int64) (int, error) {
-	var rowsCount int64
-
-	err := s.store.WithDbSession(ctx, func(session *db.Session) error {
-		uids_sql := `SELECT uid FROM (
-			SELECT uid FROM query_history
-			LEFT JOIN query_history_star
-			ON query_history_star.query_uid = query_history.uid
-			WHERE query_history_star.query_uid IS NULL
-			AND query_history.created_at <= ?
-			ORDER BY query_history.id ASC
-			LIMIT 10000
-		) AS q`
-
-		details_sql := `DELETE
-			FROM query_history_details
-			WHERE query_history_item_uid IN (` + uids_sql + `)`
-
-		sql := `DELETE
-			FROM query_history
-			WHERE uid IN (` + uids_sql + `)`
-
-		_, err := session.Exec(details_sql, strconv.FormatInt(olderThan, 10))
-		if err != nil {
-			return err
-		}
-
-		res, err := session.Exec(sql, strconv.FormatInt(olderThan, 10))
-		if err != nil {
-			return err
-		}
-
-		rowsCount, err = res.RowsAffected()
-		if err != nil {
-			return err
-		}
-
-		return nil
-	})
-
+	rowsCount, err := s.deleteUnstarredQueries(ctx, " AND query_history.created_at <= ?", []any{strconv.FormatInt(olderThan, 10)}, maxDeletedRows)
 	if err != nil {
 		return 0, err
 	}
@@ -419,3 +388,246 @@ func (s QueryHistoryService) enforceQueryHistoryRowLimit(ctx context.Context, li
 
 	return int(deletedRowsCount), nil
 }
+
+// shareQuery shares query owned by the user with a team or, if no team is provided, with the whole organization
+func (s QueryHistoryService) shareQuery(ctx context.Context, user *user.SignedInUser, UID string, cmd ShareQueryInQueryHistoryCommand) (QueryHistoryShareDTO, error) {
+	share := QueryHistoryShare{
+		QueryUID:  UID,
+		OrgID:     user.OrgID,
+		TeamID:    cmd.TeamID,
+		CreatedBy: user.UserID,
+		CreatedAt: s.now().Unix(),
+	}
+
+	// The owner can share the query with the teams they are a member of or can read
+	canReadTeam := false
+	if cmd.TeamID != 0 {
+		var err error
+		canReadTeam, err = s.accessControl.Evaluate(ctx, user, ac.EvalPermission(ac.ActionTeamsRead, ac.Scope("teams", "id", strconv.FormatInt(cmd.TeamID, 10))))
+		if err != nil {
+			return QueryHistoryShareDTO{}, err
+		}
+	}
+
+	err := s.store.WithDbSession(ctx, func(session *db.Session) error {
+		// Only the owner of the query can share it
+		exists, err := session.Table("query_history").Where("org_id = ? AND created_by = ? AND uid = ?", user.OrgID, user.UserID, UID).Exist()
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrQueryNotFound
+		}
+
+		if cmd.TeamID != 0 {
+			exists, err = session.Table("team").Where("org_id = ? AND id = ?", user.OrgID, cmd.TeamID).Exist()
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return ErrTeamNotFound
+			}
+
+			if !canReadTeam {
+				isMember, err := session.Table("team_member").Where("org_id = ? AND team_id = ? AND user_id = ?", user.OrgID, cmd.TeamID, user.UserID).Exist()
+				if err != nil {
+					return err
+				}
+				if !isMember {
+					return ErrTeamAccessDenied
+				}
+			}
+		}
+
+		_, err = session.Insert(&share)
+		if err != nil {
+			if s.store.GetDialect().IsUniqueConstraintViolation(err) {
+				return ErrQueryAlreadyShared
+			}
+			return err
+		}
+		return nil
+	})
+
+	if err != nil {
+		return QueryHistoryShareDTO{}, err
+	}
+
+	return QueryHistoryShareDTO{
+		QueryUID:  share.QueryUID,
+		TeamID:    share.TeamID,
+		CreatedAt: share.CreatedAt,
+	}, nil
+}
+
+// unshareQuery stops sharing query owned by the user with a team or with the whole organization
+func (s QueryHistoryService) unshareQuery(ctx context.Context, user *user.SignedInUser, UID string, teamID int64) error {
+	return s.store.WithDbSession(ctx, func(session *db.Session) error {
+		exists, err := session.Table("query_history").Where("org_id = ? AND created_by = ? AND uid = ?", user.OrgID, user.UserID, UID).Exist()
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrQueryNotFound
+		}
+
+		id, err := session.Where("org_id = ? AND query_uid = ? AND team_id = ?", user.OrgID, UID, teamID).Delete(QueryHistoryShare{})
+		if err != nil {
+			return err
+		}
+		if id == 0 {
+			return ErrSharedQueryNotFound
+		}
+		return nil
+	})
+}
+
+// exportQueries returns all queries of the user in query history, oldest first
+func (s QueryHistoryService) exportQueries(ctx context.Context, user *user.SignedInUser) (QueryHistoryExport, error) {
+	var dtos []QueryHistoryDTO
+
+	err := s.store.WithDbSession(ctx, func(session *db.Session) error {
+		builder := db.SQLBuilder{}
+		builder.Write(`SELECT
+			query_history.uid,
+			query_history.datasource_uid,
+			query_history.created_by,
+			query_history.created_at AS created_at,
+			query_history.comment,
+			query_history.queries,
+		`)
+		writeStarredSQL(SearchInQueryHistoryQuery{}, user, s.store, &builder, false)
+		builder.Write(" WHERE query_history.org_id = ? AND query_history.created_by = ? ORDER BY created_at ASC", user.OrgID, user.UserID)
+
+		return session.SQL(builder.GetSQLString(), builder.GetParams()...).Find(&dtos)
+	})
+	if err != nil {
+		return QueryHistoryExport{}, err
+	}
+
+	export := QueryHistoryExport{Queries: make([]QueryHistoryExportItem, 0, len(dtos))}
+	for _, dto := range dtos {
+		export.Queries = append(export.Queries, QueryHistoryExportItem{
+			DatasourceUID: dto.DatasourceUID,
+			CreatedAt:     dto.CreatedAt,
+			Comment:       dto.Comment,
+			Queries:       dto.Queries,
+			Starred:       dto.Starred,
+		})
+	}
+
+	return export, nil
+}
+
+// importQueries adds exported queries into query history of the user. Imported queries get new UIDs.
+func (s QueryHistoryService) importQueries(ctx context.Context, user *user.SignedInUser, export QueryHistoryExport) (int, error) {
+	for i, item := range export.Queries {
+		if item.Queries == nil {
+			return 0, fmt.Errorf("%w: query %d has no queries", ErrInvalidImport, i)
+		}
+	}
+
+	err := s.store.WithTransactionalDbSession(ctx, func(session *db.Session) error {
+		for _, item := range export.Queries {
+			queryHistory := QueryHistory{
+				OrgID:         user.OrgID,
+				UID:           util.GenerateShortUID(),
+				Queries:       item.Queries,
+				DatasourceUID: item.DatasourceUID,
+				CreatedBy:     user.UserID,
+				CreatedAt:     item.CreatedAt,
+				Comment:       item.Comment,
+			}
+			if queryHistory.CreatedAt <= 0 {
+				queryHistory.CreatedAt = s.now().Unix()
+			}
+
+			if _, err := session.Insert(&queryHistory); err != nil {
+				return err
+			}
+
+			// Details are only used for filtering, so queries with unknown structure are still imported
+			if dsUids, err := FindDataSourceUIDs(item.Queries); err == nil {
+				for _, uid := range dsUids {
+					if _, err := session.Insert(&QueryHistoryDetails{QueryHistoryItemUID: queryHistory.UID, DatasourceUID: uid}); err != nil {
+						return err
+					}
+				}
+			}
+
+			if item.Starred {
+				if _, err := session.Insert(&QueryHistoryStar{UserID: user.UserID, QueryUID: queryHistory.UID}); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(export.Queries), nil
+}
+
+// getUsage reports the number of queries in query history per user and organization, largest first
+func (s QueryHistoryService) getUsage(ctx context.Context, query GetQueryHistoryUsageQuery) (QueryHistoryUsageResult, error) {
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+
+	if query.PerPage <= 0 {
+		query.PerPage = 100
+	}
+
+	usage := make([]QueryHistoryUsage, 0)
+	var totalCount int64
+
+	err := s.store.WithDbSession(ctx, func(session *db.Session) error {
+		filter := db.SQLBuilder{}
+		filter.Write(" WHERE 1 = 1")
+		if query.OrgID != 0 {
+			filter.Write(" AND query_history.org_id = ?", query.OrgID)
+		}
+		if query.UserID != 0 {
+			filter.Write(" AND query_history.created_by = ?", query.UserID)
+		}
+
+		userTable := s.store.GetDialect().Quote("user")
+		builder := db.SQLBuilder{}
+		builder.Write(`SELECT
+			query_history.created_by AS user_id,
+			` + userTable + `.login,
+			query_history.org_id,
+			COUNT(query_history.id) AS queries,
+			COUNT(query_history_star.id) AS starred,
+			MIN(query_history.created_at) AS oldest_query_at,
+			MAX(query_history.created_at) AS newest_query_at
+			FROM query_history
+			LEFT JOIN query_history_star ON query_history_star.query_uid = query_history.uid AND query_history_star.user_id = query_history.created_by
+			LEFT JOIN ` + userTable + ` ON ` + userTable + `.id = query_history.created_by`)
+		builder.Write(filter.GetSQLString(), filter.GetParams()...)
+		builder.Write(` GROUP BY query_history.created_by, ` + userTable + `.login, query_history.org_id
+			ORDER BY queries DESC, query_history.created_by ASC`)
+		builder.Write(s.store.GetDialect().LimitOffset(int64(query.PerPage), int64((query.Page-1)*query.PerPage)))
+
+		if err := session.SQL(builder.GetSQLString(), builder.GetParams()...).Find(&usage); err != nil {
+			return err
+		}
+
+		_, err := session.SQL(`SELECT COUNT(*) FROM (
+			SELECT DISTINCT query_history.created_by, query_history.org_id FROM query_history`+filter.GetSQLString()+`
+		) AS u`, filter.GetParams()...).Get(&totalCount)
+		return err
+	})
+	if err != nil {
+		return QueryHistoryUsageResult{}, err
+	}
+
+	return QueryHistoryUsageResult{
+		TotalCount: totalCount,
+		Usage:      usage,
+		Page:       query.Page,
+		PerPage:    query.PerPage,
+	}, nil
+}
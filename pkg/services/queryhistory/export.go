@@ -0,0 +1,99 @@
+package queryhistory
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+const (
+	exportFormatJSON = "json"
+	exportFormatCSV  = "csv"
+)
+
+// csvHeader are the columns of query history exported as CSV. Queries are stored as JSON encoded string.
+var csvHeader = []string{"datasource_uid", "created_at", "comment", "starred", "queries"}
+
+func writeExportCSV(w io.Writer, export QueryHistoryExport) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, item := range export.Queries {
+		queries, err := item.Queries.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		record := []string{
+			item.DatasourceUID,
+			strconv.FormatInt(item.CreatedAt, 10),
+			item.Comment,
+			strconv.FormatBool(item.Starred),
+			string(queries),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func readExportCSV(r io.Reader) (QueryHistoryExport, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = len(csvHeader)
+
+	header, err := reader.Read()
+	if err != nil {
+		return QueryHistoryExport{}, fmt.Errorf("%w: %s", ErrInvalidImport, err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[name] = i
+	}
+	for _, name := range csvHeader {
+		if _, ok := columns[name]; !ok {
+			return QueryHistoryExport{}, fmt.Errorf("%w: missing column %q", ErrInvalidImport, name)
+		}
+	}
+
+	export := QueryHistoryExport{Queries: make([]QueryHistoryExportItem, 0)}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return QueryHistoryExport{}, fmt.Errorf("%w: %s", ErrInvalidImport, err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		createdAt, err := strconv.ParseInt(record[columns["created_at"]], 10, 64)
+		if err != nil {
+			return QueryHistoryExport{}, fmt.Errorf("%w: line %d: invalid created_at", ErrInvalidImport, line)
+		}
+		starred, err := strconv.ParseBool(record[columns["starred"]])
+		if err != nil {
+			return QueryHistoryExport{}, fmt.Errorf("%w: line %d: invalid starred", ErrInvalidImport, line)
+		}
+		queries, err := simplejson.NewJson([]byte(record[columns["queries"]]))
+		if err != nil {
+			return QueryHistoryExport{}, fmt.Errorf("%w: line %d: invalid queries", ErrInvalidImport, line)
+		}
+
+		export.Queries = append(export.Queries, QueryHistoryExportItem{
+			DatasourceUID: record[columns["datasource_uid"]],
+			CreatedAt:     createdAt,
+			Comment:       record[columns["comment"]],
+			Queries:       queries,
+			Starred:       starred,
+		})
+	}
+
+	return export, nil
+}
@@ -10,6 +10,12 @@ var (
 	ErrQueryNotFound        = errors.New("query in query history not found")
 	ErrStarredQueryNotFound = errors.New("starred query not found")
 	ErrQueryAlreadyStarred  = errors.New("query was already starred")
+	ErrQueryAlreadyShared   = errors.New("query was already shared")
+	ErrSharedQueryNotFound  = errors.New("shared query not found")
+	ErrTeamNotFound         = errors.New("team not found")
+	ErrTeamAccessDenied     = errors.New("user is not a member of the team")
+	ErrInvalidImport        = errors.New("invalid query history import")
+	ErrInvalidExportFormat  = errors.New("invalid query history export format")
 )
 
 // QueryHistory is the model for query history definitions
@@ -31,10 +37,22 @@ type QueryHistoryStar struct {
 	UserID   int64  `xorm:"user_id"`
 }
 
+// QueryHistoryShare is the model for sharing queries with a team or, when
+// TeamID is 0, with the whole organization
+type QueryHistoryShare struct {
+	ID        int64  `xorm:"pk autoincr 'id'"`
+	QueryUID  string `xorm:"query_uid"`
+	OrgID     int64  `xorm:"org_id"`
+	TeamID    int64  `xorm:"team_id"`
+	CreatedBy int64
+	CreatedAt int64
+}
+
 type SearchInQueryHistoryQuery struct {
 	DatasourceUIDs []string `json:"datasourceUids"`
 	SearchString   string   `json:"searchString"`
 	OnlyStarred    bool     `json:"onlyStarred"`
+	OnlyShared     bool     `json:"onlyShared"`
 	Sort           string   `json:"sort"`
 	Page           int      `json:"page"`
 	Limit          int      `json:"limit"`
@@ -91,3 +109,69 @@ type PatchQueryCommentInQueryHistoryCommand struct {
 	// Updated comment
 	Comment string `json:"comment"`
 }
+
+// ShareQueryInQueryHistoryCommand is the command for sharing query in query history
+// swagger:model
+type ShareQueryInQueryHistoryCommand struct {
+	// ID of the team the query is shared with. The query is shared with the whole organization when omitted.
+	TeamID int64 `json:"teamId"`
+}
+
+// QueryHistoryShareDTO describes with whom a query is shared
+type QueryHistoryShareDTO struct {
+	QueryUID  string `json:"queryUid"`
+	TeamID    int64  `json:"teamId"`
+	CreatedAt int64  `json:"createdAt"`
+}
+
+// QueryHistoryShareResponse is a response struct for QueryHistoryShareDTO
+type QueryHistoryShareResponse struct {
+	Result QueryHistoryShareDTO `json:"result"`
+}
+
+// QueryHistoryExportItem is a single query of an export. It does not contain
+// any instance specific identifiers so that it can be imported anywhere.
+type QueryHistoryExportItem struct {
+	DatasourceUID string           `json:"datasourceUid"`
+	CreatedAt     int64            `json:"createdAt"`
+	Comment       string           `json:"comment"`
+	Queries       *simplejson.Json `json:"queries"`
+	Starred       bool             `json:"starred"`
+}
+
+// QueryHistoryExport is the JSON document for exporting and importing query history
+// swagger:model
+type QueryHistoryExport struct {
+	Queries []QueryHistoryExportItem `json:"queries"`
+}
+
+// QueryHistoryImportResult is the response struct for importing query history
+type QueryHistoryImportResult struct {
+	Imported int    `json:"imported"`
+	Message  string `json:"message"`
+}
+
+type GetQueryHistoryUsageQuery struct {
+	OrgID   int64 `json:"orgId"`
+	UserID  int64 `json:"userId"`
+	Page    int   `json:"page"`
+	PerPage int   `json:"perPage"`
+}
+
+// QueryHistoryUsage is the usage of query history of a user in an organization
+type QueryHistoryUsage struct {
+	UserID        int64  `json:"userId" xorm:"user_id"`
+	Login         string `json:"login"`
+	OrgID         int64  `json:"orgId" xorm:"org_id"`
+	Queries       int64  `json:"queries"`
+	Starred       int64  `json:"starred"`
+	OldestQueryAt int64  `json:"oldestQueryAt"`
+	NewestQueryAt int64  `json:"newestQueryAt"`
+}
+
+type QueryHistoryUsageResult struct {
+	TotalCount int64               `json:"totalCount"`
+	Usage      []QueryHistoryUsage `json:"usage"`
+	Page       int                 `json:"page"`
+	PerPage    int                 `json:"perPage"`
+}
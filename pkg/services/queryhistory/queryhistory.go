@@ -8,26 +8,41 @@ import (
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
-func ProvideService(cfg *setting.Cfg, sqlStore db.DB, routeRegister routing.RouteRegister, accessControl ac.AccessControl) *QueryHistoryService {
+func ProvideService(cfg *setting.Cfg, sqlStore db.DB, routeRegister routing.RouteRegister, accessControl ac.AccessControl, quotaService quota.Service) (*QueryHistoryService, error) {
 	s := &QueryHistoryService{
 		store:         sqlStore,
 		Cfg:           cfg,
 		RouteRegister: routeRegister,
+		QuotaService:  quotaService,
 		log:           log.New("query-history"),
 		now:           time.Now,
 		accessControl: accessControl,
 	}
 
+	defaultLimits, err := readQuotaConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := quotaService.RegisterQuotaReporter(&quota.NewUsageReporter{
+		TargetSrv:     QuotaTargetSrv,
+		DefaultLimits: defaultLimits,
+		Reporter:      s.Usage,
+	}); err != nil {
+		return nil, err
+	}
+
 	// Register routes only when query history is enabled
 	if s.Cfg.QueryHistoryEnabled {
 		s.registerAPIEndpoints()
 	}
 
-	return s
+	return s, nil
 }
 
 type Service interface {
@@ -37,14 +52,21 @@ type Service interface {
 	PatchQueryCommentInQueryHistory(ctx context.Context, user *user.SignedInUser, UID string, cmd PatchQueryCommentInQueryHistoryCommand) (QueryHistoryDTO, error)
 	StarQueryInQueryHistory(ctx context.Context, user *user.SignedInUser, UID string) (QueryHistoryDTO, error)
 	UnstarQueryInQueryHistory(ctx context.Context, user *user.SignedInUser, UID string) (QueryHistoryDTO, error)
+	ShareQueryInQueryHistory(ctx context.Context, user *user.SignedInUser, UID string, cmd ShareQueryInQueryHistoryCommand) (QueryHistoryShareDTO, error)
+	UnshareQueryInQueryHistory(ctx context.Context, user *user.SignedInUser, UID string, teamID int64) error
+	ExportQueryHistory(ctx context.Context, user *user.SignedInUser) (QueryHistoryExport, error)
+	ImportQueryHistory(ctx context.Context, user *user.SignedInUser, export QueryHistoryExport) (int, error)
 	DeleteStaleQueriesInQueryHistory(ctx context.Context, olderThan int64) (int, error)
 	EnforceRowLimitInQueryHistory(ctx context.Context, limit int, starredQueries bool) (int, error)
+	EnforceQuotasInQueryHistory(ctx context.Context) (int, error)
+	GetQueryHistoryUsage(ctx context.Context, query GetQueryHistoryUsageQuery) (QueryHistoryUsageResult, error)
 }
 
 type QueryHistoryService struct {
 	store         db.DB
 	Cfg           *setting.Cfg
 	RouteRegister routing.RouteRegister
+	QuotaService  quota.Service
 	log           log.Logger
 	now           func() time.Time
 	accessControl ac.AccessControl
@@ -74,6 +96,22 @@ func (s QueryHistoryService) UnstarQueryInQueryHistory(ctx context.Context, user
 	return s.unstarQuery(ctx, user, UID)
 }
 
+func (s QueryHistoryService) ShareQueryInQueryHistory(ctx context.Context, user *user.SignedInUser, UID string, cmd ShareQueryInQueryHistoryCommand) (QueryHistoryShareDTO, error) {
+	return s.shareQuery(ctx, user, UID, cmd)
+}
+
+func (s QueryHistoryService) UnshareQueryInQueryHistory(ctx context.Context, user *user.SignedInUser, UID string, teamID int64) error {
+	return s.unshareQuery(ctx, user, UID, teamID)
+}
+
+func (s QueryHistoryService) ExportQueryHistory(ctx context.Context, user *user.SignedInUser) (QueryHistoryExport, error) {
+	return s.exportQueries(ctx, user)
+}
+
+func (s QueryHistoryService) ImportQueryHistory(ctx context.Context, user *user.SignedInUser, export QueryHistoryExport) (int, error) {
+	return s.importQueries(ctx, user, export)
+}
+
 func (s QueryHistoryService) DeleteStaleQueriesInQueryHistory(ctx context.Context, olderThan int64) (int, error) {
 	return s.deleteStaleQueries(ctx, olderThan)
 }
@@ -81,3 +119,11 @@ func (s QueryHistoryService) DeleteStaleQueriesInQueryHistory(ctx context.Contex
 func (s QueryHistoryService) EnforceRowLimitInQueryHistory(ctx context.Context, limit int, starredQueries bool) (int, error) {
 	return s.enforceQueryHistoryRowLimit(ctx, limit, starredQueries)
 }
+
+func (s QueryHistoryService) EnforceQuotasInQueryHistory(ctx context.Context) (int, error) {
+	return s.enforceQueryHistoryQuotas(ctx)
+}
+
+func (s QueryHistoryService) GetQueryHistoryUsage(ctx context.Context, query GetQueryHistoryUsageQuery) (QueryHistoryUsageResult, error) {
+	return s.getUsage(ctx, query)
+}
@@ -0,0 +1,106 @@
+package queryhistory
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationExportQueryHistory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	testScenarioWithMultipleQueriesInQueryHistory(t, "When users tries to export query history as JSON, it should return all queries",
+		func(t *testing.T, sc scenarioContext) {
+			resp := sc.service.exportHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+
+			var export QueryHistoryExport
+			require.NoError(t, json.Unmarshal(resp.Body(), &export))
+			require.Len(t, export.Queries, 3)
+			require.Equal(t, "test comment 2", export.Queries[0].Comment)
+			require.False(t, export.Queries[0].Starred)
+			require.True(t, export.Queries[1].Starred)
+			require.Equal(t, testDsUID2, export.Queries[2].DatasourceUID)
+		})
+
+	testScenarioWithMultipleQueriesInQueryHistory(t, "When users tries to export query history as CSV, it should return all queries",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.Req.Form.Add("format", "csv")
+			resp := sc.service.exportHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+
+			export, err := readExportCSV(bytes.NewReader(resp.Body()))
+			require.NoError(t, err)
+			require.Len(t, export.Queries, 3)
+			require.Equal(t, "test", export.Queries[0].Queries.GetIndex(0).Get("expr").MustString())
+		})
+
+	testScenario(t, "When users tries to export query history in unknown format, it should fail", false, false,
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.Req.Form.Add("format", "xml")
+			resp := sc.service.exportHandler(sc.reqContext)
+			require.Equal(t, 400, resp.Status())
+		})
+}
+
+func TestIntegrationImportQueryHistory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	testScenarioWithMultipleQueriesInQueryHistory(t, "When users tries to import exported JSON, it should create new queries",
+		func(t *testing.T, sc scenarioContext) {
+			resp := sc.service.exportHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+
+			sc.reqContext.Req.Body = io.NopCloser(bytes.NewReader(resp.Body()))
+			resp = sc.service.importHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+
+			var result QueryHistoryImportResult
+			require.NoError(t, json.Unmarshal(resp.Body(), &result))
+			require.Equal(t, 3, result.Imported)
+
+			search, err := sc.service.SearchInQueryHistory(sc.reqContext.Req.Context(), sc.reqContext.SignedInUser, SearchInQueryHistoryQuery{})
+			require.NoError(t, err)
+			require.Equal(t, 6, search.TotalCount)
+
+			search, err = sc.service.SearchInQueryHistory(sc.reqContext.Req.Context(), sc.reqContext.SignedInUser, SearchInQueryHistoryQuery{OnlyStarred: true})
+			require.NoError(t, err)
+			require.Equal(t, 4, search.TotalCount)
+		})
+
+	testScenarioWithMultipleQueriesInQueryHistory(t, "When users tries to import exported CSV, it should create new queries",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.Req.Form.Add("format", "csv")
+			resp := sc.service.exportHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+
+			sc.reqContext.Req.Header.Set("Content-Type", "text/csv")
+			sc.reqContext.Req.Body = io.NopCloser(bytes.NewReader(resp.Body()))
+			resp = sc.service.importHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+
+			search, err := sc.service.SearchInQueryHistory(sc.reqContext.Req.Context(), sc.reqContext.SignedInUser, SearchInQueryHistoryQuery{DatasourceUIDs: []string{testDsUID2}})
+			require.NoError(t, err)
+			require.Equal(t, 2, search.TotalCount)
+		})
+
+	testScenario(t, "When users tries to import queries without queries model, it should fail", false, false,
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.Req.Body = mockRequestBody(QueryHistoryExport{Queries: []QueryHistoryExportItem{{DatasourceUID: testDsUID1}}})
+			resp := sc.service.importHandler(sc.reqContext)
+			require.Equal(t, 400, resp.Status())
+		})
+
+	testScenario(t, "When users tries to import invalid CSV, it should fail", false, false,
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.Req.Header.Set("Content-Type", "text/csv")
+			sc.reqContext.Req.Body = io.NopCloser(bytes.NewReader([]byte("datasource_uid,created_at\nabc,1\n")))
+			resp := sc.service.importHandler(sc.reqContext)
+			require.Equal(t, 400, resp.Status())
+		})
+}
@@ -0,0 +1,211 @@
+package queryhistory
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/services/quota/quotaimpl"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestIntegrationEnforceQuotasInQueryHistory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	testScenarioWithMultipleQueriesInQueryHistory(t, "When quotas are disabled, global row limit is enforced without deleting starred queries",
+		func(t *testing.T, sc scenarioContext) {
+			setupQuotaService(t, sc, false)
+			sc.service.Cfg.Quota.Global.QueryHistory = 1
+
+			rowsDeleted, err := sc.service.EnforceQuotasInQueryHistory(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, 1, rowsDeleted)
+			requireQueriesCount(t, sc, 2)
+		})
+
+	testScenarioWithMultipleQueriesInQueryHistory(t, "When quotas are disabled, global retention is enforced without deleting starred queries",
+		func(t *testing.T, sc scenarioContext) {
+			setupQuotaService(t, sc, false)
+			sc.service.Cfg.Quota.Global.QueryHistoryRetentionDays = 0
+
+			rowsDeleted, err := sc.service.EnforceQuotasInQueryHistory(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, 1, rowsDeleted)
+			requireQueriesCount(t, sc, 2)
+		})
+
+	testScenarioWithMultipleQueriesInQueryHistory(t, "When quotas are disabled, custom limits are not enforced",
+		func(t *testing.T, sc scenarioContext) {
+			setupQuotaService(t, sc, false)
+			sc.service.Cfg.Quota.Org.QueryHistory = 0
+
+			rowsDeleted, err := sc.service.EnforceQuotasInQueryHistory(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, 0, rowsDeleted)
+			requireQueriesCount(t, sc, 3)
+		})
+
+	testScenarioWithMultipleQueriesInQueryHistory(t, "When user quota is reached, oldest queries that are not starred are deleted",
+		func(t *testing.T, sc scenarioContext) {
+			quotaService := setupQuotaService(t, sc, true)
+			err := quotaService.Update(context.Background(), &quota.UpdateQuotaCmd{UserID: testUserID, Target: string(QuotaTarget), Limit: 0})
+			require.NoError(t, err)
+
+			rowsDeleted, err := sc.service.EnforceQuotasInQueryHistory(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, 1, rowsDeleted)
+			requireQueriesCount(t, sc, 2)
+		})
+
+	testScenarioWithMultipleQueriesInQueryHistory(t, "When default user quota is exceeded, oldest queries that are not starred are deleted",
+		func(t *testing.T, sc scenarioContext) {
+			setupQuotaService(t, sc, true)
+			sc.service.Cfg.Quota.User.QueryHistory = 0
+
+			rowsDeleted, err := sc.service.EnforceQuotasInQueryHistory(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, 1, rowsDeleted)
+			requireQueriesCount(t, sc, 2)
+		})
+
+	testScenarioWithMultipleQueriesInQueryHistory(t, "When user quota is above the default quota, queries are not deleted",
+		func(t *testing.T, sc scenarioContext) {
+			quotaService := setupQuotaService(t, sc, true)
+			sc.service.Cfg.Quota.User.QueryHistory = 0
+			err := quotaService.Update(context.Background(), &quota.UpdateQuotaCmd{UserID: testUserID, Target: string(QuotaTarget), Limit: 10})
+			require.NoError(t, err)
+
+			rowsDeleted, err := sc.service.EnforceQuotasInQueryHistory(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, 0, rowsDeleted)
+			requireQueriesCount(t, sc, 3)
+		})
+
+	testScenarioWithMultipleQueriesInQueryHistory(t, "When default team retention is exceeded, queries of team members are deleted",
+		func(t *testing.T, sc scenarioContext) {
+			setupQuotaService(t, sc, true)
+			createTeamWithMembers(t, sc.sqlStore, testUserID)
+			sc.service.Cfg.Quota.Team.QueryHistoryRetentionDays = 0
+
+			rowsDeleted, err := sc.service.EnforceQuotasInQueryHistory(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, 1, rowsDeleted)
+			requireQueriesCount(t, sc, 2)
+		})
+
+	testScenarioWithMultipleQueriesInQueryHistory(t, "When team retention is set, queries of team members are deleted",
+		func(t *testing.T, sc scenarioContext) {
+			quotaService := setupQuotaService(t, sc, true)
+			teamID := createTeamWithMembers(t, sc.sqlStore, testUserID)
+			err := quotaService.Update(context.Background(), &quota.UpdateQuotaCmd{OrgID: testOrgID, TeamID: teamID, Target: string(QuotaRetentionTarget), Limit: 0})
+			require.NoError(t, err)
+
+			rowsDeleted, err := sc.service.EnforceQuotasInQueryHistory(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, 1, rowsDeleted)
+			requireQueriesCount(t, sc, 2)
+		})
+
+	testScenarioWithMultipleQueriesInQueryHistory(t, "When team retention is set, queries of other users are not deleted",
+		func(t *testing.T, sc scenarioContext) {
+			quotaService := setupQuotaService(t, sc, true)
+			teamID := createTeamWithMembers(t, sc.sqlStore, otherSignedInUser().UserID)
+			err := quotaService.Update(context.Background(), &quota.UpdateQuotaCmd{OrgID: testOrgID, TeamID: teamID, Target: string(QuotaRetentionTarget), Limit: 0})
+			require.NoError(t, err)
+
+			rowsDeleted, err := sc.service.EnforceQuotasInQueryHistory(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, 0, rowsDeleted)
+			requireQueriesCount(t, sc, 3)
+		})
+}
+
+func TestIntegrationQueryHistoryUsage(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	testScenarioWithMultipleQueriesInQueryHistory(t, "Usage reports number of queries per scope",
+		func(t *testing.T, sc scenarioContext) {
+			u, err := sc.service.Usage(context.Background(), &quota.ScopeParameters{OrgID: testOrgID, UserID: otherSignedInUser().UserID})
+			require.NoError(t, err)
+
+			for scope, expected := range map[quota.Scope]int64{quota.GlobalScope: 3, quota.OrgScope: 3, quota.UserScope: 0} {
+				tag, err := quota.NewTag(QuotaTargetSrv, QuotaTarget, scope)
+				require.NoError(t, err)
+				used, ok := u.Get(tag)
+				require.True(t, ok)
+				require.Equal(t, expected, used, scope)
+			}
+
+			tag, err := quota.NewTag(QuotaTargetSrv, QuotaRetentionTarget, quota.GlobalScope)
+			require.NoError(t, err)
+			days, ok := u.Get(tag)
+			require.True(t, ok)
+			require.Equal(t, int64(0), days)
+		})
+
+	testScenarioWithMultipleQueriesInQueryHistory(t, "Admin usage endpoint reports usage per user",
+		func(t *testing.T, sc scenarioContext) {
+			resp := sc.service.usageHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+
+			var result QueryHistoryUsageResult
+			require.NoError(t, json.Unmarshal(resp.Body(), &result))
+			require.Equal(t, int64(1), result.TotalCount)
+			require.Len(t, result.Usage, 1)
+			require.Equal(t, testUserID, result.Usage[0].UserID)
+			require.Equal(t, "signed_in_user", result.Usage[0].Login)
+			require.Equal(t, int64(3), result.Usage[0].Queries)
+			require.Equal(t, int64(2), result.Usage[0].Starred)
+		})
+
+	testScenarioWithMultipleQueriesInQueryHistory(t, "Admin usage endpoint filters by user",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.Req.Form.Add("userId", "2")
+			resp := sc.service.usageHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+
+			var result QueryHistoryUsageResult
+			require.NoError(t, json.Unmarshal(resp.Body(), &result))
+			require.Equal(t, int64(0), result.TotalCount)
+			require.Empty(t, result.Usage)
+		})
+}
+
+func setupQuotaService(t *testing.T, sc scenarioContext, enabled bool) quota.Service {
+	t.Helper()
+
+	sc.service.Cfg.Quota = setting.QuotaSettings{
+		Enabled: enabled,
+		Org:     setting.OrgQuota{QueryHistory: -1, QueryHistoryRetentionDays: -1},
+		User:    setting.UserQuota{QueryHistory: -1},
+		Team:    setting.TeamQuota{QueryHistory: -1, QueryHistoryRetentionDays: -1},
+		Global:  setting.GlobalQuota{QueryHistory: -1, QueryHistoryRetentionDays: -1},
+	}
+	quotaService := quotaimpl.ProvideService(db.FakeReplDBFromDB(sc.sqlStore), sc.service.Cfg)
+
+	defaultLimits, err := readQuotaConfig(sc.service.Cfg)
+	require.NoError(t, err)
+	err = quotaService.RegisterQuotaReporter(&quota.NewUsageReporter{
+		TargetSrv:     QuotaTargetSrv,
+		DefaultLimits: defaultLimits,
+		Reporter:      sc.service.Usage,
+	})
+	require.NoError(t, err)
+
+	sc.service.QuotaService = quotaService
+	return quotaService
+}
+
+func requireQueriesCount(t *testing.T, sc scenarioContext, expected int) {
+	t.Helper()
+
+	result, err := sc.service.SearchInQueryHistory(context.Background(), sc.reqContext.SignedInUser, SearchInQueryHistoryQuery{})
+	require.NoError(t, err)
+	require.Equal(t, expected, result.TotalCount)
+}
@@ -0,0 +1,210 @@
+package queryhistory
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestIntegrationShareQueryInQueryHistory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	testScenarioWithQueryInQueryHistory(t, "When users tries to share query in query history that does not exists, it should fail",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.Req.Body = mockRequestBody(ShareQueryInQueryHistoryCommand{})
+			resp := sc.service.shareHandler(sc.reqContext)
+			require.Equal(t, 404, resp.Status())
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "When users tries to share query with the organization, it should succeed",
+		func(t *testing.T, sc scenarioContext) {
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.reqContext.Req.Body = mockRequestBody(ShareQueryInQueryHistoryCommand{})
+			resp := sc.service.shareHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "When users tries to share query that is already shared, it should fail",
+		func(t *testing.T, sc scenarioContext) {
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.reqContext.Req.Body = mockRequestBody(ShareQueryInQueryHistoryCommand{})
+			sc.service.shareHandler(sc.reqContext)
+			sc.reqContext.Req.Body = mockRequestBody(ShareQueryInQueryHistoryCommand{})
+			resp := sc.service.shareHandler(sc.reqContext)
+			require.Equal(t, 409, resp.Status())
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "When users tries to share query with team that does not exist, it should fail",
+		func(t *testing.T, sc scenarioContext) {
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.reqContext.Req.Body = mockRequestBody(ShareQueryInQueryHistoryCommand{TeamID: 100})
+			resp := sc.service.shareHandler(sc.reqContext)
+			require.Equal(t, 400, resp.Status())
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "When users tries to share query with team they are not a member of, it should fail",
+		func(t *testing.T, sc scenarioContext) {
+			teamID := createTeamWithMembers(t, sc.sqlStore, otherSignedInUser().UserID)
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.reqContext.Req.Body = mockRequestBody(ShareQueryInQueryHistoryCommand{TeamID: teamID})
+			resp := sc.service.shareHandler(sc.reqContext)
+			require.Equal(t, 403, resp.Status())
+
+			count, err := countSharedQueries(sc.sqlStore)
+			require.NoError(t, err)
+			require.Equal(t, int64(0), count)
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "When users tries to share query with team they can read, it should succeed",
+		func(t *testing.T, sc scenarioContext) {
+			teamID := createTeamWithMembers(t, sc.sqlStore, otherSignedInUser().UserID)
+			sc.reqContext.SignedInUser.Permissions = map[int64]map[string][]string{
+				testOrgID: {"teams:read": {"teams:id:" + strconv.FormatInt(teamID, 10)}},
+			}
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.reqContext.Req.Body = mockRequestBody(ShareQueryInQueryHistoryCommand{TeamID: teamID})
+			resp := sc.service.shareHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "When users tries to share query of other user, it should fail",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.SignedInUser = otherSignedInUser()
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.reqContext.Req.Body = mockRequestBody(ShareQueryInQueryHistoryCommand{})
+			resp := sc.service.shareHandler(sc.reqContext)
+			require.Equal(t, 404, resp.Status())
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "When users tries to unshare query that is not shared, it should fail",
+		func(t *testing.T, sc scenarioContext) {
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			resp := sc.service.unshareHandler(sc.reqContext)
+			require.Equal(t, 404, resp.Status())
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "When users tries to unshare query that is shared, it should succeed",
+		func(t *testing.T, sc scenarioContext) {
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.reqContext.Req.Body = mockRequestBody(ShareQueryInQueryHistoryCommand{})
+			sc.service.shareHandler(sc.reqContext)
+			resp := sc.service.unshareHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+		})
+}
+
+func TestIntegrationSearchSharedQueriesInQueryHistory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	testScenarioWithQueryInQueryHistory(t, "When query is shared with the organization, other users should find it",
+		func(t *testing.T, sc scenarioContext) {
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.reqContext.Req.Body = mockRequestBody(ShareQueryInQueryHistoryCommand{})
+			sc.service.shareHandler(sc.reqContext)
+
+			sc.reqContext.SignedInUser = otherSignedInUser()
+			require.Equal(t, 0, searchSharedQueries(t, sc, false).TotalCount)
+
+			result := searchSharedQueries(t, sc, true)
+			require.Equal(t, 1, result.TotalCount)
+			require.Equal(t, sc.initialResult.Result.UID, result.QueryHistory[0].UID)
+			require.Equal(t, testUserID, result.QueryHistory[0].CreatedBy)
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "When query is shared with a team, only team members should find it",
+		func(t *testing.T, sc scenarioContext) {
+			teamID := createTeamWithMembers(t, sc.sqlStore, testUserID, otherSignedInUser().UserID)
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.reqContext.Req.Body = mockRequestBody(ShareQueryInQueryHistoryCommand{TeamID: teamID})
+			resp := sc.service.shareHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+
+			sc.reqContext.SignedInUser = otherSignedInUser()
+			require.Equal(t, 1, searchSharedQueries(t, sc, true).TotalCount)
+
+			sc.reqContext.SignedInUser = &user.SignedInUser{UserID: 3, OrgID: testOrgID, OrgRole: org.RoleEditor}
+			require.Equal(t, 0, searchSharedQueries(t, sc, true).TotalCount)
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "When query is shared, other users should not be able to modify it",
+		func(t *testing.T, sc scenarioContext) {
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.reqContext.Req.Body = mockRequestBody(ShareQueryInQueryHistoryCommand{})
+			sc.service.shareHandler(sc.reqContext)
+
+			sc.reqContext.SignedInUser = otherSignedInUser()
+			sc.reqContext.Req.Body = mockRequestBody(PatchQueryCommentInQueryHistoryCommand{Comment: "changed"})
+			require.Equal(t, 500, sc.service.patchCommentHandler(sc.reqContext).Status())
+			require.Equal(t, 500, sc.service.deleteHandler(sc.reqContext).Status())
+			require.Equal(t, 500, sc.service.starHandler(sc.reqContext).Status())
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "When shared query is deleted, it should not be shared anymore",
+		func(t *testing.T, sc scenarioContext) {
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.reqContext.Req.Body = mockRequestBody(ShareQueryInQueryHistoryCommand{})
+			sc.service.shareHandler(sc.reqContext)
+			require.Equal(t, 200, sc.service.deleteHandler(sc.reqContext).Status())
+
+			count, err := countSharedQueries(sc.sqlStore)
+			require.NoError(t, err)
+			require.Equal(t, int64(0), count)
+		})
+}
+
+func otherSignedInUser() *user.SignedInUser {
+	return &user.SignedInUser{
+		UserID:  2,
+		Login:   "other_user",
+		OrgID:   testOrgID,
+		OrgRole: org.RoleEditor,
+	}
+}
+
+func searchSharedQueries(t *testing.T, sc scenarioContext, onlyShared bool) QueryHistorySearchResult {
+	t.Helper()
+
+	result, err := sc.service.SearchInQueryHistory(context.Background(), sc.reqContext.SignedInUser, SearchInQueryHistoryQuery{OnlyShared: onlyShared})
+	require.NoError(t, err)
+	return result
+}
+
+func createTeamWithMembers(t *testing.T, sqlStore db.DB, userIDs ...int64) int64 {
+	t.Helper()
+
+	tm := team.Team{UID: "team", OrgID: testOrgID, Name: "team", Created: time.Now(), Updated: time.Now()}
+	err := sqlStore.WithDbSession(context.Background(), func(session *db.Session) error {
+		if _, err := session.Insert(&tm); err != nil {
+			return err
+		}
+		for _, userID := range userIDs {
+			if _, err := session.Insert(&team.TeamMember{OrgID: testOrgID, TeamID: tm.ID, UserID: userID, Created: time.Now(), Updated: time.Now()}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	return tm.ID
+}
+
+func countSharedQueries(sqlStore db.DB) (int64, error) {
+	var count int64
+	err := sqlStore.WithDbSession(context.Background(), func(session *db.Session) error {
+		var err error
+		count, err = session.Count(QueryHistoryShare{})
+		return err
+	})
+	return count, err
+}
@@ -0,0 +1,401 @@
+package queryhistory
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	QuotaTargetSrv quota.TargetSrv = "query_history"
+	// QuotaTarget limits the number of queries in query history
+	QuotaTarget quota.Target = "query_history"
+	// QuotaRetentionTarget limits the age in days of queries in query history
+	QuotaRetentionTarget quota.Target = "query_history_retention_days"
+
+	// maxDeletedRows is the maximum number of queries deleted per scope in a single cleanup run
+	maxDeletedRows = 10000
+)
+
+// teamQuotaCondition matches the queries of the members of a team created in the organization of the team
+const teamQuotaCondition = `query_history.created_by IN (SELECT team_member.user_id FROM team_member WHERE team_member.team_id = ?)
+	AND query_history.org_id IN (SELECT team.org_id FROM team WHERE team.id = ?)`
+
+type quotaScope struct {
+	scope     quota.Scope
+	condition string
+	params    []any
+}
+
+func readQuotaConfig(cfg *setting.Cfg) (*quota.Map, error) {
+	limits := &quota.Map{}
+
+	if cfg == nil {
+		return limits, nil
+	}
+
+	for _, l := range []struct {
+		target quota.Target
+		scope  quota.Scope
+		limit  int64
+	}{
+		{QuotaTarget, quota.GlobalScope, cfg.Quota.Global.QueryHistory},
+		{QuotaTarget, quota.OrgScope, cfg.Quota.Org.QueryHistory},
+		{QuotaTarget, quota.UserScope, cfg.Quota.User.QueryHistory},
+		{QuotaTarget, quota.TeamScope, cfg.Quota.Team.QueryHistory},
+		{QuotaRetentionTarget, quota.GlobalScope, cfg.Quota.Global.QueryHistoryRetentionDays},
+		{QuotaRetentionTarget, quota.OrgScope, cfg.Quota.Org.QueryHistoryRetentionDays},
+		{QuotaRetentionTarget, quota.TeamScope, cfg.Quota.Team.QueryHistoryRetentionDays},
+	} {
+		tag, err := quota.NewTag(QuotaTargetSrv, l.target, l.scope)
+		if err != nil {
+			return limits, err
+		}
+		limits.Set(tag, l.limit)
+	}
+
+	return limits, nil
+}
+
+func quotaScopes(scopeParams *quota.ScopeParameters) []quotaScope {
+	scopes := []quotaScope{{scope: quota.GlobalScope}}
+	if scopeParams == nil {
+		return scopes
+	}
+	if scopeParams.OrgID != 0 {
+		scopes = append(scopes, quotaScope{quota.OrgScope, "query_history.org_id = ?", []any{scopeParams.OrgID}})
+	}
+	if scopeParams.UserID != 0 {
+		scopes = append(scopes, quotaScope{quota.UserScope, "query_history.created_by = ?", []any{scopeParams.UserID}})
+	}
+	if scopeParams.TeamID != 0 {
+		scopes = append(scopes, quotaScope{quota.TeamScope, teamQuotaCondition, []any{scopeParams.TeamID, scopeParams.TeamID}})
+	}
+	return scopes
+}
+
+// Usage reports the number of queries in query history and the age in days of the oldest query
+// that is not starred, as it is the oldest query the retention applies to
+func (s QueryHistoryService) Usage(ctx context.Context, scopeParams *quota.ScopeParameters) (*quota.Map, error) {
+	u := &quota.Map{}
+
+	err := s.store.WithDbSession(ctx, func(session *db.Session) error {
+		for _, scope := range quotaScopes(scopeParams) {
+			var count int64
+			if _, err := session.SQL("SELECT COUNT(*) FROM query_history"+whereSQL(scope.condition), scope.params...).Get(&count); err != nil {
+				return err
+			}
+
+			tag, err := quota.NewTag(QuotaTargetSrv, QuotaTarget, scope.scope)
+			if err != nil {
+				return err
+			}
+			u.Set(tag, count)
+
+			if scope.scope == quota.UserScope {
+				continue
+			}
+
+			var oldest int64
+			if _, err := session.SQL(`SELECT COALESCE(MIN(query_history.created_at), 0) FROM query_history
+				LEFT JOIN query_history_star ON query_history_star.query_uid = query_history.uid
+				WHERE query_history_star.query_uid IS NULL`+andSQL(scope.condition), scope.params...).Get(&oldest); err != nil {
+				return err
+			}
+
+			var days int64
+			if oldest > 0 {
+				days = int64(s.now().Sub(time.Unix(oldest, 0)) / (24 * time.Hour))
+			}
+			tag, err = quota.NewTag(QuotaTargetSrv, QuotaRetentionTarget, scope.scope)
+			if err != nil {
+				return err
+			}
+			u.Set(tag, days)
+		}
+		return nil
+	})
+
+	return u, err
+}
+
+// enforceQueryHistoryQuotas deletes the oldest queries that are not starred to keep query history
+// within the global limits and the limits of every organization, user and team.
+// When quotas are disabled, only the default global limits are enforced.
+func (s QueryHistoryService) enforceQueryHistoryQuotas(ctx context.Context) (int, error) {
+	var deleted int64
+
+	globalLimits, err := s.QuotaService.GetLimits(ctx, QuotaTargetSrv, nil)
+	quotasEnabled := !errors.Is(err, quota.ErrDisabled)
+	if !quotasEnabled {
+		globalLimits, err = readQuotaConfig(s.Cfg)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := s.enforceScopeLimits(ctx, globalLimits, quotaScope{scope: quota.GlobalScope})
+	deleted += n
+	if err != nil || !quotasEnabled {
+		return int(deleted), err
+	}
+
+	defaultLimits, err := readQuotaConfig(s.Cfg)
+	if err != nil {
+		return int(deleted), err
+	}
+
+	overrides, err := s.getQuotaOverrides(ctx)
+	if err != nil {
+		return int(deleted), err
+	}
+
+	// scopes within the default limits and without an override have nothing to delete,
+	// so only the scopes over the default limits and the overridden ones are enforced
+	keys, err := s.getScopesOverLimits(ctx, defaultLimits)
+	if err != nil {
+		return int(deleted), err
+	}
+	for key := range overrides {
+		keys[key] = struct{}{}
+	}
+
+	sortedKeys := make([]scopeKey, 0, len(keys))
+	for key := range keys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Slice(sortedKeys, func(i, j int) bool {
+		if sortedKeys[i].scope != sortedKeys[j].scope {
+			return sortedKeys[i].scope < sortedKeys[j].scope
+		}
+		return sortedKeys[i].id < sortedKeys[j].id
+	})
+
+	for _, key := range sortedKeys {
+		limits := &quota.Map{}
+		limits.Merge(defaultLimits)
+		if override, ok := overrides[key]; ok {
+			limits.Merge(override)
+		}
+
+		n, err := s.enforceScopeLimits(ctx, limits, key.quotaScope())
+		deleted += n
+		if err != nil {
+			return int(deleted), err
+		}
+	}
+
+	return int(deleted), nil
+}
+
+// scopeKey identifies the organization, user or team a query history quota applies to
+type scopeKey struct {
+	scope quota.Scope
+	id    int64
+}
+
+func (k scopeKey) quotaScope() quotaScope {
+	params := &quota.ScopeParameters{}
+	switch k.scope {
+	case quota.OrgScope:
+		params.OrgID = k.id
+	case quota.UserScope:
+		params.UserID = k.id
+	case quota.TeamScope:
+		params.TeamID = k.id
+	}
+	// the first scope is always the global one
+	return quotaScopes(params)[1]
+}
+
+// getQuotaOverrides loads the query history limits that are set for a single organization, user or team
+func (s QueryHistoryService) getQuotaOverrides(ctx context.Context) (map[scopeKey]*quota.Map, error) {
+	var quotas []quota.Quota
+	err := s.store.WithDbSession(ctx, func(session *db.Session) error {
+		return session.Table("quota").In("target", string(QuotaTarget), string(QuotaRetentionTarget)).Find(&quotas)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	overrides := make(map[scopeKey]*quota.Map)
+	for _, q := range quotas {
+		var key scopeKey
+		switch {
+		case q.TeamId != 0:
+			key = scopeKey{quota.TeamScope, q.TeamId}
+		case q.UserId != 0:
+			key = scopeKey{quota.UserScope, q.UserId}
+		case q.OrgId != 0:
+			key = scopeKey{quota.OrgScope, q.OrgId}
+		default:
+			continue
+		}
+
+		tag, err := quota.NewTag(QuotaTargetSrv, quota.Target(q.Target), key.scope)
+		if err != nil {
+			return nil, err
+		}
+		if overrides[key] == nil {
+			overrides[key] = &quota.Map{}
+		}
+		overrides[key].Set(tag, q.Limit)
+	}
+
+	return overrides, nil
+}
+
+// scopeGroupSQL selects the queries of every organization, user or team together with the id it is grouped by
+var scopeGroupSQL = map[quota.Scope]struct {
+	id   string
+	from string
+}{
+	quota.OrgScope:  {"query_history.org_id", "query_history"},
+	quota.UserScope: {"query_history.created_by", "query_history"},
+	quota.TeamScope: {"team_member.team_id", `query_history
+		INNER JOIN team_member ON team_member.user_id = query_history.created_by
+		INNER JOIN team ON team.id = team_member.team_id AND team.org_id = query_history.org_id`},
+}
+
+// getScopesOverLimits returns the organizations, users and teams whose queries exceed the given limits
+func (s QueryHistoryService) getScopesOverLimits(ctx context.Context, limits *quota.Map) (map[scopeKey]struct{}, error) {
+	keys := make(map[scopeKey]struct{})
+
+	err := s.store.WithDbSession(ctx, func(session *db.Session) error {
+		for _, scope := range []quota.Scope{quota.OrgScope, quota.UserScope, quota.TeamScope} {
+			group := scopeGroupSQL[scope]
+
+			var overRows, overRetention []int64
+			rowsTag, err := quota.NewTag(QuotaTargetSrv, QuotaTarget, scope)
+			if err != nil {
+				return err
+			}
+			if limit, ok := limits.Get(rowsTag); ok && limit >= 0 {
+				if err := session.SQL("SELECT "+group.id+" FROM "+group.from+
+					" GROUP BY "+group.id+" HAVING COUNT(*) > ?", limit).Find(&overRows); err != nil {
+					return err
+				}
+			}
+
+			retentionTag, err := quota.NewTag(QuotaTargetSrv, QuotaRetentionTarget, scope)
+			if err != nil {
+				return err
+			}
+			if days, ok := limits.Get(retentionTag); ok && days >= 0 {
+				olderThan := s.now().Add(-time.Duration(days) * 24 * time.Hour).Unix()
+				if err := session.SQL("SELECT DISTINCT "+group.id+" FROM "+group.from+`
+					LEFT JOIN query_history_star ON query_history_star.query_uid = query_history.uid
+					WHERE query_history_star.query_uid IS NULL AND query_history.created_at <= ?`, olderThan).Find(&overRetention); err != nil {
+					return err
+				}
+			}
+
+			for _, id := range append(overRows, overRetention...) {
+				keys[scopeKey{scope, id}] = struct{}{}
+			}
+		}
+		return nil
+	})
+
+	return keys, err
+}
+
+func (s QueryHistoryService) enforceScopeLimits(ctx context.Context, limits *quota.Map, scope quotaScope) (int64, error) {
+	var deleted int64
+
+	retentionTag, err := quota.NewTag(QuotaTargetSrv, QuotaRetentionTarget, scope.scope)
+	if err != nil {
+		return 0, err
+	}
+	if days, ok := limits.Get(retentionTag); ok && days >= 0 {
+		olderThan := s.now().Add(-time.Duration(days) * 24 * time.Hour).Unix()
+		params := append(append([]any{}, scope.params...), olderThan)
+		n, err := s.deleteUnstarredQueries(ctx, andSQL(scope.condition)+" AND query_history.created_at <= ?", params, maxDeletedRows)
+		deleted += n
+		if err != nil {
+			return deleted, err
+		}
+	}
+
+	rowsTag, err := quota.NewTag(QuotaTargetSrv, QuotaTarget, scope.scope)
+	if err != nil {
+		return deleted, err
+	}
+	if limit, ok := limits.Get(rowsTag); ok && limit >= 0 {
+		var count int64
+		err := s.store.WithDbSession(ctx, func(session *db.Session) error {
+			_, err := session.SQL("SELECT COUNT(*) FROM query_history"+whereSQL(scope.condition), scope.params...).Get(&count)
+			return err
+		})
+		if err != nil {
+			return deleted, err
+		}
+
+		if excess := count - limit; excess > 0 {
+			n, err := s.deleteUnstarredQueries(ctx, andSQL(scope.condition), scope.params, min(excess, maxDeletedRows))
+			deleted += n
+			if err != nil {
+				return deleted, err
+			}
+		}
+	}
+
+	return deleted, nil
+}
+
+// deleteUnstarredQueries deletes up to limit oldest queries matching the condition that are not starred,
+// together with their details and shares. The condition is appended to the WHERE clause and must start with AND.
+func (s QueryHistoryService) deleteUnstarredQueries(ctx context.Context, condition string, params []any, limit int64) (int64, error) {
+	var rowsCount int64
+
+	err := s.store.WithTransactionalDbSession(ctx, func(session *db.Session) error {
+		uidsSQL := `SELECT uid FROM (
+			SELECT uid FROM query_history
+			LEFT JOIN query_history_star
+			ON query_history_star.query_uid = query_history.uid
+			WHERE query_history_star.query_uid IS NULL` + condition + `
+			ORDER BY query_history.id ASC
+			LIMIT ?
+		) AS q`
+		args := append(append([]any{}, params...), strconv.FormatInt(limit, 10))
+
+		for _, sql := range []string{
+			`DELETE FROM query_history_details WHERE query_history_item_uid IN (` + uidsSQL + `)`,
+			`DELETE FROM query_history_share WHERE query_uid IN (` + uidsSQL + `)`,
+		} {
+			if _, err := session.Exec(append([]any{sql}, args...)...); err != nil {
+				return err
+			}
+		}
+
+		res, err := session.Exec(append([]any{`DELETE FROM query_history WHERE uid IN (` + uidsSQL + `)`}, args...)...)
+		if err != nil {
+			return err
+		}
+
+		rowsCount, err = res.RowsAffected()
+		return err
+	})
+
+	return rowsCount, err
+}
+
+func whereSQL(condition string) string {
+	if condition == "" {
+		return ""
+	}
+	return " WHERE " + condition
+}
+
+func andSQL(condition string) string {
+	if condition == "" {
+		return ""
+	}
+	return " AND " + condition
+}
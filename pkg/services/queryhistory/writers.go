@@ -8,7 +8,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/user"
 )
 
-func writeStarredSQL(query SearchInQueryHistoryQuery, sqlStore db.DB, builder *db.SQLBuilder, isCount bool) {
+func writeStarredSQL(query SearchInQueryHistoryQuery, user *user.SignedInUser, sqlStore db.DB, builder *db.SQLBuilder, isCount bool) {
 	var sql bytes.Buffer
 	if isCount {
 		sql.WriteString(`COUNT(`)
@@ -29,14 +29,29 @@ func writeStarredSQL(query SearchInQueryHistoryQuery, sqlStore db.DB, builder *d
 		sql.WriteString(`LEFT`)
 	}
 
-	sql.WriteString(` JOIN query_history_star ON query_history_star.query_uid = query_history.uid `)
-	builder.Write(sql.String())
+	sql.WriteString(` JOIN query_history_star ON query_history_star.query_uid = query_history.uid AND query_history_star.user_id = ? `)
+	builder.Write(sql.String(), user.UserID)
 }
 
 func writeFiltersSQL(query SearchInQueryHistoryQuery, user *user.SignedInUser, sqlStore db.DB, builder *db.SQLBuilder) {
-	params := []any{user.OrgID, user.UserID, query.From, query.To, "%" + query.SearchString + "%", "%" + query.SearchString + "%"}
+	params := []any{user.OrgID, user.UserID}
 	var sql bytes.Buffer
-	sql.WriteString(" WHERE query_history.org_id = ? AND query_history.created_by = ? AND query_history.created_at >= ? AND query_history.created_at <= ? AND (query_history.queries " + sqlStore.GetDialect().LikeStr() + " ? OR query_history.comment " + sqlStore.GetDialect().LikeStr() + " ?) ")
+	sql.WriteString(" WHERE query_history.org_id = ?")
+	if query.OnlyShared {
+		// Queries shared by other users with the whole organization or with a team of the user
+		params = append(params, user.OrgID, user.UserID)
+		sql.WriteString(` AND query_history.created_by <> ? AND query_history.uid IN (
+			SELECT query_history_share.query_uid FROM query_history_share
+			WHERE query_history_share.org_id = ? AND (query_history_share.team_id = 0 OR query_history_share.team_id IN (
+				SELECT team_member.team_id FROM team_member WHERE team_member.user_id = ?
+			))
+		)`)
+	} else {
+		sql.WriteString(" AND query_history.created_by = ?")
+	}
+
+	params = append(params, query.From, query.To, "%"+query.SearchString+"%", "%"+query.SearchString+"%")
+	sql.WriteString(" AND query_history.created_at >= ? AND query_history.created_at <= ? AND (query_history.queries " + sqlStore.GetDialect().LikeStr() + " ? OR query_history.comment " + sqlStore.GetDialect().LikeStr() + " ?) ")
 
 	if len(query.DatasourceUIDs) > 0 {
 		q := "?" + strings.Repeat(",?", len(query.DatasourceUIDs)-1)
@@ -21,6 +21,7 @@ var ErrInvalidTagFormat = errutil.Internal("quota.invalid-invalid-tag-format")
 type ScopeParameters struct {
 	OrgID  int64
 	UserID int64
+	TeamID int64
 }
 
 type Scope string
@@ -29,11 +30,12 @@ const (
 	GlobalScope Scope = "global"
 	OrgScope    Scope = "org"
 	UserScope   Scope = "user"
+	TeamScope   Scope = "team"
 )
 
 func (s Scope) Validate() error {
 	switch s {
-	case GlobalScope, OrgScope, UserScope:
+	case GlobalScope, OrgScope, UserScope, TeamScope:
 		return nil
 	default:
 		return ErrInvalidScope.Errorf("bad scope: %s", s)
@@ -184,6 +186,7 @@ type Quota struct {
 	Id      int64
 	OrgId   int64
 	UserId  int64
+	TeamId  int64
 	Target  string
 	Limit   int64
 	Created time.Time
@@ -193,6 +196,7 @@ type Quota struct {
 type QuotaDTO struct {
 	OrgId   int64  `json:"org_id,omitempty"`
 	UserId  int64  `json:"user_id,omitempty"`
+	TeamId  int64  `json:"team_id,omitempty"`
 	Target  string `json:"target"`
 	Limit   int64  `json:"limit"`
 	Used    int64  `json:"used"`
@@ -209,6 +213,7 @@ type UpdateQuotaCmd struct {
 	Limit  int64  `json:"limit"`
 	OrgID  int64  `json:"-"`
 	UserID int64  `json:"-"`
+	TeamID int64  `json:"-"`
 }
 
 type NewUsageReporter struct {
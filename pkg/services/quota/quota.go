@@ -7,14 +7,20 @@ import (
 )
 
 type Service interface {
-	// GetQuotasByScope returns the quota for the specific scope (global, organization, user)
+	// GetQuotasByScope returns the quota for the specific scope (global, organization, user, team)
 	// If the scope is organization, the ID is expected to be the organisation ID.
 	// If the scope is user, the id is expected to be the user ID.
+	// If the scope is team, the id is expected to be the team ID.
 	GetQuotasByScope(ctx context.Context, scope Scope, ID int64) ([]QuotaDTO, error)
-	// Update overrides the quota for a specific scope (global, organization, user).
+	// Update overrides the quota for a specific scope (global, organization, user, team).
 	// If the cmd.OrgID is set, then the organization quota are updated.
 	// If the cmd.UseID is set, then the user quota are updated.
+	// If the cmd.TeamID is set, then the team quota are updated and cmd.OrgID is expected to be the organization of the team.
 	Update(ctx context.Context, cmd *UpdateQuotaCmd) error
+	// GetLimits returns the limits of a specific service for the scopes in scopeParams,
+	// taking the custom limits of the organization, user and team into account.
+	// It does not calculate the usage, so it can be used by background services enforcing limits.
+	GetLimits(ctx context.Context, targetSrv TargetSrv, scopeParams *ScopeParameters) (*Map, error)
 	// QuotaReached is called by the quota middleware for applying quota enforcement to API handlers
 	QuotaReached(c *contextmodel.ReqContext, targetSrv TargetSrv) (bool, error)
 	// CheckQuotaReached checks if the quota limitations have been reached for a specific service
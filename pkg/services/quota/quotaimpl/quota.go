@@ -28,6 +28,10 @@ func (s *serviceDisabled) Update(ctx context.Context, cmd *quota.UpdateQuotaCmd)
 	return quota.ErrDisabled
 }
 
+func (s *serviceDisabled) GetLimits(ctx context.Context, targetSrv quota.TargetSrv, scopeParams *quota.ScopeParameters) (*quota.Map, error) {
+	return nil, quota.ErrDisabled
+}
+
 func (s *serviceDisabled) CheckQuotaReached(ctx context.Context, targetSrv quota.TargetSrv, scopeParams *quota.ScopeParameters) (bool, error) {
 	return false, nil
 }
@@ -98,10 +102,13 @@ func (s *service) GetQuotasByScope(ctx context.Context, scope quota.Scope, id in
 	q := make([]quota.QuotaDTO, 0)
 
 	scopeParams := quota.ScopeParameters{}
-	if scope == quota.OrgScope {
+	switch scope {
+	case quota.OrgScope:
 		scopeParams.OrgID = id
-	} else if scope == quota.UserScope {
+	case quota.UserScope:
 		scopeParams.UserID = id
+	case quota.TeamScope:
+		scopeParams.TeamID = id
 	}
 
 	c, err := s.getContext(ctx)
@@ -150,6 +157,7 @@ func (s *service) GetQuotasByScope(ctx context.Context, scope quota.Scope, id in
 			Limit:   limit,
 			OrgId:   scopeParams.OrgID,
 			UserId:  scopeParams.UserID,
+			TeamId:  scopeParams.TeamID,
 			Used:    used,
 			Service: string(srv),
 			Scope:   string(scope),
@@ -220,6 +228,11 @@ func (s *service) CheckQuotaReached(ctx context.Context, targetSrv quota.TargetS
 				continue
 			}
 
+			// do not check team quota if the team information is not available
+			if scope == quota.TeamScope && (scopeParams == nil || scopeParams.TeamID == 0) {
+				continue
+			}
+
 			u, ok := targetUsage.Get(t)
 			if !ok {
 				return false, quota.ErrUsageFoundForTarget.Errorf("no usage for target:%s", t)
@@ -232,6 +245,23 @@ func (s *service) CheckQuotaReached(ctx context.Context, targetSrv quota.TargetS
 	return false, nil
 }
 
+func (s *service) GetLimits(ctx context.Context, targetSrv quota.TargetSrv, scopeParams *quota.ScopeParameters) (*quota.Map, error) {
+	if _, ok := s.getReporter(targetSrv); !ok {
+		return nil, quota.ErrInvalidTargetSrv
+	}
+
+	targetSrvLimits, err := s.getOverridenLimits(ctx, targetSrv, scopeParams)
+	if err != nil {
+		return nil, err
+	}
+
+	limits := &quota.Map{}
+	for t, limit := range targetSrvLimits {
+		limits.Set(t, limit)
+	}
+	return limits, nil
+}
+
 func (s *service) DeleteQuotaForUser(ctx context.Context, userID int64) error {
 	c, err := s.getContext(ctx)
 	if err != nil {
@@ -446,6 +446,81 @@ func TestIntegrationQuotaCommandsAndQueries(t *testing.T) {
 	// TODO data_source, file
 }
 
+func TestIntegrationTeamQuota(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlStore, cfg := db.InitTestReplDBWithCfg(t)
+	cfg.Quota = setting.QuotaSettings{Enabled: true}
+	quotaService := ProvideService(sqlStore, cfg)
+
+	var srv quota.TargetSrv = "test"
+	var target quota.Target = "test"
+	teamTag, err := quota.NewTag(srv, target, quota.TeamScope)
+	require.NoError(t, err)
+	orgTag, err := quota.NewTag(srv, target, quota.OrgScope)
+	require.NoError(t, err)
+
+	defaultLimits := &quota.Map{}
+	defaultLimits.Set(teamTag, 10)
+	defaultLimits.Set(orgTag, 20)
+	err = quotaService.RegisterQuotaReporter(&quota.NewUsageReporter{
+		TargetSrv:     srv,
+		DefaultLimits: defaultLimits,
+		Reporter: func(ctx context.Context, scopeParams *quota.ScopeParameters) (*quota.Map, error) {
+			u := &quota.Map{}
+			if scopeParams != nil && scopeParams.TeamID != 0 {
+				u.Set(teamTag, 5)
+			}
+			return u, nil
+		},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, quotaService.Update(context.Background(), &quota.UpdateQuotaCmd{Target: string(target), Limit: 5, OrgID: 1, TeamID: 2}))
+	// updating again should not create a second quota for the team
+	require.NoError(t, quotaService.Update(context.Background(), &quota.UpdateQuotaCmd{Target: string(target), Limit: 3, OrgID: 1, TeamID: 2}))
+
+	t.Run("Should be able to get saved limit/usage for team", func(t *testing.T) {
+		result, err := quotaService.GetQuotasByScope(context.Background(), quota.TeamScope, 2)
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		require.Equal(t, int64(2), result[0].TeamId)
+		require.Equal(t, int64(3), result[0].Limit)
+		require.Equal(t, int64(5), result[0].Used)
+	})
+
+	t.Run("Should not use team quota as org quota", func(t *testing.T) {
+		result, err := quotaService.GetQuotasByScope(context.Background(), quota.OrgScope, 1)
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		require.Equal(t, int64(20), result[0].Limit)
+	})
+
+	t.Run("Should be able to get limits without usage", func(t *testing.T) {
+		limits, err := quotaService.GetLimits(context.Background(), srv, &quota.ScopeParameters{OrgID: 1, TeamID: 2})
+		require.NoError(t, err)
+
+		limit, ok := limits.Get(teamTag)
+		require.True(t, ok)
+		require.Equal(t, int64(3), limit)
+
+		limit, ok = limits.Get(orgTag)
+		require.True(t, ok)
+		require.Equal(t, int64(20), limit)
+	})
+
+	t.Run("Should check team quota only when team is known", func(t *testing.T) {
+		reached, err := quotaService.CheckQuotaReached(context.Background(), srv, &quota.ScopeParameters{TeamID: 2})
+		require.NoError(t, err)
+		require.True(t, reached)
+
+		reached, err = quotaService.CheckQuotaReached(context.Background(), srv, &quota.ScopeParameters{})
+		require.NoError(t, err)
+		require.False(t, reached)
+	})
+}
+
 func getQuotaBySrvTargetScope(t *testing.T, quotaService quota.Service, srv quota.TargetSrv, target quota.Target, scope quota.Scope, scopeParams *quota.ScopeParameters) (quota.QuotaDTO, error) {
 	t.Helper()
 
@@ -24,6 +24,10 @@ func (f *FakeQuotaService) Update(ctx context.Context, cmd *quota.UpdateQuotaCmd
 	return nil
 }
 
+func (f *FakeQuotaService) GetLimits(ctx context.Context, targetSrv quota.TargetSrv, scopeParams *quota.ScopeParameters) (*quota.Map, error) {
+	return &quota.Map{}, f.err
+}
+
 func (f *FakeQuotaService) QuotaReached(c *contextmodel.ReqContext, target quota.TargetSrv) (bool, error) {
 	return f.reached, f.err
 }
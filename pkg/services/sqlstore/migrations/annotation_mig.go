@@ -183,6 +183,10 @@ func addAnnotationMig(mg *Migrator) {
 	mg.AddMigration("Increase tags column to length 4096", NewRawSQLMigration("").
 		Postgres("ALTER TABLE annotation ALTER COLUMN tags TYPE VARCHAR(4096);").
 		Mysql("ALTER TABLE annotation MODIFY tags VARCHAR(4096);"))
+
+	mg.AddMigration("Add full text index for text on annotation table", NewRawSQLMigration("").
+		Postgres("CREATE INDEX IF NOT EXISTS IDX_annotation_text_fulltext ON annotation USING gin (to_tsvector('simple', text));").
+		Mysql("ALTER TABLE annotation ADD FULLTEXT INDEX IDX_annotation_text_fulltext (text);"))
 }
 
 type AddMakeRegionSingleRowMigration struct {
@@ -126,6 +126,18 @@ func (oss *OSSMigrations) AddMigration(mg *Migrator) {
 
 	ualert.AddStateResolvedAtColumns(mg)
 
+	ualert.AddRecordingRuleSeriesTable(mg)
+
+	ualert.AddRuleDependencyColumns(mg)
+
+	addLivePipelineMigrations(mg)
+
+	addQueryHistoryShareMigrations(mg)
+
+	addQuotaTeamMigration(mg)
+
+	addLibraryElementVersionMigrations(mg)
+
 	enableTraceQLStreaming(mg, oss.features != nil && oss.features.IsEnabledGlobally(featuremgmt.FlagTraceQLStreaming))
 }
 
@@ -29,3 +29,22 @@ func addQuotaMigration(mg *Migrator) {
 		{Name: "target", Type: DB_NVarchar, Length: 190, Nullable: false},
 	}))
 }
+
+func addQuotaTeamMigration(mg *Migrator) {
+	var quotaV1 = Table{
+		Name: "quota",
+		Indices: []*Index{
+			{Cols: []string{"org_id", "user_id", "target"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("add team_id column to quota", NewAddColumnMigration(quotaV1, &Column{
+		Name: "team_id", Type: DB_BigInt, Nullable: false, Default: "0",
+	}))
+
+	mg.AddMigration("drop index UQE_quota_org_id_user_id_target - v1", NewDropIndexMigration(quotaV1, quotaV1.Indices[0]))
+
+	mg.AddMigration("add unique index quota.org_id_user_id_team_id_target", NewAddIndexMigration(quotaV1, &Index{
+		Cols: []string{"org_id", "user_id", "team_id", "target"}, Type: UniqueIndex,
+	}))
+}
@@ -0,0 +1,27 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddRecordingRuleSeriesTable adds the table that the SQL writer of recording rules writes to.
+func AddRecordingRuleSeriesTable(mg *migrator.Migrator) {
+	recordingRuleSeries := migrator.Table{
+		Name: "recording_rule_series",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "metric", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "labels", Type: migrator.DB_Text, Nullable: false}, // Text, as this contains a JSON-ified map.
+			{Name: "labels_hash", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "timestamp_ms", Type: migrator.DB_BigInt, Nullable: false}, // BigInt, to match existing time fields.
+			{Name: "value", Type: migrator.DB_Double, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "metric", "labels_hash", "timestamp_ms"}, Type: migrator.UniqueIndex},
+			{Cols: []string{"org_id", "timestamp_ms"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("create recording_rule_series table", migrator.NewAddTableMigration(recordingRuleSeries))
+	mg.AddMigration("add unique index recording_rule_series org_id, metric, labels_hash, timestamp_ms", migrator.NewAddIndexMigration(recordingRuleSeries, recordingRuleSeries.Indices[0]))
+	mg.AddMigration("add index recording_rule_series org_id, timestamp_ms", migrator.NewAddIndexMigration(recordingRuleSeries, recordingRuleSeries.Indices[1]))
+}
@@ -0,0 +1,32 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddRuleDependencyColumns adds the columns that chain the evaluation of the rules of a rule group.
+func AddRuleDependencyColumns(mg *migrator.Migrator) {
+	mg.AddMigration("add sequential_evaluation column to alert_rule table", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule"}, &migrator.Column{
+		Name:     "sequential_evaluation",
+		Type:     migrator.DB_Bool,
+		Nullable: true,
+	}))
+
+	mg.AddMigration("add depends_on column to alert_rule table", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule"}, &migrator.Column{
+		Name:     "depends_on",
+		Type:     migrator.DB_NVarchar,
+		Length:   UIDMaxLength,
+		Nullable: true,
+	}))
+
+	mg.AddMigration("add sequential_evaluation column to alert_rule_version table", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule_version"}, &migrator.Column{
+		Name:     "sequential_evaluation",
+		Type:     migrator.DB_Bool,
+		Nullable: true,
+	}))
+
+	mg.AddMigration("add depends_on column to alert_rule_version table", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule_version"}, &migrator.Column{
+		Name:     "depends_on",
+		Type:     migrator.DB_NVarchar,
+		Length:   UIDMaxLength,
+		Nullable: true,
+	}))
+}
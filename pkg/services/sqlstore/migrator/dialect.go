@@ -65,6 +65,9 @@ type Dialect interface {
 
 	PreInsertId(table string, sess *xorm.Session) error
 	PostInsertId(table string, sess *xorm.Session) error
+	// ResetSequenceSQL returns the sql statement that moves the auto-increment counter of a column
+	// past the largest value in the table, or an empty string if the dialect does not need it.
+	ResetSequenceSQL(tableName, columnName string) string
 
 	CleanDB(engine *xorm.Engine) error
 	TruncateDBTables(engine *xorm.Engine) error
@@ -337,6 +340,10 @@ func (b *BaseDialect) PostInsertId(table string, sess *xorm.Session) error {
 	return nil
 }
 
+func (b *BaseDialect) ResetSequenceSQL(tableName, columnName string) string {
+	return ""
+}
+
 func (b *BaseDialect) CleanDB(engine *xorm.Engine) error {
 	return nil
 }
@@ -115,3 +115,15 @@ func TestUpdateQuery(t *testing.T) {
 		})
 	}
 }
+
+func TestResetSequenceSQL(t *testing.T) {
+	require.Equal(t,
+		"SELECT setval(pg_get_serial_sequence('some_table', 'id'), COALESCE(MAX(\"id\"), 0) + 1, false) FROM \"some_table\";",
+		NewPostgresDialect().ResetSequenceSQL("some_table", "id"))
+	require.Equal(t,
+		"ALTER TABLE `some_table` AUTO_INCREMENT = 1;",
+		NewMysqlDialect().ResetSequenceSQL("some_table", "id"))
+	require.Equal(t,
+		"UPDATE sqlite_sequence SET seq = (SELECT COALESCE(MAX(`id`), 0) FROM `some_table`) WHERE name = 'some_table';",
+		NewSQLite3Dialect().ResetSequenceSQL("some_table", "id"))
+}
@@ -192,6 +192,13 @@ func (db *MySQLDialect) TruncateDBTables(engine *xorm.Engine) error {
 	return nil
 }
 
+// ResetSequenceSQL returns the sql statement that resets the auto-increment counter of a table.
+// MySQL does not allow the counter to be lower than the largest value in the table, so resetting
+// it to 1 moves it to the value following the largest one.
+func (db *MySQLDialect) ResetSequenceSQL(tableName, columnName string) string {
+	return fmt.Sprintf("ALTER TABLE %s AUTO_INCREMENT = 1;", db.Quote(tableName))
+}
+
 func (db *MySQLDialect) isThisError(err error, errcode uint16) bool {
 	var driverErr *mysql.MySQLError
 	if errors.As(err, &driverErr) {
@@ -213,6 +213,13 @@ func (db *PostgresDialect) PostInsertId(table string, sess *xorm.Session) error
 	return nil
 }
 
+// ResetSequenceSQL returns the sql statement that sets the sequence of a serial column
+// to the value following the largest value in the table.
+func (db *PostgresDialect) ResetSequenceSQL(tableName, columnName string) string {
+	return fmt.Sprintf("SELECT setval(pg_get_serial_sequence('%s', '%s'), COALESCE(MAX(%s), 0) + 1, false) FROM %s;",
+		tableName, columnName, db.Quote(columnName), db.Quote(tableName))
+}
+
 // UpsertSQL returns the upsert sql statement for PostgreSQL dialect
 func (db *PostgresDialect) UpsertSQL(tableName string, keyCols, updateCols []string) string {
 	str, _ := db.UpsertMultipleSQL(tableName, keyCols, updateCols, 1)
@@ -92,6 +92,13 @@ func (db *SQLite3) CleanDB(engine *xorm.Engine) error {
 	return nil
 }
 
+// ResetSequenceSQL returns the sql statement that sets the sequence of an AUTOINCREMENT column
+// to the largest value in the table.
+func (db *SQLite3) ResetSequenceSQL(tableName, columnName string) string {
+	return fmt.Sprintf("UPDATE sqlite_sequence SET seq = (SELECT COALESCE(MAX(%s), 0) FROM %s) WHERE name = '%s';",
+		db.Quote(columnName), db.Quote(tableName), tableName)
+}
+
 // TruncateDBTables deletes all data from all the tables and resets the sequences.
 // A special case is the dashboard_acl table where we keep the default permissions.
 func (db *SQLite3) TruncateDBTables(engine *xorm.Engine) error {
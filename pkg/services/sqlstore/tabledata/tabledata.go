@@ -0,0 +1,235 @@
+// Package tabledata reads and writes the rows of database tables independently of the database
+// type, for copying, backing up and restoring Grafana databases.
+package tabledata
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+
+	"xorm.io/core"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+// Table is a database table whose rows are read or written.
+type Table struct {
+	Name string
+	// Columns are the columns that are read or written.
+	Columns []string
+	// Kinds are the kinds of the columns, values are converted to them.
+	Kinds []ColumnKind
+	// Sequences are the auto-increment columns of the table.
+	Sequences []string
+	// References are the tables referenced by <table>_id columns, see SortTables.
+	References []string
+}
+
+// Checksum is the number of rows of a table and the sum of the hashes of the rows, which does
+// not depend on the order in which the database returns the rows.
+type Checksum struct {
+	Rows int64
+	Sum  uint64
+}
+
+// ReadTables returns the tables of the database with all their columns.
+func ReadTables(store db.DB) ([]*Table, error) {
+	metas, err := store.GetEngine().DBMetas()
+	if err != nil {
+		return nil, err
+	}
+
+	tables := make([]*Table, 0, len(metas))
+	for _, meta := range metas {
+		t := &Table{Name: meta.Name}
+		for _, column := range meta.Columns() {
+			t.Columns = append(t.Columns, column.Name)
+			t.Kinds = append(t.Kinds, ColumnKindOf(column.SQLType.Name))
+			if column.IsAutoIncrement {
+				t.Sequences = append(t.Sequences, column.Name)
+			}
+		}
+		tables = append(tables, t)
+	}
+	return tables, nil
+}
+
+// SortTables sorts the tables so that tables come after the tables they reference. Grafana does
+// not use foreign keys, so a column named <table>_id, for example dashboard_id or datasource_id,
+// is considered a reference to the table; underscores are ignored when matching the names.
+// Tables are otherwise sorted by name, and reference cycles are broken in name order.
+func SortTables(tables []*Table) []*Table {
+	byName := make(map[string]*Table, len(tables))
+	for _, t := range tables {
+		byName[strings.ReplaceAll(t.Name, "_", "")] = t
+	}
+	for _, t := range tables {
+		t.References = nil
+		for _, column := range t.Columns {
+			name, ok := strings.CutSuffix(column, "_id")
+			if !ok {
+				continue
+			}
+			if ref, ok := byName[strings.ReplaceAll(name, "_", "")]; ok && ref != t {
+				t.References = append(t.References, ref.Name)
+			}
+		}
+	}
+
+	remaining := make([]*Table, len(tables))
+	copy(remaining, tables)
+	sort.Slice(remaining, func(i, j int) bool { return remaining[i].Name < remaining[j].Name })
+
+	sorted := make([]*Table, 0, len(tables))
+	done := make(map[string]bool, len(tables))
+	for len(remaining) > 0 {
+		next := 0
+		for i, t := range remaining {
+			ready := true
+			for _, ref := range t.References {
+				if !done[ref] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				next = i
+				break
+			}
+		}
+		sorted = append(sorted, remaining[next])
+		done[remaining[next].Name] = true
+		remaining = append(remaining[:next], remaining[next+1:]...)
+	}
+	return sorted
+}
+
+// Scan calls fn with the values of the columns of every row of the table. The values are the
+// ones returned by the driver, the slice is reused between rows.
+func Scan(ctx context.Context, store db.DB, t *Table, fn func(values []any) error) error {
+	return scan(ctx, store.GetEngine().DB(), store.GetDialect(), t, fn)
+}
+
+// Snapshot is a read-only transaction in which several tables are scanned, so that their rows
+// are read as of the same point in time while the database is being written to.
+type Snapshot struct {
+	tx      *core.Tx
+	dialect migrator.Dialect
+}
+
+// BeginSnapshot starts a snapshot of the database, it must be closed once the tables are read.
+// PostgreSQL and MySQL use a repeatable read transaction, which reads all the tables from the
+// snapshot taken by its first query. SQLite transactions are serializable.
+func BeginSnapshot(ctx context.Context, store db.DB) (*Snapshot, error) {
+	opts := &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+	if store.GetDBType() == migrator.SQLite {
+		// the SQLite driver does not support isolation levels
+		opts = nil
+	}
+	tx, err := store.GetEngine().DB().BeginTx(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start a snapshot of the database: %w", err)
+	}
+	return &Snapshot{tx: tx, dialect: store.GetDialect()}, nil
+}
+
+// Scan calls fn with the values of the columns of every row of the table, see Scan.
+func (s *Snapshot) Scan(ctx context.Context, t *Table, fn func(values []any) error) error {
+	return scan(ctx, s.tx, s.dialect, t, fn)
+}
+
+// Close ends the snapshot.
+func (s *Snapshot) Close() error {
+	return s.tx.Rollback()
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*core.Rows, error)
+}
+
+func scan(ctx context.Context, q queryer, dialect migrator.Dialect, t *Table, fn func(values []any) error) error {
+	columns := make([]string, len(t.Columns))
+	for i, column := range t.Columns {
+		columns[i] = dialect.Quote(column)
+	}
+
+	rows, err := q.QueryContext(ctx, fmt.Sprintf("SELECT %s FROM %s", strings.Join(columns, ", "), dialect.Quote(t.Name)))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+
+	values := make([]any, len(columns))
+	dest := make([]any, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+		if err := fn(values); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Truncate deletes all the rows of the table.
+func Truncate(sess *db.Session, dialect migrator.Dialect, t *Table) error {
+	_, err := sess.Exec(fmt.Sprintf("DELETE FROM %s", dialect.Quote(t.Name)))
+	return err
+}
+
+// Insert inserts a row in the table, the values are converted to the kinds of the columns.
+func Insert(sess *db.Session, dialect migrator.Dialect, t *Table, values []any) error {
+	row := make(map[string]any, len(values))
+	for i, value := range values {
+		converted, err := ConvertValue(value, t.Kinds[i])
+		if err != nil {
+			return fmt.Errorf("column %s: %w", t.Columns[i], err)
+		}
+		row[t.Columns[i]] = InsertValue(dialect, converted)
+	}
+
+	query, args, err := dialect.InsertQuery(t.Name, row)
+	if err != nil {
+		return err
+	}
+	_, err = sess.Exec(append([]any{query}, args...)...)
+	return err
+}
+
+// ResetSequences moves the auto-increment counters of the table past the rows that have been
+// inserted with explicit values.
+func ResetSequences(sess *db.Session, dialect migrator.Dialect, t *Table) error {
+	for _, column := range t.Sequences {
+		if query := dialect.ResetSequenceSQL(t.Name, column); query != "" {
+			if _, err := sess.Exec(query); err != nil {
+				return fmt.Errorf("failed to reset the sequence of column %s: %w", column, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ChecksumTable returns the checksum of the rows of the table, with the values normalized
+// according to the kinds of the columns so that it can be compared between databases.
+func ChecksumTable(ctx context.Context, store db.DB, t *Table) (Checksum, error) {
+	var checksum Checksum
+	err := Scan(ctx, store, t, func(values []any) error {
+		h := fnv.New64a()
+		for i, value := range values {
+			_, _ = h.Write([]byte(NormalizeValue(value, t.Kinds[i])))
+			_, _ = h.Write([]byte{0x1f})
+		}
+		checksum.Rows++
+		checksum.Sum += h.Sum64()
+		return nil
+	})
+	return checksum, err
+}
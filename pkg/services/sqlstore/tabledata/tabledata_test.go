@@ -0,0 +1,74 @@
+package tabledata
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortTables(t *testing.T) {
+	table := func(name string, columns ...string) *Table {
+		return &Table{Name: name, Columns: append([]string{"id"}, columns...)}
+	}
+	tables := SortTables([]*Table{
+		table("dashboard_version", "dashboard_id"),
+		table("alert", "dashboard_id", "datasource_id", "org_id"),
+		table("dashboard", "org_id", "folder_id"),
+		table("data_source", "org_id"),
+		table("org"),
+		table("folder", "org_id", "parent_id"),
+		table("star", "user_id", "dashboard_id"),
+		table("user", "org_id"),
+	})
+
+	names := make([]string, len(tables))
+	for i, t := range tables {
+		names[i] = t.Name
+	}
+	assert.Equal(t, []string{"org", "data_source", "folder", "dashboard", "alert", "dashboard_version", "user", "star"}, names)
+}
+
+func TestConvertValue(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 30, 45, 0, time.UTC)
+	tests := []struct {
+		name     string
+		sqlType  string
+		value    any
+		expected any
+	}{
+		{name: "nil", sqlType: "BIGINT", value: nil, expected: nil},
+		{name: "SQLite integer to boolean", sqlType: "BOOL", value: int64(1), expected: true},
+		{name: "MySQL bytes to boolean", sqlType: "BOOL", value: []byte("0"), expected: false},
+		{name: "boolean to integer", sqlType: "TINYINT", value: true, expected: int64(1)},
+		{name: "MySQL bytes to integer", sqlType: "BIGINT", value: []byte("42"), expected: int64(42)},
+		{name: "MySQL bytes to float", sqlType: "DOUBLE", value: []byte("1.5"), expected: 1.5},
+		{name: "bytes to text", sqlType: "VARCHAR(190)", value: []byte("text"), expected: "text"},
+		{name: "text to blob", sqlType: "BYTEA", value: "blob", expected: []byte("blob")},
+		{name: "SQLite text to time", sqlType: "DATETIME", value: "2024-03-01 12:30:45", expected: now},
+		{name: "SQLite text with zone to time", sqlType: "TIMESTAMP", value: "2024-03-01 14:30:45+02:00", expected: now},
+		{name: "time to UTC", sqlType: "DATETIME", value: now.In(time.FixedZone("", 3600)), expected: now},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			value, err := ConvertValue(tc.value, ColumnKindOf(tc.sqlType))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, value)
+		})
+	}
+
+	t.Run("invalid value", func(t *testing.T) {
+		_, err := ConvertValue([]byte("abc"), KindInt)
+		require.Error(t, err)
+	})
+
+	t.Run("normalized values do not depend on the database", func(t *testing.T) {
+		assert.Equal(t, NormalizeValue(true, KindBool), NormalizeValue(int64(1), KindBool))
+		assert.Equal(t, NormalizeValue(now, KindTime), NormalizeValue("2024-03-01 12:30:45.123+00:00", KindTime))
+		assert.Equal(t, NormalizeValue(0.1, KindFloat), NormalizeValue(float64(float32(0.1)), KindFloat))
+		assert.Equal(t, NormalizeValue(int64(42), KindInt), NormalizeValue(json.Number("42"), KindInt))
+	})
+}
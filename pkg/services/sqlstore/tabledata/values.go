@@ -0,0 +1,234 @@
+package tabledata
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+// ColumnKind is the kind of values stored in a column, independently of the database.
+type ColumnKind int
+
+const (
+	KindOther ColumnKind = iota
+	KindBool
+	KindInt
+	KindFloat
+	KindText
+	KindBlob
+	KindTime
+)
+
+// sqliteTimeFormat is the format xorm stores times in SQLite databases with.
+const sqliteTimeFormat = "2006-01-02 15:04:05"
+
+var timeFormats = []string{
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02T15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02T15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+// ColumnKindOf returns the kind of a column from its SQL type as reported by xorm, for example
+// VARCHAR(190) in SQLite, BOOL in PostgreSQL or TINYINT in MySQL.
+func ColumnKindOf(sqlType string) ColumnKind {
+	name, _, _ := strings.Cut(strings.ToUpper(strings.TrimSpace(sqlType)), "(")
+	switch strings.TrimSpace(name) {
+	case "BOOL", "BOOLEAN":
+		return KindBool
+	case "BIT", "TINYINT", "SMALLINT", "MEDIUMINT", "INT", "INTEGER", "BIGINT", "SERIAL", "BIGSERIAL":
+		return KindInt
+	case "REAL", "FLOAT", "DOUBLE", "DOUBLE PRECISION", "DECIMAL", "NUMERIC":
+		return KindFloat
+	case "CHAR", "VARCHAR", "NCHAR", "NVARCHAR", "TINYTEXT", "TEXT", "MEDIUMTEXT", "LONGTEXT", "UUID", "JSON", "JSONB":
+		return KindText
+	case "BINARY", "VARBINARY", "TINYBLOB", "BLOB", "MEDIUMBLOB", "LONGBLOB", "BYTEA":
+		return KindBlob
+	case "DATE", "DATETIME", "TIMESTAMP", "TIMESTAMPZ":
+		return KindTime
+	default:
+		return KindOther
+	}
+}
+
+// ConvertValue converts a value read from any of the supported databases to the Go type that
+// matches the kind of a column. The drivers return different types for the same data: MySQL
+// returns most values as []byte, SQLite stores booleans as integers and times as text. Numbers
+// decoded from JSON with json.Decoder.UseNumber are converted too.
+func ConvertValue(value any, kind ColumnKind) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	if n, ok := value.(json.Number); ok {
+		value = jsonNumber(n)
+	}
+
+	switch kind {
+	case KindBool:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case int64:
+			return v != 0, nil
+		case []byte:
+			return parseBool(string(v))
+		case string:
+			return parseBool(v)
+		}
+	case KindInt:
+		switch v := value.(type) {
+		case int64:
+			return v, nil
+		case bool:
+			if v {
+				return int64(1), nil
+			}
+			return int64(0), nil
+		case float64:
+			return int64(v), nil
+		case []byte:
+			return parseInt(string(v))
+		case string:
+			return parseInt(v)
+		}
+	case KindFloat:
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case float32:
+			return float64(v), nil
+		case int64:
+			return float64(v), nil
+		case []byte:
+			return strconv.ParseFloat(strings.TrimSpace(string(v)), 64)
+		case string:
+			return strconv.ParseFloat(strings.TrimSpace(v), 64)
+		}
+	case KindText:
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case []byte:
+			return string(v), nil
+		case time.Time:
+			return v.UTC().Format(sqliteTimeFormat), nil
+		default:
+			return fmt.Sprint(v), nil
+		}
+	case KindBlob:
+		switch v := value.(type) {
+		case []byte:
+			return v, nil
+		case string:
+			return []byte(v), nil
+		}
+	case KindTime:
+		switch v := value.(type) {
+		case time.Time:
+			return v.UTC(), nil
+		case int64:
+			return time.Unix(v, 0).UTC(), nil
+		case []byte:
+			return parseTime(string(v))
+		case string:
+			return parseTime(v)
+		}
+	default:
+		if v, ok := value.([]byte); ok {
+			return string(v), nil
+		}
+		return value, nil
+	}
+
+	return nil, fmt.Errorf("cannot convert value of type %T", value)
+}
+
+// InsertValue returns the value to insert in a database of the dialect. Times are stored as
+// text in SQLite, in the format used by xorm so that Grafana reads them back as it wrote them.
+func InsertValue(dialect migrator.Dialect, value any) any {
+	if t, ok := value.(time.Time); ok && strings.HasPrefix(dialect.DriverName(), migrator.SQLite) {
+		return t.Format(sqliteTimeFormat)
+	}
+	return value
+}
+
+// NormalizeValue returns the representation of a value that is compared between databases.
+// Times are compared to the second because MySQL does not store fractions of seconds, and
+// floating point numbers with single precision because PostgreSQL stores REAL columns so.
+func NormalizeValue(value any, kind ColumnKind) string {
+	converted, err := ConvertValue(value, kind)
+	if err != nil {
+		converted = value
+	}
+
+	switch v := converted.(type) {
+	case nil:
+		return "\x00"
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 32)
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.UTC().Truncate(time.Second).Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func jsonNumber(n json.Number) any {
+	if i, err := n.Int64(); err == nil {
+		return i
+	}
+	if f, err := n.Float64(); err == nil {
+		return f
+	}
+	return n.String()
+}
+
+func parseBool(s string) (bool, error) {
+	s = strings.TrimSpace(s)
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n != 0, nil
+	}
+	return strconv.ParseBool(s)
+}
+
+func parseInt(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		if b, boolErr := strconv.ParseBool(s); boolErr == nil {
+			if b {
+				return 1, nil
+			}
+			return 0, nil
+		}
+	}
+	return n, err
+}
+
+func parseTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, format := range timeFormats {
+		if t, err := time.Parse(format, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot parse time %q", s)
+}
@@ -218,9 +218,10 @@ type Cfg struct {
 	MetricsGrafanaEnvironmentInfo    map[string]string
 
 	// Dashboards
-	DashboardVersionsToKeep  int
-	MinRefreshInterval       string
-	DefaultHomeDashboardPath string
+	DashboardVersionsToKeep    int
+	MinRefreshInterval         string
+	DefaultHomeDashboardPath   string
+	LibraryPanelVersionsToKeep int
 
 	// Auth
 	LoginCookieName               string
@@ -287,6 +288,9 @@ type Cfg struct {
 	// DistributedCache
 	RemoteCacheOptions *RemoteCacheOptions
 
+	// Query and resource caching
+	QueryCaching QueryCachingSettings
+
 	ViewersCanEdit  bool
 	EditorsCanAdmin bool
 
@@ -320,6 +324,7 @@ type Cfg struct {
 	AlertingAnnotationCleanupSetting   AnnotationCleanupSettings
 	DashboardAnnotationCleanupSettings AnnotationCleanupSettings
 	APIAnnotationCleanupSettings       AnnotationCleanupSettings
+	AnnotationRetentionRules           []AnnotationRetentionRule
 
 	// GrafanaJavascriptAgent config
 	GrafanaJavascriptAgent GrafanaJavascriptAgent
@@ -732,6 +737,12 @@ func (cfg *Cfg) readAnnotationSettings() error {
 	cfg.DashboardAnnotationCleanupSettings = newAnnotationCleanupSettings(dashboardAnnotation, "max_age")
 	cfg.APIAnnotationCleanupSettings = newAnnotationCleanupSettings(apiIAnnotation, "max_age")
 
+	rules, err := readAnnotationRetentionRules(cfg.Raw.Sections())
+	if err != nil {
+		return err
+	}
+	cfg.AnnotationRetentionRules = rules
+
 	return nil
 }
 
@@ -1098,6 +1109,7 @@ func (cfg *Cfg) parseINIFile(iniFile *ini.File) error {
 	cfg.DashboardVersionsToKeep = dashboards.Key("versions_to_keep").MustInt(20)
 	cfg.MinRefreshInterval = valueAsString(dashboards, "min_refresh_interval", "5s")
 	cfg.DefaultHomeDashboardPath = dashboards.Key("default_home_dashboard_path").MustString("")
+	cfg.LibraryPanelVersionsToKeep = dashboards.Key("library_panel_versions_to_keep").MustInt(20)
 
 	if err := readUserSettings(iniFile, cfg); err != nil {
 		return err
@@ -1287,6 +1299,8 @@ func (cfg *Cfg) parseINIFile(iniFile *ini.File) error {
 		Encryption: encryption,
 	}
 
+	cfg.readQueryCachingSettings()
+
 	geomapSection := iniFile.Section("geomap")
 	basemapJSON := valueAsString(geomapSection, "default_baselayer_config", "")
 	if basemapJSON != "" {
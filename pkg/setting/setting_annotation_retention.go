@@ -0,0 +1,78 @@
+package setting
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"gopkg.in/ini.v1"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const annotationRetentionSectionPrefix = "annotations.retention."
+
+// AnnotationRetentionRule applies its cleanup settings to the annotations
+// matching the org, the dashboard and all the tags of the rule. Matchers that
+// are not set match all annotations.
+type AnnotationRetentionRule struct {
+	Name         string
+	OrgID        int64
+	DashboardUID string
+	Tags         []string
+	AnnotationCleanupSettings
+}
+
+func (r AnnotationRetentionRule) Validate() error {
+	if r.Name == "" {
+		return errors.New("name is required")
+	}
+	if r.OrgID == 0 && r.DashboardUID == "" && len(r.Tags) == 0 {
+		return fmt.Errorf("retention rule %q must match on org, dashboard or tags", r.Name)
+	}
+	if r.OrgID < 0 {
+		return fmt.Errorf("retention rule %q has invalid org id %d", r.Name, r.OrgID)
+	}
+	if r.MaxAge <= 0 && r.MaxCount <= 0 {
+		return fmt.Errorf("retention rule %q must set max age or max annotations to keep", r.Name)
+	}
+	if r.MaxCount < 0 {
+		return fmt.Errorf("retention rule %q has invalid max annotations to keep %d", r.Name, r.MaxCount)
+	}
+	return nil
+}
+
+// readAnnotationRetentionRules reads the retention rules from the
+// [annotations.retention.<name>] sections.
+func readAnnotationRetentionRules(sections []*ini.Section) ([]AnnotationRetentionRule, error) {
+	rules := make([]AnnotationRetentionRule, 0)
+	for _, section := range sections {
+		if !strings.HasPrefix(section.Name(), annotationRetentionSectionPrefix) {
+			continue
+		}
+
+		name := strings.TrimPrefix(section.Name(), annotationRetentionSectionPrefix)
+		maxAge, err := gtime.ParseDuration(section.Key("max_age").MustString(""))
+		if err != nil && section.Key("max_age").String() != "" {
+			return nil, fmt.Errorf("[%s] invalid max_age: %w", section.Name(), err)
+		}
+
+		rule := AnnotationRetentionRule{
+			Name:         name,
+			OrgID:        section.Key("org_id").MustInt64(0),
+			DashboardUID: section.Key("dashboard_uid").MustString(""),
+			Tags:         util.SplitString(section.Key("tags").MustString("")),
+			AnnotationCleanupSettings: AnnotationCleanupSettings{
+				MaxAge:   maxAge,
+				MaxCount: section.Key("max_annotations_to_keep").MustInt64(0),
+			},
+		}
+		if err := rule.Validate(); err != nil {
+			return nil, fmt.Errorf("[%s] %w", section.Name(), err)
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,76 @@
+package setting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+)
+
+func TestReadAnnotationRetentionRules(t *testing.T) {
+	t.Run("reads rules from retention sections", func(t *testing.T) {
+		f, err := ini.Load([]byte(`
+[annotations.retention.deployments]
+tags = deploy, env:prod
+max_age = 30d
+
+[annotations.retention.noisy]
+org_id = 2
+dashboard_uid = abc
+max_annotations_to_keep = 100
+
+[annotations.api]
+max_age = 1d
+`))
+		require.NoError(t, err)
+
+		rules, err := readAnnotationRetentionRules(f.Sections())
+		require.NoError(t, err)
+		require.Equal(t, []AnnotationRetentionRule{
+			{
+				Name:                      "deployments",
+				Tags:                      []string{"deploy", "env:prod"},
+				AnnotationCleanupSettings: AnnotationCleanupSettings{MaxAge: 30 * 24 * time.Hour},
+			},
+			{
+				Name:                      "noisy",
+				OrgID:                     2,
+				DashboardUID:              "abc",
+				Tags:                      []string{},
+				AnnotationCleanupSettings: AnnotationCleanupSettings{MaxCount: 100},
+			},
+		}, rules)
+	})
+
+	testCases := []struct {
+		desc string
+		ini  string
+		err  string
+	}{
+		{
+			desc: "rule without matchers",
+			ini:  "[annotations.retention.all]\nmax_age = 1d",
+			err:  "must match on org, dashboard or tags",
+		},
+		{
+			desc: "rule without limits",
+			ini:  "[annotations.retention.deploy]\ntags = deploy",
+			err:  "must set max age or max annotations to keep",
+		},
+		{
+			desc: "rule with invalid max age",
+			ini:  "[annotations.retention.deploy]\ntags = deploy\nmax_age = forever",
+			err:  "invalid max_age",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run("fails on "+tc.desc, func(t *testing.T) {
+			f, err := ini.Load([]byte(tc.ini))
+			require.NoError(t, err)
+
+			_, err = readAnnotationRetentionRules(f.Sections())
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
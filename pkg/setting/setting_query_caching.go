@@ -0,0 +1,44 @@
+package setting
+
+import (
+	"time"
+)
+
+const (
+	QueryCachingBackendMemory = "memory"
+	QueryCachingBackendRemote = "remote"
+
+	defaultQueryCachingMaxMemoryMB = 100
+)
+
+type QueryCachingSettings struct {
+	Enabled bool
+	// Backend is either "memory" (local to the instance) or "remote" (uses the [remote_cache] configuration).
+	Backend     string
+	TTL         time.Duration
+	ResourceTTL time.Duration
+	// MaxValueBytes is the largest encoded response that will be written to the cache.
+	MaxValueBytes int
+	// MaxMemoryBytes is the total size of the responses kept by the memory backend. The least
+	// recently used responses are evicted when it is exceeded.
+	MaxMemoryBytes int
+}
+
+func (cfg *Cfg) readQueryCachingSettings() {
+	section := cfg.Raw.Section("caching")
+	cfg.QueryCaching.Enabled = section.Key("enabled").MustBool(false)
+	cfg.QueryCaching.Backend = valueAsString(section, "backend", QueryCachingBackendMemory)
+	cfg.QueryCaching.TTL = section.Key("ttl").MustDuration(5 * time.Minute)
+	cfg.QueryCaching.ResourceTTL = section.Key("resource_ttl").MustDuration(5 * time.Minute)
+	cfg.QueryCaching.MaxValueBytes = section.Key("max_value_mb").MustInt(1) * 1024 * 1024
+	cfg.QueryCaching.MaxMemoryBytes = section.Key("max_memory_mb").MustInt(defaultQueryCachingMaxMemoryMB) * 1024 * 1024
+
+	if cfg.QueryCaching.Backend != QueryCachingBackendMemory && cfg.QueryCaching.Backend != QueryCachingBackendRemote {
+		cfg.Logger.Warn("Unknown query caching backend, falling back to memory", "backend", cfg.QueryCaching.Backend)
+		cfg.QueryCaching.Backend = QueryCachingBackendMemory
+	}
+	if cfg.QueryCaching.MaxMemoryBytes <= 0 {
+		cfg.Logger.Warn("Query caching max_memory_mb must be positive, falling back to the default", "max_memory_mb", section.Key("max_memory_mb").String(), "default", defaultQueryCachingMaxMemoryMB)
+		cfg.QueryCaching.MaxMemoryBytes = defaultQueryCachingMaxMemoryMB * 1024 * 1024
+	}
+}
@@ -6,10 +6,19 @@ type OrgQuota struct {
 	Dashboard  int64 `target:"dashboard"`
 	ApiKey     int64 `target:"api_key"`
 	AlertRule  int64 `target:"alert_rule"`
+
+	QueryHistory              int64 `target:"query_history"`
+	QueryHistoryRetentionDays int64 `target:"query_history_retention_days"`
 }
 
 type UserQuota struct {
-	Org int64 `target:"org_user"`
+	Org          int64 `target:"org_user"`
+	QueryHistory int64 `target:"query_history"`
+}
+
+type TeamQuota struct {
+	QueryHistory              int64 `target:"query_history"`
+	QueryHistoryRetentionDays int64 `target:"query_history_retention_days"`
 }
 
 type GlobalQuota struct {
@@ -22,12 +31,16 @@ type GlobalQuota struct {
 	AlertRule    int64 `target:"alert_rule"`
 	File         int64 `target:"file"`
 	Correlations int64 `target:"correlations"`
+
+	QueryHistory              int64 `target:"query_history"`
+	QueryHistoryRetentionDays int64 `target:"query_history_retention_days"`
 }
 
 type QuotaSettings struct {
 	Enabled bool
 	Org     OrgQuota
 	User    UserQuota
+	Team    TeamQuota
 	Global  GlobalQuota
 }
 
@@ -43,11 +56,21 @@ func (cfg *Cfg) readQuotaSettings() {
 		Dashboard:  quota.Key("org_dashboard").MustInt64(10),
 		ApiKey:     quota.Key("org_api_key").MustInt64(10),
 		AlertRule:  quota.Key("org_alert_rule").MustInt64(100),
+
+		QueryHistory:              quota.Key("org_query_history").MustInt64(-1),
+		QueryHistoryRetentionDays: quota.Key("org_query_history_retention_days").MustInt64(-1),
 	}
 
 	// per User limits
 	cfg.Quota.User = UserQuota{
-		Org: quota.Key("user_org").MustInt64(10),
+		Org:          quota.Key("user_org").MustInt64(10),
+		QueryHistory: quota.Key("user_query_history").MustInt64(-1),
+	}
+
+	// per Team limits
+	cfg.Quota.Team = TeamQuota{
+		QueryHistory:              quota.Key("team_query_history").MustInt64(-1),
+		QueryHistoryRetentionDays: quota.Key("team_query_history_retention_days").MustInt64(-1),
 	}
 
 	// Global Limits
@@ -61,5 +84,8 @@ func (cfg *Cfg) readQuotaSettings() {
 		File:         quota.Key("global_file").MustInt64(-1),
 		AlertRule:    quota.Key("global_alert_rule").MustInt64(-1),
 		Correlations: quota.Key("global_correlations").MustInt64(-1),
+
+		QueryHistory:              quota.Key("global_query_history").MustInt64(200000),
+		QueryHistoryRetentionDays: quota.Key("global_query_history_retention_days").MustInt64(14),
 	}
 }
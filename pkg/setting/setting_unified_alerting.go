@@ -64,7 +64,12 @@ const (
 	stateHistoryDefaultEnabled     = true
 	lokiDefaultMaxQueryLength      = 721 * time.Hour // 30d1h, matches the default value in Loki
 	defaultRecordingRequestTimeout = 10 * time.Second
+	defaultRecordingRuleWriter     = "prometheus"
 	lokiDefaultMaxQuerySize        = 65536 // 64kb
+	flapDetectionDefaultMinEvals   = 10
+	flapDetectionHistorySize       = 21 // the number of evaluations kept by the flap detection of alert states
+	flapDetectionDefaultHigh       = 20.0
+	flapDetectionDefaultLow        = 5.0
 )
 
 type UnifiedAlertingSettings struct {
@@ -90,6 +95,7 @@ type UnifiedAlertingSettings struct {
 	HARedisMaxConns                 int
 	HARedisTLSEnabled               bool
 	HARedisTLSConfig                dstls.ClientConfig
+	HARuleShardingEnabled           bool
 	MaxAttempts                     int64
 	MinInterval                     time.Duration
 	EvaluationTimeout               time.Duration
@@ -108,6 +114,7 @@ type UnifiedAlertingSettings struct {
 	ReservedLabels                UnifiedAlertingReservedLabelSettings
 	SkipClustering                bool
 	StateHistory                  UnifiedAlertingStateHistorySettings
+	FlapDetection                 UnifiedAlertingFlapDetectionSettings
 	RemoteAlertmanager            RemoteAlertmanagerSettings
 	RecordingRules                RecordingRuleSettings
 
@@ -129,6 +136,38 @@ type RecordingRuleSettings struct {
 	BasicAuthPassword string
 	CustomHeaders     map[string]string
 	Timeout           time.Duration
+
+	// Writer is the type of the writer used by recording rules that do not select a writer.
+	Writer string
+	// OrgWriters overrides Writer for organizations, by organization ID.
+	OrgWriters map[int64]string
+
+	InfluxDB RecordingRuleInfluxDBSettings
+	OTLP     RecordingRuleOTLPSettings
+	SQL      RecordingRuleSQLSettings
+}
+
+// RecordingRuleInfluxDBSettings configures the writer of recording rules that writes to InfluxDB with line protocol.
+type RecordingRuleInfluxDBSettings struct {
+	// URL of the InfluxDB server, without the write path.
+	URL          string
+	Organization string
+	Bucket       string
+	Token        string
+	Timeout      time.Duration
+}
+
+// RecordingRuleOTLPSettings configures the writer of recording rules that exports to an OpenTelemetry OTLP/HTTP metrics endpoint.
+type RecordingRuleOTLPSettings struct {
+	// URL of the metrics endpoint, including the path, e.g. http://localhost:4318/v1/metrics.
+	URL           string
+	CustomHeaders map[string]string
+	Timeout       time.Duration
+}
+
+// RecordingRuleSQLSettings configures the writer of recording rules that writes to a table in the Grafana database.
+type RecordingRuleSQLSettings struct {
+	Enabled bool
 }
 
 // RemoteAlertmanagerSettings contains the configuration needed
@@ -170,6 +209,19 @@ type UnifiedAlertingStateHistorySettings struct {
 	ExternalLabels        map[string]string
 }
 
+// UnifiedAlertingFlapDetectionSettings configures the detection of alert instances that change state too often.
+type UnifiedAlertingFlapDetectionSettings struct {
+	Enabled bool
+	// MinEvaluations is the number of evaluations in the history required before an alert instance can be flapping.
+	MinEvaluations int
+	// HighThreshold is the percentage of evaluations changing the state at which an alert instance starts flapping.
+	HighThreshold float64
+	// LowThreshold is the percentage of evaluations changing the state below which an alert instance stops flapping.
+	LowThreshold float64
+	// SuppressNotifications stops sending flapping alert instances to the Alertmanager until they stop flapping.
+	SuppressNotifications bool
+}
+
 // IsEnabled returns true if UnifiedAlertingSettings.Enabled is either nil or true.
 // It hides the implementation details of the Enabled and simplifies its usage.
 func (u *UnifiedAlertingSettings) IsEnabled() bool {
@@ -276,6 +328,7 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 	uaCfg.HARedisTLSConfig.InsecureSkipVerify = ua.Key("ha_redis_tls_insecure_skip_verify").MustBool(false)
 	uaCfg.HARedisTLSConfig.CipherSuites = ua.Key("ha_redis_tls_cipher_suites").MustString("")
 	uaCfg.HARedisTLSConfig.MinVersion = ua.Key("ha_redis_tls_min_version").MustString("")
+	uaCfg.HARuleShardingEnabled = ua.Key("ha_rule_sharding_enabled").MustBool(false)
 
 	// TODO load from ini file
 	uaCfg.DefaultConfiguration = alertmanagerDefaultConfiguration
@@ -419,6 +472,24 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 	}
 	uaCfg.StateHistory = uaCfgStateHistory
 
+	flapDetection := iniFile.Section("unified_alerting.flap_detection")
+	// Keys missing in a child section are looked up in [unified_alerting], so only read enabled from the section itself.
+	flapDetectionEnabled, _ := strconv.ParseBool(flapDetection.KeysHash()["enabled"])
+	uaCfgFlapDetection := UnifiedAlertingFlapDetectionSettings{
+		Enabled:               flapDetectionEnabled,
+		MinEvaluations:        flapDetection.Key("min_evaluations").MustInt(flapDetectionDefaultMinEvals),
+		HighThreshold:         flapDetection.Key("high_threshold").MustFloat64(flapDetectionDefaultHigh),
+		LowThreshold:          flapDetection.Key("low_threshold").MustFloat64(flapDetectionDefaultLow),
+		SuppressNotifications: flapDetection.Key("suppress_notifications").MustBool(false),
+	}
+	if uaCfgFlapDetection.MinEvaluations < 1 || uaCfgFlapDetection.MinEvaluations > flapDetectionHistorySize {
+		return fmt.Errorf("value of setting 'min_evaluations' in section 'unified_alerting.flap_detection' must be between 1 and %d", flapDetectionHistorySize)
+	}
+	if uaCfgFlapDetection.LowThreshold < 0 || uaCfgFlapDetection.HighThreshold > 100 || uaCfgFlapDetection.LowThreshold > uaCfgFlapDetection.HighThreshold {
+		return fmt.Errorf("values of settings 'low_threshold' and 'high_threshold' in section 'unified_alerting.flap_detection' must be percentages and 'low_threshold' cannot exceed 'high_threshold'")
+	}
+	uaCfg.FlapDetection = uaCfgFlapDetection
+
 	rr := iniFile.Section("recording_rules")
 	uaCfgRecordingRules := RecordingRuleSettings{
 		URL:               rr.Key("url").MustString(""),
@@ -434,6 +505,38 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 		uaCfgRecordingRules.CustomHeaders[key.Name()] = key.Value()
 	}
 
+	uaCfgRecordingRules.Writer = rr.Key("writer").MustString(defaultRecordingRuleWriter)
+
+	rrOrgWriters := iniFile.Section("recording_rules.org_writers")
+	uaCfgRecordingRules.OrgWriters = make(map[int64]string, len(rrOrgWriters.Keys()))
+	for _, key := range rrOrgWriters.Keys() {
+		orgID, err := strconv.ParseInt(key.Name(), 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid organization ID %q in [recording_rules.org_writers]: %w", key.Name(), err)
+		}
+		uaCfgRecordingRules.OrgWriters[orgID] = key.Value()
+	}
+
+	rrInflux := iniFile.Section("recording_rules.influxdb")
+	uaCfgRecordingRules.InfluxDB = RecordingRuleInfluxDBSettings{
+		URL:          rrInflux.Key("url").MustString(""),
+		Organization: rrInflux.Key("organization").MustString(""),
+		Bucket:       rrInflux.Key("bucket").MustString(""),
+		Token:        rrInflux.Key("token").MustString(""),
+		Timeout:      rrInflux.Key("timeout").MustDuration(defaultRecordingRequestTimeout),
+	}
+
+	rrOTLP := iniFile.Section("recording_rules.otlp")
+	uaCfgRecordingRules.OTLP = RecordingRuleOTLPSettings{
+		URL:           rrOTLP.Key("url").MustString(""),
+		CustomHeaders: iniFile.Section("recording_rules.otlp.custom_headers").KeysHash(),
+		Timeout:       rrOTLP.Key("timeout").MustDuration(defaultRecordingRequestTimeout),
+	}
+
+	uaCfgRecordingRules.SQL = RecordingRuleSQLSettings{
+		Enabled: iniFile.Section("recording_rules.sql").Key("enabled").MustBool(false),
+	}
+
 	uaCfg.RecordingRules = uaCfgRecordingRules
 
 	uaCfg.MaxStateSaveConcurrency = ua.Key("max_state_save_concurrency").MustInt(1)
@@ -346,3 +346,138 @@ func TestHARedisTLSSettings(t *testing.T) {
 	require.Equal(t, cipherSuites, cfg.UnifiedAlerting.HARedisTLSConfig.CipherSuites)
 	require.Equal(t, minVersion, cfg.UnifiedAlerting.HARedisTLSConfig.MinVersion)
 }
+
+func TestRecordingRuleWriterSettings(t *testing.T) {
+	t.Run("defaults to the prometheus writer", func(t *testing.T) {
+		cfg := NewCfg()
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(ini.Empty()))
+
+		require.Equal(t, "prometheus", cfg.UnifiedAlerting.RecordingRules.Writer)
+		require.Empty(t, cfg.UnifiedAlerting.RecordingRules.OrgWriters)
+		require.Empty(t, cfg.UnifiedAlerting.RecordingRules.InfluxDB.URL)
+		require.Empty(t, cfg.UnifiedAlerting.RecordingRules.OTLP.URL)
+		require.False(t, cfg.UnifiedAlerting.RecordingRules.SQL.Enabled)
+	})
+
+	t.Run("reads the writers", func(t *testing.T) {
+		f, err := ini.Load([]byte(`
+[recording_rules]
+writer = sql
+
+[recording_rules.org_writers]
+2 = influxdb
+3 = otlp
+
+[recording_rules.influxdb]
+url = http://localhost:8086
+organization = org
+bucket = bucket
+token = token
+timeout = 5s
+
+[recording_rules.otlp]
+url = http://localhost:4318/v1/metrics
+
+[recording_rules.otlp.custom_headers]
+X-Scope-OrgID = tenant
+
+[recording_rules.sql]
+enabled = true
+`))
+		require.NoError(t, err)
+
+		cfg := NewCfg()
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(f))
+
+		rr := cfg.UnifiedAlerting.RecordingRules
+		require.Equal(t, "sql", rr.Writer)
+		require.Equal(t, map[int64]string{2: "influxdb", 3: "otlp"}, rr.OrgWriters)
+		require.Equal(t, RecordingRuleInfluxDBSettings{
+			URL:          "http://localhost:8086",
+			Organization: "org",
+			Bucket:       "bucket",
+			Token:        "token",
+			Timeout:      5 * time.Second,
+		}, rr.InfluxDB)
+		require.Equal(t, RecordingRuleOTLPSettings{
+			URL:           "http://localhost:4318/v1/metrics",
+			CustomHeaders: map[string]string{"X-Scope-OrgID": "tenant"},
+			Timeout:       defaultRecordingRequestTimeout,
+		}, rr.OTLP)
+		require.True(t, rr.SQL.Enabled)
+	})
+
+	t.Run("error when organization ID is invalid", func(t *testing.T) {
+		f, err := ini.Load([]byte(`
+[recording_rules.org_writers]
+main = influxdb
+`))
+		require.NoError(t, err)
+
+		cfg := NewCfg()
+		require.Error(t, cfg.ReadUnifiedAlertingSettings(f))
+	})
+}
+
+func TestFlapDetectionSettings(t *testing.T) {
+	t.Run("disabled by default", func(t *testing.T) {
+		cfg := NewCfg()
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(ini.Empty()))
+
+		require.Equal(t, UnifiedAlertingFlapDetectionSettings{
+			MinEvaluations: 10,
+			HighThreshold:  20,
+			LowThreshold:   5,
+		}, cfg.UnifiedAlerting.FlapDetection)
+	})
+
+	t.Run("does not inherit enabled from unified_alerting section", func(t *testing.T) {
+		f, err := ini.Load([]byte(`
+[unified_alerting]
+enabled = true
+`))
+		require.NoError(t, err)
+
+		cfg := NewCfg()
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(f))
+		require.False(t, cfg.UnifiedAlerting.FlapDetection.Enabled)
+	})
+
+	t.Run("reads the settings", func(t *testing.T) {
+		f, err := ini.Load([]byte(`
+[unified_alerting.flap_detection]
+enabled = true
+min_evaluations = 5
+high_threshold = 40
+low_threshold = 10.5
+suppress_notifications = true
+`))
+		require.NoError(t, err)
+
+		cfg := NewCfg()
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(f))
+
+		require.Equal(t, UnifiedAlertingFlapDetectionSettings{
+			Enabled:               true,
+			MinEvaluations:        5,
+			HighThreshold:         40,
+			LowThreshold:          10.5,
+			SuppressNotifications: true,
+		}, cfg.UnifiedAlerting.FlapDetection)
+	})
+
+	t.Run("error when thresholds are invalid", func(t *testing.T) {
+		for _, section := range []string{
+			"[unified_alerting.flap_detection]\nhigh_threshold = 10\nlow_threshold = 20",
+			"[unified_alerting.flap_detection]\nhigh_threshold = 120",
+			"[unified_alerting.flap_detection]\nmin_evaluations = 0",
+			"[unified_alerting.flap_detection]\nmin_evaluations = 22",
+		} {
+			f, err := ini.Load([]byte(section))
+			require.NoError(t, err)
+
+			cfg := NewCfg()
+			require.Error(t, cfg.ReadUnifiedAlertingSettings(f), section)
+		}
+	})
+}
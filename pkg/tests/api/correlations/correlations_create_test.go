@@ -274,6 +274,73 @@ func TestIntegrationCreateCorrelation(t *testing.T) {
 		require.NoError(t, res.Body.Close())
 	})
 
+	t.Run("Should correctly create and read an external correlation", func(t *testing.T) {
+		res := ctx.Post(PostParams{
+			url: fmt.Sprintf("/api/datasources/uid/%s/correlations", writableDs),
+			body: `{
+					"label": "external",
+					"config": {
+						"type": "external",
+						"field": "line",
+						"target": { "url": "https://tickets.example.com/search?q=${traceId}" },
+						"transformations": [
+							{"type": "jsonpath", "expression": "$.trace.id", "mapValue": "traceId"}
+						]
+					}
+				}`,
+			user: adminUser,
+		})
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		responseBody, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		var response correlations.CreateCorrelationResponseBody
+		err = json.Unmarshal(responseBody, &response)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+
+		require.Nil(t, response.Result.TargetUID)
+		require.Equal(t, correlations.ConfigTypeExternal, response.Result.Config.Type)
+
+		res = ctx.Get(GetParams{
+			url:  fmt.Sprintf("/api/datasources/uid/%s/correlations/%s", writableDs, response.Result.UID),
+			user: adminUser,
+		})
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		responseBody, err = io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		var correlation correlations.Correlation
+		err = json.Unmarshal(responseBody, &correlation)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+
+		require.Equal(t, response.Result.UID, correlation.UID)
+		require.Equal(t, map[string]any{"url": "https://tickets.example.com/search?q=${traceId}"}, correlation.Config.Target)
+		require.Equal(t, correlations.Transformations{
+			{Type: "jsonpath", Expression: "$.trace.id", MapValue: "traceId"},
+		}, correlation.Config.Transformations)
+	})
+
+	t.Run("Should not create an external correlation with an invalid url", func(t *testing.T) {
+		res := ctx.Post(PostParams{
+			url: fmt.Sprintf("/api/datasources/uid/%s/correlations", writableDs),
+			body: `{
+					"label": "external",
+					"config": {
+						"type": "external",
+						"field": "line",
+						"target": { "url": "javascript:alert(1)" }
+					}
+				}`,
+			user: adminUser,
+		})
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
 	t.Run("Should not create a correlation with incorrect config", func(t *testing.T) {
 		description := "a description"
 		label := "a label"
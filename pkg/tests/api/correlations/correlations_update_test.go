@@ -214,6 +214,40 @@ func TestIntegrationUpdateCorrelation(t *testing.T) {
 		require.NoError(t, res.Body.Close())
 	})
 
+	t.Run("updating an external correlation to a query correlation without a target should result in a 400", func(t *testing.T) {
+		correlation := ctx.createCorrelation(correlations.CreateCorrelationCommand{
+			SourceUID: writableDs,
+			OrgId:     writableDsOrgId,
+			Config: correlations.CorrelationConfig{
+				Field:  "traceId",
+				Type:   correlations.ConfigTypeExternal,
+				Target: map[string]any{"url": "https://example.com/${traceId}"},
+			},
+		})
+
+		res := ctx.Patch(PatchParams{
+			url:  fmt.Sprintf("/api/datasources/uid/%s/correlations/%s", correlation.SourceUID, correlation.UID),
+			user: adminUser,
+			body: `{
+				"config": {
+					"type": "query",
+					"target": { "expr": "foo" }
+				}
+			}`,
+		})
+		responseBody, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		var response errorResponseBody
+		err = json.Unmarshal(responseBody, &response)
+		require.NoError(t, err)
+
+		require.Equal(t, "Invalid query correlation", response.Message)
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+		require.NoError(t, res.Body.Close())
+	})
+
 	t.Run("updating a correlation pointing to a read-only data source should work", func(t *testing.T) {
 		correlation := ctx.createCorrelation(correlations.CreateCorrelationCommand{
 			SourceUID: writableDs,
@@ -2,6 +2,7 @@ package es
 
 import (
 	"encoding/json"
+	"slices"
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
@@ -262,6 +263,93 @@ type GeoHashGridAggregation struct {
 	Precision int    `json:"precision"`
 }
 
+// GeoTileGridAggregation represents a geo tile grid aggregation
+type GeoTileGridAggregation struct {
+	Field     string `json:"field"`
+	Precision int    `json:"precision"`
+}
+
+// CompositeAggregation represents a composite aggregation
+type CompositeAggregation struct {
+	Size    int                    `json:"size,omitempty"`
+	Sources []*CompositeSource     `json:"sources"`
+	After   map[string]interface{} `json:"after,omitempty"`
+}
+
+// CompositeSource represents a values source of a composite aggregation
+type CompositeSource struct {
+	Name          string
+	Type          string
+	Field         string
+	Interval      interface{}
+	Order         string
+	MissingBucket bool
+}
+
+// MarshalJSON returns the JSON encoding of the composite source
+func (s *CompositeSource) MarshalJSON() ([]byte, error) {
+	source := map[string]interface{}{
+		"field": s.Field,
+	}
+	switch s.Type {
+	case "histogram":
+		source["interval"] = s.Interval
+	case "date_histogram":
+		if interval, ok := s.Interval.(string); ok && slices.Contains(GetCalendarIntervals(), interval) {
+			source["calendar_interval"] = interval
+		} else {
+			source["fixed_interval"] = s.Interval
+		}
+	}
+	if s.Order != "" {
+		source["order"] = s.Order
+	}
+	if s.MissingBucket {
+		source["missing_bucket"] = true
+	}
+
+	return json.Marshal(map[string]interface{}{
+		s.Name: map[string]interface{}{
+			s.Type: source,
+		},
+	})
+}
+
+// RangeAggregation represents a range aggregation
+type RangeAggregation struct {
+	Field  string                   `json:"field"`
+	Ranges []*RangeAggregationRange `json:"ranges"`
+}
+
+// RangeAggregationRange represents a bucket of a range aggregation, the lower bound is inclusive
+type RangeAggregationRange struct {
+	Key  string   `json:"key,omitempty"`
+	From *float64 `json:"from,omitempty"`
+	To   *float64 `json:"to,omitempty"`
+}
+
+// DateRangeAggregation represents a date range aggregation
+type DateRangeAggregation struct {
+	Field    string                       `json:"field"`
+	Format   string                       `json:"format,omitempty"`
+	TimeZone string                       `json:"time_zone,omitempty"`
+	Ranges   []*DateRangeAggregationRange `json:"ranges"`
+}
+
+// DateRangeAggregationRange represents a bucket of a date range aggregation, bounds are dates or date math expressions
+type DateRangeAggregationRange struct {
+	Key  string `json:"key,omitempty"`
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+// SignificantTermsAggregation represents a significant terms aggregation
+type SignificantTermsAggregation struct {
+	Field       string `json:"field"`
+	Size        int    `json:"size"`
+	MinDocCount *int   `json:"min_doc_count,omitempty"`
+}
+
 // MetricAggregation represents a metric aggregation
 type MetricAggregation struct {
 	Type     string
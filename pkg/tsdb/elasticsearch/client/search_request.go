@@ -12,6 +12,7 @@ const (
 	HighlightPostTagsString = "@/HIGHLIGHT@"
 	HighlightFragmentSize   = 2147483647
 	DefaultGeoHashPrecision = 3
+	DefaultGeoTilePrecision = 7
 )
 
 // SearchRequestBuilder represents a builder which can build a search request
@@ -306,6 +307,11 @@ type AggBuilder interface {
 	Nested(key, path string, fn func(a *NestedAggregation, b AggBuilder)) AggBuilder
 	Filters(key string, fn func(a *FiltersAggregation, b AggBuilder)) AggBuilder
 	GeoHashGrid(key, field string, fn func(a *GeoHashGridAggregation, b AggBuilder)) AggBuilder
+	GeoTileGrid(key, field string, fn func(a *GeoTileGridAggregation, b AggBuilder)) AggBuilder
+	Composite(key string, fn func(a *CompositeAggregation, b AggBuilder)) AggBuilder
+	Range(key, field string, fn func(a *RangeAggregation, b AggBuilder)) AggBuilder
+	DateRange(key, field string, fn func(a *DateRangeAggregation, b AggBuilder)) AggBuilder
+	SignificantTerms(key, field string, fn func(a *SignificantTermsAggregation, b AggBuilder)) AggBuilder
 	Metric(key, metricType, field string, fn func(a *MetricAggregation)) AggBuilder
 	Pipeline(key, pipelineType string, bucketPath any, fn func(a *PipelineAggregation)) AggBuilder
 	Build() (AggArray, error)
@@ -476,6 +482,109 @@ func (b *aggBuilderImpl) GeoHashGrid(key, field string, fn func(a *GeoHashGridAg
 	return b
 }
 
+func (b *aggBuilderImpl) GeoTileGrid(key, field string, fn func(a *GeoTileGridAggregation, b AggBuilder)) AggBuilder {
+	innerAgg := &GeoTileGridAggregation{
+		Field:     field,
+		Precision: DefaultGeoTilePrecision,
+	}
+	aggDef := newAggDef(key, &aggContainer{
+		Type:        "geotile_grid",
+		Aggregation: innerAgg,
+	})
+
+	if fn != nil {
+		builder := newAggBuilder()
+		aggDef.builders = append(aggDef.builders, builder)
+		fn(innerAgg, builder)
+	}
+
+	b.aggDefs = append(b.aggDefs, aggDef)
+
+	return b
+}
+
+func (b *aggBuilderImpl) Composite(key string, fn func(a *CompositeAggregation, b AggBuilder)) AggBuilder {
+	innerAgg := &CompositeAggregation{
+		Sources: make([]*CompositeSource, 0),
+	}
+	aggDef := newAggDef(key, &aggContainer{
+		Type:        "composite",
+		Aggregation: innerAgg,
+	})
+
+	if fn != nil {
+		builder := newAggBuilder()
+		aggDef.builders = append(aggDef.builders, builder)
+		fn(innerAgg, builder)
+	}
+
+	b.aggDefs = append(b.aggDefs, aggDef)
+
+	return b
+}
+
+func (b *aggBuilderImpl) Range(key, field string, fn func(a *RangeAggregation, b AggBuilder)) AggBuilder {
+	innerAgg := &RangeAggregation{
+		Field:  field,
+		Ranges: make([]*RangeAggregationRange, 0),
+	}
+	aggDef := newAggDef(key, &aggContainer{
+		Type:        "range",
+		Aggregation: innerAgg,
+	})
+
+	if fn != nil {
+		builder := newAggBuilder()
+		aggDef.builders = append(aggDef.builders, builder)
+		fn(innerAgg, builder)
+	}
+
+	b.aggDefs = append(b.aggDefs, aggDef)
+
+	return b
+}
+
+func (b *aggBuilderImpl) DateRange(key, field string, fn func(a *DateRangeAggregation, b AggBuilder)) AggBuilder {
+	innerAgg := &DateRangeAggregation{
+		Field:  field,
+		Ranges: make([]*DateRangeAggregationRange, 0),
+	}
+	aggDef := newAggDef(key, &aggContainer{
+		Type:        "date_range",
+		Aggregation: innerAgg,
+	})
+
+	if fn != nil {
+		builder := newAggBuilder()
+		aggDef.builders = append(aggDef.builders, builder)
+		fn(innerAgg, builder)
+	}
+
+	b.aggDefs = append(b.aggDefs, aggDef)
+
+	return b
+}
+
+func (b *aggBuilderImpl) SignificantTerms(key, field string, fn func(a *SignificantTermsAggregation, b AggBuilder)) AggBuilder {
+	innerAgg := &SignificantTermsAggregation{
+		Field: field,
+	}
+	aggDef := newAggDef(key, &aggContainer{
+		Type:        "significant_terms",
+		Aggregation: innerAgg,
+	})
+
+	if fn != nil {
+		builder := newAggBuilder()
+		aggDef.builders = append(aggDef.builders, builder)
+		fn(innerAgg, builder)
+	}
+
+	b.aggDefs = append(b.aggDefs, aggDef)
+
+	return b
+}
+
 func (b *aggBuilderImpl) Metric(key, metricType, field string, fn func(a *MetricAggregation)) AggBuilder {
 	innerAgg := &MetricAggregation{
 		Type:     metricType,
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"regexp"
 	"slices"
 	"strconv"
@@ -19,6 +20,8 @@ import (
 
 const (
 	defaultSize = 500
+	// defaultCompositeMaxPages is the default number of pages requested for a composite aggregation
+	defaultCompositeMaxPages = 10
 )
 
 type elasticsearchDataQuery struct {
@@ -81,9 +84,83 @@ func (e *elasticsearchDataQuery) execute() (*backend.QueryDataResponse, error) {
 		return errorsource.AddErrorToResponse(e.dataQueries[0].RefID, response, err), nil
 	}
 
+	if err := e.fetchCompositePages(queries, res.Responses); err != nil {
+		return errorsource.AddErrorToResponse(e.dataQueries[0].RefID, response, err), nil
+	}
+
 	return parseResponse(e.ctx, res.Responses, queries, e.client.GetConfiguredFields(), e.keepLabelsInResponse, e.logger)
 }
 
+// fetchCompositePages requests the following pages of the composite aggregations that are the first
+// bucket aggregation of a query, using the after key of the previous page, and appends their buckets
+// to the response of the query. At most maxPages pages are requested for each query.
+func (e *elasticsearchDataQuery) fetchCompositePages(queries []*Query, responses []*es.SearchResponse) error {
+	for i, q := range queries {
+		if i >= len(responses) || responses[i] == nil || responses[i].Error != nil {
+			continue
+		}
+		if len(q.BucketAggs) == 0 || q.BucketAggs[0].Type != compositeType || isLogsQuery(q) || isDocumentQuery(q) {
+			continue
+		}
+
+		bucketAgg := q.BucketAggs[0]
+		size := getCompositeSize(bucketAgg)
+
+		// the following pages are requested with a copy of the query so that the after key is not
+		// written into the settings of the query model
+		pageAgg := *bucketAgg
+		pageAgg.Settings = simplejson.NewFromAny(maps.Clone(bucketAgg.Settings.MustMap(map[string]any{})))
+		pageQuery := *q
+		pageQuery.BucketAggs = append([]*BucketAgg{&pageAgg}, q.BucketAggs[1:]...)
+		maxPages, err := bucketAgg.Settings.Get("maxPages").Int()
+		if err != nil || maxPages <= 0 {
+			maxPages = stringToIntWithDefaultValue(bucketAgg.Settings.Get("maxPages").MustString(), defaultCompositeMaxPages)
+		}
+
+		aggResult := simplejson.NewFromAny(responses[i].Aggregations[bucketAgg.ID])
+		buckets := aggResult.Get("buckets").MustArray()
+		lastPageSize := len(buckets)
+		afterKey := aggResult.Get("after_key").MustMap()
+
+		from := q.TimeRange.From.UnixNano() / int64(time.Millisecond)
+		to := q.TimeRange.To.UnixNano() / int64(time.Millisecond)
+		for page := 1; page < maxPages && len(afterKey) > 0 && lastPageSize >= size; page++ {
+			pageAgg.Settings.Set("after", afterKey)
+			ms := e.client.MultiSearch()
+			if err := e.processQuery(&pageQuery, ms, from, to); err != nil {
+				return err
+			}
+			req, err := ms.Build()
+			if err != nil {
+				return err
+			}
+			res, err := e.client.ExecuteMultisearch(req)
+			if err != nil {
+				return err
+			}
+			if len(res.Responses) == 0 || res.Responses[0] == nil {
+				break
+			}
+			if res.Responses[0].Error != nil {
+				responses[i].Error = res.Responses[0].Error
+				break
+			}
+
+			pageResult := simplejson.NewFromAny(res.Responses[0].Aggregations[bucketAgg.ID])
+			pageBuckets := pageResult.Get("buckets").MustArray()
+			buckets = append(buckets, pageBuckets...)
+			lastPageSize = len(pageBuckets)
+			afterKey = pageResult.Get("after_key").MustMap()
+		}
+
+		if len(buckets) > 0 {
+			aggResult.Set("buckets", buckets)
+			responses[i].Aggregations[bucketAgg.ID] = aggResult.Interface()
+		}
+	}
+	return nil
+}
+
 func (e *elasticsearchDataQuery) processQuery(q *Query, ms *es.MultiSearchRequestBuilder, from, to int64) error {
 	err := isQueryWithError(q)
 	if err != nil {
@@ -309,6 +386,138 @@ func addGeoHashGridAgg(aggBuilder es.AggBuilder, bucketAgg *BucketAgg) es.AggBui
 	return aggBuilder
 }
 
+func addGeoTileGridAgg(aggBuilder es.AggBuilder, bucketAgg *BucketAgg) es.AggBuilder {
+	aggBuilder.GeoTileGrid(bucketAgg.ID, bucketAgg.Field, func(a *es.GeoTileGridAggregation, b es.AggBuilder) {
+		a.Precision = stringToIntWithDefaultValue(bucketAgg.Settings.Get("precision").MustString(), es.DefaultGeoTilePrecision)
+		aggBuilder = b
+	})
+
+	return aggBuilder
+}
+
+func addCompositeAgg(aggBuilder es.AggBuilder, bucketAgg *BucketAgg) es.AggBuilder {
+	aggBuilder.Composite(bucketAgg.ID, func(a *es.CompositeAggregation, b es.AggBuilder) {
+		a.Size = getCompositeSize(bucketAgg)
+		a.Sources = getCompositeSources(bucketAgg)
+		if after, err := bucketAgg.Settings.Get("after").Map(); err == nil && len(after) > 0 {
+			a.After = after
+		}
+		aggBuilder = b
+	})
+
+	return aggBuilder
+}
+
+func getCompositeSize(bucketAgg *BucketAgg) int {
+	if size, err := bucketAgg.Settings.Get("size").Int(); err == nil && size > 0 {
+		return size
+	}
+	return stringToIntWithDefaultValue(bucketAgg.Settings.Get("size").MustString(), defaultSize)
+}
+
+// getCompositeSources returns the values sources of a composite aggregation.
+// If no sources are configured, the field of the aggregation is used as a terms source.
+func getCompositeSources(bucketAgg *BucketAgg) []*es.CompositeSource {
+	sources := make([]*es.CompositeSource, 0)
+	for _, s := range bucketAgg.Settings.Get("sources").MustArray() {
+		source := simplejson.NewFromAny(s)
+		field := source.Get("field").MustString()
+		if field == "" {
+			continue
+		}
+		sourceType := source.Get("type").MustString(termsType)
+		compositeSource := &es.CompositeSource{
+			Name:          source.Get("name").MustString(field),
+			Type:          sourceType,
+			Field:         field,
+			Order:         source.Get("order").MustString(),
+			MissingBucket: source.Get("missing_bucket").MustBool(),
+		}
+		switch sourceType {
+		case histogramType:
+			compositeSource.Interval = stringToIntWithDefaultValue(source.Get("interval").MustString(), 1000)
+		case dateHistType:
+			compositeSource.Interval = source.Get("interval").MustString("1h")
+		}
+		sources = append(sources, compositeSource)
+	}
+
+	if len(sources) == 0 && bucketAgg.Field != "" {
+		sources = append(sources, &es.CompositeSource{
+			Name:  bucketAgg.Field,
+			Type:  termsType,
+			Field: bucketAgg.Field,
+		})
+	}
+	return sources
+}
+
+func addRangeAgg(aggBuilder es.AggBuilder, bucketAgg *BucketAgg) es.AggBuilder {
+	aggBuilder.Range(bucketAgg.ID, bucketAgg.Field, func(a *es.RangeAggregation, b es.AggBuilder) {
+		for _, r := range bucketAgg.Settings.Get("ranges").MustArray() {
+			rangeJSON := simplejson.NewFromAny(r)
+			a.Ranges = append(a.Ranges, &es.RangeAggregationRange{
+				Key:  rangeJSON.Get("key").MustString(),
+				From: getRangeBound(rangeJSON.Get("from")),
+				To:   getRangeBound(rangeJSON.Get("to")),
+			})
+		}
+		aggBuilder = b
+	})
+
+	return aggBuilder
+}
+
+// getRangeBound returns the bound of a range bucket, which the frontend stores as a string.
+// An empty or missing bound means the range is unbounded on that side.
+func getRangeBound(bound *simplejson.Json) *float64 {
+	if value, err := bound.Float64(); err == nil {
+		return &value
+	}
+	if stringValue, err := bound.String(); err == nil && stringValue != "" {
+		if value, err := strconv.ParseFloat(stringValue, 64); err == nil {
+			return &value
+		}
+	}
+	return nil
+}
+
+func addDateRangeAgg(aggBuilder es.AggBuilder, bucketAgg *BucketAgg) es.AggBuilder {
+	aggBuilder.DateRange(bucketAgg.ID, bucketAgg.Field, func(a *es.DateRangeAggregation, b es.AggBuilder) {
+		a.Format = bucketAgg.Settings.Get("format").MustString()
+		if timezone, err := bucketAgg.Settings.Get("timeZone").String(); err == nil && timezone != "utc" {
+			a.TimeZone = timezone
+		}
+		for _, r := range bucketAgg.Settings.Get("ranges").MustArray() {
+			rangeJSON := simplejson.NewFromAny(r)
+			a.Ranges = append(a.Ranges, &es.DateRangeAggregationRange{
+				Key:  rangeJSON.Get("key").MustString(),
+				From: rangeJSON.Get("from").MustString(),
+				To:   rangeJSON.Get("to").MustString(),
+			})
+		}
+		aggBuilder = b
+	})
+
+	return aggBuilder
+}
+
+func addSignificantTermsAgg(aggBuilder es.AggBuilder, bucketAgg *BucketAgg) es.AggBuilder {
+	aggBuilder.SignificantTerms(bucketAgg.ID, bucketAgg.Field, func(a *es.SignificantTermsAggregation, b es.AggBuilder) {
+		if size, err := bucketAgg.Settings.Get("size").Int(); err == nil {
+			a.Size = size
+		} else {
+			a.Size = stringToIntWithDefaultValue(bucketAgg.Settings.Get("size").MustString(), defaultSize)
+		}
+		if minDocCount, err := bucketAgg.Settings.Get("min_doc_count").Int(); err == nil {
+			a.MinDocCount = &minDocCount
+		}
+		aggBuilder = b
+	})
+
+	return aggBuilder
+}
+
 func getPipelineAggField(m *MetricAgg) string {
 	// In frontend we are using Field as pipelineAggField
 	// There might be historical reason why in backend we were using PipelineAggregate as pipelineAggField
@@ -329,6 +538,12 @@ func isQueryWithError(query *Query) error {
 			return fmt.Errorf("invalid query, missing metrics and aggregations")
 		}
 	}
+	// Elasticsearch only accepts composite aggregations at the top level of the aggregation tree
+	for i, bucketAgg := range query.BucketAggs {
+		if i > 0 && bucketAgg.Type == compositeType {
+			return fmt.Errorf("invalid query, composite aggregation %s must be the first bucket aggregation", bucketAgg.ID)
+		}
+	}
 	return nil
 }
 
@@ -420,6 +635,16 @@ func processTimeSeriesQuery(q *Query, b *es.SearchRequestBuilder, from, to int64
 			aggBuilder = addTermsAgg(aggBuilder, bucketAgg, q.Metrics)
 		case geohashGridType:
 			aggBuilder = addGeoHashGridAgg(aggBuilder, bucketAgg)
+		case geotileGridType:
+			aggBuilder = addGeoTileGridAgg(aggBuilder, bucketAgg)
+		case compositeType:
+			aggBuilder = addCompositeAgg(aggBuilder, bucketAgg)
+		case rangeType:
+			aggBuilder = addRangeAgg(aggBuilder, bucketAgg)
+		case dateRangeType:
+			aggBuilder = addDateRangeAgg(aggBuilder, bucketAgg)
+		case significantTermsType:
+			aggBuilder = addSignificantTermsAgg(aggBuilder, bucketAgg)
 		case nestedType:
 			aggBuilder = addNestedAgg(aggBuilder, bucketAgg)
 		}
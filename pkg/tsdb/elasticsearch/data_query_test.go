@@ -680,6 +680,241 @@ func TestExecuteElasticsearchDataQuery(t *testing.T) {
 			require.Equal(t, ghGridAgg.Precision, 3)
 		})
 
+		t.Run("With geo tile grid agg", func(t *testing.T) {
+			c := newFakeClient()
+			_, err := executeElasticsearchDataQuery(c, `{
+				"bucketAggs": [
+					{
+						"id": "3",
+						"type": "geotile_grid",
+						"field": "@location",
+						"settings": { "precision": "9" }
+					}
+				],
+				"metrics": [{"type": "count", "id": "1" }]
+			}`, from, to)
+			require.NoError(t, err)
+			sr := c.multisearchRequests[0].Requests[0]
+
+			firstLevel := sr.Aggs[0]
+			require.Equal(t, firstLevel.Key, "3")
+			require.Equal(t, firstLevel.Aggregation.Type, "geotile_grid")
+			gtGridAgg := firstLevel.Aggregation.Aggregation.(*es.GeoTileGridAggregation)
+			require.Equal(t, gtGridAgg.Field, "@location")
+			require.Equal(t, gtGridAgg.Precision, 9)
+		})
+
+		t.Run("With geo tile grid agg with no precision", func(t *testing.T) {
+			c := newFakeClient()
+			_, err := executeElasticsearchDataQuery(c, `{
+				"bucketAggs": [{ "id": "3", "type": "geotile_grid", "field": "@location", "settings": {} }],
+				"metrics": [{"type": "count", "id": "1" }]
+			}`, from, to)
+			require.NoError(t, err)
+			sr := c.multisearchRequests[0].Requests[0]
+
+			gtGridAgg := sr.Aggs[0].Aggregation.Aggregation.(*es.GeoTileGridAggregation)
+			require.Equal(t, gtGridAgg.Precision, es.DefaultGeoTilePrecision)
+		})
+
+		t.Run("With composite agg", func(t *testing.T) {
+			c := newFakeClient()
+			_, err := executeElasticsearchDataQuery(c, `{
+				"bucketAggs": [
+					{
+						"id": "2",
+						"type": "composite",
+						"settings": {
+							"size": "100",
+							"sources": [
+								{ "field": "@host" },
+								{ "name": "bytes", "type": "histogram", "field": "@bytes", "interval": "10", "missing_bucket": true },
+								{ "name": "day", "type": "date_histogram", "field": "@timestamp", "interval": "1w", "order": "desc" }
+							]
+						}
+					},
+					{ "type": "date_histogram", "field": "@timestamp", "id": "3" }
+				],
+				"metrics": [{"type": "count", "id": "1" }]
+			}`, from, to)
+			require.NoError(t, err)
+			sr := c.multisearchRequests[0].Requests[0]
+
+			firstLevel := sr.Aggs[0]
+			require.Equal(t, firstLevel.Key, "2")
+			require.Equal(t, firstLevel.Aggregation.Type, "composite")
+			compositeAgg := firstLevel.Aggregation.Aggregation.(*es.CompositeAggregation)
+			require.Equal(t, 100, compositeAgg.Size)
+			require.Nil(t, compositeAgg.After)
+			require.Equal(t, []*es.CompositeSource{
+				{Name: "@host", Type: "terms", Field: "@host"},
+				{Name: "bytes", Type: "histogram", Field: "@bytes", Interval: 10, MissingBucket: true},
+				{Name: "day", Type: "date_histogram", Field: "@timestamp", Interval: "1w", Order: "desc"},
+			}, compositeAgg.Sources)
+
+			sources, err := json.Marshal(compositeAgg.Sources)
+			require.NoError(t, err)
+			require.JSONEq(t, `[
+				{"@host": {"terms": {"field": "@host"}}},
+				{"bytes": {"histogram": {"field": "@bytes", "interval": 10, "missing_bucket": true}}},
+				{"day": {"date_histogram": {"field": "@timestamp", "calendar_interval": "1w", "order": "desc"}}}
+			]`, string(sources))
+
+			secondLevel := firstLevel.Aggregation.Aggs[0]
+			require.Equal(t, secondLevel.Key, "3")
+			require.Equal(t, secondLevel.Aggregation.Type, "date_histogram")
+		})
+
+		t.Run("With composite agg without sources uses the field", func(t *testing.T) {
+			c := newFakeClient()
+			_, err := executeElasticsearchDataQuery(c, `{
+				"bucketAggs": [{ "id": "2", "type": "composite", "field": "@host" }],
+				"metrics": [{"type": "count", "id": "1" }]
+			}`, from, to)
+			require.NoError(t, err)
+			sr := c.multisearchRequests[0].Requests[0]
+
+			compositeAgg := sr.Aggs[0].Aggregation.Aggregation.(*es.CompositeAggregation)
+			require.Equal(t, defaultSize, compositeAgg.Size)
+			require.Equal(t, []*es.CompositeSource{{Name: "@host", Type: "terms", Field: "@host"}}, compositeAgg.Sources)
+		})
+
+		t.Run("With composite agg nested under another bucket agg should return error", func(t *testing.T) {
+			c := newFakeClient()
+			res, err := executeElasticsearchDataQuery(c, `{
+				"bucketAggs": [
+					{ "id": "2", "type": "terms", "field": "@host" },
+					{ "id": "3", "type": "composite", "field": "@service" }
+				],
+				"metrics": [{"type": "count", "id": "1" }]
+			}`, from, to)
+			require.NoError(t, err)
+			require.Empty(t, c.multisearchRequests)
+			require.Equal(t, backend.ErrorSourcePlugin, res.Responses["A"].ErrorSource)
+			require.EqualError(t, res.Responses["A"].Error, "received invalid query. invalid query, composite aggregation 3 must be the first bucket aggregation")
+		})
+
+		t.Run("With composite agg requests the following pages", func(t *testing.T) {
+			c := newFakeClient()
+			c.multiSearchResponses = []*es.MultiSearchResponse{
+				{Responses: []*es.SearchResponse{{Aggregations: map[string]any{"2": map[string]any{
+					"after_key": map[string]any{"@host": "b"},
+					"buckets": []any{
+						map[string]any{"key": map[string]any{"@host": "a"}, "doc_count": 1},
+						map[string]any{"key": map[string]any{"@host": "b"}, "doc_count": 2},
+					},
+				}}}}},
+				{Responses: []*es.SearchResponse{{Aggregations: map[string]any{"2": map[string]any{
+					"after_key": map[string]any{"@host": "c"},
+					"buckets": []any{
+						map[string]any{"key": map[string]any{"@host": "c"}, "doc_count": 3},
+					},
+				}}}}},
+			}
+			res, err := executeElasticsearchDataQuery(c, `{
+				"bucketAggs": [{ "id": "2", "type": "composite", "field": "@host", "settings": { "size": "2" } }],
+				"metrics": [{"type": "count", "id": "1" }]
+			}`, from, to)
+			require.NoError(t, err)
+			require.Len(t, c.multisearchRequests, 2)
+
+			firstPage := c.multisearchRequests[0].Requests[0].Aggs[0].Aggregation.Aggregation.(*es.CompositeAggregation)
+			require.Nil(t, firstPage.After)
+			secondPage := c.multisearchRequests[1].Requests[0].Aggs[0].Aggregation.Aggregation.(*es.CompositeAggregation)
+			require.Equal(t, map[string]any{"@host": "b"}, secondPage.After)
+
+			frames := res.Responses["A"].Frames
+			require.Len(t, frames, 1)
+			require.Equal(t, 3, frames[0].Rows())
+		})
+
+		t.Run("With composite agg stops at max pages", func(t *testing.T) {
+			c := newFakeClient()
+			c.multiSearchResponse = &es.MultiSearchResponse{Responses: []*es.SearchResponse{{Aggregations: map[string]any{"2": map[string]any{
+				"after_key": map[string]any{"@host": "a"},
+				"buckets": []any{
+					map[string]any{"key": map[string]any{"@host": "a"}, "doc_count": 1},
+				},
+			}}}}}
+			_, err := executeElasticsearchDataQuery(c, `{
+				"bucketAggs": [{ "id": "2", "type": "composite", "field": "@host", "settings": { "size": "1", "maxPages": "3" } }],
+				"metrics": [{"type": "count", "id": "1" }]
+			}`, from, to)
+			require.NoError(t, err)
+			require.Len(t, c.multisearchRequests, 3)
+		})
+
+		t.Run("With range agg", func(t *testing.T) {
+			c := newFakeClient()
+			_, err := executeElasticsearchDataQuery(c, `{
+				"bucketAggs": [
+					{
+						"id": "2",
+						"type": "range",
+						"field": "@bytes",
+						"settings": { "ranges": [{ "to": "100" }, { "from": "100", "to": 200, "key": "medium" }, { "from": "200", "to": "" }] }
+					}
+				],
+				"metrics": [{"type": "count", "id": "1" }]
+			}`, from, to)
+			require.NoError(t, err)
+			sr := c.multisearchRequests[0].Requests[0]
+
+			firstLevel := sr.Aggs[0]
+			require.Equal(t, firstLevel.Aggregation.Type, "range")
+			rangeAgg := firstLevel.Aggregation.Aggregation.(*es.RangeAggregation)
+			require.Equal(t, "@bytes", rangeAgg.Field)
+			ranges, err := json.Marshal(rangeAgg.Ranges)
+			require.NoError(t, err)
+			require.JSONEq(t, `[{"to": 100}, {"key": "medium", "from": 100, "to": 200}, {"from": 200}]`, string(ranges))
+		})
+
+		t.Run("With date range agg", func(t *testing.T) {
+			c := newFakeClient()
+			_, err := executeElasticsearchDataQuery(c, `{
+				"bucketAggs": [
+					{
+						"id": "2",
+						"type": "date_range",
+						"field": "@timestamp",
+						"settings": { "format": "yyyy-MM-dd", "timeZone": "Europe/Berlin", "ranges": [{ "from": "now-1d/d", "key": "today" }] }
+					}
+				],
+				"metrics": [{"type": "count", "id": "1" }]
+			}`, from, to)
+			require.NoError(t, err)
+			sr := c.multisearchRequests[0].Requests[0]
+
+			firstLevel := sr.Aggs[0]
+			require.Equal(t, firstLevel.Aggregation.Type, "date_range")
+			dateRangeAgg := firstLevel.Aggregation.Aggregation.(*es.DateRangeAggregation)
+			require.Equal(t, &es.DateRangeAggregation{
+				Field:    "@timestamp",
+				Format:   "yyyy-MM-dd",
+				TimeZone: "Europe/Berlin",
+				Ranges:   []*es.DateRangeAggregationRange{{Key: "today", From: "now-1d/d"}},
+			}, dateRangeAgg)
+		})
+
+		t.Run("With significant terms agg", func(t *testing.T) {
+			c := newFakeClient()
+			_, err := executeElasticsearchDataQuery(c, `{
+				"bucketAggs": [
+					{ "id": "2", "type": "significant_terms", "field": "@crime", "settings": { "size": "5", "min_doc_count": "3" } }
+				],
+				"metrics": [{"type": "count", "id": "1" }]
+			}`, from, to)
+			require.NoError(t, err)
+			sr := c.multisearchRequests[0].Requests[0]
+
+			firstLevel := sr.Aggs[0]
+			require.Equal(t, firstLevel.Aggregation.Type, "significant_terms")
+			significantTermsAgg := firstLevel.Aggregation.Aggregation.(*es.SignificantTermsAggregation)
+			require.Equal(t, "@crime", significantTermsAgg.Field)
+			require.Equal(t, 5, significantTermsAgg.Size)
+			require.Equal(t, 3, *significantTermsAgg.MinDocCount)
+		})
+
 		t.Run("With moving average (from frontend tests)", func(t *testing.T) {
 			c := newFakeClient()
 			_, err := executeElasticsearchDataQuery(c, `{
@@ -1802,9 +2037,11 @@ func TestSettingsCasting(t *testing.T) {
 type fakeClient struct {
 	configuredFields    es.ConfiguredFields
 	multiSearchResponse *es.MultiSearchResponse
-	multiSearchError    error
-	builder             *es.MultiSearchRequestBuilder
-	multisearchRequests []*es.MultiSearchRequest
+	// multiSearchResponses are returned in order before multiSearchResponse
+	multiSearchResponses []*es.MultiSearchResponse
+	multiSearchError     error
+	builder              *es.MultiSearchRequestBuilder
+	multisearchRequests  []*es.MultiSearchRequest
 }
 
 func newFakeClient() *fakeClient {
@@ -1827,6 +2064,11 @@ func (c *fakeClient) GetConfiguredFields() es.ConfiguredFields {
 
 func (c *fakeClient) ExecuteMultisearch(r *es.MultiSearchRequest) (*es.MultiSearchResponse, error) {
 	c.multisearchRequests = append(c.multisearchRequests, r)
+	if len(c.multiSearchResponses) > 0 {
+		res := c.multiSearchResponses[0]
+		c.multiSearchResponses = c.multiSearchResponses[1:]
+		return res, c.multiSearchError
+	}
 	return c.multiSearchResponse, c.multiSearchError
 }
 
@@ -11,12 +11,24 @@ package dataquery
 
 // Defines values for BucketAggregationType.
 const (
-	BucketAggregationTypeDateHistogram BucketAggregationType = "date_histogram"
-	BucketAggregationTypeFilters       BucketAggregationType = "filters"
-	BucketAggregationTypeGeohashGrid   BucketAggregationType = "geohash_grid"
-	BucketAggregationTypeHistogram     BucketAggregationType = "histogram"
-	BucketAggregationTypeNested        BucketAggregationType = "nested"
-	BucketAggregationTypeTerms         BucketAggregationType = "terms"
+	BucketAggregationTypeComposite        BucketAggregationType = "composite"
+	BucketAggregationTypeDateHistogram    BucketAggregationType = "date_histogram"
+	BucketAggregationTypeDateRange        BucketAggregationType = "date_range"
+	BucketAggregationTypeFilters          BucketAggregationType = "filters"
+	BucketAggregationTypeGeohashGrid      BucketAggregationType = "geohash_grid"
+	BucketAggregationTypeGeotileGrid      BucketAggregationType = "geotile_grid"
+	BucketAggregationTypeHistogram        BucketAggregationType = "histogram"
+	BucketAggregationTypeNested           BucketAggregationType = "nested"
+	BucketAggregationTypeRange            BucketAggregationType = "range"
+	BucketAggregationTypeSignificantTerms BucketAggregationType = "significant_terms"
+	BucketAggregationTypeTerms            BucketAggregationType = "terms"
+)
+
+// Defines values for CompositeSourceType.
+const (
+	CompositeSourceTypeDateHistogram CompositeSourceType = "date_histogram"
+	CompositeSourceTypeHistogram     CompositeSourceType = "histogram"
+	CompositeSourceTypeTerms         CompositeSourceType = "terms"
 )
 
 // Defines values for ExtendedStatMetaType.
@@ -143,6 +155,37 @@ type BucketScript struct {
 	Type MetricAggregationType `json:"type"`
 }
 
+// Composite defines model for Composite.
+type Composite struct {
+	BucketAggregationWithField
+	Id       string                `json:"id"`
+	Settings *any                  `json:"settings,omitempty"`
+	Type     BucketAggregationType `json:"type"`
+}
+
+// CompositeSettings defines model for CompositeSettings.
+type CompositeSettings struct {
+	// Maximum number of pages requested using the after key of the previous page
+	MaxPages *string           `json:"maxPages,omitempty"`
+	Size     *string           `json:"size,omitempty"`
+	Sources  []CompositeSource `json:"sources,omitempty"`
+}
+
+// CompositeSource defines model for CompositeSource.
+type CompositeSource struct {
+	Field         string  `json:"field"`
+	Interval      *string `json:"interval,omitempty"`
+	MissingBucket *bool   `json:"missing_bucket,omitempty"`
+
+	// Name of the source in the bucket keys, defaults to the field
+	Name  *string              `json:"name,omitempty"`
+	Order *TermsOrder          `json:"order,omitempty"`
+	Type  *CompositeSourceType `json:"type,omitempty"`
+}
+
+// CompositeSourceType defines model for CompositeSourceType.
+type CompositeSourceType string
+
 // Count defines model for Count.
 type Count struct {
 	BaseMetricAggregation
@@ -202,6 +245,21 @@ type DateHistogramSettings struct {
 	TrimEdges   *string `json:"trimEdges,omitempty"`
 }
 
+// DateRange defines model for DateRange.
+type DateRange struct {
+	BucketAggregationWithField
+	Id       string                `json:"id"`
+	Settings *any                  `json:"settings,omitempty"`
+	Type     BucketAggregationType `json:"type"`
+}
+
+// DateRangeSettings defines model for DateRangeSettings.
+type DateRangeSettings struct {
+	Format   *string       `json:"format,omitempty"`
+	Ranges   []RangeBucket `json:"ranges,omitempty"`
+	TimeZone *string       `json:"timeZone,omitempty"`
+}
+
 // Derivative defines model for Derivative.
 type Derivative struct {
 	BasePipelineMetricAggregation
@@ -292,6 +350,19 @@ type GeoHashGridSettings struct {
 	Precision *string `json:"precision,omitempty"`
 }
 
+// GeoTileGrid defines model for GeoTileGrid.
+type GeoTileGrid struct {
+	BucketAggregationWithField
+	Id       string                `json:"id"`
+	Settings *any                  `json:"settings,omitempty"`
+	Type     BucketAggregationType `json:"type"`
+}
+
+// GeoTileGridSettings defines model for GeoTileGridSettings.
+type GeoTileGridSettings struct {
+	Precision *string `json:"precision,omitempty"`
+}
+
 // Histogram defines model for Histogram.
 type Histogram struct {
 	BucketAggregationWithField
@@ -493,6 +564,26 @@ type PipelineVariable struct {
 	PipelineAgg string `json:"pipelineAgg"`
 }
 
+// Range defines model for Range.
+type Range struct {
+	BucketAggregationWithField
+	Id       string                `json:"id"`
+	Settings *any                  `json:"settings,omitempty"`
+	Type     BucketAggregationType `json:"type"`
+}
+
+// RangeBucket defines model for RangeBucket.
+type RangeBucket struct {
+	From *string `json:"from,omitempty"`
+	Key  *string `json:"key,omitempty"`
+	To   *string `json:"to,omitempty"`
+}
+
+// RangeSettings defines model for RangeSettings.
+type RangeSettings struct {
+	Ranges []RangeBucket `json:"ranges,omitempty"`
+}
+
 // Rate defines model for Rate.
 type Rate struct {
 	MetricAggregationWithField
@@ -538,6 +629,20 @@ type SerialDiff struct {
 	Type MetricAggregationType `json:"type"`
 }
 
+// SignificantTerms defines model for SignificantTerms.
+type SignificantTerms struct {
+	BucketAggregationWithField
+	Id       string                `json:"id"`
+	Settings *any                  `json:"settings,omitempty"`
+	Type     BucketAggregationType `json:"type"`
+}
+
+// SignificantTermsSettings defines model for SignificantTermsSettings.
+type SignificantTermsSettings struct {
+	MinDocCount *string `json:"min_doc_count,omitempty"`
+	Size        *string `json:"size,omitempty"`
+}
+
 // Sum defines model for Sum.
 type Sum struct {
 	MetricAggregationWithField
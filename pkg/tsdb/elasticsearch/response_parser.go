@@ -32,12 +32,17 @@ const (
 	extendedStatsType = "extended_stats"
 	topMetricsType    = "top_metrics"
 	// Bucket types
-	dateHistType    = "date_histogram"
-	nestedType      = "nested"
-	histogramType   = "histogram"
-	filtersType     = "filters"
-	termsType       = "terms"
-	geohashGridType = "geohash_grid"
+	dateHistType         = "date_histogram"
+	nestedType           = "nested"
+	histogramType        = "histogram"
+	filtersType          = "filters"
+	termsType            = "terms"
+	geohashGridType      = "geohash_grid"
+	geotileGridType      = "geotile_grid"
+	compositeType        = "composite"
+	rangeType            = "range"
+	dateRangeType        = "date_range"
+	significantTermsType = "significant_terms"
 	//  Document types
 	rawDocumentType = "raw_document"
 	rawDataType     = "raw_data"
@@ -439,7 +444,11 @@ func processBuckets(aggs map[string]interface{}, target *Query,
 					newProps[k] = v
 				}
 
-				if key, err := bucket.Get("key").String(); err == nil {
+				if aggDef.Type == compositeType {
+					for _, source := range getCompositeSources(aggDef) {
+						newProps[source.Name] = getCompositeKeyValue(bucket, source.Name)
+					}
+				} else if key, err := bucket.Get("key").String(); err == nil {
 					newProps[aggDef.Field] = key
 				} else if key, err := bucket.Get("key").Int64(); err == nil {
 					newProps[aggDef.Field] = strconv.FormatInt(key, 10)
@@ -753,7 +762,6 @@ func processAggregationDocs(esAgg *simplejson.Json, aggDef *BucketAgg, target *Q
 		bucket := simplejson.NewFromAny(v)
 		var values []interface{}
 
-		found := false
 		for _, field := range fields {
 			for _, propKey := range propKeys {
 				if field.Name == propKey {
@@ -761,34 +769,17 @@ func processAggregationDocs(esAgg *simplejson.Json, aggDef *BucketAgg, target *Q
 					field.Append(&value)
 				}
 			}
-			if field.Name == aggDef.Field {
-				found = true
-				if key, err := bucket.Get("key").String(); err == nil {
-					field.Append(&key)
-				} else {
-					f, err := bucket.Get("key").Float64()
-					if err != nil {
-						return fmt.Errorf("error appending bucket key to existing field with name %s: %w", field.Name, err)
-					}
-					field.Append(&f)
-				}
-			}
 		}
 
-		if !found {
-			var aggDefField *data.Field
-			if key, err := bucket.Get("key").String(); err == nil {
-				aggDefField = extractDataField(aggDef.Field, &key)
-				aggDefField.Append(&key)
-			} else {
-				f, err := bucket.Get("key").Float64()
-				if err != nil {
-					return fmt.Errorf("error appending bucket key to new field with name %s: %w", aggDef.Field, err)
+		if aggDef.Type == compositeType {
+			// the key of a composite bucket has a value for every source, which is added as a separate field
+			for _, source := range getCompositeSources(aggDef) {
+				if err := appendBucketKey(&fields, source.Name, bucket.GetPath("key", source.Name), true); err != nil {
+					return err
 				}
-				aggDefField = extractDataField(aggDef.Field, &f)
-				aggDefField.Append(&f)
 			}
-			fields = append(fields, aggDefField)
+		} else if err := appendBucketKey(&fields, aggDef.Field, bucket.Get("key"), false); err != nil {
+			return err
 		}
 
 		for _, metric := range target.Metrics {
@@ -818,6 +809,66 @@ func processAggregationDocs(esAgg *simplejson.Json, aggDef *BucketAgg, target *Q
 	return nil
 }
 
+// appendBucketKey appends the key of a bucket to the field with the given name, the field is created if it does not exist.
+// If allowNull is true, a null key is appended as a null value, which is the case for the missing bucket of composite sources.
+func appendBucketKey(fields *[]*data.Field, name string, key *simplejson.Json, allowNull bool) error {
+	var field *data.Field
+	for _, f := range *fields {
+		if f.Name == name {
+			field = f
+			break
+		}
+	}
+
+	if allowNull && key.Interface() == nil {
+		if field == nil {
+			field = extractDataField(name, (*string)(nil))
+			*fields = append(*fields, field)
+		}
+		field.Extend(1)
+		return nil
+	}
+
+	var value interface{}
+	if k, err := key.String(); err == nil {
+		value = &k
+	} else if f, err := key.Float64(); err == nil {
+		value = &f
+	} else {
+		return fmt.Errorf("error appending bucket key to field with name %s: %w", name, err)
+	}
+
+	if field == nil {
+		field = extractDataField(name, value)
+		*fields = append(*fields, field)
+	} else if field.Type() != extractDataField(name, value).Type() {
+		// the field was created by the null keys of the missing bucket, which sorts first, before the
+		// type of the keys was known, so it is recreated with the type of the key and the nulls backfilled
+		for i := 0; i < field.Len(); i++ {
+			if _, ok := field.ConcreteAt(i); ok {
+				return fmt.Errorf("error appending bucket key to field with name %s: keys have different types", name)
+			}
+		}
+		nulls := field.Len()
+		*field = *extractDataField(name, value)
+		field.Extend(nulls)
+	}
+	field.Append(value)
+	return nil
+}
+
+// getCompositeKeyValue returns the value of a source in the key of a composite bucket as a string.
+func getCompositeKeyValue(bucket *simplejson.Json, sourceName string) string {
+	key := bucket.GetPath("key", sourceName)
+	if value, err := key.String(); err == nil {
+		return value
+	}
+	if value, err := key.Float64(); err == nil {
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	return ""
+}
+
 func extractDataField(name string, v interface{}) *data.Field {
 	var field *data.Field
 	switch v.(type) {
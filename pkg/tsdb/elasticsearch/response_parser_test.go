@@ -3165,6 +3165,239 @@ func TestProcessBuckets(t *testing.T) {
 			requireNumberValue(t, 12, frames[2], 1)
 		})
 	})
+
+	t.Run("Composite", func(t *testing.T) {
+		t.Run("Composite sources become labels of time series", func(t *testing.T) {
+			targets := map[string]string{
+				"A": `{
+					"metrics": [{ "type": "count", "id": "1" }],
+					"bucketAggs": [
+						{ "type": "composite", "id": "2", "settings": { "sources": [{ "field": "host" }, { "name": "code", "field": "status" }] } },
+						{ "type": "date_histogram", "field": "@timestamp", "id": "3" }
+					]
+				}`,
+			}
+			response := `{
+				"responses": [
+					{
+						"aggregations": {
+							"2": {
+								"after_key": { "host": "server2", "code": 500 },
+								"buckets": [
+									{
+										"3": { "buckets": [{ "doc_count": 1, "key": 1000 }, { "doc_count": 3, "key": 2000 }] },
+										"doc_count": 4,
+										"key": { "host": "server1", "code": 200 }
+									},
+									{
+										"3": { "buckets": [{ "doc_count": 2, "key": 1000 }, { "doc_count": 8, "key": 2000 }] },
+										"doc_count": 10,
+										"key": { "host": "server2", "code": 500 }
+									}
+								]
+							}
+						}
+					}
+				]
+			}`
+			result, err := parseTestResponse(targets, response, true)
+			require.NoError(t, err)
+
+			frames := result.Responses["A"].Frames
+			require.Len(t, frames, 2)
+			require.Equal(t, data.Labels{"host": "server1", "code": "200"}, frames[0].Fields[1].Labels)
+			require.Equal(t, data.Labels{"host": "server2", "code": "500"}, frames[1].Fields[1].Labels)
+			requireNumberValue(t, 8, frames[1], 1)
+		})
+
+		t.Run("Composite sources become fields of a table", func(t *testing.T) {
+			targets := map[string]string{
+				"A": `{
+					"metrics": [{ "type": "count", "id": "1" }, { "type": "avg", "field": "@value", "id": "4" }],
+					"bucketAggs": [
+						{ "type": "composite", "id": "2", "settings": { "sources": [{ "field": "host" }, { "name": "code", "field": "status", "missing_bucket": true }] } }
+					]
+				}`,
+			}
+			response := `{
+				"responses": [
+					{
+						"aggregations": {
+							"2": {
+								"buckets": [
+									{ "4": { "value": 10 }, "doc_count": 4, "key": { "host": "server1", "code": 200 } },
+									{ "4": { "value": 20 }, "doc_count": 10, "key": { "host": "server2", "code": null } }
+								]
+							}
+						}
+					}
+				]
+			}`
+			result, err := parseTestResponse(targets, response, false)
+			require.NoError(t, err)
+
+			frames := result.Responses["A"].Frames
+			require.Len(t, frames, 1)
+			frame := frames[0]
+			require.Len(t, frame.Fields, 4)
+			require.Equal(t, "host", frame.Fields[0].Name)
+			requireStringAt(t, "server1", frame.Fields[0], 0)
+			requireStringAt(t, "server2", frame.Fields[0], 1)
+			require.Equal(t, "code", frame.Fields[1].Name)
+			requireFloatAt(t, 200, frame.Fields[1], 0)
+			require.Nil(t, frame.Fields[1].At(1))
+			require.Equal(t, "Count", frame.Fields[2].Name)
+			requireFloatAt(t, 10, frame.Fields[2], 1)
+			require.Equal(t, "Average", frame.Fields[3].Name)
+			requireFloatAt(t, 20, frame.Fields[3], 1)
+		})
+		t.Run("Null keys of the missing bucket before numeric keys are backfilled", func(t *testing.T) {
+			targets := map[string]string{
+				"A": `{
+					"metrics": [{ "type": "count", "id": "1" }],
+					"bucketAggs": [
+						{ "type": "composite", "id": "2", "settings": { "sources": [{ "name": "code", "field": "status", "missing_bucket": true }] } }
+					]
+				}`,
+			}
+			response := `{
+				"responses": [
+					{
+						"aggregations": {
+							"2": {
+								"buckets": [
+									{ "doc_count": 1, "key": { "code": null } },
+									{ "doc_count": 4, "key": { "code": 200 } },
+									{ "doc_count": 2, "key": { "code": 500 } }
+								]
+							}
+						}
+					}
+				]
+			}`
+			result, err := parseTestResponse(targets, response, false)
+			require.NoError(t, err)
+
+			frames := result.Responses["A"].Frames
+			require.Len(t, frames, 1)
+			field := frames[0].Fields[0]
+			require.Equal(t, "code", field.Name)
+			require.Equal(t, data.FieldTypeNullableFloat64, field.Type())
+			require.Equal(t, 3, field.Len())
+			require.Nil(t, field.At(0))
+			requireFloatAt(t, 200, field, 1)
+			requireFloatAt(t, 500, field, 2)
+			requireFloatAt(t, 1, frames[0].Fields[1], 0)
+		})
+	})
+
+	t.Run("Range", func(t *testing.T) {
+		t.Run("Range keys become a field of a table", func(t *testing.T) {
+			targets := map[string]string{
+				"A": `{
+					"metrics": [{ "type": "count", "id": "1" }],
+					"bucketAggs": [
+						{ "type": "range", "field": "bytes", "id": "2", "settings": { "ranges": [{ "to": "100" }, { "from": "100" }] } }
+					]
+				}`,
+			}
+			response := `{
+				"responses": [
+					{
+						"aggregations": {
+							"2": {
+								"buckets": [
+									{ "key": "*-100.0", "to": 100, "doc_count": 3 },
+									{ "key": "100.0-*", "from": 100, "doc_count": 5 }
+								]
+							}
+						}
+					}
+				]
+			}`
+			result, err := parseTestResponse(targets, response, false)
+			require.NoError(t, err)
+
+			frames := result.Responses["A"].Frames
+			require.Len(t, frames, 1)
+			require.Len(t, frames[0].Fields, 2)
+			require.Equal(t, "bytes", frames[0].Fields[0].Name)
+			requireStringAt(t, "*-100.0", frames[0].Fields[0], 0)
+			requireStringAt(t, "100.0-*", frames[0].Fields[0], 1)
+			requireFloatAt(t, 5, frames[0].Fields[1], 1)
+		})
+
+		t.Run("Date range keys become labels of time series", func(t *testing.T) {
+			targets := map[string]string{
+				"A": `{
+					"metrics": [{ "type": "count", "id": "1" }],
+					"bucketAggs": [
+						{ "type": "date_range", "field": "@timestamp", "id": "2", "settings": { "ranges": [{ "from": "now-1d", "key": "today" }] } },
+						{ "type": "date_histogram", "field": "@timestamp", "id": "3" }
+					]
+				}`,
+			}
+			response := `{
+				"responses": [
+					{
+						"aggregations": {
+							"2": {
+								"buckets": [
+									{
+										"3": { "buckets": [{ "doc_count": 1, "key": 1000 }] },
+										"key": "today",
+										"from": 1000,
+										"doc_count": 1
+									}
+								]
+							}
+						}
+					}
+				]
+			}`
+			result, err := parseTestResponse(targets, response, true)
+			require.NoError(t, err)
+
+			frames := result.Responses["A"].Frames
+			require.Len(t, frames, 1)
+			require.Equal(t, data.Labels{"@timestamp": "today"}, frames[0].Fields[1].Labels)
+		})
+	})
+
+	t.Run("Significant terms and geo tile grid", func(t *testing.T) {
+		for _, aggType := range []string{"significant_terms", "geotile_grid"} {
+			t.Run(aggType, func(t *testing.T) {
+				targets := map[string]string{
+					"A": fmt.Sprintf(`{
+						"metrics": [{ "type": "count", "id": "1" }],
+						"bucketAggs": [{ "type": %q, "field": "location", "id": "2" }]
+					}`, aggType),
+				}
+				response := `{
+					"responses": [
+						{
+							"aggregations": {
+								"2": {
+									"buckets": [
+										{ "key": "7/64/42", "doc_count": 3, "score": 0.5, "bg_count": 10 },
+										{ "key": "7/64/43", "doc_count": 5, "score": 0.2, "bg_count": 50 }
+									]
+								}
+							}
+						}
+					]
+				}`
+				result, err := parseTestResponse(targets, response, false)
+				require.NoError(t, err)
+
+				frames := result.Responses["A"].Frames
+				require.Len(t, frames, 1)
+				require.Equal(t, "location", frames[0].Fields[0].Name)
+				requireStringAt(t, "7/64/43", frames[0].Fields[0], 1)
+				requireFloatAt(t, 5, frames[0].Fields[1], 1)
+			})
+		}
+	})
 }
 
 func TestParseResponse(t *testing.T) {
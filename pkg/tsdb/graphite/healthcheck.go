@@ -0,0 +1,47 @@
+package graphite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+const healthCheckRefID = "__healthcheck__"
+
+// CheckHealth renders a constant line for the last hour, the same way the data source
+// configuration page used to test the connection from the browser.
+func (s *Service) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+	logger := logger.FromContext(ctx)
+
+	model, err := json.Marshal(map[string]string{TargetModelField: "constantLine(100)"})
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	resp, err := s.QueryData(ctx, &backend.QueryDataRequest{
+		PluginContext: req.PluginContext,
+		Queries: []backend.DataQuery{{
+			RefID:     healthCheckRefID,
+			TimeRange: backend.TimeRange{From: now.Add(-time.Hour), To: now},
+			JSON:      model,
+		}},
+	})
+	if err == nil {
+		err = resp.Responses[healthCheckRefID].Error
+	}
+	if err != nil {
+		logger.Warn("Graphite health check failed", "error", err)
+		return &backend.CheckHealthResult{
+			Status:  backend.HealthStatusError,
+			Message: fmt.Sprintf("Graphite health check failed: %s", err),
+		}, nil
+	}
+
+	return &backend.CheckHealthResult{
+		Status:  backend.HealthStatusOk,
+		Message: "Data source is working",
+	}, nil
+}
@@ -0,0 +1,232 @@
+package graphite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// infinityDefaultExp matches the invalid JSON returned by the /functions endpoint of Graphite 1.1.7,
+// see https://github.com/graphite-project/graphite-web/issues/2609
+var infinityDefaultExp = regexp.MustCompile(`"default": ?Infinity`)
+
+type processResponse func(body []byte) ([]byte, error)
+
+func (s *Service) newResourceMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics/find", s.handleResourceReq(s.handleMetricsFind))
+	mux.HandleFunc("/tags/autoComplete/tags", s.handleResourceReq(s.handleTagsAutoComplete))
+	mux.HandleFunc("/tags/autoComplete/values", s.handleResourceReq(s.handleTagValuesAutoComplete))
+	mux.HandleFunc("/functions", s.handleResourceReq(s.handleFunctions))
+	return mux
+}
+
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	return s.resourceHandler.CallResource(ctx, req, sender)
+}
+
+func (s *Service) handleResourceReq(handlerFn func(rw http.ResponseWriter, req *http.Request, dsInfo *datasourceInfo)) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			writeResponse(rw, http.StatusMethodNotAllowed, fmt.Sprintf("method %s is not allowed", req.Method))
+			return
+		}
+
+		dsInfo, err := s.getDSInfo(req.Context(), backend.PluginConfigFromContext(req.Context()))
+		if err != nil {
+			logger.FromContext(req.Context()).Error("Failed to get data source info", "error", err)
+			writeResponse(rw, http.StatusInternalServerError, "failed to get data source info")
+			return
+		}
+		handlerFn(rw, req, dsInfo)
+	}
+}
+
+func (s *Service) handleMetricsFind(rw http.ResponseWriter, req *http.Request, dsInfo *datasourceInfo) {
+	query := req.URL.Query()
+	if query.Get("query") == "" {
+		writeResponse(rw, http.StatusBadRequest, `missing required parameter "query"`)
+		return
+	}
+	// the query is sent in the body, it can be too long for the URL
+	params := url.Values{"query": query["query"]}
+	copyParams(params, query, "from", "until")
+	s.getResources(rw, req, dsInfo, http.MethodPost, "metrics/find", params, processMetricsFind)
+}
+
+func (s *Service) handleTagsAutoComplete(rw http.ResponseWriter, req *http.Request, dsInfo *datasourceInfo) {
+	params := url.Values{}
+	copyParams(params, req.URL.Query(), "expr", "tagPrefix", "limit", "from", "until")
+	s.getResources(rw, req, dsInfo, http.MethodGet, "tags/autoComplete/tags", params, processTagsAutoComplete)
+}
+
+func (s *Service) handleTagValuesAutoComplete(rw http.ResponseWriter, req *http.Request, dsInfo *datasourceInfo) {
+	query := req.URL.Query()
+	if query.Get("tag") == "" {
+		writeResponse(rw, http.StatusBadRequest, `missing required parameter "tag"`)
+		return
+	}
+	params := url.Values{}
+	copyParams(params, query, "expr", "tag", "valuePrefix", "limit", "from", "until")
+	s.getResources(rw, req, dsInfo, http.MethodGet, "tags/autoComplete/values", params, processTagsAutoComplete)
+}
+
+func (s *Service) handleFunctions(rw http.ResponseWriter, req *http.Request, dsInfo *datasourceInfo) {
+	s.getResources(rw, req, dsInfo, http.MethodGet, "functions", url.Values{}, processFunctions)
+}
+
+// copyParams copies the query parameters with the names, other parameters are not sent to Graphite.
+func copyParams(dst, src url.Values, names ...string) {
+	for _, name := range names {
+		if values, ok := src[name]; ok {
+			dst[name] = values
+		}
+	}
+}
+
+// getResources calls Graphite and writes its response, converted by responseFn if the call succeeds.
+func (s *Service) getResources(rw http.ResponseWriter, req *http.Request, dsInfo *datasourceInfo, method, graphitePath string, params url.Values, responseFn processResponse) {
+	logger := logger.FromContext(req.Context())
+
+	body, code, err := s.doResourceRequest(req.Context(), dsInfo, method, graphitePath, params)
+	if err != nil {
+		logger.Error("Failed resource call from graphite", "error", err, "path", graphitePath)
+		writeResponse(rw, http.StatusBadGateway, fmt.Sprintf("failed to call graphite: %v", err))
+		return
+	}
+	if code/100 != 2 {
+		logger.Info("Graphite resource request failed", "status", code, "path", graphitePath, "body", string(body))
+		writeResponseBytes(rw, code, body)
+		return
+	}
+
+	body, err = responseFn(body)
+	if err != nil {
+		logger.Error("Failed to process graphite response", "error", err, "path", graphitePath)
+		writeResponse(rw, http.StatusInternalServerError, fmt.Sprintf("failed to process graphite response: %v", err))
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	writeResponseBytes(rw, http.StatusOK, body)
+}
+
+func (s *Service) doResourceRequest(ctx context.Context, dsInfo *datasourceInfo, method, graphitePath string, params url.Values) ([]byte, int, error) {
+	u, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		return nil, 0, err
+	}
+	u.Path = path.Join(u.Path, graphitePath)
+
+	var req *http.Request
+	if method == http.MethodPost {
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, u.String(), strings.NewReader(params.Encode()))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	} else {
+		u.RawQuery = params.Encode()
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ctx, span := s.tracer.Start(ctx, "graphite resource")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("path", graphitePath),
+		attribute.Int64("datasource_id", dsInfo.Id),
+	)
+	s.tracer.Inject(ctx, req.Header, span)
+
+	res, err := dsInfo.HTTPClient.Do(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, 0, err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+	span.SetAttributes(attribute.Int("graphite.response.code", res.StatusCode))
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+	return body, res.StatusCode, nil
+}
+
+// graphiteBool reads flags that Graphite implementations return either as booleans or as 0 and 1.
+type graphiteBool bool
+
+func (b *graphiteBool) UnmarshalJSON(data []byte) error {
+	switch string(data) {
+	case "true", "1":
+		*b = true
+	case "false", "0", "null":
+		*b = false
+	default:
+		return fmt.Errorf("invalid boolean value %s", data)
+	}
+	return nil
+}
+
+type MetricsFindResult struct {
+	Text       string       `json:"text"`
+	Id         string       `json:"id,omitempty"`
+	Expandable graphiteBool `json:"expandable"`
+}
+
+func processMetricsFind(body []byte) ([]byte, error) {
+	var results []MetricsFindResult
+	if err := json.Unmarshal(body, &results); err != nil {
+		return nil, err
+	}
+	if results == nil {
+		results = []MetricsFindResult{}
+	}
+	return json.Marshal(results)
+}
+
+func processTagsAutoComplete(body []byte) ([]byte, error) {
+	var results []string
+	if err := json.Unmarshal(body, &results); err != nil {
+		return nil, err
+	}
+	if results == nil {
+		results = []string{}
+	}
+	return json.Marshal(results)
+}
+
+func processFunctions(body []byte) ([]byte, error) {
+	fixed := infinityDefaultExp.ReplaceAll(body, []byte(`"default": 1e9999`))
+	if !json.Valid(fixed) {
+		return nil, fmt.Errorf("invalid functions response")
+	}
+	return fixed, nil
+}
+
+func writeResponseBytes(rw http.ResponseWriter, code int, msg []byte) {
+	rw.WriteHeader(code)
+	if _, err := rw.Write(msg); err != nil {
+		logger.Error("Unable to write HTTP response", "error", err)
+	}
+}
+
+func writeResponse(rw http.ResponseWriter, code int, msg string) {
+	writeResponseBytes(rw, code, []byte(msg))
+}
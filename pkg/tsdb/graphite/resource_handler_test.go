@@ -0,0 +1,182 @@
+package graphite
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+func newTestService(t *testing.T, handler http.HandlerFunc) *Service {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	s := &Service{
+		im: datasource.NewInstanceManager(func(_ context.Context, _ backend.DataSourceInstanceSettings) (instancemgmt.Instance, error) {
+			return datasourceInfo{HTTPClient: srv.Client(), URL: srv.URL}, nil
+		}),
+		tracer: tracing.InitializeTracerForTest(),
+	}
+	s.resourceHandler = httpadapter.New(s.newResourceMux())
+	return s
+}
+
+func callResource(t *testing.T, s *Service, method, rawURL string) *backend.CallResourceResponse {
+	t.Helper()
+	var resp *backend.CallResourceResponse
+	path, _, _ := strings.Cut(rawURL, "?")
+	err := s.CallResource(context.Background(), &backend.CallResourceRequest{
+		PluginContext: backend.PluginContext{DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{ID: 1}},
+		Method:        method,
+		Path:          path,
+		URL:           rawURL,
+	}, backend.CallResourceResponseSenderFunc(func(r *backend.CallResourceResponse) error {
+		resp = r
+		return nil
+	}))
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+	return resp
+}
+
+func TestCallResource(t *testing.T) {
+	t.Run("metrics/find sends the query in the body and normalizes the response", func(t *testing.T) {
+		s := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/metrics/find", r.URL.Path)
+			assert.Equal(t, http.MethodPost, r.Method)
+			body, _ := io.ReadAll(r.Body)
+			assert.Equal(t, "from=-1h&query=prod.%2A", string(body))
+			_, _ = w.Write([]byte(`[{"text":"servers","id":"prod.servers","leaf":0,"expandable":1,"allowChildren":1},{"text":"cpu","id":"prod.cpu","expandable":false}]`))
+		})
+
+		resp := callResource(t, s, http.MethodGet, "metrics/find?query=prod.*&from=-1h&foo=bar")
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.JSONEq(t, `[{"text":"servers","id":"prod.servers","expandable":true},{"text":"cpu","id":"prod.cpu","expandable":false}]`, string(resp.Body))
+	})
+
+	t.Run("metrics/find requires a query", func(t *testing.T) {
+		s := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("graphite should not be called")
+		})
+
+		resp := callResource(t, s, http.MethodGet, "metrics/find")
+		require.Equal(t, http.StatusBadRequest, resp.Status)
+	})
+
+	t.Run("tags/autoComplete/tags forwards repeated expressions", func(t *testing.T) {
+		s := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/tags/autoComplete/tags", r.URL.Path)
+			assert.Equal(t, http.MethodGet, r.Method)
+			assert.Equal(t, []string{"name=a", "dc=b"}, r.URL.Query()["expr"])
+			assert.Equal(t, "d", r.URL.Query().Get("tagPrefix"))
+			_, _ = w.Write([]byte(`["dc","disk"]`))
+		})
+
+		resp := callResource(t, s, http.MethodGet, "tags/autoComplete/tags?expr=name%3Da&expr=dc%3Db&tagPrefix=d")
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.JSONEq(t, `["dc","disk"]`, string(resp.Body))
+	})
+
+	t.Run("tags/autoComplete/values requires a tag", func(t *testing.T) {
+		s := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("graphite should not be called")
+		})
+
+		resp := callResource(t, s, http.MethodGet, "tags/autoComplete/values?expr=name%3Da")
+		require.Equal(t, http.StatusBadRequest, resp.Status)
+	})
+
+	t.Run("tags/autoComplete/values", func(t *testing.T) {
+		s := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/tags/autoComplete/values", r.URL.Path)
+			assert.Equal(t, "dc", r.URL.Query().Get("tag"))
+			_, _ = w.Write([]byte(`null`))
+		})
+
+		resp := callResource(t, s, http.MethodGet, "tags/autoComplete/values?tag=dc")
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.JSONEq(t, `[]`, string(resp.Body))
+	})
+
+	t.Run("functions fixes infinite defaults", func(t *testing.T) {
+		s := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/functions", r.URL.Path)
+			_, _ = w.Write([]byte(`{"removeAboveValue":{"params":[{"name":"n","default": Infinity}]}}`))
+		})
+
+		resp := callResource(t, s, http.MethodGet, "functions")
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.Equal(t, `{"removeAboveValue":{"params":[{"name":"n","default": 1e9999}]}}`, string(resp.Body))
+	})
+
+	t.Run("graphite errors are returned", func(t *testing.T) {
+		s := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("boom"))
+		})
+
+		resp := callResource(t, s, http.MethodGet, "functions")
+		require.Equal(t, http.StatusInternalServerError, resp.Status)
+		require.Equal(t, "boom", string(resp.Body))
+	})
+
+	t.Run("only GET is allowed", func(t *testing.T) {
+		s := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("graphite should not be called")
+		})
+
+		resp := callResource(t, s, http.MethodPost, "functions")
+		require.Equal(t, http.StatusMethodNotAllowed, resp.Status)
+	})
+
+	t.Run("unknown paths are not forwarded", func(t *testing.T) {
+		s := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("graphite should not be called")
+		})
+
+		resp := callResource(t, s, http.MethodGet, "render")
+		require.Equal(t, http.StatusNotFound, resp.Status)
+	})
+}
+
+func TestCheckHealth(t *testing.T) {
+	t.Run("ok when graphite renders the test target", func(t *testing.T) {
+		s := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/render", r.URL.Path)
+			require.NoError(t, r.ParseForm())
+			assert.Contains(t, r.PostForm.Get("target"), "constantLine(100)")
+			_, _ = w.Write([]byte(`[{"target":"constantLine(100) __healthcheck__","datapoints":[[100,1]]}]`))
+		})
+
+		res, err := s.CheckHealth(context.Background(), &backend.CheckHealthRequest{
+			PluginContext: backend.PluginContext{DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{ID: 1}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, backend.HealthStatusOk, res.Status)
+	})
+
+	t.Run("error when graphite fails", func(t *testing.T) {
+		s := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		})
+
+		res, err := s.CheckHealth(context.Background(), &backend.CheckHealthRequest{
+			PluginContext: backend.PluginContext{DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{ID: 1}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, backend.HealthStatusError, res.Status)
+		require.Contains(t, res.Message, "401")
+	})
+}
@@ -0,0 +1,41 @@
+package opentsdb
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+// CheckHealth requests metric name suggestions, the same way the data source
+// configuration page used to test the connection from the browser.
+func (s *Service) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+	logger := logger.FromContext(ctx)
+
+	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("type", "metrics")
+	params.Set("q", "cpu")
+	params.Set("max", "1")
+	_, code, err := s.doResourceRequest(ctx, dsInfo, "api/suggest", params)
+	if err == nil && code/100 != 2 {
+		err = fmt.Errorf("request failed, status: %d", code)
+	}
+	if err != nil {
+		logger.Warn("OpenTSDB health check failed", "error", err)
+		return &backend.CheckHealthResult{
+			Status:  backend.HealthStatusError,
+			Message: fmt.Sprintf("OpenTSDB health check failed: %s", err),
+		}, nil
+	}
+
+	return &backend.CheckHealthResult{
+		Status:  backend.HealthStatusOk,
+		Message: "Data source is working",
+	}, nil
+}
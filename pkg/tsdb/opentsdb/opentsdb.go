@@ -8,12 +8,14 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
@@ -25,13 +27,22 @@ import (
 var logger = log.New("tsdb.opentsdb")
 
 type Service struct {
-	im instancemgmt.InstanceManager
+	im              instancemgmt.InstanceManager
+	resourceHandler backend.CallResourceHandler
 }
 
+var (
+	_ backend.QueryDataHandler    = (*Service)(nil)
+	_ backend.CheckHealthHandler  = (*Service)(nil)
+	_ backend.CallResourceHandler = (*Service)(nil)
+)
+
 func ProvideService(httpClientProvider httpclient.Provider) *Service {
-	return &Service{
+	s := &Service{
 		im: datasource.NewInstanceManager(newInstanceSettings(httpClientProvider)),
 	}
+	s.resourceHandler = httpadapter.New(s.newResourceMux())
+	return s
 }
 
 type datasourceInfo struct {
@@ -63,18 +74,38 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 }
 
 func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
-	var tsdbQuery OpenTsdbQuery
-
 	logger := logger.FromContext(ctx)
 
-	q := req.Queries[0]
+	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		return nil, err
+	}
+
+	result := backend.NewQueryDataResponse()
+	metricQueries := make([]backend.DataQuery, 0, len(req.Queries))
+	for _, query := range req.Queries {
+		model, err := simplejson.NewJson(query.JSON)
+		if err != nil || !model.Get("fromAnnotations").MustBool() {
+			metricQueries = append(metricQueries, query)
+			continue
+		}
+		result.Responses[query.RefID] = s.queryAnnotations(ctx, logger, dsInfo, query, model)
+	}
+
+	if len(metricQueries) == 0 {
+		return result, nil
+	}
+
+	var tsdbQuery OpenTsdbQuery
+
+	q := metricQueries[0]
 
 	myRefID := q.RefID
 
 	tsdbQuery.Start = q.TimeRange.From.UnixNano() / int64(time.Millisecond)
 	tsdbQuery.End = q.TimeRange.To.UnixNano() / int64(time.Millisecond)
 
-	for _, query := range req.Queries {
+	for _, query := range metricQueries {
 		metric := s.buildMetric(query)
 		tsdbQuery.Queries = append(tsdbQuery.Queries, metric)
 	}
@@ -84,11 +115,6 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 		logger.Debug("OpenTsdb request", "params", tsdbQuery)
 	}
 
-	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
-	if err != nil {
-		return nil, err
-	}
-
 	request, err := s.createRequest(ctx, logger, dsInfo, tsdbQuery)
 	if err != nil {
 		return &backend.QueryDataResponse{}, err
@@ -106,14 +132,53 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 		}
 	}()
 
-	result, err := s.parseResponse(logger, res, myRefID)
+	metricResult, err := s.parseResponse(logger, res, myRefID)
 	if err != nil {
 		return &backend.QueryDataResponse{}, err
 	}
 
+	for refID, r := range metricResult.Responses {
+		result.Responses[refID] = r
+	}
 	return result, nil
 }
 
+// queryAnnotations queries the annotations of the metric in the target of an annotation query.
+// When isGlobal is set, the global annotations of the time range are returned instead.
+func (s *Service) queryAnnotations(ctx context.Context, logger log.Logger, dsInfo *datasourceInfo, query backend.DataQuery, model *simplejson.Json) backend.DataResponse {
+	target := model.Get("target").MustString()
+	if target == "" {
+		return backend.DataResponse{Frames: data.Frames{newAnnotationFrame(query.RefID, nil)}}
+	}
+	isGlobal := model.Get("isGlobal").MustBool()
+
+	tsdbQuery := OpenTsdbQuery{
+		Start: query.TimeRange.From.UnixNano() / int64(time.Millisecond),
+		End:   query.TimeRange.To.UnixNano() / int64(time.Millisecond),
+		Queries: []map[string]any{{
+			"aggregator": "sum",
+			"metric":     target,
+		}},
+		GlobalAnnotations: isGlobal,
+	}
+
+	request, err := s.createRequest(ctx, logger, dsInfo, tsdbQuery)
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+
+	res, err := dsInfo.HTTPClient.Do(request)
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+
+	frame, err := s.parseAnnotationResponse(logger, res, query.RefID, isGlobal)
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+	return backend.DataResponse{Frames: data.Frames{frame}}
+}
+
 func (s *Service) createRequest(ctx context.Context, logger log.Logger, dsInfo *datasourceInfo, data OpenTsdbQuery) (*http.Request, error) {
 	u, err := url.Parse(dsInfo.URL)
 	if err != nil {
@@ -193,6 +258,87 @@ func (s *Service) parseResponse(logger log.Logger, res *http.Response, myRefID s
 	return resp, nil
 }
 
+func (s *Service) parseAnnotationResponse(logger log.Logger, res *http.Response, refID string, isGlobal bool) (*data.Frame, error) {
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "err", err)
+		}
+	}()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode/100 != 2 {
+		logger.Info("Request failed", "status", res.Status, "body", string(body))
+		return nil, fmt.Errorf("request failed, status: %s", res.Status)
+	}
+
+	var responseData []OpenTsdbResponse
+	err = json.Unmarshal(body, &responseData)
+	if err != nil {
+		logger.Info("Failed to unmarshal opentsdb response", "error", err, "status", res.Status, "body", string(body))
+		return nil, err
+	}
+
+	// the annotations are returned with the first series, global annotations are repeated for every series
+	var annotations []OpenTsdbAnnotation
+	if len(responseData) > 0 {
+		annotations = responseData[0].Annotations
+		if isGlobal {
+			annotations = responseData[0].GlobalAnnotations
+		}
+	}
+	return newAnnotationFrame(refID, annotations), nil
+}
+
+// newAnnotationFrame converts OpenTSDB annotations into a frame with the time, timeEnd, text and tags fields
+// of Grafana annotations. Custom fields of an annotation are added as tags in the key:value format.
+func newAnnotationFrame(refID string, annotations []OpenTsdbAnnotation) *data.Frame {
+	times := make([]time.Time, 0, len(annotations))
+	timeEnds := make([]*time.Time, 0, len(annotations))
+	texts := make([]string, 0, len(annotations))
+	tags := make([]json.RawMessage, 0, len(annotations))
+
+	for _, a := range annotations {
+		times = append(times, secondsToTime(a.StartTime))
+		var timeEnd *time.Time
+		if a.EndTime > 0 {
+			t := secondsToTime(a.EndTime)
+			timeEnd = &t
+		}
+		timeEnds = append(timeEnds, timeEnd)
+		texts = append(texts, a.Description)
+
+		keys := make([]string, 0, len(a.Custom))
+		for k := range a.Custom {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		annotationTags := make([]string, 0, len(keys))
+		for _, k := range keys {
+			annotationTags = append(annotationTags, k+":"+a.Custom[k])
+		}
+		raw, _ := json.Marshal(annotationTags)
+		tags = append(tags, raw)
+	}
+
+	frame := data.NewFrame("annotations",
+		data.NewField("time", nil, times),
+		data.NewField("timeEnd", nil, timeEnds),
+		data.NewField("text", nil, texts),
+		data.NewField("tags", nil, tags),
+	)
+	frame.RefID = refID
+	return frame
+}
+
+// secondsToTime converts an OpenTSDB timestamp in seconds, which may have a millisecond fraction, to a time.
+func secondsToTime(seconds float64) time.Time {
+	return time.UnixMilli(int64(seconds * 1000)).UTC()
+}
+
 func (s *Service) buildMetric(query backend.DataQuery) map[string]any {
 	metric := make(map[string]any)
 
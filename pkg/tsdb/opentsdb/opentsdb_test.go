@@ -2,6 +2,7 @@ package opentsdb
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"net/http"
 	"strings"
@@ -118,6 +119,67 @@ func TestOpenTsdbExecutor(t *testing.T) {
 		}
 	})
 
+	t.Run("Parse annotation response", func(t *testing.T) {
+		response := `
+		[
+			{
+				"metric": "test",
+				"dps": [],
+				"annotations": [
+					{"description": "deploy", "startTime": 1405544146, "custom": {"version": "1.2", "env": "prod"}}
+				],
+				"globalAnnotations": [
+					{"description": "outage", "startTime": 1405544146.5, "endTime": 1405544746}
+				]
+			}
+		]`
+
+		end := time.Date(2014, 7, 16, 21, 5, 46, 0, time.UTC)
+		tests := []struct {
+			name     string
+			isGlobal bool
+			expected *data.Frame
+		}{
+			{
+				name: "annotations",
+				expected: data.NewFrame("annotations",
+					data.NewField("time", nil, []time.Time{time.Date(2014, 7, 16, 20, 55, 46, 0, time.UTC)}),
+					data.NewField("timeEnd", nil, []*time.Time{nil}),
+					data.NewField("text", nil, []string{"deploy"}),
+					data.NewField("tags", nil, []json.RawMessage{json.RawMessage(`["env:prod","version:1.2"]`)}),
+				),
+			},
+			{
+				name:     "global annotations",
+				isGlobal: true,
+				expected: data.NewFrame("annotations",
+					data.NewField("time", nil, []time.Time{time.Date(2014, 7, 16, 20, 55, 46, 500000000, time.UTC)}),
+					data.NewField("timeEnd", nil, []*time.Time{&end}),
+					data.NewField("text", nil, []string{"outage"}),
+					data.NewField("tags", nil, []json.RawMessage{json.RawMessage(`[]`)}),
+				),
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				resp := http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(response))}
+				frame, err := service.parseAnnotationResponse(logger, &resp, "A", tt.isGlobal)
+				require.NoError(t, err)
+
+				tt.expected.RefID = "A"
+				if diff := cmp.Diff(tt.expected, frame, data.FrameTestCompareOptions()...); diff != "" {
+					t.Errorf("Result mismatch (-want +got):\n%s", diff)
+				}
+			})
+		}
+	})
+
+	t.Run("Parse annotation response should handle failed requests", func(t *testing.T) {
+		resp := http.Response{StatusCode: 500, Status: "500 Internal Server Error", Body: io.NopCloser(strings.NewReader("boom"))}
+		_, err := service.parseAnnotationResponse(logger, &resp, "A", false)
+		require.ErrorContains(t, err, "request failed")
+	})
+
 	t.Run("Build metric with downsampling enabled", func(t *testing.T) {
 		query := backend.DataQuery{
 			JSON: []byte(`
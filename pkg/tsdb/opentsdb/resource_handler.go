@@ -0,0 +1,138 @@
+package opentsdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+func (s *Service) newResourceMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/suggest", s.handleResourceReq(s.handleSuggest))
+	mux.HandleFunc("/api/search/lookup", s.handleResourceReq(s.handleSearchLookup))
+	return mux
+}
+
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	return s.resourceHandler.CallResource(ctx, req, sender)
+}
+
+func (s *Service) handleResourceReq(handlerFn func(rw http.ResponseWriter, req *http.Request, dsInfo *datasourceInfo)) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			writeResponse(rw, http.StatusMethodNotAllowed, fmt.Sprintf("method %s is not allowed", req.Method))
+			return
+		}
+
+		dsInfo, err := s.getDSInfo(req.Context(), backend.PluginConfigFromContext(req.Context()))
+		if err != nil {
+			logger.FromContext(req.Context()).Error("Failed to get data source info", "error", err)
+			writeResponse(rw, http.StatusInternalServerError, "failed to get data source info")
+			return
+		}
+		handlerFn(rw, req, dsInfo)
+	}
+}
+
+func (s *Service) handleSuggest(rw http.ResponseWriter, req *http.Request, dsInfo *datasourceInfo) {
+	query := req.URL.Query()
+	if query.Get("type") == "" {
+		writeResponse(rw, http.StatusBadRequest, `missing required parameter "type"`)
+		return
+	}
+	params := url.Values{}
+	copyParams(params, query, "type", "q", "max")
+	s.getResources(rw, req, dsInfo, "api/suggest", params)
+}
+
+func (s *Service) handleSearchLookup(rw http.ResponseWriter, req *http.Request, dsInfo *datasourceInfo) {
+	query := req.URL.Query()
+	if query.Get("m") == "" {
+		writeResponse(rw, http.StatusBadRequest, `missing required parameter "m"`)
+		return
+	}
+	params := url.Values{}
+	copyParams(params, query, "m", "limit", "useMeta")
+	s.getResources(rw, req, dsInfo, "api/search/lookup", params)
+}
+
+// copyParams copies the query parameters with the names, other parameters are not sent to OpenTSDB.
+func copyParams(dst, src url.Values, names ...string) {
+	for _, name := range names {
+		if values, ok := src[name]; ok {
+			dst[name] = values
+		}
+	}
+}
+
+// getResources calls the OpenTSDB API and writes its response.
+func (s *Service) getResources(rw http.ResponseWriter, req *http.Request, dsInfo *datasourceInfo, apiPath string, params url.Values) {
+	logger := logger.FromContext(req.Context())
+
+	body, code, err := s.doResourceRequest(req.Context(), dsInfo, apiPath, params)
+	if err != nil {
+		logger.Error("Failed resource call from OpenTSDB", "error", err, "path", apiPath)
+		writeResponse(rw, http.StatusBadGateway, fmt.Sprintf("failed to call OpenTSDB: %v", err))
+		return
+	}
+	if code/100 != 2 {
+		logger.Info("OpenTSDB resource request failed", "status", code, "path", apiPath, "body", string(body))
+		writeResponseBytes(rw, code, body)
+		return
+	}
+	if !json.Valid(body) {
+		logger.Error("Invalid OpenTSDB response", "path", apiPath, "body", string(body))
+		writeResponse(rw, http.StatusInternalServerError, "invalid OpenTSDB response")
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	writeResponseBytes(rw, http.StatusOK, body)
+}
+
+func (s *Service) doResourceRequest(ctx context.Context, dsInfo *datasourceInfo, apiPath string, params url.Values) ([]byte, int, error) {
+	u, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		return nil, 0, err
+	}
+	u.Path = path.Join(u.Path, apiPath)
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	res, err := dsInfo.HTTPClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+	return body, res.StatusCode, nil
+}
+
+func writeResponseBytes(rw http.ResponseWriter, code int, msg []byte) {
+	rw.WriteHeader(code)
+	if _, err := rw.Write(msg); err != nil {
+		logger.Error("Unable to write HTTP response", "error", err)
+	}
+}
+
+func writeResponse(rw http.ResponseWriter, code int, msg string) {
+	writeResponseBytes(rw, code, []byte(msg))
+}
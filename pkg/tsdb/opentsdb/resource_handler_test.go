@@ -0,0 +1,180 @@
+package opentsdb
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testPluginContext = backend.PluginContext{DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{ID: 1}}
+
+func newTestService(t *testing.T, handler http.HandlerFunc) *Service {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	s := &Service{
+		im: datasource.NewInstanceManager(func(_ context.Context, _ backend.DataSourceInstanceSettings) (instancemgmt.Instance, error) {
+			return &datasourceInfo{HTTPClient: srv.Client(), URL: srv.URL}, nil
+		}),
+	}
+	s.resourceHandler = httpadapter.New(s.newResourceMux())
+	return s
+}
+
+func callResource(t *testing.T, s *Service, method, rawURL string) *backend.CallResourceResponse {
+	t.Helper()
+	var resp *backend.CallResourceResponse
+	path, _, _ := strings.Cut(rawURL, "?")
+	err := s.CallResource(context.Background(), &backend.CallResourceRequest{
+		PluginContext: testPluginContext,
+		Method:        method,
+		Path:          path,
+		URL:           rawURL,
+	}, backend.CallResourceResponseSenderFunc(func(r *backend.CallResourceResponse) error {
+		resp = r
+		return nil
+	}))
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+	return resp
+}
+
+func TestCallResource(t *testing.T) {
+	t.Run("suggest forwards the allowed parameters", func(t *testing.T) {
+		s := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/suggest", r.URL.Path)
+			assert.Equal(t, "max=10&q=cpu&type=metrics", r.URL.RawQuery)
+			_, _ = w.Write([]byte(`["cpu.idle","cpu.user"]`))
+		})
+
+		resp := callResource(t, s, http.MethodGet, "api/suggest?type=metrics&q=cpu&max=10&foo=bar")
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.JSONEq(t, `["cpu.idle","cpu.user"]`, string(resp.Body))
+	})
+
+	t.Run("suggest requires a type", func(t *testing.T) {
+		s := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("OpenTSDB should not be called")
+		})
+
+		resp := callResource(t, s, http.MethodGet, "api/suggest?q=cpu")
+		require.Equal(t, http.StatusBadRequest, resp.Status)
+	})
+
+	t.Run("search lookup", func(t *testing.T) {
+		s := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/search/lookup", r.URL.Path)
+			assert.Equal(t, "cpu{host=*}", r.URL.Query().Get("m"))
+			assert.Equal(t, "1000", r.URL.Query().Get("limit"))
+			_, _ = w.Write([]byte(`{"results":[{"metric":"cpu","tags":{"host":"a"}}]}`))
+		})
+
+		resp := callResource(t, s, http.MethodGet, "api/search/lookup?m=cpu%7Bhost%3D%2A%7D&limit=1000")
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.JSONEq(t, `{"results":[{"metric":"cpu","tags":{"host":"a"}}]}`, string(resp.Body))
+	})
+
+	t.Run("OpenTSDB errors are returned", func(t *testing.T) {
+		s := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":{"code":400}}`))
+		})
+
+		resp := callResource(t, s, http.MethodGet, "api/search/lookup?m=cpu")
+		require.Equal(t, http.StatusBadRequest, resp.Status)
+		require.JSONEq(t, `{"error":{"code":400}}`, string(resp.Body))
+	})
+
+	t.Run("only GET is allowed", func(t *testing.T) {
+		s := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("OpenTSDB should not be called")
+		})
+
+		resp := callResource(t, s, http.MethodPost, "api/suggest?type=metrics")
+		require.Equal(t, http.StatusMethodNotAllowed, resp.Status)
+	})
+}
+
+func TestCheckHealth(t *testing.T) {
+	t.Run("ok when OpenTSDB returns suggestions", func(t *testing.T) {
+		s := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/suggest", r.URL.Path)
+			_, _ = w.Write([]byte(`[]`))
+		})
+
+		res, err := s.CheckHealth(context.Background(), &backend.CheckHealthRequest{PluginContext: testPluginContext})
+		require.NoError(t, err)
+		require.Equal(t, backend.HealthStatusOk, res.Status)
+	})
+
+	t.Run("error when OpenTSDB fails", func(t *testing.T) {
+		s := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		res, err := s.CheckHealth(context.Background(), &backend.CheckHealthRequest{PluginContext: testPluginContext})
+		require.NoError(t, err)
+		require.Equal(t, backend.HealthStatusError, res.Status)
+		require.Contains(t, res.Message, "404")
+	})
+}
+
+func TestQueryDataAnnotations(t *testing.T) {
+	var requests []OpenTsdbQuery
+	s := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var q OpenTsdbQuery
+		require.NoError(t, json.Unmarshal(body, &q))
+		requests = append(requests, q)
+		_, _ = w.Write([]byte(`[{"metric":"events","dps":[[1405544146,1]],
+			"annotations":[{"description":"deploy","startTime":1405544146}],
+			"globalAnnotations":[{"description":"outage","startTime":1405544146}]}]`))
+	})
+
+	timeRange := backend.TimeRange{From: time.Unix(1405540000, 0), To: time.Unix(1405550000, 0)}
+	resp, err := s.QueryData(context.Background(), &backend.QueryDataRequest{
+		PluginContext: testPluginContext,
+		Queries: []backend.DataQuery{
+			{RefID: "A", TimeRange: timeRange, JSON: []byte(`{"metric":"events","aggregator":"sum","disableDownsampling":true}`)},
+			{RefID: "Anno", TimeRange: timeRange, JSON: []byte(`{"fromAnnotations":true,"target":"events"}`)},
+			{RefID: "Global", TimeRange: timeRange, JSON: []byte(`{"fromAnnotations":true,"target":"events","isGlobal":true}`)},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, requests, 3)
+
+	require.Len(t, resp.Responses, 3)
+	require.NoError(t, resp.Responses["A"].Error)
+	require.Equal(t, "events", resp.Responses["A"].Frames[0].Name)
+
+	anno := resp.Responses["Anno"]
+	require.NoError(t, anno.Error)
+	require.Equal(t, "deploy", anno.Frames[0].Fields[2].At(0))
+	require.Equal(t, "Anno", anno.Frames[0].RefID)
+
+	global := resp.Responses["Global"]
+	require.NoError(t, global.Error)
+	require.Equal(t, "outage", global.Frames[0].Fields[2].At(0))
+
+	globalRequests := 0
+	for _, q := range requests {
+		if q.GlobalAnnotations {
+			globalRequests++
+		}
+	}
+	require.Equal(t, 1, globalRequests)
+}
@@ -34,6 +34,10 @@ export enum EvalFunction {
   'IsBelow' = 'lt',
   'IsOutsideRange' = 'outside_range',
   'IsWithinRange' = 'within_range',
+  'IsAboveOrEqual' = 'gte',
+  'IsBelowOrEqual' = 'lte',
+  'IsEqual' = 'eq',
+  'IsNotEqual' = 'ne',
   'HasNoValue' = 'no_value',
 }
 
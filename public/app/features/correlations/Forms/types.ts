@@ -73,6 +73,53 @@ export function getSupportedTransTypeDetails(
           ),
         },
       };
+    case SupportedTransformationType.JSONPath:
+      return {
+        label: t('correlations.trans-details.jsonpath-label', 'JSON path'),
+        value: SupportedTransformationType.JSONPath,
+        description: t(
+          'correlations.trans-details.jsonpath-description',
+          'Field will be parsed as JSON and the value at the JSON path is added to a variable.'
+        ),
+        expressionDetails: {
+          show: true,
+          required: true,
+          helpText: t(
+            'correlations.trans-details.jsonpath-expression',
+            'JSON path of the value, for example $.user.id or $.items[0].name'
+          ),
+        },
+        mapValueDetails: {
+          show: true,
+          required: false,
+          helpText: t(
+            'correlations.trans-details.jsonpath-map-values',
+            'Defines the name of the variable. Defaults to the name of the field.'
+          ),
+        },
+      };
+    case SupportedTransformationType.Template:
+      return {
+        label: t('correlations.trans-details.template-label', 'Template'),
+        value: SupportedTransformationType.Template,
+        description: t(
+          'correlations.trans-details.template-description',
+          'Creates a variable from a template. Use ${variable} to reference the field or variables created by previous transformations.'
+        ),
+        expressionDetails: {
+          show: true,
+          required: true,
+          helpText: t(
+            'correlations.trans-details.template-expression',
+            'Template of the value, for example ${service}-${env}'
+          ),
+        },
+        mapValueDetails: {
+          show: true,
+          required: true,
+          helpText: t('correlations.trans-details.template-map-values', 'Defines the name of the variable.'),
+        },
+      };
     default:
       return {
         label: transType,
@@ -0,0 +1,59 @@
+import { SupportedTransformationType } from '@grafana/data';
+
+import { getJSONPathValue, getTransformationVars } from './transformations';
+
+describe('correlation transformations', () => {
+  describe('getJSONPathValue', () => {
+    const value = { user: { id: 'u-123', 'first name': 'John' }, items: [{ name: 'first' }] };
+
+    it.each([
+      ['$.user.id', 'u-123'],
+      ["$.user['first name']", 'John'],
+      ['$.items[0].name', 'first'],
+      ['$', value],
+      ['$.user.missing', undefined],
+      ['$.items[1].name', undefined],
+      ['user.id', undefined],
+      ['$.user[*]', undefined],
+    ])('returns the value at %s', (path, expected) => {
+      expect(getJSONPathValue(value, path)).toEqual(expected);
+    });
+  });
+
+  it('creates a variable from a json path', () => {
+    const vars = getTransformationVars(
+      { type: SupportedTransformationType.JSONPath, expression: '$.trace.id', mapValue: 'traceId' },
+      '{"trace": {"id": "abc"}}',
+      'line'
+    );
+    expect(vars).toEqual({ traceId: { value: 'abc' } });
+  });
+
+  it('maps the json path value to the field name by default', () => {
+    const vars = getTransformationVars(
+      { type: SupportedTransformationType.JSONPath, expression: '$.ids' },
+      '{"ids": [1, 2]}',
+      'line'
+    );
+    expect(vars).toEqual({ line: { value: '[1,2]' } });
+  });
+
+  it('does not create a variable if the field is not json', () => {
+    const vars = getTransformationVars(
+      { type: SupportedTransformationType.JSONPath, expression: '$.trace.id', mapValue: 'traceId' },
+      'not json',
+      'line'
+    );
+    expect(vars).toEqual({});
+  });
+
+  it('creates a variable from a template', () => {
+    const vars = getTransformationVars(
+      { type: SupportedTransformationType.Template, expression: '${service}-${env}-${missing}', mapValue: 'app' },
+      'loginService',
+      'service',
+      { env: { value: 'prod' } }
+    );
+    expect(vars).toEqual({ app: { value: 'loginService-prod-' } });
+  });
+});
@@ -3,13 +3,55 @@ import logfmt from 'logfmt';
 import { ScopedVars, DataLinkTransformationConfig, SupportedTransformationType } from '@grafana/data';
 import { safeStringifyValue } from 'app/core/utils/explore';
 
+// matches one segment of a JSON path following the root, e.g. .name, [0] or ['some key']
+const jsonPathSegment = /^(?:\.([\w-]+)|\[(\d+)\]|\['([^']+)'\]|\["([^"]+)"\])/;
+
+// matches a ${...} variable with an optional format, e.g. ${traceId:queryparam}
+const templateVariable = /\$\{([\w.-]+)(?::\w+)?\}/g;
+
+/**
+ * Returns the value at the JSON path in the object. Only the root followed by object keys and array indexes is
+ * supported, e.g. $.user.ids[0] or $['user id'].
+ */
+export const getJSONPathValue = (value: unknown, path: string): unknown => {
+  if (!path.startsWith('$')) {
+    return undefined;
+  }
+  let rest = path.slice(1);
+  let current = value;
+  while (rest.length > 0) {
+    const match = rest.match(jsonPathSegment);
+    if (!match || current === null || typeof current !== 'object') {
+      return undefined;
+    }
+    const key = match[1] ?? match[2] ?? match[3] ?? match[4];
+    current = Object.prototype.hasOwnProperty.call(current, key)
+      ? (current as Record<string, unknown>)[key]
+      : undefined;
+    rest = rest.slice(match[0].length);
+  }
+  return current;
+};
+
+const parseJSON = (fieldValue: unknown): unknown => {
+  if (typeof fieldValue !== 'string') {
+    return fieldValue;
+  }
+  try {
+    return JSON.parse(fieldValue);
+  } catch (e) {
+    return undefined;
+  }
+};
+
 export const getTransformationVars = (
   transformation: DataLinkTransformationConfig,
   fieldValue: string,
-  fieldName: string
+  fieldName: string,
+  previousVars: ScopedVars = {}
 ): ScopedVars => {
   let transformationScopedVars: ScopedVars = {};
-  let transformVal: { [key: string]: string | boolean | null | undefined } = {};
+  let transformVal: { [key: string]: unknown } = {};
   if (transformation.type === SupportedTransformationType.Regex && transformation.expression) {
     const regexp = new RegExp(transformation.expression, 'gi');
     const stringFieldVal = typeof fieldValue === 'string' ? fieldValue : safeStringifyValue(fieldValue);
@@ -24,6 +66,28 @@ export const getTransformationVars = (
     }
   } else if (transformation.type === SupportedTransformationType.Logfmt) {
     transformVal = logfmt.parse(fieldValue);
+  } else if (transformation.type === SupportedTransformationType.JSONPath && transformation.expression) {
+    const value = getJSONPathValue(parseJSON(fieldValue), transformation.expression);
+    if (value !== undefined) {
+      transformVal[transformation.mapValue || fieldName] = value;
+    }
+  } else if (
+    transformation.type === SupportedTransformationType.Template &&
+    transformation.expression &&
+    transformation.mapValue
+  ) {
+    // variables created by previous transformations take precedence over the field value
+    const vars: Record<string, unknown> = { [fieldName]: fieldValue };
+    Object.keys(previousVars).forEach((key) => {
+      vars[key] = previousVars[key]?.value;
+    });
+    transformVal[transformation.mapValue] = transformation.expression.replace(templateVariable, (match, name) => {
+      const value = vars[name];
+      if (value === undefined || value === null) {
+        return '';
+      }
+      return typeof value === 'string' ? value : safeStringifyValue(value);
+    });
   }
 
   Object.keys(transformVal).forEach((key) => {
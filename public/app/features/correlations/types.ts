@@ -26,11 +26,11 @@ export interface RemoveCorrelationResponse {
   message: string;
 }
 
-type CorrelationConfigType = 'query';
+type CorrelationConfigType = 'query' | 'external';
 
 export interface CorrelationConfig {
   field: string;
-  target: object; // this contains anything that would go in the query editor, so any extension off DataQuery a datasource would have, and needs to be generic
+  target: object; // this contains anything that would go in the query editor, so any extension off DataQuery a datasource would have, and needs to be generic. External correlations store the link in target.url
   type: CorrelationConfigType;
   transformations?: DataLinkTransformationConfig[];
 }
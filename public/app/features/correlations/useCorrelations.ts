@@ -26,7 +26,8 @@ export interface CorrelationsResponse {
 
 export interface CorrelationData extends Omit<Correlation, 'sourceUID' | 'targetUID'> {
   source: DataSourceInstanceSettings;
-  target: DataSourceInstanceSettings;
+  // undefined for external correlations, which link to a URL
+  target?: DataSourceInstanceSettings;
 }
 
 export interface CorrelationsData {
@@ -51,6 +52,10 @@ const toEnrichedCorrelationData = ({
     correlationsLogger.logWarning('Invalid correlation config: Missing org id.');
   }
 
+  if (correlation.config?.type === 'external' && sourceDatasource && sourceDatasource.uid !== undefined) {
+    return { ...correlation, source: sourceDatasource };
+  }
+
   if (
     sourceDatasource &&
     sourceDatasource?.uid !== undefined &&
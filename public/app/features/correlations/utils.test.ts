@@ -1,4 +1,11 @@
-import { DataFrame, DataSourceInstanceSettings, FieldType, toDataFrame } from '@grafana/data';
+import {
+  DataFrame,
+  DataLinkConfigOrigin,
+  DataSourceInstanceSettings,
+  FieldType,
+  SupportedTransformationType,
+  toDataFrame,
+} from '@grafana/data';
 
 import { CorrelationData } from './useCorrelations';
 import { attachCorrelationsToDataFrames } from './utils';
@@ -68,6 +75,38 @@ describe('correlations utils', () => {
     // Prometheus value (linked to Elastic)
     expect(testDataFrames[2].fields[0].config.links).toHaveLength(1);
   });
+
+  it('attaches external correlations as url links', () => {
+    const { testDataFrames, refIdMap, loki } = setup();
+    const transformations = [
+      { type: SupportedTransformationType.JSONPath, expression: '$.trace.id', mapValue: 'traceId' },
+    ];
+    const correlations: CorrelationData[] = [
+      {
+        uid: 'loki-to-tickets',
+        label: 'logs to tickets',
+        source: loki,
+        config: {
+          type: 'external',
+          field: 'line',
+          target: { url: 'https://tickets.example.com/search?q=${traceId}' },
+          transformations,
+        },
+        provisioned: false,
+      },
+    ];
+    attachCorrelationsToDataFrames(testDataFrames, correlations, refIdMap);
+
+    expect(testDataFrames[0].fields[0].config.links).toEqual([
+      {
+        url: 'https://tickets.example.com/search?q=${traceId}',
+        targetBlank: true,
+        title: 'logs to tickets',
+        transformations,
+        origin: DataLinkConfigOrigin.Correlations,
+      },
+    ]);
+  });
 });
 
 function setup() {
@@ -133,5 +172,5 @@ function setup() {
     },
   ];
 
-  return { testDataFrames, correlations, refIdMap, prometheus, elastic };
+  return { testDataFrames, correlations, refIdMap, loki, prometheus, elastic };
 }
@@ -53,7 +53,21 @@ const decorateDataFrameWithInternalDataLinks = (dataFrame: DataFrame, correlatio
   dataFrame.fields.forEach((field) => {
     field.config.links = field.config.links?.filter((link) => link.origin !== DataLinkConfigOrigin.Correlations) || [];
     correlations.map((correlation) => {
-      if (correlation.config?.field === field.name) {
+      if (correlation.config?.field !== field.name) {
+        return;
+      }
+      if (correlation.config.type === 'external' || !correlation.target) {
+        const { url } = (correlation.config.target || {}) as { url?: string };
+        if (url) {
+          field.config.links!.push({
+            url,
+            targetBlank: true,
+            title: correlation.label || url,
+            transformations: correlation.config.transformations,
+            origin: DataLinkConfigOrigin.Correlations,
+          });
+        }
+      } else {
         const targetQuery = correlation.config?.target || {};
         field.config.links!.push({
           internal: {
@@ -17,6 +17,7 @@ import {
   CoreApp,
   SplitOpenOptions,
   DataLinkPostProcessor,
+  DataLinkTransformationConfig,
   ExploreUrlState,
   urlUtil,
 } from '@grafana/data';
@@ -160,8 +161,11 @@ export const getFieldLinksForExplore = (options: {
 
     const fieldLinks = links.map((link) => {
       if (!link.internal) {
-        const replace: InterpolateFunction = (value, vars) =>
-          getTemplateSrv().replace(value, { ...vars, ...scopedVars });
+        const linkVars = {
+          ...scopedVars,
+          ...getLinkTransformationVars(link.transformations, field, rowIndex, dataFrame),
+        };
+        const replace: InterpolateFunction = (value, vars) => getTemplateSrv().replace(value, { ...vars, ...linkVars });
 
         const linkModel = getLinkSrv().getDataLinkUIModel(link, replace, field);
         if (!linkModel.title) {
@@ -169,23 +173,12 @@ export const getFieldLinksForExplore = (options: {
         }
         return linkModel;
       } else {
-        let internalLinkSpecificVars: ScopedVars = {};
-        if (link.internal?.transformations) {
-          link.internal?.transformations.forEach((transformation) => {
-            let fieldValue;
-            if (transformation.field) {
-              const transformField = dataFrame?.fields.find((field) => field.name === transformation.field);
-              fieldValue = transformField?.values[rowIndex];
-            } else {
-              fieldValue = field.values[rowIndex];
-            }
-
-            internalLinkSpecificVars = {
-              ...internalLinkSpecificVars,
-              ...getTransformationVars(transformation, fieldValue, field.name),
-            };
-          });
-        }
+        const internalLinkSpecificVars = getLinkTransformationVars(
+          link.internal?.transformations,
+          field,
+          rowIndex,
+          dataFrame
+        );
 
         const allVars = { ...scopedVars, ...internalLinkSpecificVars };
         const variableData = getVariableUsageInfo(link, allVars);
@@ -231,6 +224,34 @@ export const getFieldLinksForExplore = (options: {
   return [];
 };
 
+/**
+ * Applies the transformations of a link in order and returns the variables they create. A transformation reads the
+ * value of the field it is configured for, or the value of the linked field.
+ */
+function getLinkTransformationVars(
+  transformations: DataLinkTransformationConfig[] | undefined,
+  field: Field,
+  rowIndex: number,
+  dataFrame?: DataFrame
+): ScopedVars {
+  let transformationVars: ScopedVars = {};
+  transformations?.forEach((transformation) => {
+    let fieldValue;
+    if (transformation.field) {
+      const transformField = dataFrame?.fields.find((field) => field.name === transformation.field);
+      fieldValue = transformField?.values[rowIndex];
+    } else {
+      fieldValue = field.values[rowIndex];
+    }
+
+    transformationVars = {
+      ...transformationVars,
+      ...getTransformationVars(transformation, fieldValue, field.name, transformationVars),
+    };
+  });
+  return transformationVars;
+}
+
 /**
  * @internal
  */
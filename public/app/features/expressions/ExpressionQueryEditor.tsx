@@ -3,6 +3,7 @@ import { useCallback, useEffect, useRef } from 'react';
 import { DataSourceApi, QueryEditorProps, SelectableValue } from '@grafana/data';
 import { InlineField, Select } from '@grafana/ui';
 
+import { Anomaly } from './components/Anomaly';
 import { ClassicConditions } from './components/ClassicConditions';
 import { Math } from './components/Math';
 import { Reduce } from './components/Reduce';
@@ -29,6 +30,7 @@ function useExpressionsCache() {
       case ExpressionQueryType.resample:
       case ExpressionQueryType.threshold:
       case ExpressionQueryType.sql:
+      case ExpressionQueryType.anomaly:
         return expressionCache.current[queryType];
       case ExpressionQueryType.classic:
         return undefined;
@@ -96,6 +98,9 @@ export function ExpressionQueryEditor(props: Props) {
 
       case ExpressionQueryType.sql:
         return <SqlExpr onChange={onChange} query={query} refIds={refIds} />;
+
+      case ExpressionQueryType.anomaly:
+        return <Anomaly query={query} labelWidth={labelWidth} onChange={onChange} refIds={refIds} />;
     }
   };
 
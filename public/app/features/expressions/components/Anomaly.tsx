@@ -0,0 +1,95 @@
+import { ChangeEvent } from 'react';
+
+import { SelectableValue } from '@grafana/data';
+import { InlineField, InlineFieldRow, InlineSwitch, Input, Select } from '@grafana/ui';
+
+import { anomalyMethods, anomalyOutputs, ExpressionQuery } from '../types';
+
+interface Props {
+  refIds: Array<SelectableValue<string>>;
+  query: ExpressionQuery;
+  labelWidth?: number | 'auto';
+  onChange: (query: ExpressionQuery) => void;
+}
+
+export const Anomaly = ({ labelWidth = 'auto', onChange, refIds, query }: Props) => {
+  const method = anomalyMethods.find((o) => o.value === query.method);
+  const output = anomalyOutputs.find((o) => o.value === (query.output ?? 'is_anomaly'));
+  const isHoltWinters = query.method === 'holt_winters';
+
+  const onRefIdChange = (value: SelectableValue<string>) => {
+    onChange({ ...query, expression: value.value });
+  };
+
+  const onSelectMethod = (value: SelectableValue<string>) => {
+    onChange({ ...query, method: value.value });
+  };
+
+  const onSelectOutput = (value: SelectableValue<string>) => {
+    onChange({ ...query, output: value.value });
+  };
+
+  const onWindowChange = (event: ChangeEvent<HTMLInputElement>) => {
+    onChange({ ...query, window: event.target.value });
+  };
+
+  const onDeviationsChange = (event: ChangeEvent<HTMLInputElement>) => {
+    const deviations = event.target.valueAsNumber;
+    onChange({ ...query, deviations: Number.isNaN(deviations) || deviations <= 0 ? undefined : deviations });
+  };
+
+  const onSeasonLengthChange = (event: ChangeEvent<HTMLInputElement>) => {
+    const seasonLength = event.target.valueAsNumber;
+    onChange({ ...query, seasonLength: Number.isNaN(seasonLength) || seasonLength <= 0 ? undefined : seasonLength });
+  };
+
+  const onLatestOnlyChange = (event: ChangeEvent<HTMLInputElement>) => {
+    onChange({ ...query, latestOnly: event.currentTarget.checked || undefined });
+  };
+
+  return (
+    <>
+      <InlineFieldRow>
+        <InlineField label="Input" labelWidth={labelWidth}>
+          <Select onChange={onRefIdChange} options={refIds} value={query.expression} width={20} />
+        </InlineField>
+        <InlineField label="Method">
+          <Select options={anomalyMethods} value={method} onChange={onSelectMethod} width={20} />
+        </InlineField>
+        {isHoltWinters ? (
+          <InlineField
+            label="Season length"
+            tooltip="Number of points in a season. Leave empty to disable seasonality."
+          >
+            <Input type="number" min={0} onChange={onSeasonLengthChange} value={query.seasonLength ?? ''} width={10} />
+          </InlineField>
+        ) : (
+          <InlineField label="Window" tooltip="The rolling window preceding each point, for example 1h">
+            <Input onChange={onWindowChange} value={query.window} width={10} />
+          </InlineField>
+        )}
+        <InlineField label="Deviations" tooltip="Width of the expected band on each side of the expected value">
+          <Input
+            type="number"
+            min={0}
+            placeholder="3"
+            onChange={onDeviationsChange}
+            value={query.deviations ?? ''}
+            width={10}
+          />
+        </InlineField>
+      </InlineFieldRow>
+      <InlineFieldRow>
+        <InlineField label="Output" labelWidth={labelWidth}>
+          <Select options={anomalyOutputs} value={output} onChange={onSelectOutput} width={20} />
+        </InlineField>
+        <InlineField
+          label="Latest only"
+          tooltip="Return only the output at the latest point of each series, so the expression can be used as the alert condition"
+        >
+          <InlineSwitch value={query.latestOnly ?? false} onChange={onLatestOnlyChange} />
+        </InlineField>
+      </InlineFieldRow>
+    </>
+  );
+};
@@ -88,6 +88,30 @@ export const Math = ({ labelWidth, onChange, query, onRunQuery }: Props) => {
                       name="floor"
                       description="rounds the number down to the nearest integer value. It's able to operate on series or escalar values."
                     />
+                    <DocumentedFunction
+                      name="clamp"
+                      description="limits its first argument to the range given by the min and max scalars, e.g. clamp($A, 0, 100). It's able to operate on series or scalar values."
+                    />
+                    <DocumentedFunction
+                      name="rate"
+                      description="returns the per-second rate of increase between consecutive points of a series, treating decreases as counter resets."
+                    />
+                    <DocumentedFunction
+                      name="delta"
+                      description="returns the difference between consecutive points of a series."
+                    />
+                    <DocumentedFunction
+                      name="cumsum"
+                      description="returns the running total of a series."
+                    />
+                    <DocumentedFunction
+                      name="moving_avg"
+                      description='returns the average of each point and the points before it within a time window, e.g. moving_avg($A, "5m").'
+                    />
+                    <DocumentedFunction
+                      name="time_shift"
+                      description='moves every point of a series forward in time by a duration, e.g. time_shift($A, "1w"). Use a negative duration to move points backwards.'
+                    />
                   </div>
                 </div>
               }
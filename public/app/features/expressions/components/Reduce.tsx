@@ -20,7 +20,15 @@ export const Reduce = ({ labelWidth = 'auto', onChange, refIds, query }: Props)
   };
 
   const onSelectReducer = (value: SelectableValue<string>) => {
-    onChange({ ...query, reducer: value.value });
+    if (value.value === 'percentile') {
+      onChange({ ...query, reducer: value.value, percentile: query.percentile ?? 95 });
+      return;
+    }
+    onChange({ ...query, reducer: value.value, percentile: undefined });
+  };
+
+  const onPercentileChanged = (e: React.FormEvent<HTMLInputElement>) => {
+    onChange({ ...query, percentile: e.currentTarget.valueAsNumber });
   };
 
   const onSettingsChanged = (settings: ExpressionQuerySettings) => {
@@ -66,6 +74,17 @@ export const Reduce = ({ labelWidth = 'auto', onChange, refIds, query }: Props)
     );
   };
 
+  const percentile = () => {
+    if (query.reducer !== 'percentile') {
+      return;
+    }
+    return (
+      <InlineField label="Percentile" labelWidth={labelWidth}>
+        <Input type="number" min={0} max={100} width={10} onChange={onPercentileChanged} value={query.percentile ?? 95} />
+      </InlineField>
+    );
+  };
+
   return (
     <>
       <InlineFieldRow>
@@ -77,6 +96,7 @@ export const Reduce = ({ labelWidth = 'auto', onChange, refIds, query }: Props)
         <InlineField label="Function" labelWidth={labelWidth}>
           <Select options={reducerTypes} value={reducer} onChange={onSelectReducer} width={20} />
         </InlineField>
+        {percentile()}
         <InlineField label="Mode" labelWidth={labelWidth}>
           <Select onChange={onModeChanged} options={reducerModes} value={mode} width={25} />
         </InlineField>
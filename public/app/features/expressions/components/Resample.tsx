@@ -32,6 +32,11 @@ export const Resample = ({ labelWidth = 'auto', onChange, refIds, query }: Props
     onChange({ ...query, upsampler: value.value });
   };
 
+  const onMaxGapChange = (event: ChangeEvent<HTMLInputElement>) => {
+    const maxGap = event.target.valueAsNumber;
+    onChange({ ...query, maxGap: Number.isNaN(maxGap) || maxGap <= 0 ? undefined : maxGap });
+  };
+
   return (
     <>
       <InlineFieldRow>
@@ -49,6 +54,12 @@ export const Resample = ({ labelWidth = 'auto', onChange, refIds, query }: Props
         <InlineField label="Upsample">
           <Select options={upsamplingTypes} value={upsampler} onChange={onSelectUpsampler} width={25} />
         </InlineField>
+        <InlineField
+          label="Max gap"
+          tooltip="Only fill gaps that span at most this many windows when upsampling. Leave empty to fill all gaps."
+        >
+          <Input type="number" min={0} onChange={onMaxGapChange} value={query.maxGap ?? ''} width={10} />
+        </InlineField>
       </InlineFieldRow>
     </>
   );
@@ -110,6 +110,18 @@ function getUnloadEvaluatorTypeFromEvaluatorType(type: EvalFunction) {
   if (type === EvalFunction.IsOutsideRange) {
     return EvalFunction.IsWithinRange;
   }
+  if (type === EvalFunction.IsAboveOrEqual) {
+    return EvalFunction.IsBelowOrEqual;
+  }
+  if (type === EvalFunction.IsBelowOrEqual) {
+    return EvalFunction.IsAboveOrEqual;
+  }
+  if (type === EvalFunction.IsEqual) {
+    return EvalFunction.IsNotEqual;
+  }
+  if (type === EvalFunction.IsNotEqual) {
+    return EvalFunction.IsEqual;
+  }
   return EvalFunction.IsBelow;
 }
 
@@ -140,11 +152,13 @@ export function isInvalid(condition: ClassicCondition) {
 
   switch (type) {
     case EvalFunction.IsAbove:
+    case EvalFunction.IsAboveOrEqual:
       if (firstParamInUnloadEvaluator > firstParamInEvaluator) {
         return { errorMsg: `Enter a number less than or equal to ${firstParamInEvaluator}` };
       }
       break;
     case EvalFunction.IsBelow:
+    case EvalFunction.IsBelowOrEqual:
       if (firstParamInUnloadEvaluator < firstParamInEvaluator) {
         return { errorMsg: `Enter a number more than or equal to ${firstParamInEvaluator}` };
       }
@@ -165,6 +179,10 @@ export function isInvalid(condition: ClassicCondition) {
         return { errorMsgTo: `Enter a number be more than or equal to ${secondParamInEvaluator}` };
       }
       break;
+    case EvalFunction.IsEqual:
+    case EvalFunction.IsNotEqual:
+      // any recovery value is valid as there is no direction to compare
+      break;
     default:
       throw new Error(`evaluator function type ${type} not supported.`);
   }
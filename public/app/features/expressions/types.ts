@@ -15,6 +15,7 @@ export enum ExpressionQueryType {
   classic = 'classic_conditions',
   threshold = 'threshold',
   sql = 'sql',
+  anomaly = 'anomaly',
 }
 
 export const getExpressionLabel = (type: ExpressionQueryType) => {
@@ -31,6 +32,8 @@ export const getExpressionLabel = (type: ExpressionQueryType) => {
       return 'Threshold';
     case ExpressionQueryType.sql:
       return 'SQL';
+    case ExpressionQueryType.anomaly:
+      return 'Anomaly detection';
   }
 };
 
@@ -63,6 +66,12 @@ export const expressionTypes: Array<SelectableValue<ExpressionQueryType>> = [
     description:
       'Takes one or more time series returned from a query or an expression and checks if any of the series match the threshold condition.',
   },
+  {
+    value: ExpressionQueryType.anomaly,
+    label: 'Anomaly detection',
+    description:
+      'Checks each point of one or more time series against the values expected from the preceding points.',
+  },
   {
     value: ExpressionQueryType.sql,
     label: 'SQL',
@@ -83,6 +92,26 @@ export const reducerTypes: Array<SelectableValue<string>> = [
   { value: ReducerID.sum, label: 'Sum', description: 'Get the sum of all values' },
   { value: ReducerID.count, label: 'Count', description: 'Get the number of values' },
   { value: ReducerID.last, label: 'Last', description: 'Get the last value' },
+  { value: ReducerID.first, label: 'First', description: 'Get the first value' },
+  { value: 'stddev', label: 'Standard deviation', description: 'Get the standard deviation of all values' },
+  { value: ReducerID.range, label: 'Range', description: 'Get the difference between the maximum and minimum values' },
+  { value: ReducerID.diff, label: 'Difference', description: 'Get the difference between the last and first values' },
+  { value: 'count_non_null', label: 'Count non-null', description: 'Get the number of non-null values' },
+  { value: 'percentile', label: 'Percentile', description: 'Get the nth percentile of all values' },
+];
+
+export const anomalyMethods: Array<SelectableValue<string>> = [
+  { value: 'zscore', label: 'Z-score', description: 'Mean and standard deviation of the window' },
+  { value: 'mad', label: 'MAD', description: 'Median and median absolute deviation of the window' },
+  { value: 'holt_winters', label: 'Holt-Winters', description: 'Forecast of a Holt-Winters model' },
+];
+
+export const anomalyOutputs: Array<SelectableValue<string>> = [
+  { value: 'is_anomaly', label: 'Is anomaly', description: '1 when the point is outside of the band, otherwise 0' },
+  { value: 'score', label: 'Score', description: 'Distance from the expected value in deviations' },
+  { value: 'expected', label: 'Expected', description: 'The expected value' },
+  { value: 'lower', label: 'Lower bound', description: 'The lower bound of the band' },
+  { value: 'upper', label: 'Upper bound', description: 'The upper bound of the band' },
 ];
 
 export enum ReducerMode {
@@ -121,6 +150,7 @@ export const upsamplingTypes: Array<SelectableValue<string>> = [
   { value: 'pad', label: 'pad', description: 'fill with the last known value' },
   { value: 'backfilling', label: 'backfilling', description: 'fill with the next known value' },
   { value: 'fillna', label: 'fillna', description: 'Fill with NaNs' },
+  { value: 'linear', label: 'linear', description: 'interpolate between the last and the next known values' },
 ];
 
 export const thresholdFunctions: Array<SelectableValue<EvalFunction>> = [
@@ -128,6 +158,10 @@ export const thresholdFunctions: Array<SelectableValue<EvalFunction>> = [
   { value: EvalFunction.IsBelow, label: 'Is below' },
   { value: EvalFunction.IsWithinRange, label: 'Is within range' },
   { value: EvalFunction.IsOutsideRange, label: 'Is outside range' },
+  { value: EvalFunction.IsAboveOrEqual, label: 'Is above or equal' },
+  { value: EvalFunction.IsBelowOrEqual, label: 'Is below or equal' },
+  { value: EvalFunction.IsEqual, label: 'Is equal' },
+  { value: EvalFunction.IsNotEqual, label: 'Is not equal' },
 ];
 
 /**
@@ -137,10 +171,17 @@ export const thresholdFunctions: Array<SelectableValue<EvalFunction>> = [
 export interface ExpressionQuery extends DataQuery {
   type: ExpressionQueryType;
   reducer?: string;
+  percentile?: number;
   expression?: string;
   window?: string;
   downsampler?: string;
   upsampler?: string;
+  maxGap?: number;
+  method?: string;
+  output?: string;
+  deviations?: number;
+  seasonLength?: number;
+  latestOnly?: boolean;
   conditions?: ClassicCondition[];
   settings?: ExpressionQuerySettings;
 }
@@ -153,14 +194,30 @@ export interface ExpressionQuerySettings {
   replaceWithValue?: number;
 }
 
+export interface ThresholdTimeInterval {
+  times?: Array<{ start_time: string; end_time: string }>;
+  weekdays?: string[];
+  days_of_month?: string[];
+  months?: string[];
+  years?: string[];
+  location?: string;
+}
+
+export interface ThresholdSchedule {
+  timeIntervals: ThresholdTimeInterval[];
+  params: number[];
+}
+
 export interface ClassicCondition {
   evaluator: {
     params: number[];
     type: EvalFunction;
+    schedule?: ThresholdSchedule[];
   };
   unloadEvaluator?: {
     params: number[];
     type: EvalFunction;
+    schedule?: ThresholdSchedule[];
   };
   operator?: {
     type: string;
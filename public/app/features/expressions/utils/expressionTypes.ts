@@ -24,6 +24,18 @@ export const getDefaults = (query: ExpressionQuery) => {
       query.reducer = undefined;
       break;
 
+    case ExpressionQueryType.anomaly:
+      if (!query.method) {
+        query.method = 'zscore';
+      }
+
+      if (!query.window) {
+        query.window = '1h';
+      }
+
+      query.reducer = undefined;
+      break;
+
     case ExpressionQueryType.math:
       query.expression = undefined;
       break;
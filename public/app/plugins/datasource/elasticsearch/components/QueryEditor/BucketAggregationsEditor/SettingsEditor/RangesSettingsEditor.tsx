@@ -0,0 +1,81 @@
+import { css } from '@emotion/css';
+import { uniqueId } from 'lodash';
+import { useRef } from 'react';
+
+import { InlineField, Input } from '@grafana/ui';
+
+import { useDispatch } from '../../../../hooks/useStatelessReducer';
+import { DateRange, Range, RangeBucket } from '../../../../types';
+import { AddRemove } from '../../../AddRemove';
+import { changeBucketAggregationSetting } from '../state/actions';
+
+interface Props {
+  bucketAgg: Range | DateRange;
+}
+
+export const RangesSettingsEditor = ({ bucketAgg }: Props) => {
+  const { current: baseId } = useRef(uniqueId('es-ranges-'));
+
+  const dispatch = useDispatch();
+
+  const ranges: RangeBucket[] = bucketAgg.settings?.ranges?.length ? bucketAgg.settings.ranges : [{}];
+  const placeholders = bucketAgg.type === 'date_range' ? { from: 'now-1d', to: 'now' } : { from: '0', to: '100' };
+
+  const onChange = (newValue: RangeBucket[]) =>
+    dispatch(changeBucketAggregationSetting({ bucketAgg, settingName: 'ranges', newValue }));
+
+  const changeRange = (index: number, range: RangeBucket) =>
+    onChange(ranges.map((r, i) => (i === index ? range : r)));
+
+  return (
+    <div
+      className={css`
+        display: flex;
+        flex-direction: column;
+      `}
+    >
+      {ranges.map((range, index) => (
+        <div
+          key={index}
+          className={css`
+            display: flex;
+          `}
+        >
+          <InlineField label="From" labelWidth={8}>
+            <Input
+              width={12}
+              id={`${baseId}-from-${index}`}
+              placeholder={placeholders.from}
+              onBlur={(e) => changeRange(index, { ...range, from: e.target.value })}
+              defaultValue={range.from}
+            />
+          </InlineField>
+          <InlineField label="To" labelWidth={8}>
+            <Input
+              width={12}
+              id={`${baseId}-to-${index}`}
+              placeholder={placeholders.to}
+              onBlur={(e) => changeRange(index, { ...range, to: e.target.value })}
+              defaultValue={range.to}
+            />
+          </InlineField>
+          <InlineField label="Key" labelWidth={8}>
+            <Input
+              width={12}
+              id={`${baseId}-key-${index}`}
+              placeholder="Key"
+              onBlur={(e) => changeRange(index, { ...range, key: e.target.value })}
+              defaultValue={range.key}
+            />
+          </InlineField>
+          <AddRemove
+            index={index}
+            elements={ranges}
+            onAdd={() => onChange([...ranges, {}])}
+            onRemove={() => onChange(ranges.filter((_, i) => i !== index))}
+          />
+        </div>
+      ))}
+    </div>
+  );
+};
@@ -11,6 +11,7 @@ import { bucketAggregationConfig } from '../utils';
 
 import { DateHistogramSettingsEditor } from './DateHistogramSettingsEditor';
 import { FiltersSettingsEditor } from './FiltersSettingsEditor';
+import { RangesSettingsEditor } from './RangesSettingsEditor';
 import { TermsSettingsEditor } from './TermsSettingsEditor';
 import { useDescription } from './useDescription';
 
@@ -34,6 +35,9 @@ export const SettingsEditor = ({ bucketAgg }: Props) => {
       {bucketAgg.type === 'terms' && <TermsSettingsEditor bucketAgg={bucketAgg} />}
       {bucketAgg.type === 'date_histogram' && <DateHistogramSettingsEditor bucketAgg={bucketAgg} />}
       {bucketAgg.type === 'filters' && <FiltersSettingsEditor bucketAgg={bucketAgg} />}
+      {(bucketAgg.type === 'range' || bucketAgg.type === 'date_range') && (
+        <RangesSettingsEditor bucketAgg={bucketAgg} />
+      )}
 
       {bucketAgg.type === 'geohash_grid' && (
         <InlineField label="Precision" {...inlineFieldProps}>
@@ -51,6 +55,83 @@ export const SettingsEditor = ({ bucketAgg }: Props) => {
         </InlineField>
       )}
 
+      {bucketAgg.type === 'geotile_grid' && (
+        <InlineField label="Precision" {...inlineFieldProps}>
+          <Input
+            id={`${baseId}-geotile_grid-precision`}
+            onBlur={(e) =>
+              dispatch(
+                changeBucketAggregationSetting({ bucketAgg, settingName: 'precision', newValue: e.target.value })
+              )
+            }
+            defaultValue={
+              bucketAgg.settings?.precision || bucketAggregationConfig[bucketAgg.type].defaultSettings?.precision
+            }
+          />
+        </InlineField>
+      )}
+
+      {bucketAgg.type === 'composite' && (
+        <>
+          <InlineField label="Size" {...inlineFieldProps}>
+            <Input
+              id={`${baseId}-composite-size`}
+              onBlur={(e) =>
+                dispatch(changeBucketAggregationSetting({ bucketAgg, settingName: 'size', newValue: e.target.value }))
+              }
+              defaultValue={bucketAgg.settings?.size || bucketAggregationConfig[bucketAgg.type].defaultSettings?.size}
+            />
+          </InlineField>
+
+          <InlineField
+            label="Max pages"
+            tooltip="Maximum number of pages requested, each page starts after the last bucket of the previous page"
+            {...inlineFieldProps}
+          >
+            <Input
+              id={`${baseId}-composite-maxPages`}
+              onBlur={(e) =>
+                dispatch(
+                  changeBucketAggregationSetting({ bucketAgg, settingName: 'maxPages', newValue: e.target.value })
+                )
+              }
+              defaultValue={
+                bucketAgg.settings?.maxPages || bucketAggregationConfig[bucketAgg.type].defaultSettings?.maxPages
+              }
+            />
+          </InlineField>
+        </>
+      )}
+
+      {bucketAgg.type === 'significant_terms' && (
+        <>
+          <InlineField label="Size" {...inlineFieldProps}>
+            <Input
+              id={`${baseId}-significant_terms-size`}
+              onBlur={(e) =>
+                dispatch(changeBucketAggregationSetting({ bucketAgg, settingName: 'size', newValue: e.target.value }))
+              }
+              defaultValue={bucketAgg.settings?.size || bucketAggregationConfig[bucketAgg.type].defaultSettings?.size}
+            />
+          </InlineField>
+
+          <InlineField label="Min Doc Count" {...inlineFieldProps}>
+            <Input
+              id={`${baseId}-significant_terms-min_doc_count`}
+              onBlur={(e) =>
+                dispatch(
+                  changeBucketAggregationSetting({ bucketAgg, settingName: 'min_doc_count', newValue: e.target.value })
+                )
+              }
+              defaultValue={
+                bucketAgg.settings?.min_doc_count ||
+                bucketAggregationConfig[bucketAgg.type].defaultSettings?.min_doc_count
+              }
+            />
+          </InlineField>
+        </>
+      )}
+
       {bucketAgg.type === 'histogram' && (
         <>
           <InlineField label="Interval" {...inlineFieldProps}>
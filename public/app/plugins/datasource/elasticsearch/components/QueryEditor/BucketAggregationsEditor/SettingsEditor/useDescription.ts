@@ -1,4 +1,4 @@
-import { defaultGeoHashPrecisionString } from '../../../../queryDef';
+import { defaultGeoHashPrecisionString, defaultGeoTilePrecisionString } from '../../../../queryDef';
 import { BucketAggregation } from '../../../../types';
 import { describeMetric, convertOrderByToMetricId } from '../../../../utils';
 import { useQuery } from '../../ElasticsearchQueryContext';
@@ -67,6 +67,32 @@ export const useDescription = (bucketAgg: BucketAggregation): string => {
       return `Precision: ${precision}`;
     }
 
+    case 'geotile_grid': {
+      const precision = parseInt(bucketAgg.settings?.precision || defaultGeoTilePrecisionString, 10);
+
+      return `Precision: ${precision}`;
+    }
+
+    case 'composite': {
+      const size = bucketAgg.settings?.size || bucketAggregationConfig['composite'].defaultSettings?.size;
+      const maxPages = bucketAgg.settings?.maxPages || bucketAggregationConfig['composite'].defaultSettings?.maxPages;
+
+      return `Size: ${size}, Max pages: ${maxPages}`;
+    }
+
+    case 'range':
+    case 'date_range': {
+      const ranges = bucketAgg.settings?.ranges || [];
+      return `Ranges (${ranges.length})`;
+    }
+
+    case 'significant_terms': {
+      const size = bucketAgg.settings?.size || '10';
+      const minDocCount = parseInt(bucketAgg.settings?.min_doc_count || '3', 10);
+
+      return `Size: ${size}${minDocCount > 0 ? `, Min Doc Count: ${minDocCount}` : ''}`;
+    }
+
     case 'date_histogram': {
       const interval = bucketAgg.settings?.interval || 'auto';
       const minDocCount = parseInt(bucketAgg.settings?.min_doc_count || '0', 10);
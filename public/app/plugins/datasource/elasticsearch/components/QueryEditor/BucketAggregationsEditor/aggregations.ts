@@ -13,6 +13,11 @@ export const BUCKET_AGGREGATION_TYPES: BucketAggregationType[] = [
   'filters',
   'geohash_grid',
   'nested',
+  'geotile_grid',
+  'composite',
+  'range',
+  'date_range',
+  'significant_terms',
 ];
 
 export const isBucketAggregationType = (s: BucketAggregationType | string): s is BucketAggregationType =>
@@ -1,6 +1,6 @@
 import { InternalTimeZones, SelectableValue } from '@grafana/data';
 
-import { defaultGeoHashPrecisionString } from '../../../queryDef';
+import { defaultGeoHashPrecisionString, defaultGeoTilePrecisionString } from '../../../queryDef';
 import { BucketsConfiguration } from '../../../types';
 
 import { defaultFilter } from './SettingsEditor/FiltersSettingsEditor/utils';
@@ -53,6 +53,43 @@ export const bucketAggregationConfig: BucketsConfiguration = {
     requiresField: true,
     defaultSettings: {},
   },
+  geotile_grid: {
+    label: 'Geo Tile Grid',
+    requiresField: true,
+    defaultSettings: {
+      precision: defaultGeoTilePrecisionString,
+    },
+  },
+  composite: {
+    label: 'Composite',
+    requiresField: true,
+    defaultSettings: {
+      size: '500',
+      maxPages: '10',
+    },
+  },
+  range: {
+    label: 'Range',
+    requiresField: true,
+    defaultSettings: {
+      ranges: [{ to: '100' }, { from: '100' }],
+    },
+  },
+  date_range: {
+    label: 'Date Range',
+    requiresField: true,
+    defaultSettings: {
+      ranges: [{ from: 'now-1d' }],
+    },
+  },
+  significant_terms: {
+    label: 'Significant Terms',
+    requiresField: true,
+    defaultSettings: {
+      size: '10',
+      min_doc_count: '3',
+    },
+  },
 };
 
 export const orderByOptions: Array<SelectableValue<string>> = [
@@ -38,10 +38,10 @@ composableKinds: DataQuery: {
 				// List of metric aggregations
 				metrics?: [...#MetricAggregation]
 
-				#BucketAggregation: #DateHistogram | #Histogram | #Terms | #Filters | #GeoHashGrid | #Nested @cuetsy(kind="type")
+				#BucketAggregation: #DateHistogram | #Histogram | #Terms | #Filters | #GeoHashGrid | #Nested | #GeoTileGrid | #Composite | #Range | #DateRange | #SignificantTerms @cuetsy(kind="type")
 				#MetricAggregation: #Count | #PipelineMetricAggregation | #MetricAggregationWithSettings     @cuetsy(kind="type")
 
-				#BucketAggregationType: "terms" | "filters" | "geohash_grid" | "date_histogram" | "histogram" | "nested" @cuetsy(kind="type")
+				#BucketAggregationType: "terms" | "filters" | "geohash_grid" | "date_histogram" | "histogram" | "nested" | "geotile_grid" | "composite" | "range" | "date_range" | "significant_terms" @cuetsy(kind="type")
 
 				#BaseBucketAggregation: {
 					id:        string
@@ -126,6 +126,80 @@ composableKinds: DataQuery: {
 					precision?: string
 				} @cuetsy(kind="interface")
 
+				#GeoTileGrid: {
+					#BucketAggregationWithField
+					type:      #BucketAggregationType & "geotile_grid"
+					settings?: #GeoTileGridSettings
+				} @cuetsy(kind="interface")
+
+				#GeoTileGridSettings: {
+					precision?: string
+				} @cuetsy(kind="interface")
+
+				#Composite: {
+					#BucketAggregationWithField
+					type:      #BucketAggregationType & "composite"
+					settings?: #CompositeSettings
+				} @cuetsy(kind="interface")
+
+				#CompositeSourceType: "terms" | "histogram" | "date_histogram" @cuetsy(kind="type")
+
+				#CompositeSource: {
+					// Name of the source in the bucket keys, defaults to the field
+					name?:           string
+					type?:           #CompositeSourceType
+					field:           string
+					interval?:       string
+					order?:          #TermsOrder
+					missing_bucket?: bool
+				} @cuetsy(kind="interface")
+
+				#CompositeSettings: {
+					size?: string
+					// Maximum number of pages requested using the after key of the previous page
+					maxPages?: string
+					sources?: [...#CompositeSource]
+				} @cuetsy(kind="interface")
+
+				#RangeBucket: {
+					key?:  string
+					from?: string
+					to?:   string
+				} @cuetsy(kind="interface")
+
+				#Range: {
+					#BucketAggregationWithField
+					type:      #BucketAggregationType & "range"
+					settings?: #RangeSettings
+				} @cuetsy(kind="interface")
+
+				#RangeSettings: {
+					ranges?: [...#RangeBucket]
+				} @cuetsy(kind="interface")
+
+				#DateRange: {
+					#BucketAggregationWithField
+					type:      #BucketAggregationType & "date_range"
+					settings?: #DateRangeSettings
+				} @cuetsy(kind="interface")
+
+				#DateRangeSettings: {
+					ranges?: [...#RangeBucket]
+					format?:   string
+					timeZone?: string
+				} @cuetsy(kind="interface")
+
+				#SignificantTerms: {
+					#BucketAggregationWithField
+					type:      #BucketAggregationType & "significant_terms"
+					settings?: #SignificantTermsSettings
+				} @cuetsy(kind="interface")
+
+				#SignificantTermsSettings: {
+					size?:          string
+					min_doc_count?: string
+				} @cuetsy(kind="interface")
+
 				#PipelineMetricAggregationType: "moving_avg" | "moving_fn" | "derivative" | "serial_diff" | "cumulative_sum" | "bucket_script"                                                                                              @cuetsy(kind="type")
 				#MetricAggregationType:         "count" | "avg" | "sum" | "min" | "max" | "extended_stats" | "percentiles" | "cardinality" | "raw_document" | "raw_data" | "logs" | "rate" | "top_metrics" | #PipelineMetricAggregationType @cuetsy(kind="type")
 
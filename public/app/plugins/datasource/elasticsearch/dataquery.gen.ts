@@ -10,11 +10,11 @@
 
 import * as common from '@grafana/schema';
 
-export type BucketAggregation = (DateHistogram | Histogram | Terms | Filters | GeoHashGrid | Nested);
+export type BucketAggregation = (DateHistogram | Histogram | Terms | Filters | GeoHashGrid | Nested | GeoTileGrid | Composite | Range | DateRange | SignificantTerms);
 
 export type MetricAggregation = (Count | PipelineMetricAggregation | MetricAggregationWithSettings);
 
-export type BucketAggregationType = ('terms' | 'filters' | 'geohash_grid' | 'date_histogram' | 'histogram' | 'nested');
+export type BucketAggregationType = ('terms' | 'filters' | 'geohash_grid' | 'date_histogram' | 'histogram' | 'nested' | 'geotile_grid' | 'composite' | 'range' | 'date_range' | 'significant_terms');
 
 export interface BaseBucketAggregation {
   id: string;
@@ -115,6 +115,109 @@ export interface GeoHashGridSettings {
   precision?: string;
 }
 
+export interface GeoTileGrid extends BucketAggregationWithField {
+  settings?: {
+    precision?: string;
+  };
+  type: 'geotile_grid';
+}
+
+export interface GeoTileGridSettings {
+  precision?: string;
+}
+
+export interface Composite extends BucketAggregationWithField {
+  settings?: {
+    size?: string;
+    /**
+     * Maximum number of pages requested using the after key of the previous page
+     */
+    maxPages?: string;
+    sources?: Array<CompositeSource>;
+  };
+  type: 'composite';
+}
+
+export type CompositeSourceType = ('terms' | 'histogram' | 'date_histogram');
+
+export interface CompositeSource {
+  field: string;
+  interval?: string;
+  missing_bucket?: boolean;
+  /**
+   * Name of the source in the bucket keys, defaults to the field
+   */
+  name?: string;
+  order?: TermsOrder;
+  type?: CompositeSourceType;
+}
+
+export interface CompositeSettings {
+  /**
+   * Maximum number of pages requested using the after key of the previous page
+   */
+  maxPages?: string;
+  size?: string;
+  sources?: Array<CompositeSource>;
+}
+
+export const defaultCompositeSettings: Partial<CompositeSettings> = {
+  sources: [],
+};
+
+export interface RangeBucket {
+  from?: string;
+  key?: string;
+  to?: string;
+}
+
+export interface Range extends BucketAggregationWithField {
+  settings?: {
+    ranges?: Array<RangeBucket>;
+  };
+  type: 'range';
+}
+
+export interface RangeSettings {
+  ranges?: Array<RangeBucket>;
+}
+
+export const defaultRangeSettings: Partial<RangeSettings> = {
+  ranges: [],
+};
+
+export interface DateRange extends BucketAggregationWithField {
+  settings?: {
+    ranges?: Array<RangeBucket>;
+    format?: string;
+    timeZone?: string;
+  };
+  type: 'date_range';
+}
+
+export interface DateRangeSettings {
+  format?: string;
+  ranges?: Array<RangeBucket>;
+  timeZone?: string;
+}
+
+export const defaultDateRangeSettings: Partial<DateRangeSettings> = {
+  ranges: [],
+};
+
+export interface SignificantTerms extends BucketAggregationWithField {
+  settings?: {
+    size?: string;
+    min_doc_count?: string;
+  };
+  type: 'significant_terms';
+}
+
+export interface SignificantTermsSettings {
+  min_doc_count?: string;
+  size?: string;
+}
+
 export type PipelineMetricAggregationType = ('moving_avg' | 'moving_fn' | 'derivative' | 'serial_diff' | 'cumulative_sum' | 'bucket_script');
 
 export type MetricAggregationType = ('count' | 'avg' | 'sum' | 'min' | 'max' | 'extended_stats' | 'percentiles' | 'cardinality' | 'raw_document' | 'raw_data' | 'logs' | 'rate' | 'top_metrics' | PipelineMetricAggregationType);
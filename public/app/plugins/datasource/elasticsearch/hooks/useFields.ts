@@ -29,9 +29,13 @@ const getFilter = (type: AggregationType) => {
     switch (type) {
       case 'date_histogram':
         return ['date'];
+      case 'date_range':
+        return ['date'];
       case 'geohash_grid':
+      case 'geotile_grid':
         return ['geo_point'];
       case 'histogram':
+      case 'range':
         return ['number'];
       default:
         return [];
@@ -33,6 +33,7 @@ export const highlightTags = {
 };
 
 export const defaultGeoHashPrecisionString = '3';
+export const defaultGeoTilePrecisionString = '7';
 
 export function defaultMetricAgg(id = '1'): MetricAggregation {
   return { type: 'count', id };
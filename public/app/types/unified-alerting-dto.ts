@@ -130,6 +130,7 @@ export interface PromAlertingRuleDTO extends PromRuleDTOBase {
     state: Exclude<PromAlertingRuleState | GrafanaAlertStateWithReason, PromAlertingRuleState.Inactive>;
     activeAt: string;
     value: string;
+    flapping?: boolean;
   }>;
   labels: Labels;
   annotations?: Annotations;
@@ -226,10 +227,12 @@ export interface PostableGrafanaRuleDefinition {
   exec_err_state?: GrafanaAlertStateDecision;
   data: AlertQuery[];
   is_paused?: boolean;
+  depends_on?: string;
   notification_settings?: GrafanaNotificationSettings;
   record?: {
     metric: string;
     from: string;
+    writer?: string;
   };
 }
 export interface GrafanaRuleDefinition extends PostableGrafanaRuleDefinition {
@@ -258,6 +261,7 @@ export type RulerRuleGroupDTO<R = RulerRuleDTO> = {
   name: string;
   interval?: string;
   source_tenants?: string[];
+  sequential?: boolean;
   rules: R[];
 };
 